@@ -0,0 +1,531 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/mail"
+	"net/textproto"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Bounces and spam complaints. They arrive either as reports in a bounce mailbox, polled over IMAP,
+// or from the mail provider's webhook. Each one is matched to the outbox message it is about, the donor's
+// record is flagged, and the donor's team is asked to follow up by phone.
+//
+// IMAP: 'BOUNCE_IMAP_ADDRESS' (host:port), 'BOUNCE_IMAP_USERNAME', 'BOUNCE_IMAP_PASSWORD', and optionally
+// 'BOUNCE_IMAP_MAILBOX' (default INBOX) and 'BOUNCE_IMAP_TLS' (default true).
+// Webhook: POST /mail/events signed with 'MAIL_WEBHOOK_SECRET', see registerMailEventWebhook.
+
+type mailEvent struct {
+	ID         string    `json:"id"`
+	Type       string    `json:"type"` // MailEventBounce or MailEventComplaint
+	Recipient  string    `json:"recipient"`
+	Status     string    `json:"status,omitempty"` // Enhanced status code, e.g. 5.1.1
+	Diagnostic string    `json:"diagnostic,omitempty"`
+	MessageID  string    `json:"messageId,omitempty"`
+	OutboxID   string    `json:"outboxId,omitempty"`
+	Kind       string    `json:"kind,omitempty"`
+	Reference  string    `json:"reference,omitempty"`
+	Donor      bool      `json:"donor"`
+	Source     string    `json:"source"` // "imap" or "webhook"
+	Received   time.Time `json:"received"`
+}
+
+type mailEventData struct {
+	Events []mailEvent `json:"events"`
+}
+
+// What the donor's record says about a bounce or complaint, until finance clears it
+type EmailProblem struct {
+	Type       string    `json:"type"`
+	Status     string    `json:"status,omitempty"`
+	Diagnostic string    `json:"diagnostic,omitempty"`
+	MessageID  string    `json:"messageId,omitempty"`
+	Date       time.Time `json:"date"`
+}
+
+const mailEventsDocument string = "mailevents"
+
+const MailEventBounce string = "bounce"
+const MailEventComplaint string = "complaint"
+
+const bouncePollInterval time.Duration = 5 * time.Minute
+
+// The bounces and complaints in a delivery status notification (RFC 3464) or an abuse report (RFC 5965).
+// Anything else, such as an out-of-office reply, has none. Delayed deliveries aren't bounces and are left out.
+func parseMailReport(raw []byte) ([]mailEvent, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/report" {
+		return nil, nil
+	}
+
+	var recipients []textproto.MIMEHeader
+	var feedback textproto.MIMEHeader
+	var original textproto.MIMEHeader
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		body, err := ioutil.ReadAll(decodedPart(part))
+		if err != nil {
+			return nil, err
+		}
+		switch partType {
+		case "message/delivery-status", "message/global-delivery-status":
+			// Per-message fields, then one block of fields per recipient
+			blocks := headerBlocks(body)
+			if len(blocks) > 1 {
+				recipients = append(recipients, blocks[1:]...)
+			}
+		case "message/feedback-report":
+			if blocks := headerBlocks(body); len(blocks) > 0 {
+				feedback = blocks[0]
+			}
+		case "message/rfc822", "text/rfc822-headers", "message/rfc822-headers", "message/global", "message/global-headers":
+			if blocks := headerBlocks(body); len(blocks) > 0 {
+				original = blocks[0]
+			}
+		}
+	}
+
+	messageID := ""
+	if original != nil {
+		messageID = strings.Trim(strings.TrimSpace(original.Get("Message-Id")), "<>")
+	}
+	var events []mailEvent
+	for _, fields := range recipients {
+		if !strings.EqualFold(strings.TrimSpace(fields.Get("Action")), "failed") {
+			continue
+		}
+		recipient := fields.Get("Final-Recipient")
+		if recipient == "" {
+			recipient = fields.Get("Original-Recipient")
+		}
+		events = append(events, mailEvent{
+			Type:       MailEventBounce,
+			Recipient:  reportAddress(recipient),
+			Status:     strings.TrimSpace(fields.Get("Status")),
+			Diagnostic: strings.TrimSpace(fields.Get("Diagnostic-Code")),
+			MessageID:  messageID,
+		})
+	}
+	if feedback != nil {
+		recipient := reportAddress(feedback.Get("Original-Rcpt-To"))
+		if recipient == "" && original != nil {
+			if to, err := mail.ParseAddress(original.Get("To")); err == nil {
+				recipient = to.Address
+			}
+		}
+		events = append(events, mailEvent{Type: MailEventComplaint, Recipient: recipient, Diagnostic: strings.TrimSpace(feedback.Get("Feedback-Type")), MessageID: messageID})
+	}
+	return events, nil
+}
+
+// multipart.Reader already undoes quoted-printable, but not base64
+func decodedPart(part *multipart.Part) io.Reader {
+	switch strings.ToLower(part.Header.Get("Content-Transfer-Encoding")) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, part)
+	}
+	return part
+}
+
+// Header-style field blocks separated by blank lines
+func headerBlocks(body []byte) []textproto.MIMEHeader {
+	text := strings.Replace(string(body), "\r\n", "\n", -1)
+	var blocks []textproto.MIMEHeader
+	for _, block := range strings.Split(text, "\n\n") {
+		if strings.TrimSpace(block) == "" {
+			continue
+		}
+		fields, err := textproto.NewReader(bufio.NewReader(strings.NewReader(strings.TrimLeft(block, "\n") + "\n\n"))).ReadMIMEHeader()
+		if err != nil && len(fields) == 0 {
+			continue
+		}
+		blocks = append(blocks, fields)
+	}
+	return blocks
+}
+
+// "rfc822; donor@example.com" to "donor@example.com"
+func reportAddress(field string) string {
+	if semicolon := strings.Index(field, ";"); semicolon >= 0 {
+		field = field[semicolon+1:]
+	}
+	return donorKey(strings.Trim(strings.TrimSpace(field), "<>"))
+}
+
+// Saves new events, matched to the outbox message they are about, and flags donors and tells their teams.
+// An event already recorded (a webhook retry, say) is skipped.
+func processMailEvents(events []mailEvent, source string) error {
+	var outbox outboxData
+	err := dataStore().load(outboxDocument, &outbox)
+	if err != nil {
+		return err
+	}
+	var recorded []mailEvent
+	var data mailEventData
+	err = dataStore().update(mailEventsDocument, &data, func() error {
+		for _, event := range events {
+			event.Recipient = donorKey(event.Recipient)
+			event.MessageID = strings.Trim(strings.TrimSpace(event.MessageID), "<>")
+			if event.Recipient == "" || (event.Type != MailEventBounce && event.Type != MailEventComplaint) {
+				continue
+			}
+			if queued, found := bouncedMessage(outbox.Messages, event); found {
+				event.OutboxID, event.Kind, event.Reference = queued.ID, queued.Kind, queued.Reference
+				if event.MessageID == "" {
+					event.MessageID = queued.MessageID
+				}
+			}
+			duplicate := false
+			for _, existing := range data.Events {
+				if existing.Type == event.Type && existing.Recipient == event.Recipient && existing.MessageID == event.MessageID {
+					duplicate = true
+				}
+			}
+			if duplicate {
+				continue
+			}
+			event.Donor = false
+			event.ID = newID()
+			event.Source = source
+			event.Received = time.Now()
+			data.Events = append(data.Events, event)
+			recorded = append(recorded, event)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, event := range recorded {
+		donor, found, err := flagDonorEmail(event)
+		if err != nil {
+			return err
+		}
+		if !found {
+			fmt.Println("A " + event.Type + " for " + event.Recipient + " was recorded. It isn't a donor address, so nobody was asked to follow up.")
+			continue
+		}
+		err = markDonorEvent(event.ID)
+		if err != nil {
+			fmt.Println(err)
+		}
+		err = sendBounceFollowUp(event, donor)
+		if err != nil {
+			fmt.Println(err)
+			fmt.Println("ERROR: FOLLOW UP REQUEST FOR " + event.Recipient + " COULD NOT BE QUEUED")
+		}
+	}
+	return nil
+}
+
+// The outbox message with the event's Message-ID, or else the latest one sent to the recipient
+func bouncedMessage(messages []outboxMessage, event mailEvent) (outboxMessage, bool) {
+	var latest *outboxMessage
+	for i, queued := range messages {
+		if event.MessageID != "" && queued.MessageID == event.MessageID {
+			return queued, true
+		}
+		if queued.Status == OutboxSent && containsAddress(queued.To, event.Recipient) && (latest == nil || queued.Created.After(latest.Created)) {
+			latest = &messages[i]
+		}
+	}
+	if latest == nil || event.MessageID != "" {
+		return outboxMessage{}, false
+	}
+	return *latest, true
+}
+
+func markDonorEvent(id string) error {
+	var data mailEventData
+	return dataStore().update(mailEventsDocument, &data, func() error {
+		for i := range data.Events {
+			if data.Events[i].ID == id {
+				data.Events[i].Donor = true
+			}
+		}
+		return nil
+	})
+}
+
+// Who the team should call about a flagged address
+type bounceContact struct {
+	Name  string
+	Phone string
+	Team  string
+}
+
+// Sets EmailProblem on the donor's record, creating it from their latest gift if they don't have one yet.
+// Addresses that never gave (the team's own address, say) aren't flagged.
+func flagDonorEmail(event mailEvent) (bounceContact, bool, error) {
+	var gifts giftData
+	err := dataStore().load(giftsDocument, &gifts)
+	if err != nil {
+		return bounceContact{}, false, err
+	}
+	var given []Gift
+	for _, gift := range gifts.Gifts {
+		if donorKey(gift.DonorEmail) == event.Recipient {
+			given = append(given, gift)
+		}
+	}
+	sort.Slice(given, func(i, j int) bool { return given[i].Date.Before(given[j].Date) })
+	profile := donorProfile(event.Recipient, given)
+
+	problem := &EmailProblem{Type: event.Type, Status: event.Status, Diagnostic: event.Diagnostic, MessageID: event.MessageID, Date: event.Received}
+	found := false
+	var data donorData
+	err = dataStore().update(donorsDocument, &data, func() error {
+		for i := range data.Donors {
+			if data.Donors[i].Email == event.Recipient {
+				data.Donors[i].EmailProblem = problem
+				found = true
+				return nil
+			}
+		}
+		if len(given) > 0 {
+			profile.EmailProblem = problem
+			profile.Updated = time.Now()
+			data.Donors = append(data.Donors, profile)
+			found = true
+		}
+		return nil
+	})
+	contact := bounceContact{Name: profile.Name, Phone: profile.Phone}
+	if len(given) > 0 {
+		contact.Team = given[len(given)-1].Team
+		if contact.Phone == "" {
+			contact.Phone = given[len(given)-1].Phone
+		}
+	}
+	return contact, found, err
+}
+
+func teamEmailAddress(team string) string {
+	switch team {
+	case FTCPathfinders13497:
+		return Email13497
+	case FLLPhoenixVoyagers7885:
+		return Email7885
+	}
+	return EmailFinance
+}
+
+func sendBounceFollowUp(event mailEvent, donor bounceContact) error {
+	what := "An email"
+	if event.Kind != "" {
+		what = "Their " + event.Kind + " email"
+	}
+	detail := "bounced"
+	if event.Diagnostic != "" {
+		detail += " (" + event.Diagnostic + ")"
+	}
+	ask := "Please call them to confirm their email address, then let finance know so the address can be corrected and the email sent again."
+	subject := "Follow up with donor " + donor.Name + ": email bounced"
+	if event.Type == MailEventComplaint {
+		detail = "was reported as spam by the donor"
+		ask = "Please don't email them again for now. If you need to reach them, call instead."
+		subject = "Follow up with donor " + donor.Name + ": spam complaint"
+	}
+	phone := donor.Phone
+	if phone == "" {
+		phone = "no phone number on file"
+	}
+	body := "<html><body style=\"font-family: 'Times New Roman', Times, serif;\"><p>" + html.EscapeString(what) + " to " + html.EscapeString(donor.Name) + " &lt;" + html.EscapeString(event.Recipient) + "&gt; " + html.EscapeString(detail) + ".</p>" +
+		"<p><b>Phone:</b> " + html.EscapeString(phone) + "</p><p>" + ask + "</p></body></html>"
+	return queueHTMLMail(MailAccountWebServer, "bounce-followup", "donor:"+event.Recipient, []string{teamEmailAddress(donor.Team), EmailFinance}, subject, body)
+}
+
+func startBounceWorker(configs *liveConfig) {
+	go func() {
+		ticker := time.NewTicker(bouncePollInterval)
+		defer ticker.Stop()
+		for {
+			cfg := configs.current()
+			err := pollBounceMailbox(cfg.Bounces, cfg.mailTimeout())
+			if err != nil {
+				fmt.Println(err)
+				fmt.Println("ERROR: THE BOUNCE MAILBOX COULD NOT BE CHECKED")
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// Processes every unseen message in the bounce mailbox and marks it seen.
+// A message that fails to process is left unseen and tried again on the next poll.
+func pollBounceMailbox(settings BounceConfig, timeout time.Duration) error {
+	client, err := dialIMAP(settings.IMAPAddress, settings.IMAPTLS, timeout)
+	if err != nil {
+		return err
+	}
+	defer client.logout()
+	err = client.login(settings.IMAPUsername, settings.IMAPPassword)
+	if err != nil {
+		return err
+	}
+	err = client.selectMailbox(settings.IMAPMailbox)
+	if err != nil {
+		return err
+	}
+	uids, err := client.searchUnseen()
+	if err != nil {
+		return err
+	}
+	for _, uid := range uids {
+		client.conn.SetDeadline(time.Now().Add(timeout))
+		raw, err := client.fetch(uid)
+		if err != nil {
+			return err
+		}
+		events, err := parseMailReport(raw)
+		if err != nil {
+			fmt.Println("Bounce mailbox message " + uid + " could not be read: " + err.Error())
+		} else if len(events) > 0 {
+			err = processMailEvents(events, "imap")
+			if err != nil {
+				return err
+			}
+		}
+		err = client.markSeen(uid)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// The provider posts either a raw report with Content-Type message/rfc822, or JSON like
+// {"type": "bounce", "recipient": "donor@example.com", "messageId": "...", "status": "5.1.1", "diagnostic": "..."}
+// (or a list of them). The X-Signature header must be the hex HMAC-SHA256 of the body with 'MAIL_WEBHOOK_SECRET'.
+func registerMailEventWebhook(router *gin.Engine, configs *liveConfig) {
+	router.POST("/mail/events", func(c *gin.Context) {
+		// A reload may have removed the secret, and nothing is signed with an empty one
+		secret := configs.current().Mail.WebhookSecret
+		if secret == "" {
+			c.String(http.StatusUnauthorized, "Invalid signature")
+			return
+		}
+		body, err := ioutil.ReadAll(io.LimitReader(c.Request.Body, 10<<20))
+		if err != nil {
+			c.String(http.StatusBadRequest, "Error")
+			return
+		}
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		signature, err := hex.DecodeString(strings.TrimPrefix(c.GetHeader("X-Signature"), "sha256="))
+		if err != nil || !hmac.Equal(signature, mac.Sum(nil)) {
+			c.String(http.StatusUnauthorized, "Invalid signature")
+			return
+		}
+
+		var events []mailEvent
+		if strings.HasPrefix(c.ContentType(), "message/") {
+			events, err = parseMailReport(body)
+		} else if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+			err = json.Unmarshal(body, &events)
+		} else {
+			var event mailEvent
+			err = json.Unmarshal(body, &event)
+			events = []mailEvent{event}
+		}
+		if err != nil {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		err = processMailEvents(events, "webhook")
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		c.JSON(200, gin.H{"received": len(events)})
+	})
+}
+
+func registerMailEventRoutes(admin *gin.RouterGroup) {
+	// Filter with ?type=bounce, ?type=complaint or ?to=someone@example.com
+	admin.GET("/mail-events", func(c *gin.Context) {
+		var data mailEventData
+		err := dataStore().load(mailEventsDocument, &data)
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		events := []mailEvent{}
+		for _, event := range data.Events {
+			if kind := c.Query("type"); kind != "" && event.Type != kind {
+				continue
+			}
+			if to := donorKey(c.Query("to")); to != "" && event.Recipient != to {
+				continue
+			}
+			events = append(events, event)
+		}
+		sort.Slice(events, func(i, j int) bool { return events[i].Received.After(events[j].Received) })
+		c.JSON(200, events)
+	})
+
+	// Once the team has confirmed the donor's address by phone
+	admin.POST("/mail-events/clear", func(c *gin.Context) {
+		var body struct {
+			Email string `json:"email"`
+		}
+		err := c.BindJSON(&body)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		cleared := false
+		var data donorData
+		err = dataStore().update(donorsDocument, &data, func() error {
+			for i := range data.Donors {
+				if data.Donors[i].Email == donorKey(body.Email) && data.Donors[i].EmailProblem != nil {
+					data.Donors[i].EmailProblem = nil
+					cleared = true
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		if !cleared {
+			c.String(http.StatusNotFound, "That donor's email isn't flagged")
+			return
+		}
+		c.JSON(200, gin.H{"email": donorKey(body.Email), "cleared": true})
+	})
+}
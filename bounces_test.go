@@ -0,0 +1,268 @@
+package main
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const testDSN = "From: MAILER-DAEMON@mx.example.org\r\n" +
+	"To: receipts@example.org\r\n" +
+	"Subject: Undelivered Mail Returned to Sender\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/report; report-type=delivery-status; boundary=\"b1\"\r\n" +
+	"\r\n" +
+	"--b1\r\n" +
+	"Content-Type: text/plain\r\n" +
+	"\r\n" +
+	"Your message could not be delivered.\r\n" +
+	"--b1\r\n" +
+	"Content-Type: message/delivery-status\r\n" +
+	"\r\n" +
+	"Reporting-MTA: dns; mx.example.org\r\n" +
+	"\r\n" +
+	"Final-Recipient: rfc822; Donor@Example.com\r\n" +
+	"Action: failed\r\n" +
+	"Status: 5.1.1\r\n" +
+	"Diagnostic-Code: smtp; 550 5.1.1 No such user\r\n" +
+	"\r\n" +
+	"Original-Recipient: rfc822; slow@example.com\r\n" +
+	"Action: delayed\r\n" +
+	"Status: 4.4.1\r\n" +
+	"\r\n" +
+	"--b1\r\n" +
+	"Content-Type: text/rfc822-headers\r\n" +
+	"\r\n" +
+	"From: receipts@example.org\r\n" +
+	"To: donor@example.com\r\n" +
+	"Message-Id: <receipt-1@example.org>\r\n" +
+	"Subject: Thank you\r\n" +
+	"\r\n" +
+	"--b1--\r\n"
+
+const testARF = "From: abuse@isp.example\r\n" +
+	"To: receipts@example.org\r\n" +
+	"Subject: Abuse report\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/report; report-type=feedback-report; boundary=\"b2\"\r\n" +
+	"\r\n" +
+	"--b2\r\n" +
+	"Content-Type: text/plain\r\n" +
+	"\r\n" +
+	"This is an email abuse report.\r\n" +
+	"--b2\r\n" +
+	"Content-Type: message/feedback-report\r\n" +
+	"\r\n" +
+	"Feedback-Type: abuse\r\n" +
+	"User-Agent: ISP-FBL/1.0\r\n" +
+	"Version: 1\r\n" +
+	"Original-Rcpt-To: <donor@example.com>\r\n" +
+	"\r\n" +
+	"--b2\r\n" +
+	"Content-Type: message/rfc822\r\n" +
+	"\r\n" +
+	"From: receipts@example.org\r\n" +
+	"To: donor@example.com\r\n" +
+	"Message-Id: <receipt-1@example.org>\r\n" +
+	"\r\n" +
+	"Thank you for your gift.\r\n" +
+	"--b2--\r\n"
+
+// Points dataStore at an empty directory for the test, with one gift from donor@example.com
+func useTestStore(t *testing.T) {
+	store, err := newJSONStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous := dataStore()
+	setDataStore(store)
+	t.Cleanup(func() { setDataStore(previous) })
+	var gifts giftData
+	err = dataStore().update(giftsDocument, &gifts, func() error {
+		gifts.Gifts = append(gifts.Gifts, Gift{ID: "g1", DonorEmail: "donor@example.com", DonorName: "Pat Donor", Phone: "555-0100",
+			Amount: 5000, Date: time.Now(), Source: "stripe", Team: FTCPathfinders13497})
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func recordedMailEvents(t *testing.T) []mailEvent {
+	var data mailEventData
+	err := dataStore().load(mailEventsDocument, &data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data.Events
+}
+
+func TestParseMailReportDeliveryStatus(t *testing.T) {
+	events, err := parseMailReport([]byte(testDSN))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected only the failed recipient, got %+v", events)
+	}
+	event := events[0]
+	if event.Type != MailEventBounce || event.Recipient != "donor@example.com" || event.Status != "5.1.1" ||
+		event.Diagnostic != "smtp; 550 5.1.1 No such user" || event.MessageID != "receipt-1@example.org" {
+		t.Errorf("unexpected event: %+v", event)
+	}
+}
+
+func TestParseMailReportFeedback(t *testing.T) {
+	events, err := parseMailReport([]byte(testARF))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected one complaint, got %+v", events)
+	}
+	event := events[0]
+	if event.Type != MailEventComplaint || event.Recipient != "donor@example.com" || event.Diagnostic != "abuse" || event.MessageID != "receipt-1@example.org" {
+		t.Errorf("unexpected event: %+v", event)
+	}
+}
+
+func TestParseMailReportIgnoresOtherMail(t *testing.T) {
+	events, err := parseMailReport([]byte("From: donor@example.com\r\nSubject: Out of office\r\nContent-Type: text/plain\r\n\r\nBack Monday.\r\n"))
+	if err != nil || events != nil {
+		t.Errorf("expected no events and no error, got %+v, %v", events, err)
+	}
+}
+
+func TestMailEventWebhookChecksTheSignature(t *testing.T) {
+	useTestStore(t)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	registerMailEventWebhook(router, newLiveConfig(&Config{Mail: MailConfig{WebhookSecret: "secret"}}))
+
+	body := `{"type": "bounce", "recipient": "Donor@example.com", "messageId": "<receipt-1@example.org>", "status": "5.1.1"}`
+	post := func(signature string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodPost, "/mail/events", strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("X-Signature", signature)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	if response := post("sha256=" + strings.Repeat("0", 64)); response.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for a bad signature, got %d", response.Code)
+	}
+	if events := recordedMailEvents(t); len(events) != 0 {
+		t.Fatalf("an unsigned event was recorded: %+v", events)
+	}
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte(body))
+	if response := post("sha256=" + hex.EncodeToString(mac.Sum(nil))); response.Code != http.StatusOK {
+		t.Fatalf("expected 200 for a good signature, got %d: %s", response.Code, response.Body)
+	}
+	events := recordedMailEvents(t)
+	if len(events) != 1 || events[0].Recipient != "donor@example.com" || events[0].MessageID != "receipt-1@example.org" ||
+		events[0].Source != "webhook" || !events[0].Donor {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+
+	var donors donorData
+	dataStore().load(donorsDocument, &donors)
+	if len(donors.Donors) != 1 || donors.Donors[0].EmailProblem == nil || donors.Donors[0].EmailProblem.Status != "5.1.1" {
+		t.Errorf("expected the donor's record to be flagged, got %+v", donors.Donors)
+	}
+	var outbox outboxData
+	dataStore().load(outboxDocument, &outbox)
+	if len(outbox.Messages) != 2 || outbox.Messages[0].Kind != "bounce-followup" || outbox.Messages[0].To[0] != Email13497 || outbox.Messages[1].To[0] != EmailFinance {
+		t.Errorf("expected a follow-up request to the donor's team with a copy for finance, got %+v", outbox.Messages)
+	}
+}
+
+// Serves one IMAP session with a single unseen message and sends the commands it was given on the channel
+func serveIMAP(t *testing.T, message string) (string, <-chan []string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	sessions := make(chan []string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		var commands []string
+		defer func() { sessions <- commands }()
+		reader := bufio.NewReader(conn)
+		reply := func(lines ...string) {
+			for _, line := range lines {
+				conn.Write([]byte(line + "\r\n"))
+			}
+		}
+		reply("* OK IMAP4rev1 ready")
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			command := strings.TrimRight(line, "\r\n")
+			commands = append(commands, command)
+			parts := strings.SplitN(command, " ", 2)
+			tag, rest := parts[0], ""
+			if len(parts) > 1 {
+				rest = parts[1]
+			}
+			switch {
+			case strings.HasPrefix(rest, "UID SEARCH"):
+				reply("* SEARCH 7", tag+" OK SEARCH completed")
+			case strings.HasPrefix(rest, "UID FETCH 7"):
+				reply("* 1 FETCH (UID 7 BODY[] {" + strconv.Itoa(len(message)) + "}")
+				conn.Write([]byte(message))
+				reply(")", tag+" OK FETCH completed")
+			case rest == "LOGOUT":
+				reply("* BYE", tag+" OK LOGOUT completed")
+				return
+			default:
+				reply(tag + " OK")
+			}
+		}
+	}()
+	return listener.Addr().String(), sessions
+}
+
+func TestPollBounceMailbox(t *testing.T) {
+	useTestStore(t)
+	address, sessions := serveIMAP(t, testDSN)
+	err := pollBounceMailbox(BounceConfig{IMAPAddress: address, IMAPUsername: "bounces", IMAPPassword: "pass\"word", IMAPMailbox: "INBOX"}, 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		`A1 LOGIN "bounces" "pass\"word"`,
+		`A2 SELECT "INBOX"`,
+		`A3 UID SEARCH UNSEEN`,
+		`A4 UID FETCH 7 (BODY.PEEK[])`,
+		`A5 UID STORE 7 +FLAGS.SILENT (\Seen)`,
+		`A6 LOGOUT`,
+	}
+	commands := <-sessions
+	if strings.Join(commands, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected the commands\n%s\ngot\n%s", strings.Join(expected, "\n"), strings.Join(commands, "\n"))
+	}
+	events := recordedMailEvents(t)
+	if len(events) != 1 || events[0].Type != MailEventBounce || events[0].Recipient != "donor@example.com" || events[0].Source != "imap" {
+		t.Errorf("unexpected events: %+v", events)
+	}
+}
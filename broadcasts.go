@@ -0,0 +1,433 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Season update broadcasts, e.g. "we made it to state!" to the donors who supported a team.
+// Recipients are the team's donors, optionally only those who gave in one season and at least a minimum amount,
+// and always only those who agreed to hear from the team (see queueDonorUpdate, which checks again as each
+// email is queued). Emails are queued in batches of 'BROADCAST_EMAILS_PER_MINUTE' (default 30) a minute.
+// Opens are only tracked when the broadcast asks for it and 'BROADCAST_OPEN_TRACKING' is true.
+
+type Broadcast struct {
+	ID          string               `json:"id"`
+	Team        string               `json:"team"`
+	Season      string               `json:"season,omitempty"`      // e.g. "2025-2026", empty for every season
+	MinimumGift int                  `json:"minimumGift,omitempty"` // Cents given to the team in the season (or ever)
+	Subject     string               `json:"subject"`
+	Body        string               `json:"body"` // html/template with .Name, .Team and .Season
+	TrackOpens  bool                 `json:"trackOpens"`
+	Status      string               `json:"status"`
+	Recipients  []broadcastRecipient `json:"recipients,omitempty"`
+	Created     time.Time            `json:"created"`
+	Started     *time.Time           `json:"started,omitempty"`
+	Finished    *time.Time           `json:"finished,omitempty"`
+}
+
+type broadcastRecipient struct {
+	Email  string     `json:"email"`
+	Name   string     `json:"name"`
+	Status string     `json:"status"`
+	Reason string     `json:"reason,omitempty"` // Why a recipient was skipped
+	Opened *time.Time `json:"opened,omitempty"`
+}
+
+type broadcastData struct {
+	Broadcasts []Broadcast `json:"broadcasts"`
+}
+
+// The values a broadcast body can use
+type broadcastFields struct {
+	Name   string
+	Team   string
+	Season string
+}
+
+const broadcastsDocument string = "broadcasts"
+
+const BroadcastDraft string = "draft"
+const BroadcastSending string = "sending"
+const BroadcastSent string = "sent"
+
+const RecipientPending string = "pending"
+const RecipientQueued string = "queued"
+const RecipientSkipped string = "skipped"
+
+// A transparent 1x1 GIF for open tracking
+var trackingPixel = []byte("GIF89a\x01\x00\x01\x00\x80\x00\x00\x00\x00\x00\x00\x00\x00!\xf9\x04\x01\x00\x00\x00\x00,\x00\x00\x00\x00\x01\x00\x01\x00\x00\x02\x02D\x01\x00;")
+
+func programForTeam(team string) string {
+	if team == FLLPhoenixVoyagers7885 {
+		return "FLL"
+	}
+	return "FTC"
+}
+
+func parseBroadcastBody(body string) (*template.Template, error) {
+	tmpl, err := template.New("broadcast").Option("missingkey=error").Parse(body)
+	if err != nil {
+		return nil, err
+	}
+	return tmpl, tmpl.Execute(&bytes.Buffer{}, broadcastFields{Name: "Sample Donor", Team: FTCPathfinders13497, Season: "2025-2026"})
+}
+
+// Everyone who matches the broadcast's team, season and minimum, with those who can't be emailed marked skipped
+func broadcastAudience(broadcast Broadcast) ([]broadcastRecipient, error) {
+	donors, err := giftsByDonor(time.Time{}, time.Now().AddDate(1, 0, 0))
+	if err != nil {
+		return nil, err
+	}
+	recipients := []broadcastRecipient{}
+	for email, gifts := range donors {
+		total, name := 0, ""
+		for _, gift := range gifts {
+			if gift.Team != broadcast.Team {
+				continue
+			}
+			if broadcast.Season != "" && calendar().season(programForTeam(broadcast.Team), gift.Date) != broadcast.Season {
+				continue
+			}
+			total += gift.Amount
+			name = gift.DonorName
+		}
+		if total == 0 || total < broadcast.MinimumGift {
+			continue
+		}
+		recipient := broadcastRecipient{Email: email, Name: donorProfile(email, gifts).Name, Status: RecipientPending}
+		if recipient.Name == "" {
+			recipient.Name = name
+		}
+		allowed, err := contactAllowed(email, broadcast.Team)
+		if err != nil {
+			return nil, err
+		}
+		flagged, err := donorEmailFlagged(email)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			recipient.Status, recipient.Reason = RecipientSkipped, "no consent to updates from "+broadcast.Team
+		} else if flagged {
+			recipient.Status, recipient.Reason = RecipientSkipped, "email bouncing or marked as spam"
+		}
+		recipients = append(recipients, recipient)
+	}
+	sort.Slice(recipients, func(i, j int) bool { return recipients[i].Email < recipients[j].Email })
+	return recipients, nil
+}
+
+func findBroadcast(id string) (Broadcast, bool, error) {
+	var data broadcastData
+	err := dataStore().load(broadcastsDocument, &data)
+	if err != nil {
+		return Broadcast{}, false, err
+	}
+	for _, broadcast := range data.Broadcasts {
+		if broadcast.ID == id {
+			return broadcast, true, nil
+		}
+	}
+	return Broadcast{}, false, nil
+}
+
+// Picks up broadcasts that were still sending when the server stopped
+func resumeBroadcasts(configs *liveConfig) {
+	var data broadcastData
+	err := dataStore().load(broadcastsDocument, &data)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for _, broadcast := range data.Broadcasts {
+		if broadcast.Status == BroadcastSending {
+			go runBroadcast(configs, broadcast.ID)
+		}
+	}
+}
+
+// Queues a batch of pending recipients each minute until none are left. Each batch uses the rate in effect then.
+func runBroadcast(configs *liveConfig, id string) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		cfg := configs.current()
+		more, err := sendBroadcastBatch(id, cfg.Mail.BroadcastsPerMinute, cfg.Mail.BroadcastOpenTracking)
+		if err != nil {
+			fmt.Println(err)
+			fmt.Println("ERROR: BROADCAST " + id + " STOPPED, IT WILL CONTINUE WHEN THE SERVER RESTARTS")
+			return
+		}
+		if !more {
+			return
+		}
+		<-ticker.C
+	}
+}
+
+// Queues up to size pending recipients and reports whether any are left
+func sendBroadcastBatch(id string, size int, trackOpens bool) (bool, error) {
+	broadcast, found, err := findBroadcast(id)
+	if err != nil || !found {
+		return false, err
+	}
+	tmpl, err := parseBroadcastBody(broadcast.Body)
+	if err != nil {
+		return false, err
+	}
+	results := map[string]broadcastRecipient{}
+	for _, recipient := range broadcast.Recipients {
+		if len(results) == size {
+			break
+		}
+		if recipient.Status != RecipientPending {
+			continue
+		}
+		var body bytes.Buffer
+		err = tmpl.Execute(&body, broadcastFields{Name: recipient.Name, Team: broadcast.Team, Season: broadcast.Season})
+		if err != nil {
+			return false, err
+		}
+		html := body.String()
+		if broadcast.TrackOpens && trackOpens {
+			token, err := signToken("open|" + broadcast.ID + "|" + recipient.Email)
+			if err != nil {
+				return false, err
+			}
+			html += "<img src=\"" + SiteURL + "/broadcasts/open?token=" + url.QueryEscape(token) + "\" width=\"1\" height=\"1\" alt=\"\" />"
+		}
+		queued, err := queueDonorUpdate(recipient.Email, broadcast.Team, "broadcast", "broadcast:"+broadcast.ID, broadcast.Subject, html)
+		if err != nil {
+			return false, err
+		}
+		recipient.Status = RecipientQueued
+		if !queued {
+			// Consent was withdrawn or the address started bouncing after the broadcast was started
+			recipient.Status, recipient.Reason = RecipientSkipped, "no consent or email bouncing when queued"
+		}
+		results[recipient.Email] = recipient
+	}
+
+	more := false
+	var data broadcastData
+	err = dataStore().update(broadcastsDocument, &data, func() error {
+		for i := range data.Broadcasts {
+			if data.Broadcasts[i].ID != id {
+				continue
+			}
+			current := &data.Broadcasts[i]
+			for j := range current.Recipients {
+				if result, done := results[current.Recipients[j].Email]; done {
+					current.Recipients[j] = result
+				}
+				if current.Recipients[j].Status == RecipientPending {
+					more = true
+				}
+			}
+			if !more {
+				now := time.Now()
+				current.Status = BroadcastSent
+				current.Finished = &now
+			}
+		}
+		return nil
+	})
+	return more, err
+}
+
+func registerBroadcastRoutes(admin *gin.RouterGroup, configs *liveConfig) {
+	admin.POST("/broadcasts", func(c *gin.Context) {
+		var broadcast Broadcast
+		err := c.BindJSON(&broadcast)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		if !containsTeam(updateTeams, broadcast.Team) {
+			c.String(http.StatusBadRequest, "team must be one of: "+strings.Join(updateTeams, ", "))
+			return
+		}
+		if strings.TrimSpace(broadcast.Subject) == "" {
+			c.String(http.StatusBadRequest, "A broadcast needs a subject")
+			return
+		}
+		_, err = parseBroadcastBody(broadcast.Body)
+		if err != nil {
+			c.String(http.StatusBadRequest, "The body template could not be rendered: "+err.Error())
+			return
+		}
+		if broadcast.TrackOpens && !configs.current().Mail.BroadcastOpenTracking {
+			c.String(http.StatusBadRequest, "Open tracking is turned off by the 'BROADCAST_OPEN_TRACKING' setting")
+			return
+		}
+		broadcast.ID = newID()
+		broadcast.Status = BroadcastDraft
+		broadcast.Recipients = nil
+		broadcast.Created = time.Now()
+		broadcast.Started, broadcast.Finished = nil, nil
+		var data broadcastData
+		err = dataStore().update(broadcastsDocument, &data, func() error {
+			data.Broadcasts = append(data.Broadcasts, broadcast)
+			return nil
+		})
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		c.JSON(200, broadcast)
+	})
+
+	admin.GET("/broadcasts", func(c *gin.Context) {
+		var data broadcastData
+		err := dataStore().load(broadcastsDocument, &data)
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		summaries := []gin.H{}
+		for _, broadcast := range data.Broadcasts {
+			summaries = append(summaries, broadcastSummary(broadcast))
+		}
+		c.JSON(200, summaries)
+	})
+
+	admin.GET("/broadcasts/:id", func(c *gin.Context) {
+		broadcast, found, err := findBroadcast(c.Param("id"))
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		if !found {
+			c.String(http.StatusNotFound, "No such broadcast")
+			return
+		}
+		summary := broadcastSummary(broadcast)
+		summary["broadcast"] = broadcast
+		c.JSON(200, summary)
+	})
+
+	// Who a draft would go to, and who would be skipped and why
+	admin.GET("/broadcasts/:id/audience", func(c *gin.Context) {
+		broadcast, found, err := findBroadcast(c.Param("id"))
+		if err == nil && found {
+			var audience []broadcastRecipient
+			audience, err = broadcastAudience(broadcast)
+			if err == nil {
+				c.JSON(200, audience)
+				return
+			}
+		}
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		c.String(http.StatusNotFound, "No such broadcast")
+	})
+
+	admin.POST("/broadcasts/:id/send", func(c *gin.Context) {
+		broadcast, found, err := findBroadcast(c.Param("id"))
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		if !found || broadcast.Status != BroadcastDraft {
+			c.String(http.StatusNotFound, "No draft broadcast with that ID")
+			return
+		}
+		audience, err := broadcastAudience(broadcast)
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		started := false
+		var data broadcastData
+		err = dataStore().update(broadcastsDocument, &data, func() error {
+			for i := range data.Broadcasts {
+				if data.Broadcasts[i].ID == broadcast.ID && data.Broadcasts[i].Status == BroadcastDraft {
+					now := time.Now()
+					data.Broadcasts[i].Status = BroadcastSending
+					data.Broadcasts[i].Recipients = audience
+					data.Broadcasts[i].Started = &now
+					started = true
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		if !started {
+			c.String(http.StatusConflict, "The broadcast was already started")
+			return
+		}
+		go runBroadcast(configs, broadcast.ID)
+		broadcast.Recipients = audience
+		c.JSON(200, broadcastSummary(broadcast))
+	})
+}
+
+func broadcastSummary(broadcast Broadcast) gin.H {
+	counts := map[string]int{}
+	opened := 0
+	for _, recipient := range broadcast.Recipients {
+		counts[recipient.Status]++
+		if recipient.Opened != nil {
+			opened++
+		}
+	}
+	summary := gin.H{"id": broadcast.ID, "team": broadcast.Team, "season": broadcast.Season, "subject": broadcast.Subject, "status": broadcast.Status, "created": broadcast.Created,
+		"pending": counts[RecipientPending], "queued": counts[RecipientQueued], "skipped": counts[RecipientSkipped]}
+	if broadcast.TrackOpens {
+		summary["opened"] = opened
+	}
+	return summary
+}
+
+// The open tracking pixel. Only the first open is recorded. Only registered when 'BROADCAST_OPEN_TRACKING' is true.
+func registerBroadcastOpenRoute(router *gin.Engine) {
+	router.GET("/broadcasts/open", func(c *gin.Context) {
+		c.Header("Cache-Control", "no-store")
+		c.Data(200, "image/gif", trackingPixel)
+		payload, ok := verifyToken(c.Query("token"))
+		parts := strings.SplitN(payload, "|", 3)
+		if !ok || len(parts) != 3 || parts[0] != "open" {
+			return
+		}
+		var data broadcastData
+		err := dataStore().update(broadcastsDocument, &data, func() error {
+			for i := range data.Broadcasts {
+				if data.Broadcasts[i].ID != parts[1] || !data.Broadcasts[i].TrackOpens {
+					continue
+				}
+				for j := range data.Broadcasts[i].Recipients {
+					recipient := &data.Broadcasts[i].Recipients[j]
+					if recipient.Email == parts[2] && recipient.Opened == nil {
+						now := time.Now()
+						recipient.Opened = &now
+					}
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			fmt.Println(err)
+		}
+	})
+}
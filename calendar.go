@@ -0,0 +1,121 @@
+package main
+
+import (
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// The organization calendar: the timezone every date is shown in, when the fiscal year starts,
+// and when each FIRST program's season starts. Receipts, statements, reports and dues all go through it
+// so a gift made late on December 31st in Iowa lands in the right year everywhere.
+//
+// Configured with 'TIMEZONE' (an IANA name, default America/Chicago), 'FISCAL_YEAR_START' (month number, default 1),
+// and 'FTC_SEASON_START' / 'FLL_SEASON_START' (month numbers, default 5). The configuration checks them at startup.
+
+type orgCalendar struct {
+	location        *time.Location
+	fiscalYearStart time.Month
+	seasonStarts    map[string]time.Month // By program, "FTC" or "FLL"
+	clock           func() time.Time      // time.Now, except in tests
+}
+
+const defaultTimezone string = "America/Chicago"
+const defaultSeasonStart time.Month = time.May
+
+var defaultCalendar = newOrgCalendar(CalendarConfig{Timezone: defaultTimezone, FiscalYearStart: int(time.January), FTCSeasonStart: int(defaultSeasonStart), FLLSeasonStart: int(defaultSeasonStart)})
+
+// Replaced with the configured calendar by Config.apply
+var currentCalendar atomic.Value // *orgCalendar
+
+// The configured calendar, or the default one before the configuration is applied
+func calendar() *orgCalendar {
+	if cal, ok := currentCalendar.Load().(*orgCalendar); ok {
+		return cal
+	}
+	return defaultCalendar
+}
+
+func newOrgCalendar(settings CalendarConfig) *orgCalendar {
+	location, err := time.LoadLocation(settings.Timezone)
+	if err != nil {
+		location = time.FixedZone("CST", -6*60*60)
+	}
+	return &orgCalendar{
+		location:        location,
+		fiscalYearStart: time.Month(settings.FiscalYearStart),
+		clock:           time.Now,
+		seasonStarts: map[string]time.Month{
+			"FTC": time.Month(settings.FTCSeasonStart),
+			"FLL": time.Month(settings.FLLSeasonStart),
+		},
+	}
+}
+
+func (cal *orgCalendar) now() time.Time {
+	return cal.clock().In(cal.location)
+}
+
+// The date as printed on receipts and statements, e.g. "March 4, 2026"
+func (cal *orgCalendar) formatDate(t time.Time) string {
+	return t.In(cal.location).Format("January 2, 2006")
+}
+
+func (cal *orgCalendar) calendarYear(year int) (time.Time, time.Time) {
+	return time.Date(year, time.January, 1, 0, 0, 0, 0, cal.location), time.Date(year+1, time.January, 1, 0, 0, 0, 0, cal.location)
+}
+
+// A fiscal year is named for the calendar year it ends in, so with a July start FY2026 runs July 2025 through June 2026
+func (cal *orgCalendar) fiscalYear(year int) (time.Time, time.Time) {
+	if cal.fiscalYearStart == time.January {
+		return cal.calendarYear(year)
+	}
+	return time.Date(year-1, cal.fiscalYearStart, 1, 0, 0, 0, 0, cal.location), time.Date(year, cal.fiscalYearStart, 1, 0, 0, 0, 0, cal.location)
+}
+
+func (cal *orgCalendar) fiscalYearOf(t time.Time) int {
+	t = t.In(cal.location)
+	if cal.fiscalYearStart == time.January || t.Month() < cal.fiscalYearStart {
+		return t.Year()
+	}
+	return t.Year() + 1
+}
+
+// The season label for a program at a time, e.g. "2025-2026". Unknown programs use the default season start.
+func (cal *orgCalendar) season(program string, t time.Time) string {
+	start, found := cal.seasonStarts[program]
+	if !found {
+		start = defaultSeasonStart
+	}
+	t = t.In(cal.location)
+	year := t.Year()
+	if t.Month() < start {
+		year--
+	}
+	if start == time.January {
+		return strconv.Itoa(year)
+	}
+	return strconv.Itoa(year) + "-" + strconv.Itoa(year+1)
+}
+
+// When the program's season that t falls in ends, which is when the next one starts
+func (cal *orgCalendar) seasonEnd(program string, t time.Time) time.Time {
+	start, found := cal.seasonStarts[program]
+	if !found {
+		start = defaultSeasonStart
+	}
+	t = t.In(cal.location)
+	year := t.Year()
+	if t.Month() >= start {
+		year++
+	}
+	return time.Date(year, start, 1, 0, 0, 0, 0, cal.location)
+}
+
+// The program a receipt's FIRST suffix belongs to
+func programForSuffix(firstSuffix string) string {
+	if firstSuffix == FLLSuffix {
+		return "FLL"
+	}
+	return "FTC"
+}
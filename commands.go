@@ -0,0 +1,334 @@
+package main
+
+import (
+	"crypto"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Command line subcommands, for jobs finance runs by hand (e.g. `heroku run org.pathfindersrobotics.server statements 2026`).
+// Running the binary with no arguments starts the web server as before.
+func runCommand(args []string) int {
+	switch args[0] {
+	case "statements":
+		return statementsCommand(args[1:])
+	case "preview":
+		return previewCommand(args[1:])
+	case "dkim-check":
+		return dkimCheckCommand(args[1:])
+	case "config":
+		return configCommand(args[1:])
+	default:
+		fmt.Println("Unknown command '" + args[0] + "'. Available commands:")
+		fmt.Println("  statements [-send] [-donor email] [-server URL] <year>")
+		fmt.Println("                                             Year-end giving statements, queued by the server with -send")
+		fmt.Println("  preview <template> [-data file.json] [-format all|html|text|headers] [-send address]")
+		fmt.Println("                                             Show an email without sending it, or send it to a test address")
+		fmt.Println("  dkim-check [-account receipts|webserver] [-dns=false]")
+		fmt.Println("                                             Sign a sample message and verify it against the keys and DNS")
+		fmt.Println("  config check [-json]                       Report every setting as set, missing or invalid, with secrets redacted")
+		return 2
+	}
+}
+
+func statementsCommand(args []string) int {
+	flags := flag.NewFlagSet("statements", flag.ContinueOnError)
+	send := flags.Bool("send", false, "ask the web server to queue the statements in its outbox, instead of only listing them")
+	donor := flags.String("donor", "", "only this donor's statement, even if it was already sent")
+	server := flags.String("server", SiteURL, "the web server to ask, with -send")
+	err := flags.Parse(args)
+	if err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		fmt.Println("Usage: statements [-send] [-donor email] [-server URL] <year>")
+		return 2
+	}
+	year, err := strconv.Atoi(flags.Arg(0))
+	if err != nil {
+		fmt.Println("'" + flags.Arg(0) + "' is not a year")
+		return 2
+	}
+
+	cfg, err := setupCommandEnvironment()
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+	if *send {
+		err = requestStatements(cfg, *server, year, *donor)
+	} else {
+		err = runStatements(cfg, year, *donor, false, os.Stdout)
+	}
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+	return 0
+}
+
+// Has the running server queue the statements, so they go into the outbox it sends from
+func requestStatements(cfg *Config, server string, year int, donor string) error {
+	if cfg.Server.AdminUsername == "" {
+		return errors.New("'ADMIN_USERNAME' and 'ADMIN_PASSWORD' are needed to ask the server to send statements")
+	}
+	request, err := http.NewRequest(http.MethodPost, strings.TrimSuffix(server, "/")+"/admin/statements/"+strconv.Itoa(year)+"?donor="+url.QueryEscape(donor), nil)
+	if err != nil {
+		return err
+	}
+	request.SetBasicAuth(cfg.Server.AdminUsername, cfg.Server.AdminPassword)
+	client := &http.Client{Timeout: cfg.mailTimeout()}
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode != http.StatusAccepted {
+		return errors.New(server + " answered " + response.Status + ": " + string(body))
+	}
+	fmt.Println(string(body))
+	return nil
+}
+
+func previewCommand(args []string) int {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		fmt.Println("Usage: preview <" + strings.Join(previewTemplateNames, "|") + "> [-data file.json] [-format all|html|text|headers] [-send address]")
+		return 2
+	}
+	name := args[0]
+	flags := flag.NewFlagSet("preview", flag.ContinueOnError)
+	dataFile := flags.String("data", "", "PaymentData JSON to render instead of the sample donation")
+	format := flags.String("format", "all", "all, html, text or headers")
+	send := flags.String("send", "", "send the email to this test address only")
+	err := flags.Parse(args[1:])
+	if err != nil {
+		return 2
+	}
+
+	var data PaymentData
+	if *dataFile != "" {
+		source, err := ioutil.ReadFile(*dataFile)
+		if err == nil {
+			err = json.Unmarshal(source, &data)
+		}
+		if err != nil {
+			fmt.Println(err)
+			return 1
+		}
+	}
+	cfg, err := setupCommandEnvironment()
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+
+	if *send != "" {
+		err = sendPreview(cfg, name, data, *send)
+		if err != nil {
+			fmt.Println(err)
+			return 1
+		}
+		fmt.Println("Sent a test " + name + " to " + *send)
+		return 0
+	}
+	preview, err := renderPreview(cfg, name, data)
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+	if *format == "all" || *format == "headers" {
+		fmt.Println("Account: " + preview.Account)
+		fmt.Println("Envelope: " + strings.Join(preview.Envelope, ", "))
+		var keys []string
+		for key := range preview.Headers {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			for _, value := range preview.Headers[key] {
+				fmt.Println(key + ": " + value)
+			}
+		}
+	}
+	if (*format == "all" || *format == "html") && preview.HTML != "" {
+		if *format == "all" {
+			fmt.Println("\n----- HTML -----")
+		}
+		fmt.Println(preview.HTML)
+	}
+	if *format == "all" || *format == "text" {
+		if *format == "all" {
+			fmt.Println("\n----- Text -----")
+		}
+		fmt.Println(preview.Text)
+	}
+	return 0
+}
+
+func dkimCheckCommand(args []string) int {
+	flags := flag.NewFlagSet("dkim-check", flag.ContinueOnError)
+	only := flags.String("account", "", "only check this mailbox, receipts or webserver")
+	checkDNS := flags.Bool("dns", true, "also verify against the public keys published in DNS")
+	err := flags.Parse(args)
+	if err != nil {
+		return 2
+	}
+	accounts := []string{MailAccountReceipts, MailAccountWebServer}
+	if *only != "" {
+		if _, known := dkimAccountVars[*only]; !known {
+			fmt.Println("Unknown mailbox '" + *only + "', expected receipts or webserver")
+			return 2
+		}
+		accounts = []string{*only}
+	}
+
+	cfg, err := setupCommandEnvironment()
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+	status := 0
+	for _, account := range accounts {
+		err = dkimCheck(cfg, account, *checkDNS, *only != "")
+		if err != nil {
+			fmt.Println(account + ": " + err.Error())
+			status = 1
+		}
+	}
+	return status
+}
+
+// Signs a sample message as the mailbox would and verifies it, first with the configured keys and then with DNS
+func dkimCheck(cfg *Config, account string, checkDNS bool, required bool) error {
+	settings, err := cfg.mailAccountSettings(account)
+	if err != nil {
+		return err
+	}
+	keys, err := dkimKeys(settings)
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		if required {
+			return errors.New("DKIM is not configured for this mailbox")
+		}
+		fmt.Println(account + ": DKIM is not configured, messages are sent unsigned")
+		return nil
+	}
+	domain := addressDomain(settings.Username)
+	sample := &mailMessage{From: settings.Username, FromName: "Pathfinders Robotics", To: []string{settings.Username}, Subject: "DKIM self-check", Text: "A sample message for checking DKIM signatures.\n"}
+	raw, err := sample.bytes()
+	if err == nil {
+		raw, err = dkimSign(raw, domain, keys, time.Now())
+	}
+	if err != nil {
+		return err
+	}
+
+	configured := map[string]crypto.PublicKey{}
+	for _, key := range keys {
+		configured[key.selector] = key.signer.Public()
+		record, err := dkimDNSRecord(key.signer.Public())
+		if err != nil {
+			return err
+		}
+		fmt.Println(account + ": " + key.selector + "._domainkey." + domain + " should have the TXT record \"" + record + "\"")
+	}
+	err = dkimVerify(raw, func(selector string, _ string) (crypto.PublicKey, error) { return configured[selector], nil })
+	if err != nil {
+		return err
+	}
+	fmt.Println(account + ": the signed sample verifies with the configured keys")
+	if !checkDNS {
+		return nil
+	}
+	err = dkimVerify(raw, dkimLookupDNS)
+	if err != nil {
+		return err
+	}
+	fmt.Println(account + ": the signed sample verifies with the keys published in DNS")
+	return nil
+}
+
+// Reports the configuration even when it has problems, since that is when it's needed. Exits with 1 if it has any.
+func configCommand(args []string) int {
+	if len(args) == 0 || args[0] != "check" {
+		fmt.Println("Usage: config check [-json]")
+		return 2
+	}
+	flags := flag.NewFlagSet("config check", flag.ContinueOnError)
+	asJSON := flags.Bool("json", false, "print the report as JSON, as /admin/config does")
+	err := flags.Parse(args[1:])
+	if err != nil {
+		return 2
+	}
+
+	cfg, _ := loadConfig()
+	report := cfg.report()
+	if *asJSON {
+		encoded, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			fmt.Println(err)
+			return 1
+		}
+		fmt.Println(string(encoded))
+	} else {
+		table := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(table, "STATUS\tSETTING\tFILE KEY\tVALUE\tSOURCE")
+		for _, setting := range report.Settings {
+			fmt.Fprintln(table, strings.ToUpper(setting.Status)+"\t"+setting.Env+"\t"+setting.Key+"\t"+setting.Value+"\t"+setting.Source)
+		}
+		table.Flush()
+		for _, problem := range report.Problems {
+			fmt.Println("PROBLEM: " + problem)
+		}
+		for _, warning := range report.Warnings {
+			fmt.Println("WARNING: " + warning)
+		}
+		fmt.Println(strconv.Itoa(len(report.Problems)) + " problem(s), " + strconv.Itoa(len(report.Warnings)) + " warning(s)")
+	}
+	if len(report.Problems) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// Commands use the same configuration as the server, and refuse to run while it has problems
+func setupCommandEnvironment() (*Config, error) {
+	cfg, problems := loadConfig()
+	if len(problems) > 0 {
+		for _, problem := range problems {
+			fmt.Println(problem)
+		}
+		return nil, errors.New("The configuration has " + strconv.Itoa(len(problems)) + " problem(s), listed above")
+	}
+	cfg.apply()
+
+	store, err := openDataStore(cfg)
+	if err != nil {
+		return nil, err
+	}
+	setDataStore(store)
+
+	templates, err := loadEmailTemplates(cfg.Server.TemplatesDir)
+	if err != nil {
+		return nil, err
+	}
+	setEmailTemplates(templates)
+	return cfg, nil
+}
@@ -0,0 +1,668 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/signal"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gopkg.in/yaml.v2"
+)
+
+// Server configuration, read at startup. Each setting has a default, can be set in an optional YAML file
+// named by 'CONFIG_FILE' (default ./config.yml when it exists), and is overridden by its environment variable,
+// so Heroku config vars always win. Every problem is reported at once before the server starts, instead of
+// one at a time when a donor pays.
+//
+// The env tag is the environment variable, and the yaml tag the key within its section of the file, e.g.
+//
+//	mail:
+//	  transport: file
+//	  dir: ./maildir
+//
+// Any setting can instead be read from a file named by the environment variable with _FILE added, e.g.
+// 'STRIPE_LIVE_KEY_FILE=/run/secrets/stripe', for secret stores mounted as files. Settings tagged secret are
+// never printed.
+//
+// SIGHUP or POST /admin/config/reload reads the file and secret files again, see liveConfig. Settings tagged
+// restart, such as the routes that are enabled, are wired in at startup and keep their values until a restart.
+
+type Config struct {
+	Server        ServerConfig       `yaml:"server" restart:"true"`
+	Features      FeatureConfig      `yaml:"features" restart:"true"`
+	Stripe        StripeConfig       `yaml:"stripe"`
+	Mail          MailConfig         `yaml:"mail"`
+	Bounces       BounceConfig       `yaml:"bounces"`
+	Notifications NotificationConfig `yaml:"notifications"`
+	Organization  OrganizationConfig `yaml:"organization"`
+	Calendar      CalendarConfig     `yaml:"calendar" restart:"true"`
+
+	sources  map[string]string // Where each environment variable's value came from, see configSource
+	unparsed map[string]string // Environment variables that couldn't be read as their setting's type
+	problems []configProblem
+}
+
+type ServerConfig struct {
+	Port            string `env:"PORT" yaml:"port"`
+	GinMode         string `env:"GIN_MODE" yaml:"ginMode"`
+	GzipCompression string `env:"GZIP_COMPRESSION_LVL" yaml:"gzipCompression" default:"DefaultCompression"`
+	TemplatesDir    string `env:"TEMPLATES_DIR" yaml:"templatesDir" default:"./templates"`
+	DataDir         string `env:"DATA_DIR" yaml:"dataDir" default:"./data"`
+	DatabaseURL     string `env:"DATABASE_URL" yaml:"databaseURL" secret:"true"`
+	SigningSecret   string `env:"SIGNING_SECRET" yaml:"signingSecret" secret:"true"`
+	AdminUsername   string `env:"ADMIN_USERNAME" yaml:"adminUsername"`
+	AdminPassword   string `env:"ADMIN_PASSWORD" yaml:"adminPassword" secret:"true"`
+	BoardUsername   string `env:"BOARD_USERNAME" yaml:"boardUsername"`
+	BoardPassword   string `env:"BOARD_PASSWORD" yaml:"boardPassword" secret:"true"`
+	// For ticket check-in and merchandise pick up, so door volunteers don't need the admin password
+	VolunteerUsername string `env:"VOLUNTEER_USERNAME" yaml:"volunteerUsername"`
+	VolunteerPassword string `env:"VOLUNTEER_PASSWORD" yaml:"volunteerPassword" secret:"true"`
+}
+
+type FeatureConfig struct {
+	Ping              bool `env:"PING_FUNCTIONALITY" yaml:"ping"`
+	ServingSite       bool `env:"SERVING_SITE" yaml:"servingSite"`
+	PaymentEmails     bool `env:"EMAIL_PAYMENT_NOTIFICATIONS" yaml:"paymentEmails"`
+	YearEndStatements bool `env:"YEAR_END_STATEMENTS" yaml:"yearEndStatements"`
+	MerchandiseStore  bool `env:"MERCHANDISE_STORE" yaml:"merchandiseStore"`
+	SeasonDues        bool `env:"SEASON_DUES" yaml:"seasonDues"`
+	TicketedEvents    bool `env:"TICKETED_EVENTS" yaml:"ticketedEvents"`
+	DonorPortal       bool `env:"DONOR_PORTAL" yaml:"donorPortal"`
+}
+
+// Stripe is turned off entirely while Live is unset
+type StripeConfig struct {
+	Live     *bool  `env:"STRIPE_LIVE" yaml:"live" restart:"true"`
+	LiveKey  string `env:"STRIPE_LIVE_KEY" yaml:"liveKey" secret:"true"`
+	DebugKey string `env:"STRIPE_DEBUG_KEY" yaml:"debugKey" secret:"true"`
+	// For Stripe.js on the pages the server serves itself, such as the dues page
+	LivePublishableKey  string `env:"STRIPE_LIVE_PUBLISHABLE_KEY" yaml:"livePublishableKey"`
+	DebugPublishableKey string `env:"STRIPE_DEBUG_PUBLISHABLE_KEY" yaml:"debugPublishableKey"`
+}
+
+type MailConfig struct {
+	Transport             string `env:"MAIL_TRANSPORT" yaml:"transport" default:"smtp"`
+	SMTPServerAddress     string `env:"SmtpServerAddress" yaml:"smtpServerAddress"`
+	SMTPServerPort        string `env:"SmtpServerPort" yaml:"smtpServerPort"`
+	SMTPTLS               string `env:"SMTP_TLS" yaml:"smtpTLS"`
+	TimeoutSeconds        int    `env:"SMTP_TIMEOUT" yaml:"timeoutSeconds" default:"30"`
+	APIURL                string `env:"MAIL_API_URL" yaml:"apiURL"`
+	APIKey                string `env:"MAIL_API_KEY" yaml:"apiKey" secret:"true"`
+	Dir                   string `env:"MAIL_DIR" yaml:"dir" default:"./maildir"`
+	WebServerUsername     string `env:"WEBSERVER_EMAIL_USERNAME" yaml:"webServerUsername"`
+	WebServerPassword     string `env:"WEBSERVER_EMAIL_PASSWORD" yaml:"webServerPassword" secret:"true"`
+	ReceiptsUsername      string `env:"DONATION_RECEIPTS_EMAIL_USERNAME" yaml:"receiptsUsername"`
+	ReceiptsPassword      string `env:"DONATION_RECEIPTS_EMAIL_PASSWORD" yaml:"receiptsPassword" secret:"true"`
+	DKIMWebServerSelector string `env:"DKIM_WEBSERVER_SELECTOR" yaml:"dkimWebServerSelector"`
+	DKIMWebServerKey      string `env:"DKIM_WEBSERVER_KEY" yaml:"dkimWebServerKey" secret:"true"`
+	DKIMReceiptsSelector  string `env:"DKIM_RECEIPTS_SELECTOR" yaml:"dkimReceiptsSelector"`
+	DKIMReceiptsKey       string `env:"DKIM_RECEIPTS_KEY" yaml:"dkimReceiptsKey" secret:"true"`
+	WebhookSecret         string `env:"MAIL_WEBHOOK_SECRET" yaml:"webhookSecret" secret:"true"`
+	BroadcastsPerMinute   int    `env:"BROADCAST_EMAILS_PER_MINUTE" yaml:"broadcastsPerMinute" default:"30"`
+	BroadcastOpenTracking bool   `env:"BROADCAST_OPEN_TRACKING" yaml:"broadcastOpenTracking" restart:"true"`
+	StatementsPerMinute   int    `env:"STATEMENT_EMAILS_PER_MINUTE" yaml:"statementsPerMinute" default:"20"`
+}
+
+type BounceConfig struct {
+	IMAPAddress  string `env:"BOUNCE_IMAP_ADDRESS" yaml:"imapAddress" restart:"true"`
+	IMAPUsername string `env:"BOUNCE_IMAP_USERNAME" yaml:"imapUsername"`
+	IMAPPassword string `env:"BOUNCE_IMAP_PASSWORD" yaml:"imapPassword" secret:"true"`
+	IMAPMailbox  string `env:"BOUNCE_IMAP_MAILBOX" yaml:"imapMailbox" default:"INBOX"`
+	IMAPTLS      bool   `env:"BOUNCE_IMAP_TLS" yaml:"imapTLS" default:"true"`
+}
+
+type NotificationConfig struct {
+	Digests      string `env:"NOTIFICATION_DIGESTS" yaml:"digests"`
+	AlertDollars int    `env:"NOTIFICATION_ALERT_DOLLARS" yaml:"alertDollars" default:"1000"`
+}
+
+// Used for receipts until an organization profile is saved at /admin/organization
+type OrganizationConfig struct {
+	Addr1 string `env:"PRAddr1" yaml:"addr1"`
+	City  string `env:"PRCity" yaml:"city"`
+	State string `env:"PRState" yaml:"state"`
+	Zip   string `env:"PRZip" yaml:"zip"`
+	Phone string `env:"PRPhone" yaml:"phone"`
+	EIN   string `env:"EIN" yaml:"ein"`
+}
+
+type CalendarConfig struct {
+	Timezone        string `env:"TIMEZONE" yaml:"timezone" default:"America/Chicago"`
+	FiscalYearStart int    `env:"FISCAL_YEAR_START" yaml:"fiscalYearStart" default:"1"`
+	FTCSeasonStart  int    `env:"FTC_SEASON_START" yaml:"ftcSeasonStart" default:"5"`
+	FLLSeasonStart  int    `env:"FLL_SEASON_START" yaml:"fllSeasonStart" default:"5"`
+}
+
+const defaultConfigFile string = "config.yml"
+
+const ConfigSourceDefault string = "default"
+const ConfigSourceFile string = "file"
+const ConfigSourceEnvironment string = "environment"
+const ConfigSourceSecretFile string = "secret file" // Named by the _FILE environment variable
+
+// A problem with the configuration, and the environment variables of the settings it is about, if any
+type configProblem struct {
+	Settings []string
+	Message  string
+}
+
+// A setting, found by walking Config's sections
+type configSetting struct {
+	Env     string
+	Key     string // section.key in the YAML file
+	Secret  bool
+	Restart bool // Only takes effect at startup
+	Default string
+	value   reflect.Value
+}
+
+func (cfg *Config) settings() []configSetting {
+	var settings []configSetting
+	sections := reflect.ValueOf(cfg).Elem()
+	for i := 0; i < sections.NumField(); i++ {
+		sectionField := sections.Type().Field(i)
+		if sectionField.Type.Kind() != reflect.Struct {
+			continue
+		}
+		section := sections.Field(i)
+		for j := 0; j < section.NumField(); j++ {
+			field := section.Type().Field(j)
+			settings = append(settings, configSetting{
+				Env:     field.Tag.Get("env"),
+				Key:     sectionField.Tag.Get("yaml") + "." + field.Tag.Get("yaml"),
+				Secret:  field.Tag.Get("secret") == "true",
+				Restart: sectionField.Tag.Get("restart") == "true" || field.Tag.Get("restart") == "true",
+				Default: field.Tag.Get("default"),
+				value:   section.Field(j),
+			})
+		}
+	}
+	return settings
+}
+
+// Sets a setting from its text form, as given in an environment variable or a default tag
+func (setting configSetting) set(text string) error {
+	if _, isString := setting.value.Interface().(string); isString {
+		setting.value.SetString(text)
+		return nil
+	}
+	text = strings.TrimSpace(text)
+	switch setting.value.Interface().(type) {
+	case bool:
+		parsed, err := strconv.ParseBool(text)
+		if err != nil {
+			return fmt.Errorf("'%s' must be 'true' or 'false', not '%s'", setting.Env, text)
+		}
+		setting.value.SetBool(parsed)
+	case *bool:
+		parsed, err := strconv.ParseBool(text)
+		if err != nil {
+			return fmt.Errorf("'%s' must be 'true' or 'false', not '%s'", setting.Env, text)
+		}
+		setting.value.Set(reflect.ValueOf(&parsed))
+	case int:
+		parsed, err := strconv.Atoi(text)
+		if err != nil {
+			return fmt.Errorf("'%s' must be a whole number, not '%s'", setting.Env, text)
+		}
+		setting.value.SetInt(int64(parsed))
+	}
+	return nil
+}
+
+// Where the setting's value came from: ConfigSourceEnvironment, ConfigSourceFile, ConfigSourceDefault,
+// or "" when it isn't set at all
+func (cfg *Config) configSource(env string) string {
+	return cfg.sources[env]
+}
+
+// The configuration from the defaults, the YAML file and the environment, and every problem with it
+func loadConfig() (*Config, []string) {
+	cfg := &Config{sources: map[string]string{}, unparsed: map[string]string{}}
+	for _, setting := range cfg.settings() {
+		if setting.Default != "" {
+			setting.set(setting.Default)
+			cfg.sources[setting.Env] = ConfigSourceDefault
+		}
+	}
+
+	path := os.Getenv("CONFIG_FILE")
+	optional := path == ""
+	if optional {
+		path = defaultConfigFile
+	}
+	raw, err := ioutil.ReadFile(path)
+	if optional && os.IsNotExist(err) {
+		raw, err = nil, nil
+	}
+	if err != nil {
+		cfg.problem("The configuration file '" + path + "' could not be read: " + err.Error())
+	} else if raw != nil {
+		cfg.loadFile(path, raw)
+	}
+
+	for _, setting := range cfg.settings() {
+		value, source, err := environmentSetting(setting.Env)
+		if err != nil {
+			cfg.problem(err.Error(), setting.Env)
+			continue
+		}
+		if value == "" {
+			continue
+		}
+		cfg.sources[setting.Env] = source
+		err = setting.set(value)
+		if err != nil {
+			cfg.unparsed[setting.Env] = value
+			cfg.problem(err.Error(), setting.Env)
+		}
+	}
+	cfg.validate()
+
+	var problems []string
+	for _, problem := range cfg.problems {
+		problems = append(problems, problem.Message)
+	}
+	return cfg, problems
+}
+
+// A setting's environment variable, or the contents of the file its _FILE variable names
+func environmentSetting(env string) (string, string, error) {
+	value, path := os.Getenv(env), os.Getenv(env+"_FILE")
+	if path == "" {
+		return value, ConfigSourceEnvironment, nil
+	}
+	if value != "" {
+		return "", "", errors.New("'" + env + "' and '" + env + "_FILE' are both set. Set only one of them")
+	}
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", "", errors.New("'" + env + "_FILE' could not be read: " + err.Error())
+	}
+	// Files written with echo or an editor end with a newline that isn't part of the secret
+	return strings.TrimRight(string(raw), "\r\n"), ConfigSourceSecretFile, nil
+}
+
+func (cfg *Config) problem(message string, settings ...string) {
+	cfg.problems = append(cfg.problems, configProblem{Settings: settings, Message: message})
+}
+
+// Reads the YAML file over the defaults. Keys the server doesn't know are problems, since they are usually typos.
+func (cfg *Config) loadFile(path string, raw []byte) {
+	err := yaml.UnmarshalStrict(raw, cfg)
+	if err != nil {
+		cfg.problem("The configuration file '" + path + "' is not valid: " + err.Error())
+		return
+	}
+	var present map[string]map[string]interface{}
+	err = yaml.Unmarshal(raw, &present)
+	if err != nil {
+		cfg.problem("The configuration file '" + path + "' is not valid: " + err.Error())
+		return
+	}
+	for _, setting := range cfg.settings() {
+		keys := strings.SplitN(setting.Key, ".", 2)
+		if _, found := present[keys[0]][keys[1]]; found {
+			cfg.sources[setting.Env] = ConfigSourceFile
+		}
+	}
+}
+
+// Problems that would stop something from working, e.g. a missing SMTP password when payment emails are on
+func (cfg *Config) validate() {
+	require := func(condition bool, problem string, settings ...string) {
+		if !condition {
+			cfg.problem(problem, settings...)
+		}
+	}
+	oneOf := func(env string, value string, options ...string) {
+		for _, option := range options {
+			if value == option {
+				return
+			}
+		}
+		cfg.problem("'"+env+"' must be one of '"+strings.Join(options, "', '")+"', not '"+value+"'", env)
+	}
+	positive := func(env string, value int) {
+		require(value > 0, "'"+env+"' must be more than 0", env)
+	}
+	month := func(env string, value int) {
+		require(value >= 1 && value <= 12, "'"+env+"' must be a month number from 1 to 12", env)
+	}
+	pair := func(first string, firstValue string, second string, secondValue string) {
+		require((firstValue == "") == (secondValue == ""), "'"+first+"' and '"+second+"' must be set together", first, second)
+	}
+
+	oneOf("GZIP_COMPRESSION_LVL", cfg.Server.GzipCompression, "DefaultCompression", "BestCompression", "BestSpeed", "NoCompression")
+	pair("ADMIN_USERNAME", cfg.Server.AdminUsername, "ADMIN_PASSWORD", cfg.Server.AdminPassword)
+	pair("BOARD_USERNAME", cfg.Server.BoardUsername, "BOARD_PASSWORD", cfg.Server.BoardPassword)
+	pair("VOLUNTEER_USERNAME", cfg.Server.VolunteerUsername, "VOLUNTEER_PASSWORD", cfg.Server.VolunteerPassword)
+	features := cfg.Features
+	if features.PaymentEmails || features.DonorPortal || features.TicketedEvents || features.SeasonDues {
+		require(cfg.Server.SigningSecret != "", "'SIGNING_SECRET' is required for the links in donor emails, the donor portal, tickets and dues", "SIGNING_SECRET")
+	}
+
+	if cfg.Stripe.Live != nil {
+		if *cfg.Stripe.Live {
+			require(cfg.Stripe.LiveKey != "", "'STRIPE_LIVE_KEY' is required when 'STRIPE_LIVE' is true", "STRIPE_LIVE_KEY")
+			if features.SeasonDues {
+				require(cfg.Stripe.LivePublishableKey != "", "'STRIPE_LIVE_PUBLISHABLE_KEY' is required for the dues page when 'STRIPE_LIVE' is true", "STRIPE_LIVE_PUBLISHABLE_KEY")
+			}
+		} else {
+			require(cfg.Stripe.DebugKey != "", "'STRIPE_DEBUG_KEY' is required when 'STRIPE_LIVE' is false", "STRIPE_DEBUG_KEY")
+			if features.SeasonDues {
+				require(cfg.Stripe.DebugPublishableKey != "", "'STRIPE_DEBUG_PUBLISHABLE_KEY' is required for the dues page when 'STRIPE_LIVE' is false", "STRIPE_DEBUG_PUBLISHABLE_KEY")
+			}
+		}
+	}
+
+	mail := cfg.Mail
+	oneOf("MAIL_TRANSPORT", mail.Transport, MailTransportSMTP, MailTransportHTTP, MailTransportFile)
+	positive("SMTP_TIMEOUT", mail.TimeoutSeconds)
+	positive("BROADCAST_EMAILS_PER_MINUTE", mail.BroadcastsPerMinute)
+	positive("STATEMENT_EMAILS_PER_MINUTE", mail.StatementsPerMinute)
+	if features.PaymentEmails {
+		require(mail.WebServerUsername != "", "'WEBSERVER_EMAIL_USERNAME' is required when 'EMAIL_PAYMENT_NOTIFICATIONS' is true", "WEBSERVER_EMAIL_USERNAME")
+		require(mail.ReceiptsUsername != "", "'DONATION_RECEIPTS_EMAIL_USERNAME' is required when 'EMAIL_PAYMENT_NOTIFICATIONS' is true", "DONATION_RECEIPTS_EMAIL_USERNAME")
+	}
+	switch mail.Transport {
+	case MailTransportSMTP:
+		if mail.SMTPTLS != "" {
+			oneOf("SMTP_TLS", mail.SMTPTLS, "starttls", "tls", "none")
+		}
+		if features.PaymentEmails {
+			require(mail.SMTPServerAddress != "" && mail.SMTPServerPort != "", "'SmtpServerAddress' and 'SmtpServerPort' are required for the smtp mail transport", "SmtpServerAddress", "SmtpServerPort")
+			require(mail.WebServerPassword != "", "'WEBSERVER_EMAIL_PASSWORD' is required for the smtp mail transport", "WEBSERVER_EMAIL_PASSWORD")
+			require(mail.ReceiptsPassword != "", "'DONATION_RECEIPTS_EMAIL_PASSWORD' is required for the smtp mail transport", "DONATION_RECEIPTS_EMAIL_PASSWORD")
+		}
+	case MailTransportHTTP:
+		require(mail.APIURL != "" && mail.APIKey != "", "'MAIL_API_URL' and 'MAIL_API_KEY' are required for the http mail transport", "MAIL_API_URL", "MAIL_API_KEY")
+	}
+	for _, account := range []string{MailAccountWebServer, MailAccountReceipts} {
+		_, err := dkimKeys(cfg.mailAccount(account))
+		if err != nil {
+			prefix := "DKIM_" + dkimAccountVars[account]
+			cfg.problem(err.Error(), prefix+"_SELECTOR", prefix+"_KEY")
+		}
+	}
+
+	if cfg.Bounces.IMAPAddress != "" {
+		require(cfg.Bounces.IMAPUsername != "" && cfg.Bounces.IMAPPassword != "", "'BOUNCE_IMAP_USERNAME' and 'BOUNCE_IMAP_PASSWORD' are required when 'BOUNCE_IMAP_ADDRESS' is set", "BOUNCE_IMAP_USERNAME", "BOUNCE_IMAP_PASSWORD")
+	}
+	positive("NOTIFICATION_ALERT_DOLLARS", cfg.Notifications.AlertDollars)
+	_, digestProblems := parseDigestFrequencies(cfg.Notifications.Digests)
+	for _, problem := range digestProblems {
+		cfg.problem(problem, "NOTIFICATION_DIGESTS")
+	}
+
+	_, err := time.LoadLocation(cfg.Calendar.Timezone)
+	require(err == nil, "'TIMEZONE' must be an IANA time zone name such as America/Chicago, not '"+cfg.Calendar.Timezone+"'", "TIMEZONE")
+	month("FISCAL_YEAR_START", cfg.Calendar.FiscalYearStart)
+	month("FTC_SEASON_START", cfg.Calendar.FTCSeasonStart)
+	month("FLL_SEASON_START", cfg.Calendar.FLLSeasonStart)
+}
+
+// The key for the Stripe mode, or "" when Stripe is turned off
+func (cfg *Config) stripeKey() string {
+	if cfg.Stripe.Live == nil {
+		return ""
+	}
+	if *cfg.Stripe.Live {
+		return cfg.Stripe.LiveKey
+	}
+	return cfg.Stripe.DebugKey
+}
+
+func (cfg *Config) stripePublishableKey() string {
+	if cfg.Stripe.Live == nil {
+		return ""
+	}
+	if *cfg.Stripe.Live {
+		return cfg.Stripe.LivePublishableKey
+	}
+	return cfg.Stripe.DebugPublishableKey
+}
+
+func (cfg *Config) mailTimeout() time.Duration {
+	return time.Duration(cfg.Mail.TimeoutSeconds) * time.Second
+}
+
+// Puts the settings that package-level code reads, the calendar and the signing secret, into effect.
+// Both are restart settings, so this is only done at startup, but they are swapped atomically all the same
+// so a request or worker never reads one half-written.
+func (cfg *Config) apply() {
+	currentCalendar.Store(newOrgCalendar(cfg.Calendar))
+	signingSecret.Store(cfg.Server.SigningSecret)
+}
+
+// A report of every setting, for 'config check' and /admin/config. Secrets show only whether they are set.
+type ConfigReport struct {
+	Settings []SettingReport `json:"settings"`
+	Problems []string        `json:"problems"`
+	Warnings []string        `json:"warnings"` // Settings that are valid on their own but don't fit together
+}
+
+type SettingReport struct {
+	Env      string   `json:"env"`
+	Key      string   `json:"key"`
+	Status   string   `json:"status"`
+	Source   string   `json:"source,omitempty"`
+	Value    string   `json:"value,omitempty"`
+	Restart  bool     `json:"restart,omitempty"` // Changes only take effect after a restart
+	Problems []string `json:"problems,omitempty"`
+}
+
+const SettingSet string = "set"
+const SettingMissing string = "missing"
+const SettingInvalid string = "invalid"
+
+const redactedValue string = "[redacted]"
+
+func (cfg *Config) report() ConfigReport {
+	report := ConfigReport{Settings: []SettingReport{}, Problems: []string{}, Warnings: cfg.warnings()}
+	for _, problem := range cfg.problems {
+		report.Problems = append(report.Problems, problem.Message)
+	}
+	for _, setting := range cfg.settings() {
+		entry := SettingReport{Env: setting.Env, Key: setting.Key, Status: SettingSet, Source: cfg.configSource(setting.Env), Restart: setting.Restart}
+		if entry.Source == "" {
+			entry.Status = SettingMissing
+		} else if setting.Secret {
+			entry.Value = redactedValue
+		} else if unparsed, found := cfg.unparsed[setting.Env]; found {
+			entry.Value = unparsed
+		} else {
+			entry.Value = setting.text()
+		}
+		for _, problem := range cfg.problems {
+			for _, env := range problem.Settings {
+				if env != setting.Env {
+					continue
+				}
+				entry.Problems = append(entry.Problems, problem.Message)
+				// A required setting that isn't there stays missing
+				if entry.Status == SettingSet {
+					entry.Status = SettingInvalid
+				}
+			}
+		}
+		report.Settings = append(report.Settings, entry)
+	}
+	return report
+}
+
+func (setting configSetting) text() string {
+	if live, isPointer := setting.value.Interface().(*bool); isPointer {
+		if live == nil {
+			return ""
+		}
+		return strconv.FormatBool(*live)
+	}
+	return fmt.Sprint(setting.value.Interface())
+}
+
+func (cfg *Config) warnings() []string {
+	warnings := []string{}
+	if cfg.Stripe.Live != nil && *cfg.Stripe.Live {
+		if strings.Contains(cfg.Stripe.LiveKey, "_test_") {
+			warnings = append(warnings, "'STRIPE_LIVE' is true but 'STRIPE_LIVE_KEY' is a test key, so no real payments will be taken")
+		}
+		if cfg.Server.GinMode != "release" {
+			warnings = append(warnings, "'STRIPE_LIVE' is true but 'GIN_MODE' is not 'release', so payment pages are not redirected to HTTPS")
+		}
+		if cfg.Features.PaymentEmails && cfg.Mail.Transport == MailTransportFile {
+			warnings = append(warnings, "'STRIPE_LIVE' is true but 'MAIL_TRANSPORT' is 'file', so receipts are written to 'MAIL_DIR' instead of being emailed to donors")
+		}
+	}
+	if cfg.Stripe.Live != nil && !*cfg.Stripe.Live && strings.Contains(cfg.Stripe.DebugKey, "_live_") {
+		warnings = append(warnings, "'STRIPE_LIVE' is false but 'STRIPE_DEBUG_KEY' is a live key, so test payments will charge real cards")
+	}
+	if cfg.Features.ServingSite {
+		info, err := os.Stat("static")
+		if err != nil || !info.IsDir() {
+			warnings = append(warnings, "'SERVING_SITE' is true but there is no ./static directory to serve")
+		}
+	}
+	_, err := loadEmailTemplates(cfg.Server.TemplatesDir)
+	if err != nil {
+		warnings = append(warnings, "The email templates in 'TEMPLATES_DIR' could not be loaded: "+err.Error())
+	}
+	return warnings
+}
+
+// Receipts can't be sent without the organization's address and EIN, from the saved profile or the settings, or
+// on Heroku without the database to number them in
+func (cfg *Config) receiptProblems() []string {
+	if !cfg.Features.PaymentEmails {
+		return nil
+	}
+	// Receipt numbers must never repeat or skip, and a dyno's disk (with the data directory) is wiped on every restart
+	if os.Getenv("DYNO") != "" && cfg.Server.DatabaseURL == "" {
+		return []string{"'DATABASE_URL' is required on Heroku while 'EMAIL_PAYMENT_NOTIFICATIONS' is on, so the numbered receipts are kept across restarts. Attach Heroku Postgres to set it."}
+	}
+	org, err := organizationProfile(cfg)
+	if err == nil {
+		err = org.incomplete()
+	}
+	if err != nil {
+		return []string{err.Error()}
+	}
+	return nil
+}
+
+// The configuration in effect. A reload swaps in a whole new Config, so a request or worker pass that took
+// one with current() finishes with the values it started with, and the next one gets the new values.
+type liveConfig struct {
+	config  atomic.Value // *Config
+	reloads sync.Mutex
+}
+
+// What a reload changed, by environment variable
+type ConfigReload struct {
+	Changed []string `json:"changed"` // In effect from now on
+	Restart []string `json:"restart"` // Changed, but restart settings keep their old values until a restart
+}
+
+func newLiveConfig(cfg *Config) *liveConfig {
+	live := &liveConfig{}
+	live.config.Store(cfg)
+	return live
+}
+
+func (live *liveConfig) current() *Config {
+	return live.config.Load().(*Config)
+}
+
+// Loads the configuration again and puts it into effect. If it has any problems the old one stays in effect.
+func (live *liveConfig) reload() (ConfigReload, []string) {
+	live.reloads.Lock()
+	defer live.reloads.Unlock()
+	next, problems := loadConfig()
+	if len(problems) > 0 {
+		return ConfigReload{}, problems
+	}
+	previous := live.current()
+	reload := ConfigReload{Changed: []string{}, Restart: []string{}}
+	before := previous.settings()
+	for i, setting := range next.settings() {
+		if setting.text() == before[i].text() {
+			continue
+		}
+		if !setting.Restart {
+			reload.Changed = append(reload.Changed, setting.Env)
+			continue
+		}
+		setting.value.Set(before[i].value)
+		next.sources[setting.Env] = previous.sources[setting.Env]
+		reload.Restart = append(reload.Restart, setting.Env)
+	}
+
+	// Checked again with the restart settings that stay, e.g. payment emails that stay on need mail settings
+	next.problems = nil
+	next.validate()
+	problems = next.receiptProblems()
+	for _, problem := range next.problems {
+		problems = append(problems, problem.Message)
+	}
+	if len(problems) > 0 {
+		return ConfigReload{}, problems
+	}
+	live.config.Store(next)
+	return reload, nil
+}
+
+func logConfigReload(reload ConfigReload, problems []string) {
+	if len(problems) > 0 {
+		for _, problem := range problems {
+			fmt.Println(problem)
+		}
+		fmt.Println("ERROR: THE CONFIGURATION WAS NOT RELOADED BECAUSE OF THE PROBLEM(S) ABOVE. THE PREVIOUS CONFIGURATION IS STILL IN EFFECT.")
+		return
+	}
+	if len(reload.Changed) > 0 {
+		fmt.Println("Configuration reloaded. Changed: " + strings.Join(reload.Changed, ", "))
+	} else {
+		fmt.Println("Configuration reloaded. Nothing that can change without a restart was changed.")
+	}
+	if len(reload.Restart) > 0 {
+		fmt.Println("These settings changed but take effect only after a restart: " + strings.Join(reload.Restart, ", "))
+	}
+}
+
+// Reloads on SIGHUP, e.g. after a mounted secret is rotated
+func reloadConfigOnHangup(configs *liveConfig) {
+	hangups := make(chan os.Signal, 1)
+	signal.Notify(hangups, syscall.SIGHUP)
+	go func() {
+		for range hangups {
+			logConfigReload(configs.reload())
+		}
+	}()
+}
+
+func registerConfigRoutes(admin *gin.RouterGroup, configs *liveConfig) {
+	admin.GET("/config", func(c *gin.Context) {
+		c.JSON(200, configs.current().report())
+	})
+
+	// Like SIGHUP. With problems it answers 400 with them, and the previous configuration stays in effect.
+	admin.POST("/config/reload", func(c *gin.Context) {
+		reload, problems := configs.reload()
+		logConfigReload(reload, problems)
+		if len(problems) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"problems": problems})
+			return
+		}
+		c.JSON(200, reload)
+	})
+}
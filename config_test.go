@@ -0,0 +1,202 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Clears every setting's environment variable and its _FILE variable for the test, and points CONFIG_FILE at
+// a file in a temporary directory that the returned function writes
+func useTestEnvironment(t *testing.T) func(yaml string) {
+	for _, setting := range (&Config{}).settings() {
+		t.Setenv(setting.Env, "")
+		t.Setenv(setting.Env+"_FILE", "")
+	}
+	t.Setenv("DYNO", "")
+	path := filepath.Join(t.TempDir(), "config.yml")
+	t.Setenv("CONFIG_FILE", path)
+	writeConfig := func(yaml string) {
+		err := ioutil.WriteFile(path, []byte(yaml), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeConfig("")
+	return writeConfig
+}
+
+func writeSecretFile(t *testing.T, contents string) string {
+	path := filepath.Join(t.TempDir(), "secret")
+	err := ioutil.WriteFile(path, []byte(contents), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestConfigPrecedence(t *testing.T) {
+	writeConfig := useTestEnvironment(t)
+	writeConfig(`
+mail:
+  transport: file
+  dir: ./from-file
+  timeoutSeconds: 10
+notifications:
+  alertDollars: 250
+`)
+	t.Setenv("MAIL_DIR", "./from-env")
+	t.Setenv("SMTP_TIMEOUT", " 45 ")
+
+	cfg, problems := loadConfig()
+	if len(problems) > 0 {
+		t.Fatalf("unexpected problems: %v", problems)
+	}
+	tests := []struct {
+		env      string
+		value    interface{}
+		expected interface{}
+		source   string
+	}{
+		{"TEMPLATES_DIR", cfg.Server.TemplatesDir, "./templates", ConfigSourceDefault},
+		{"MAIL_TRANSPORT", cfg.Mail.Transport, MailTransportFile, ConfigSourceFile},
+		{"NOTIFICATION_ALERT_DOLLARS", cfg.Notifications.AlertDollars, 250, ConfigSourceFile},
+		{"MAIL_DIR", cfg.Mail.Dir, "./from-env", ConfigSourceEnvironment},
+		{"SMTP_TIMEOUT", cfg.Mail.TimeoutSeconds, 45, ConfigSourceEnvironment},
+		{"PORT", cfg.Server.Port, "", ""},
+	}
+	for _, test := range tests {
+		if test.value != test.expected || cfg.configSource(test.env) != test.source {
+			t.Errorf("%s: expected %v from %q, got %v from %q", test.env, test.expected, test.source, test.value, cfg.configSource(test.env))
+		}
+	}
+
+	// Keys the server doesn't know are reported, since they are usually typos
+	writeConfig("mail:\n  transprot: file\n")
+	if _, problems := loadConfig(); len(problems) != 1 || !strings.Contains(problems[0], "transprot") {
+		t.Errorf("expected the unknown key to be reported, got %v", problems)
+	}
+}
+
+func TestConfigSecretFiles(t *testing.T) {
+	useTestEnvironment(t)
+	t.Setenv("STRIPE_LIVE", "false")
+
+	tests := []struct {
+		name    string
+		value   string
+		file    string // The file's contents, or no _FILE variable when ""
+		key     string
+		source  string
+		problem string
+	}{
+		{"only the variable", "sk_test_env", "", "sk_test_env", ConfigSourceEnvironment, ""},
+		{"only the file", "", "sk_test_file\n", "sk_test_file", ConfigSourceSecretFile, ""},
+		{"the file's trailing CRLF", "", "sk_test_file\r\n", "sk_test_file", ConfigSourceSecretFile, ""},
+		{"both", "sk_test_env", "sk_test_file\n", "", "", "'STRIPE_DEBUG_KEY' and 'STRIPE_DEBUG_KEY_FILE' are both set. Set only one of them"},
+		{"a file that isn't there", "", "missing", "", "", "'STRIPE_DEBUG_KEY_FILE' could not be read"},
+	}
+	for _, test := range tests {
+		t.Setenv("STRIPE_DEBUG_KEY", test.value)
+		path := ""
+		if test.file == "missing" {
+			path = filepath.Join(t.TempDir(), "missing")
+		} else if test.file != "" {
+			path = writeSecretFile(t, test.file)
+		}
+		t.Setenv("STRIPE_DEBUG_KEY_FILE", path)
+
+		cfg, problems := loadConfig()
+		if test.problem == "" {
+			if len(problems) > 0 || cfg.Stripe.DebugKey != test.key || cfg.configSource("STRIPE_DEBUG_KEY") != test.source {
+				t.Errorf("%s: expected %q from %q, got %q from %q, %v", test.name, test.key, test.source, cfg.Stripe.DebugKey, cfg.configSource("STRIPE_DEBUG_KEY"), problems)
+			}
+			continue
+		}
+		// Neither value is used, and the key is then missing as well
+		if cfg.Stripe.DebugKey != "" || len(problems) != 2 || !strings.HasPrefix(problems[0], test.problem) {
+			t.Errorf("%s: expected the problem %q, got %q and %v", test.name, test.problem, cfg.Stripe.DebugKey, problems)
+		}
+		report := cfg.report()
+		for _, setting := range report.Settings {
+			if setting.Env == "STRIPE_DEBUG_KEY" && (setting.Status != SettingMissing || len(setting.Problems) != 2) {
+				t.Errorf("%s: expected the report to show the key missing with both problems, got %+v", test.name, setting)
+			}
+		}
+	}
+}
+
+func TestConfigReload(t *testing.T) {
+	writeConfig := useTestEnvironment(t)
+	writeConfig(`
+server:
+  port: "8080"
+calendar:
+  timezone: America/Chicago
+notifications:
+  alertDollars: 250
+`)
+	secret := writeSecretFile(t, "key-one\n")
+	t.Setenv("MAIL_API_KEY_FILE", secret)
+	t.Setenv("STRIPE_LIVE", "false")
+	t.Setenv("STRIPE_DEBUG_KEY", "sk_test_one")
+	first, problems := loadConfig()
+	if len(problems) > 0 {
+		t.Fatalf("unexpected problems: %v", problems)
+	}
+	live := newLiveConfig(first)
+
+	// A rotated secret file, a new file and a changed environment, as a SIGHUP would find them
+	writeConfig(`
+server:
+  port: "9090"
+calendar:
+  timezone: America/New_York
+notifications:
+  alertDollars: 500
+`)
+	ioutil.WriteFile(secret, []byte("key-two\n"), 0600)
+	t.Setenv("STRIPE_LIVE", "true")
+	t.Setenv("STRIPE_LIVE_KEY", "sk_live_two")
+	t.Setenv("STRIPE_DEBUG_KEY", "sk_test_two")
+	t.Setenv("SERVING_SITE", "true")
+
+	reload, problems := live.reload()
+	if len(problems) > 0 {
+		t.Fatalf("unexpected problems: %v", problems)
+	}
+	if changed := strings.Join(reload.Changed, ","); changed != "STRIPE_LIVE_KEY,STRIPE_DEBUG_KEY,MAIL_API_KEY,NOTIFICATION_ALERT_DOLLARS" {
+		t.Errorf("unexpected changed settings %s", changed)
+	}
+	if restart := strings.Join(reload.Restart, ","); restart != "PORT,SERVING_SITE,STRIPE_LIVE,TIMEZONE" {
+		t.Errorf("unexpected restart settings %s", restart)
+	}
+
+	cfg := live.current()
+	if cfg == first || first.Mail.APIKey != "key-one" || first.Notifications.AlertDollars != 250 {
+		t.Fatal("expected a new Config to be swapped in, leaving the one requests already hold alone")
+	}
+	if cfg.Mail.APIKey != "key-two" || cfg.Stripe.DebugKey != "sk_test_two" || cfg.Stripe.LiveKey != "sk_live_two" || cfg.Notifications.AlertDollars != 500 {
+		t.Errorf("expected the other settings to be swapped, got %+v %+v %+v", cfg.Mail, cfg.Stripe, cfg.Notifications)
+	}
+	if cfg.Server.Port != "8080" || cfg.Features.ServingSite || *cfg.Stripe.Live || cfg.Calendar.Timezone != "America/Chicago" {
+		t.Errorf("expected the restart settings to keep their values, got %+v %+v %v %+v", cfg.Server, cfg.Features, *cfg.Stripe.Live, cfg.Calendar)
+	}
+	if cfg.configSource("SERVING_SITE") != "" || cfg.configSource("PORT") != ConfigSourceFile {
+		t.Errorf("expected the restart settings to keep their sources, got %q and %q", cfg.configSource("SERVING_SITE"), cfg.configSource("PORT"))
+	}
+
+	// A reload with a problem leaves the configuration in effect alone
+	t.Setenv("SMTP_TIMEOUT", "soon")
+	if _, problems := live.reload(); len(problems) == 0 || live.current() != cfg {
+		t.Errorf("expected the reload to be refused, got %v", problems)
+	}
+
+	// A restart setting that stays is checked against the new values, e.g. debug Stripe still needs its key
+	t.Setenv("SMTP_TIMEOUT", "")
+	t.Setenv("STRIPE_DEBUG_KEY", "")
+	if _, problems := live.reload(); len(problems) != 1 || !strings.Contains(problems[0], "STRIPE_DEBUG_KEY") || live.current() != cfg {
+		t.Errorf("expected the reload to be refused for the debug key, got %v", problems)
+	}
+}
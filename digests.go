@@ -0,0 +1,416 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stripe/stripe-go"
+)
+
+// Payment digests for finance and the teams. Instead of a "New Payment" email for every donation, each
+// address in 'NOTIFICATION_DIGESTS' gets a summary of payments, refunds, disputes and failed email
+// deliveries once an hour, day or week, e.g.
+//
+// NOTIFICATION_DIGESTS=finance@pathfindersrobotics.org=daily,ftc13497@pathfindersrobotics.org=weekly
+//
+// Addresses that aren't listed, or are listed as immediate, keep getting every payment email. Gifts of at
+// least 'NOTIFICATION_ALERT_DOLLARS' (default 1000) are emailed right away to everyone either way.
+// Team addresses only see their own team; every other address sees all of them.
+
+type digestState struct {
+	Email     string    `json:"email"`
+	PeriodEnd time.Time `json:"periodEnd"` // The end of the last period summarized
+}
+
+type digestData struct {
+	Recipients []digestState `json:"recipients"`
+}
+
+type digestLine struct {
+	Date   time.Time
+	Team   string
+	Who    string
+	Amount int
+	Detail string
+}
+
+type digestTotals struct {
+	Team         string
+	Payments     int
+	PaymentTotal int
+	Refunds      int
+	RefundTotal  int
+	Disputes     int
+	DisputeTotal int
+}
+
+type digestReport struct {
+	Payments []digestLine
+	Refunds  []digestLine
+	Disputes []digestLine
+	Failed   []outboxMessage
+}
+
+const digestsDocument string = "digests"
+
+const DigestImmediate string = "immediate"
+const DigestHourly string = "hourly"
+const DigestDaily string = "daily"
+const DigestWeekly string = "weekly"
+
+const digestCheckInterval time.Duration = 5 * time.Minute
+
+// The team each team address hears about
+var digestTeams = map[string]string{
+	Email13497: FTCPathfinders13497,
+	Email7885:  FLLPhoenixVoyagers7885,
+}
+
+// Every address with a digest, and how often it gets one
+func (settings NotificationConfig) digestFrequencies() map[string]string {
+	frequencies, _ := parseDigestFrequencies(settings.Digests)
+	return frequencies
+}
+
+// Entries with an unknown frequency are returned as problems, and those addresses get every payment email
+func parseDigestFrequencies(digests string) (map[string]string, []string) {
+	frequencies := map[string]string{}
+	var problems []string
+	for _, entry := range strings.Split(digests, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, "=", 2)
+		address := donorKey(parts[0])
+		frequency := ""
+		if len(parts) == 2 {
+			frequency = strings.ToLower(strings.TrimSpace(parts[1]))
+		}
+		switch frequency {
+		case DigestHourly, DigestDaily, DigestWeekly:
+			frequencies[address] = frequency
+		case DigestImmediate:
+		default:
+			problems = append(problems, "The 'NOTIFICATION_DIGESTS' entry '"+strings.TrimSpace(entry)+"' needs a frequency of immediate, hourly, daily or weekly. That address gets every payment email.")
+		}
+	}
+	return frequencies, problems
+}
+
+// Gifts of at least this many cents are emailed right away even to addresses with a digest
+func (settings NotificationConfig) alertThreshold() int {
+	return settings.AlertDollars * 100
+}
+
+// Which of addresses should get a payment email now rather than in their digest. Without a data store
+// there are no digests, so everyone does.
+func (settings NotificationConfig) immediateRecipients(addresses []string, amount int) []string {
+	frequencies := settings.digestFrequencies()
+	var immediate []string
+	for _, address := range addresses {
+		if _, digest := frequencies[donorKey(address)]; !digest || dataStore() == nil || amount >= settings.alertThreshold() {
+			immediate = append(immediate, address)
+		}
+	}
+	return immediate
+}
+
+// The start of the period now falls in, in the organization's timezone. Weeks start on Monday.
+func digestPeriodStart(frequency string, now time.Time) time.Time {
+	now = now.In(calendar().location)
+	switch frequency {
+	case DigestHourly:
+		return time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), 0, 0, 0, calendar().location)
+	case DigestWeekly:
+		daysSinceMonday := (int(now.Weekday()) + 6) % 7
+		return time.Date(now.Year(), now.Month(), now.Day()-daysSinceMonday, 0, 0, 0, 0, calendar().location)
+	}
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, calendar().location)
+}
+
+func startDigestWorker(configs *liveConfig) {
+	go func() {
+		for {
+			err := sendDueDigests(configs.current())
+			if err != nil {
+				fmt.Println(err)
+				fmt.Println("ERROR: PAYMENT DIGESTS COULD NOT BE SENT")
+			}
+			time.Sleep(digestCheckInterval)
+		}
+	}()
+}
+
+// Sends each address the digest for any period that ended since its last one. An address seen for the
+// first time starts with the current period rather than summarizing everything before it.
+func sendDueDigests(cfg *Config) error {
+	var data digestData
+	err := dataStore().load(digestsDocument, &data)
+	if err != nil {
+		return err
+	}
+	last := map[string]time.Time{}
+	for _, state := range data.Recipients {
+		last[state.Email] = state.PeriodEnd
+	}
+	sent := map[string]time.Time{}
+	for address, frequency := range cfg.Notifications.digestFrequencies() {
+		end := digestPeriodStart(frequency, time.Now())
+		start, found := last[address]
+		if found && !end.After(start) {
+			continue
+		}
+		if found {
+			report, err := buildDigestReport(cfg, start, end, digestTeams[address])
+			if err != nil {
+				return err
+			}
+			if !report.empty() {
+				subject := strings.Title(frequency) + " payment digest for " + formatDigestPeriod(frequency, start, end)
+				err = queueHTMLMail(MailAccountWebServer, "digest", "digest:"+address, []string{address}, subject, report.render(digestTeams[address], start, end))
+				if err != nil {
+					return err
+				}
+			}
+		}
+		sent[address] = end
+	}
+	if len(sent) == 0 {
+		return nil
+	}
+	return dataStore().update(digestsDocument, &data, func() error {
+		for address, end := range sent {
+			updated := false
+			for i := range data.Recipients {
+				if data.Recipients[i].Email == address {
+					data.Recipients[i].PeriodEnd = end
+					updated = true
+				}
+			}
+			if !updated {
+				data.Recipients = append(data.Recipients, digestState{Email: address, PeriodEnd: end})
+			}
+		}
+		return nil
+	})
+}
+
+func formatDigestPeriod(frequency string, start time.Time, end time.Time) string {
+	last := end.Add(-time.Second)
+	switch {
+	case frequency == DigestHourly:
+		return start.In(calendar().location).Format("Jan 2 3:04 PM") + " to " + end.In(calendar().location).Format("3:04 PM")
+	case calendar().formatDate(start) == calendar().formatDate(last):
+		return calendar().formatDate(start)
+	}
+	return calendar().formatDate(start) + " to " + calendar().formatDate(last)
+}
+
+// Everything that happened in [from, to), for one team or, when team is empty, all of them
+func buildDigestReport(cfg *Config, from time.Time, to time.Time, team string) (digestReport, error) {
+	var report digestReport
+	donors, err := giftsByDonor(from, to)
+	if err != nil {
+		return report, err
+	}
+	for _, gifts := range donors {
+		for _, gift := range gifts {
+			if team == "" || gift.Team == team {
+				report.Payments = append(report.Payments, digestLine{Date: gift.Date, Team: gift.Team, Who: gift.DonorName + " <" + gift.DonorEmail + ">", Amount: gift.Amount, Detail: gift.Source})
+			}
+		}
+	}
+	// Merchandise isn't a gift, so its orders come from the store. They aren't for any one team.
+	if team == "" {
+		var store storeData
+		err = dataStore().load(storeDocument, &store)
+		if err != nil {
+			return report, err
+		}
+		for _, order := range store.Orders {
+			if paid := order.paidDate(); order.Paid && !paid.Before(from) && paid.Before(to) {
+				report.Payments = append(report.Payments, digestLine{Date: paid, Who: order.Name + " <" + order.Email + ">", Amount: order.Total, Detail: "merchandise"})
+			}
+		}
+	}
+	refunds, disputes, err := stripeRefundsAndDisputes(cfg.stripeKey(), from, to)
+	if err != nil {
+		return report, err
+	}
+	for _, line := range refunds {
+		if team == "" || line.Team == team {
+			report.Refunds = append(report.Refunds, line)
+		}
+	}
+	for _, line := range disputes {
+		if team == "" || line.Team == team {
+			report.Disputes = append(report.Disputes, line)
+		}
+	}
+	var outbox outboxData
+	err = dataStore().load(outboxDocument, &outbox)
+	if err != nil {
+		return report, err
+	}
+	for _, msg := range outbox.Messages {
+		if msg.Status != OutboxDead || msg.Failed == nil || msg.Failed.Before(from) || !msg.Failed.Before(to) {
+			continue
+		}
+		// A donor's message counts for the team that was copied on it
+		if team == "" || containsAddress(msg.To, teamEmailAddress(team)) || containsAddress(msg.Copies, teamEmailAddress(team)) {
+			report.Failed = append(report.Failed, msg)
+		}
+	}
+	for _, lines := range [][]digestLine{report.Payments, report.Refunds, report.Disputes} {
+		sort.Slice(lines, func(i, j int) bool { return lines[i].Date.Before(lines[j].Date) })
+	}
+	return report, nil
+}
+
+// Refunds and disputes created in [from, to), attributed to a team by the charge description like gifts are
+func stripeRefundsAndDisputes(key string, from time.Time, to time.Time) ([]digestLine, []digestLine, error) {
+	if key == "" {
+		return nil, nil, nil
+	}
+	backend := stripe.GetBackend(stripe.APIBackend)
+	line := func(created int64, ch *stripe.Charge, amount int64, detail string) digestLine {
+		result := digestLine{Date: time.Unix(created, 0), Amount: int(amount), Detail: detail}
+		if ch != nil {
+			description := ch.Description
+			_, result.Team, _ = determineTeamEmail(&PaymentData{Description: &description})
+			result.Who = ch.ReceiptEmail
+			if ch.Shipping != nil && ch.Shipping.Name != "" {
+				result.Who = ch.Shipping.Name + " <" + ch.ReceiptEmail + ">"
+			}
+		}
+		return result
+	}
+
+	var refunds []digestLine
+	params := &stripe.RefundListParams{}
+	params.Filters.AddFilter("created", "gte", strconv.FormatInt(from.Unix(), 10))
+	params.Filters.AddFilter("created", "lt", strconv.FormatInt(to.Unix(), 10))
+	params.Limit = stripe.Int64(100)
+	params.AddExpand("data.charge")
+	for {
+		var list stripe.RefundList
+		err := backend.Call("GET", "/v1/refunds", key, params, &list)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, refund := range list.Data {
+			refunds = append(refunds, line(refund.Created, refund.Charge, refund.Amount, string(refund.Reason)))
+		}
+		if !list.HasMore || len(list.Data) == 0 {
+			break
+		}
+		params.StartingAfter = stripe.String(list.Data[len(list.Data)-1].ID)
+	}
+
+	var disputes []digestLine
+	disputeParams := &stripe.DisputeListParams{CreatedRange: &stripe.RangeQueryParams{GreaterThanOrEqual: from.Unix(), LesserThan: to.Unix()}}
+	disputeParams.Limit = stripe.Int64(100)
+	disputeParams.AddExpand("data.charge")
+	for {
+		var list stripe.DisputeList
+		err := backend.Call("GET", "/v1/disputes", key, disputeParams, &list)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, dispute := range list.Data {
+			disputes = append(disputes, line(dispute.Created, dispute.Charge, dispute.Amount, string(dispute.Reason)+", "+string(dispute.Status)))
+		}
+		if !list.HasMore || len(list.Data) == 0 {
+			break
+		}
+		disputeParams.StartingAfter = stripe.String(list.Data[len(list.Data)-1].ID)
+	}
+	return refunds, disputes, nil
+}
+
+func (report digestReport) empty() bool {
+	return len(report.Payments) == 0 && len(report.Refunds) == 0 && len(report.Disputes) == 0 && len(report.Failed) == 0
+}
+
+// Totals per team, teams in the usual order and anything not for a team last
+func (report digestReport) totals() []digestTotals {
+	byTeam := map[string]*digestTotals{}
+	get := func(team string) *digestTotals {
+		if byTeam[team] == nil {
+			byTeam[team] = &digestTotals{Team: team}
+		}
+		return byTeam[team]
+	}
+	for _, line := range report.Payments {
+		get(line.Team).Payments++
+		get(line.Team).PaymentTotal += line.Amount
+	}
+	for _, line := range report.Refunds {
+		get(line.Team).Refunds++
+		get(line.Team).RefundTotal += line.Amount
+	}
+	for _, line := range report.Disputes {
+		get(line.Team).Disputes++
+		get(line.Team).DisputeTotal += line.Amount
+	}
+	var totals []digestTotals
+	for _, team := range append(append([]string{}, updateTeams...), "") {
+		if byTeam[team] != nil {
+			totals = append(totals, *byTeam[team])
+		}
+	}
+	return totals
+}
+
+func (report digestReport) render(team string, from time.Time, to time.Time) string {
+	scope := "all teams"
+	if team != "" {
+		scope = team
+	}
+	body := "<html><body style=\"font-family: Arial, sans-serif;\"><h2>Payments for " + html.EscapeString(scope) + "</h2>"
+	body += "<p>" + html.EscapeString(calendar().formatDate(from)) + " " + from.In(calendar().location).Format("3:04 PM") + " to " +
+		html.EscapeString(calendar().formatDate(to)) + " " + to.In(calendar().location).Format("3:04 PM") + "</p>"
+
+	body += "<table border=\"1\" cellpadding=\"4\" cellspacing=\"0\"><tr><th>Team</th><th>Payments</th><th>Refunds</th><th>Disputes</th><th>Net</th></tr>"
+	for _, totals := range report.totals() {
+		name := totals.Team
+		if name == "" {
+			name = "Other"
+		}
+		body += "<tr><td>" + html.EscapeString(name) + "</td>" +
+			"<td>" + strconv.Itoa(totals.Payments) + " / $" + formatCents(totals.PaymentTotal) + "</td>" +
+			"<td>" + strconv.Itoa(totals.Refunds) + " / $" + formatCents(totals.RefundTotal) + "</td>" +
+			"<td>" + strconv.Itoa(totals.Disputes) + " / $" + formatCents(totals.DisputeTotal) + "</td>" +
+			"<td style=\"text-align: right;\">$" + formatCents(totals.PaymentTotal-totals.RefundTotal-totals.DisputeTotal) + "</td></tr>"
+	}
+	body += "</table>"
+
+	for _, section := range []struct {
+		title string
+		lines []digestLine
+	}{{"Payments", report.Payments}, {"Refunds", report.Refunds}, {"Disputes", report.Disputes}} {
+		if len(section.lines) == 0 {
+			continue
+		}
+		body += "<h3>" + section.title + "</h3><table border=\"1\" cellpadding=\"4\" cellspacing=\"0\"><tr><th>When</th><th>Team</th><th>Who</th><th>Amount</th><th></th></tr>"
+		for _, line := range section.lines {
+			body += "<tr><td>" + line.Date.In(calendar().location).Format("Jan 2 3:04 PM") + "</td><td>" + html.EscapeString(line.Team) + "</td><td>" + html.EscapeString(line.Who) +
+				"</td><td style=\"text-align: right;\">$" + formatCents(line.Amount) + "</td><td>" + html.EscapeString(line.Detail) + "</td></tr>"
+		}
+		body += "</table>"
+	}
+
+	if len(report.Failed) > 0 {
+		body += "<h3>Emails that could not be delivered</h3><table border=\"1\" cellpadding=\"4\" cellspacing=\"0\"><tr><th>Kind</th><th>To</th><th>Subject</th><th>Error</th></tr>"
+		for _, msg := range report.Failed {
+			body += "<tr><td>" + html.EscapeString(msg.Kind) + "</td><td>" + html.EscapeString(strings.Join(msg.To, ", ")) + "</td><td>" + html.EscapeString(msg.Subject) +
+				"</td><td>" + html.EscapeString(msg.LastError) + "</td></tr>"
+		}
+		body += "</table><p>They can be retried from " + SiteURL + "/admin/outbox.</p>"
+	}
+	return body + "</body></html>"
+}
@@ -0,0 +1,331 @@
+package main
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DKIM signing (RFC 6376, and RFC 8463 for Ed25519) so receipts don't land in spam. Each mailbox has its own
+// selectors and keys: 'DKIM_RECEIPTS_SELECTOR' lists comma separated selectors, e.g. "rsa2026,ed2026", and
+// 'DKIM_RECEIPTS_KEY' has the matching PEM private keys in the same order (PKCS#1 or PKCS#8, RSA or Ed25519).
+// 'DKIM_WEBSERVER_SELECTOR' and 'DKIM_WEBSERVER_KEY' do the same for the web server mailbox.
+// With two keys a message gets two signatures, so receivers that don't know Ed25519 yet still check the RSA one.
+// The signing domain is the domain of the From address, and each selector's public key must be published
+// at <selector>._domainkey.<domain>.
+
+type dkimKey struct {
+	selector string
+	signer   crypto.Signer
+}
+
+// Headers included in the signature when present
+var dkimSignedHeaders = []string{"From", "Reply-To", "To", "Cc", "Subject", "Date", "Message-ID", "MIME-Version", "Content-Type", "Content-Transfer-Encoding", "List-Unsubscribe", "List-Unsubscribe-Post"}
+
+var dkimAccountVars = map[string]string{
+	MailAccountWebServer: "WEBSERVER",
+	MailAccountReceipts:  "RECEIPTS",
+}
+
+// The keys for a mailbox, or none when it isn't configured for DKIM
+func dkimKeys(settings smtpSettings) ([]dkimKey, error) {
+	prefix := "DKIM_" + dkimAccountVars[settings.Account]
+	selectors, keys := settings.DKIMSelectors, settings.DKIMPrivateKey
+	if selectors == "" && keys == "" {
+		return nil, nil
+	}
+	// Config vars pasted on one line often have literal \n in place of newlines
+	if !strings.Contains(keys, "\n") {
+		keys = strings.Replace(keys, `\n`, "\n", -1)
+	}
+	var signers []crypto.Signer
+	rest := []byte(keys)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		signer, err := parseDKIMKey(block)
+		if err != nil {
+			return nil, &osEnvVarError{"ERROR: '" + prefix + "_KEY' COULD NOT BE READ: " + err.Error()}
+		}
+		signers = append(signers, signer)
+	}
+	if len(signers) == 0 {
+		return nil, &osEnvVarError{"ERROR: '" + prefix + "_KEY' DOES NOT CONTAIN A PEM PRIVATE KEY"}
+	}
+	var result []dkimKey
+	for i, selector := range strings.Split(selectors, ",") {
+		selector = strings.TrimSpace(selector)
+		if selector == "" || i >= len(signers) {
+			return nil, &osEnvVarError{"ERROR: '" + prefix + "_SELECTOR' AND '" + prefix + "_KEY' MUST LIST THE SAME NUMBER OF SELECTORS AND KEYS"}
+		}
+		result = append(result, dkimKey{selector: selector, signer: signers[i]})
+	}
+	if len(result) != len(signers) {
+		return nil, &osEnvVarError{"ERROR: '" + prefix + "_SELECTOR' AND '" + prefix + "_KEY' MUST LIST THE SAME NUMBER OF SELECTORS AND KEYS"}
+	}
+	return result, nil
+}
+
+func parseDKIMKey(block *pem.Block) (crypto.Signer, error) {
+	if block.Type == "RSA PRIVATE KEY" {
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		if key.N.BitLen() < 1024 {
+			return nil, errors.New("RSA keys must be at least 1024 bits")
+		}
+		return key, nil
+	case ed25519.PrivateKey:
+		return key, nil
+	}
+	return nil, errors.New("only RSA and Ed25519 keys are supported")
+}
+
+func dkimAlgorithm(key crypto.PublicKey) string {
+	if _, ok := key.(ed25519.PublicKey); ok {
+		return "ed25519-sha256"
+	}
+	return "rsa-sha256"
+}
+
+// The TXT record to publish at <selector>._domainkey.<domain>
+func dkimDNSRecord(key crypto.PublicKey) (string, error) {
+	if edKey, ok := key.(ed25519.PublicKey); ok {
+		return "v=DKIM1; k=ed25519; p=" + base64.StdEncoding.EncodeToString(edKey), nil
+	}
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return "", err
+	}
+	return "v=DKIM1; k=rsa; p=" + base64.StdEncoding.EncodeToString(der), nil
+}
+
+// Adds a DKIM-Signature header for each key, using relaxed/relaxed canonicalization
+func dkimSign(message []byte, domain string, keys []dkimKey, now time.Time) ([]byte, error) {
+	headers, body, err := splitMessage(message)
+	if err != nil {
+		return nil, err
+	}
+	bodyHash := sha256.Sum256(dkimRelaxedBody(body))
+	var signed []string
+	for _, name := range dkimSignedHeaders {
+		if findHeader(headers, name) != "" {
+			signed = append(signed, strings.ToLower(name))
+		}
+	}
+
+	var signatures bytes.Buffer
+	for _, key := range keys {
+		field := "DKIM-Signature: v=1; a=" + dkimAlgorithm(key.signer.Public()) + "; c=relaxed/relaxed; d=" + domain + "; s=" + key.selector + ";\r\n" +
+			"\tt=" + strconv.FormatInt(now.Unix(), 10) + "; h=" + strings.Join(signed, ":") + ";\r\n" +
+			"\tbh=" + base64.StdEncoding.EncodeToString(bodyHash[:]) + ";\r\n" +
+			"\tb="
+		signature, err := dkimSignature(key.signer, dkimHeaderHashInput(headers, signed, field))
+		if err != nil {
+			return nil, err
+		}
+		encoded := base64.StdEncoding.EncodeToString(signature)
+		signatures.WriteString(field)
+		for len(encoded) > 72 {
+			signatures.WriteString(encoded[:72] + "\r\n\t")
+			encoded = encoded[72:]
+		}
+		signatures.WriteString(encoded + "\r\n")
+	}
+	return append(signatures.Bytes(), message...), nil
+}
+
+func dkimSignature(signer crypto.Signer, input []byte) ([]byte, error) {
+	digest := sha256.Sum256(input)
+	if _, ok := signer.Public().(ed25519.PublicKey); ok {
+		// RFC 8463 signs the SHA-256 hash rather than the data itself
+		return signer.Sign(rand.Reader, digest[:], crypto.Hash(0))
+	}
+	return signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+}
+
+// The signed headers in h= order, then the signature header itself with its b= value left empty
+func dkimHeaderHashInput(headers []string, signed []string, signatureField string) []byte {
+	var input bytes.Buffer
+	used := map[string]int{}
+	for _, name := range signed {
+		// Repeated names take instances from the bottom up; names beyond the last instance add nothing
+		var instances []string
+		for _, field := range headers {
+			if strings.EqualFold(headerName(field), name) {
+				instances = append(instances, field)
+			}
+		}
+		index := len(instances) - 1 - used[strings.ToLower(name)]
+		used[strings.ToLower(name)]++
+		if index >= 0 {
+			input.WriteString(dkimRelaxedHeader(instances[index]) + "\r\n")
+		}
+	}
+	input.WriteString(dkimRelaxedHeader(signatureField))
+	return input.Bytes()
+}
+
+var dkimWhitespace = regexp.MustCompile(`[ \t]+`)
+var dkimSignatureValue = regexp.MustCompile(`(;\s*b=)[^;]*`)
+
+func dkimRelaxedHeader(field string) string {
+	colon := strings.Index(field, ":")
+	name := strings.ToLower(strings.TrimSpace(field[:colon]))
+	value := strings.Replace(field[colon+1:], "\r\n", "", -1)
+	value = strings.TrimSpace(dkimWhitespace.ReplaceAllString(value, " "))
+	return name + ":" + value
+}
+
+func dkimRelaxedBody(body []byte) []byte {
+	lines := strings.Split(strings.Replace(string(body), "\r\n", "\n", -1), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(dkimWhitespace.ReplaceAllString(line, " "), " ")
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return []byte(strings.Join(lines, "\r\n") + "\r\n")
+}
+
+// The header fields, each with its folded continuation lines, and the body
+func splitMessage(message []byte) ([]string, []byte, error) {
+	end := bytes.Index(message, []byte("\r\n\r\n"))
+	if end < 0 {
+		return nil, nil, errors.New("mail: message has no body")
+	}
+	var headers []string
+	for _, line := range strings.Split(string(message[:end]), "\r\n") {
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(headers) > 0 {
+			headers[len(headers)-1] += "\r\n" + line
+		} else if strings.Contains(line, ":") {
+			headers = append(headers, line)
+		}
+	}
+	return headers, message[end+4:], nil
+}
+
+func headerName(field string) string {
+	return strings.TrimSpace(field[:strings.Index(field, ":")])
+}
+
+// The last field with the given name, or ""
+func findHeader(headers []string, name string) string {
+	for i := len(headers) - 1; i >= 0; i-- {
+		if strings.EqualFold(headerName(headers[i]), name) {
+			return headers[i]
+		}
+	}
+	return ""
+}
+
+// Checks every DKIM-Signature on a message. lookup returns the public key for a selector and domain.
+func dkimVerify(message []byte, lookup func(selector string, domain string) (crypto.PublicKey, error)) error {
+	headers, body, err := splitMessage(message)
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, field := range headers {
+		if !strings.EqualFold(headerName(field), "DKIM-Signature") {
+			continue
+		}
+		found = true
+		tags := map[string]string{}
+		for _, tag := range strings.Split(field[strings.Index(field, ":")+1:], ";") {
+			parts := strings.SplitN(tag, "=", 2)
+			if len(parts) == 2 {
+				tags[strings.TrimSpace(parts[0])] = strings.Join(strings.Fields(parts[1]), "")
+			}
+		}
+		if tags["c"] != "relaxed/relaxed" {
+			return errors.New("DKIM: unsupported canonicalization " + tags["c"])
+		}
+		bodyHash := sha256.Sum256(dkimRelaxedBody(body))
+		if base64.StdEncoding.EncodeToString(bodyHash[:]) != tags["bh"] {
+			return errors.New("DKIM: the body hash for selector " + tags["s"] + " does not match")
+		}
+		key, err := lookup(tags["s"], tags["d"])
+		if err != nil {
+			return err
+		}
+		if dkimAlgorithm(key) != tags["a"] {
+			return errors.New("DKIM: selector " + tags["s"] + " publishes a key for " + dkimAlgorithm(key) + " but the message is signed with " + tags["a"])
+		}
+		signature, err := base64.StdEncoding.DecodeString(tags["b"])
+		if err != nil {
+			return err
+		}
+		// The signature header is hashed with its b= value removed
+		unsigned := dkimSignatureValue.ReplaceAllString(field, "$1")
+		digest := sha256.Sum256(dkimHeaderHashInput(headers, strings.Split(tags["h"], ":"), unsigned))
+		switch key := key.(type) {
+		case ed25519.PublicKey:
+			if !ed25519.Verify(key, digest[:], signature) {
+				return errors.New("DKIM: the signature for selector " + tags["s"] + " does not verify")
+			}
+		case *rsa.PublicKey:
+			err = rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature)
+			if err != nil {
+				return errors.New("DKIM: the signature for selector " + tags["s"] + " does not verify")
+			}
+		}
+	}
+	if !found {
+		return errors.New("DKIM: the message is not signed")
+	}
+	return nil
+}
+
+// Reads the public key published in DNS for a selector
+func dkimLookupDNS(selector string, domain string) (crypto.PublicKey, error) {
+	records, err := net.LookupTXT(selector + "._domainkey." + domain)
+	if err != nil {
+		return nil, err
+	}
+	tags := map[string]string{}
+	for _, tag := range strings.Split(strings.Join(records, ""), ";") {
+		parts := strings.SplitN(tag, "=", 2)
+		if len(parts) == 2 {
+			tags[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+		}
+	}
+	raw, err := base64.StdEncoding.DecodeString(tags["p"])
+	if err != nil || len(raw) == 0 {
+		return nil, errors.New("DKIM: no public key published at " + selector + "._domainkey." + domain)
+	}
+	if tags["k"] == "ed25519" {
+		if len(raw) != ed25519.PublicKeySize {
+			return nil, errors.New("DKIM: the Ed25519 key at " + selector + "._domainkey." + domain + " is the wrong length")
+		}
+		return ed25519.PublicKey(raw), nil
+	}
+	key, err := x509.ParsePKIXPublicKey(raw)
+	if err != nil {
+		return x509.ParsePKCS1PublicKey(raw)
+	}
+	return key, nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+)
+
+// The Ed25519 example from RFC 8463 Appendix A
+const rfc8463Seed = "nWGxne/9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A="
+const rfc8463PublicKey = "11qYAYKxCrfVS/7TyWQHOg7hcvPapiMlrwIaaPcHURo="
+const rfc8463Signature = "/gCrinpcQOoIfuHNQIbq4pgh9kyIK3AQUdt9OdqQehSwhEIug4D11BusFa3bT3FY5OsU7ZbnKELq+eXdp1Q1Dw=="
+
+const rfc8463Message = "DKIM-Signature: v=1; a=ed25519-sha256; c=relaxed/relaxed;\r\n" +
+	" d=football.example.com; i=@football.example.com;\r\n" +
+	" q=dns/txt; s=brisbane; t=1528637909; h=from : to :\r\n" +
+	" subject : date : message-id : from : subject : date;\r\n" +
+	" bh=2jUSOH9NhtVGCQWNr9BrIAPreKQjO6Sn7XIkfJVOzv8=;\r\n" +
+	" b=/gCrinpcQOoIfuHNQIbq4pgh9kyIK3AQUdt9OdqQehSwhEIug4D11Bus\r\n" +
+	" Fa3bT3FY5OsU7ZbnKELq+eXdp1Q1Dw==\r\n" +
+	"From: Joe SixPack <joe@football.example.com>\r\n" +
+	"To: Suzie Q <suzie@shopping.example.net>\r\n" +
+	"Subject: Is dinner ready?\r\n" +
+	"Date: Fri, 11 Jul 2003 21:00:37 -0700 (PDT)\r\n" +
+	"Message-ID: <20030712040037.46341.5F8J@football.example.com>\r\n" +
+	"\r\n" +
+	"Hi.\r\n" +
+	"\r\n" +
+	"We lost the game.  Are you hungry yet?\r\n" +
+	"\r\n" +
+	"Joe.\r\n"
+
+func rfc8463Key(t *testing.T) ed25519.PrivateKey {
+	seed, err := base64.StdEncoding.DecodeString(rfc8463Seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := ed25519.NewKeyFromSeed(seed)
+	if base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey)) != rfc8463PublicKey {
+		t.Fatal("the seed does not give the RFC's public key")
+	}
+	return key
+}
+
+func TestDKIMEd25519KnownAnswer(t *testing.T) {
+	key := rfc8463Key(t)
+	headers, body, err := splitMessage([]byte(rfc8463Message))
+	if err != nil {
+		t.Fatal(err)
+	}
+	unsigned := dkimSignatureValue.ReplaceAllString(headers[0], "$1")
+	signed := strings.Split("from:to:subject:date:message-id:from:subject:date", ":")
+	signature, err := dkimSignature(key, dkimHeaderHashInput(headers[1:], signed, unsigned))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if encoded := base64.StdEncoding.EncodeToString(signature); encoded != rfc8463Signature {
+		t.Errorf("expected the RFC's signature\n%s\ngot\n%s", rfc8463Signature, encoded)
+	}
+	bodyHash := sha256.Sum256(dkimRelaxedBody(body))
+	if encoded := base64.StdEncoding.EncodeToString(bodyHash[:]); encoded != "2jUSOH9NhtVGCQWNr9BrIAPreKQjO6Sn7XIkfJVOzv8=" {
+		t.Errorf("expected the RFC's body hash, got %s", encoded)
+	}
+
+	lookup := func(selector string, domain string) (crypto.PublicKey, error) {
+		if selector != "brisbane" || domain != "football.example.com" {
+			t.Errorf("looked up %s._domainkey.%s", selector, domain)
+		}
+		return key.Public(), nil
+	}
+	err = dkimVerify([]byte(rfc8463Message), lookup)
+	if err != nil {
+		t.Errorf("the RFC's message does not verify: %v", err)
+	}
+	tampered := strings.Replace(rfc8463Message, "hungry", "thirsty", 1)
+	if dkimVerify([]byte(tampered), lookup) == nil {
+		t.Error("a changed body still verifies")
+	}
+}
+
+func TestDKIMSignVerifies(t *testing.T) {
+	key := rfc8463Key(t)
+	message := []byte("From: receipts@example.org\r\nTo: donor@example.com\r\nSubject: Thank you\r\n\r\nThank you for your gift.\r\n")
+	signed, err := dkimSign(message, "example.org", []dkimKey{{selector: "ed2026", signer: key}}, time.Unix(1528637909, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = dkimVerify(signed, func(selector string, domain string) (crypto.PublicKey, error) { return key.Public(), nil })
+	if err != nil {
+		t.Fatalf("the signed message does not verify: %v\n%s", err, signed)
+	}
+	if !strings.Contains(string(signed), "a=ed25519-sha256; c=relaxed/relaxed; d=example.org; s=ed2026;") {
+		t.Errorf("unexpected signature header:\n%s", signed)
+	}
+}
@@ -0,0 +1,734 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stripe/stripe-go"
+)
+
+// Season member dues. Each student gets an invoice per season, siblings are grouped in a family account
+// so the family pays all of its invoices at once, and discount rules (such as a sibling discount) are applied
+// when invoices are generated. Dues pay for participation, so they are not tax-deductible.
+
+type Student struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Program string `json:"program"` // "FTC" or "FLL"
+	Team    string `json:"team"`
+}
+
+type Family struct {
+	ID       string    `json:"id"`
+	Name     string    `json:"name"`
+	Email    string    `json:"email"`
+	Phone    string    `json:"phone"`
+	Students []Student `json:"students"`
+}
+
+type SeasonFee struct {
+	Season  string `json:"season"`
+	Program string `json:"program"`
+	Amount  int    `json:"amount"`
+}
+
+// A discount for the FromChild-th student in a family and every student after them, counting from the most expensive fee.
+// Either Percent or Amount (in cents) is used. An empty Program applies to every program.
+type DiscountRule struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+	Program     string `json:"program"`
+	FromChild   int    `json:"fromChild"`
+	Percent     int    `json:"percent"`
+	Amount      int    `json:"amount"`
+}
+
+type DuesInvoice struct {
+	ID                 string     `json:"id"`
+	FamilyID           string     `json:"familyId"`
+	StudentID          string     `json:"studentId"`
+	StudentName        string     `json:"studentName"`
+	Season             string     `json:"season"`
+	Program            string     `json:"program"`
+	Fee                int        `json:"fee"`
+	Discount           int        `json:"discount"`
+	DiscountNote       string     `json:"discountNote,omitempty"`
+	Due                int        `json:"due"`
+	Waived             int        `json:"waived,omitempty"`
+	WaiverRedemptionID string     `json:"waiverRedemptionId,omitempty"`
+	PaymentIntentID    string     `json:"paymentIntentId,omitempty"`
+	Paid               *time.Time `json:"paid,omitempty"`
+}
+
+type duesData struct {
+	Families []Family       `json:"families"`
+	Fees     []SeasonFee    `json:"fees"`
+	Rules    []DiscountRule `json:"rules"`
+	Invoices []DuesInvoice  `json:"invoices"`
+}
+
+const DuesDescriptionPrefix string = "Pathfinders Robotics Season Dues"
+
+const duesDocument string = "dues"
+
+func registerDuesRoutes(router *gin.Engine, admin *gin.RouterGroup, board *gin.RouterGroup, configs *liveConfig) {
+	// Families reach their invoices through a signed link, so no account or password is needed
+	familyFromToken := func(c *gin.Context) (string, bool) {
+		payload, ok := verifyToken(c.Query("token"))
+		if !ok || payload != "dues:"+c.Param("family") {
+			c.String(http.StatusForbidden, "This link is not valid")
+			return "", false
+		}
+		return c.Param("family"), true
+	}
+
+	// The link in the invoice email opens a page that loads the invoices from here as JSON and pays them
+	router.GET("/dues/:family", func(c *gin.Context) {
+		familyID, ok := familyFromToken(c)
+		if !ok {
+			return
+		}
+		if strings.Contains(c.GetHeader("Accept"), "text/html") {
+			c.Data(200, "text/html; charset=utf-8", []byte(duesPage))
+			return
+		}
+		var data duesData
+		err := dataStore().load(duesDocument, &data)
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		family, found := data.family(familyID)
+		if !found {
+			c.String(http.StatusNotFound, "No such family")
+			return
+		}
+		c.JSON(200, gin.H{
+			"family":         family.Name,
+			"invoices":       data.familyInvoices(familyID, false),
+			"publishableKey": configs.current().stripePublishableKey(),
+		})
+	})
+	router.GET("/dues.js", func(c *gin.Context) {
+		c.Data(200, "application/javascript", []byte(duesScript))
+	})
+
+	// Creates one PaymentIntent covering every unpaid invoice in the family, less any waiver code.
+	// A fully waived checkout is marked paid right away.
+	router.POST("/dues/:family/checkout", func(c *gin.Context) {
+		cfg := configs.current()
+		familyID, ok := familyFromToken(c)
+		if !ok {
+			return
+		}
+		var body struct {
+			WaiverCode string `json:"waiverCode"`
+		}
+		c.ShouldBindJSON(&body)
+
+		var data duesData
+		err := dataStore().load(duesDocument, &data)
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		total := 0
+		for _, invoice := range data.familyInvoices(familyID, true) {
+			total += invoice.Due
+		}
+		discount, redemptionID := 0, ""
+		if body.WaiverCode != "" && total > 0 {
+			discount, redemptionID, err = reserveWaiver(body.WaiverCode, WaiverContextDues, familyID, total)
+			if err != nil {
+				c.String(http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+
+		// Stripe is called between two updates rather than inside one, so the store isn't locked while it answers.
+		// The first works out what's due; fully waived invoices are paid right there.
+		var family Family
+		var unpaid []DuesInvoice
+		var paid []DuesInvoice
+		err = dataStore().update(duesDocument, &data, func() error {
+			var found bool
+			family, found = data.family(familyID)
+			if !found {
+				return os.ErrNotExist
+			}
+			unpaid = data.familyInvoices(familyID, true)
+			if len(unpaid) == 0 {
+				return errors.New("nothing is due")
+			}
+			total = 0
+			for _, invoice := range unpaid {
+				total += invoice.Due
+			}
+			if discount > total {
+				discount = total
+			}
+			if total > discount {
+				return nil
+			}
+			paid = data.applyDuesPayment(familyID, unpaid, "", redemptionID, discount)
+			return nil
+		})
+		var secret string
+		if err == nil && len(paid) == 0 {
+			var students []string
+			for _, invoice := range unpaid {
+				students = append(students, invoice.StudentName+" ("+invoice.Program+" "+invoice.Season+")")
+			}
+			card := "card"
+			var intent *stripe.PaymentIntent
+			intent, err = cfg.paymentIntents().New(&stripe.PaymentIntentParams{
+				Amount:             stripe.Int64(int64(total - discount)),
+				Currency:           stripe.String(string(stripe.CurrencyUSD)),
+				Description:        stripe.String(DuesDescriptionPrefix + " - " + strings.Join(students, ", ")),
+				PaymentMethodTypes: []*string{&card},
+				ReceiptEmail:       stripe.String(family.Email),
+			})
+			if err == nil {
+				secret = intent.ClientSecret
+				err = dataStore().update(duesDocument, &data, func() error {
+					data.applyDuesPayment(familyID, unpaid, intent.ID, redemptionID, discount)
+					return nil
+				})
+			}
+		}
+		if err != nil {
+			releaseWaiver(redemptionID)
+		}
+		if err == os.ErrNotExist {
+			c.String(http.StatusNotFound, "No such family")
+			return
+		}
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		if len(paid) > 0 {
+			confirmWaiver(redemptionID)
+			c.JSON(200, gin.H{
+				"paid": true,
+			})
+			go sendDuesReceipt(family, paid)
+			return
+		}
+		c.JSON(200, gin.H{
+			"secret": secret,
+			"total":  total - discount,
+		})
+	})
+
+	router.POST("/dues/:family/confirm", func(c *gin.Context) {
+		familyID, ok := familyFromToken(c)
+		if !ok {
+			return
+		}
+		family, paid, err := confirmDuesPayment(configs.current(), familyID)
+		if err != nil {
+			fmt.Println(err)
+			c.JSON(200, gin.H{
+				"success": false,
+			})
+			return
+		}
+		c.JSON(200, gin.H{
+			"success": true,
+		})
+		if len(paid) > 0 {
+			go sendDuesReceipt(family, paid)
+		}
+	})
+
+	// Invoices with the fee waivers that paid them, which coaches don't see
+	if board != nil {
+		board.GET("/dues/invoices", func(c *gin.Context) {
+			var data duesData
+			err := dataStore().load(duesDocument, &data)
+			if err != nil {
+				fmt.Println(err)
+				c.String(http.StatusInternalServerError, "Error")
+				return
+			}
+			c.JSON(200, data.Invoices)
+		})
+	}
+
+	if admin == nil {
+		fmt.Println("Dues administration is disabled because admin credentials are not configured.")
+		return
+	}
+
+	admin.POST("/dues/families", func(c *gin.Context) {
+		var family Family
+		err := c.BindJSON(&family)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		if family.ID == "" {
+			family.ID = newID()
+		}
+		for i := range family.Students {
+			if family.Students[i].ID == "" {
+				family.Students[i].ID = newID()
+			}
+		}
+		var data duesData
+		err = dataStore().update(duesDocument, &data, func() error {
+			for i := range data.Families {
+				if data.Families[i].ID == family.ID {
+					data.Families[i] = family
+					return nil
+				}
+			}
+			data.Families = append(data.Families, family)
+			return nil
+		})
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		c.JSON(200, family)
+	})
+
+	// Replaces the fee schedule and discount rules. Invoices that were already generated keep their amounts.
+	admin.POST("/dues/settings", func(c *gin.Context) {
+		var settings struct {
+			Fees  []SeasonFee    `json:"fees" binding:"exists"`
+			Rules []DiscountRule `json:"rules" binding:"exists"`
+		}
+		err := c.BindJSON(&settings)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		for i := range settings.Rules {
+			if settings.Rules[i].ID == "" {
+				settings.Rules[i].ID = newID()
+			}
+		}
+		var data duesData
+		err = dataStore().update(duesDocument, &data, func() error {
+			data.Fees = settings.Fees
+			data.Rules = settings.Rules
+			return nil
+		})
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		c.JSON(200, settings)
+	})
+
+	admin.POST("/dues/invoices/generate", func(c *gin.Context) {
+		var body struct {
+			Season string `json:"season"` // Optional, defaults to each program's current season
+		}
+		err := c.BindJSON(&body)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		var data duesData
+		var created []DuesInvoice
+		err = dataStore().update(duesDocument, &data, func() error {
+			created = data.generateInvoices(body.Season)
+			data.Invoices = append(data.Invoices, created...)
+			return nil
+		})
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		c.JSON(200, invoicesWithoutWaivers(created))
+	})
+
+	admin.GET("/dues/invoices", func(c *gin.Context) {
+		var data duesData
+		err := dataStore().load(duesDocument, &data)
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		c.JSON(200, invoicesWithoutWaivers(data.Invoices))
+	})
+
+	// Emails the family a signed link to their invoices
+	admin.POST("/dues/families/:id/notify", func(c *gin.Context) {
+		var data duesData
+		err := dataStore().load(duesDocument, &data)
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		family, found := data.family(c.Param("id"))
+		if !found {
+			c.String(http.StatusNotFound, "No such family")
+			return
+		}
+		err = sendDuesInvoiceEmail(family, data.familyInvoices(family.ID, true))
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		c.String(200, "OK")
+	})
+	fmt.Println("Dues administration is available at /admin/dues.")
+}
+
+func (data *duesData) family(id string) (Family, bool) {
+	for _, family := range data.Families {
+		if family.ID == id {
+			return family, true
+		}
+	}
+	return Family{}, false
+}
+
+// Puts the payment on the family's unpaid invoices and returns the ones it paid, which is all of them when there's
+// no PaymentIntent because a waiver covered everything. The waiver is spread over the invoices in order so each
+// receipt line shows what it covered. Only the invoices the payment was worked out for are touched, in case
+// anything changed in between.
+func (data *duesData) applyDuesPayment(familyID string, invoices []DuesInvoice, paymentIntentID string, redemptionID string, discount int) []DuesInvoice {
+	covered := map[string]bool{}
+	for _, invoice := range invoices {
+		covered[invoice.ID] = true
+	}
+	var paid []DuesInvoice
+	remaining := discount
+	now := time.Now()
+	for i := range data.Invoices {
+		invoice := &data.Invoices[i]
+		if invoice.FamilyID != familyID || invoice.Paid != nil || !covered[invoice.ID] {
+			continue
+		}
+		invoice.PaymentIntentID = paymentIntentID
+		invoice.WaiverRedemptionID = redemptionID
+		invoice.Waived = remaining
+		if invoice.Waived > invoice.Due {
+			invoice.Waived = invoice.Due
+		}
+		remaining -= invoice.Waived
+		if paymentIntentID == "" {
+			invoice.Paid = &now
+			paid = append(paid, *invoice)
+		}
+	}
+	return paid
+}
+
+func (data *duesData) familyInvoices(familyID string, unpaidOnly bool) []DuesInvoice {
+	var invoices []DuesInvoice
+	for _, invoice := range data.Invoices {
+		if invoice.FamilyID == familyID && (!unpaidOnly || invoice.Paid == nil) {
+			invoices = append(invoices, invoice)
+		}
+	}
+	return invoices
+}
+
+func (data *duesData) fee(season string, program string) (int, bool) {
+	for _, fee := range data.Fees {
+		if fee.Season == season && fee.Program == program {
+			return fee.Amount, true
+		}
+	}
+	return 0, false
+}
+
+// Creates the invoices for a season that don't exist yet. Within a family, students are ordered from the most
+// expensive fee down so sibling discounts come off the smaller fees, and each invoice gets the best rule that applies.
+// An empty season means each program's current season on the organization calendar.
+func (data *duesData) generateInvoices(season string) []DuesInvoice {
+	var created []DuesInvoice
+	for _, family := range data.Families {
+		var invoices []DuesInvoice
+		for _, student := range family.Students {
+			studentSeason := season
+			if studentSeason == "" {
+				studentSeason = calendar().season(student.Program, time.Now())
+			}
+			amount, found := data.fee(studentSeason, student.Program)
+			if !found {
+				continue
+			}
+			invoices = append(invoices, DuesInvoice{
+				FamilyID:    family.ID,
+				StudentID:   student.ID,
+				StudentName: student.Name,
+				Season:      studentSeason,
+				Program:     student.Program,
+				Fee:         amount,
+			})
+		}
+		sort.SliceStable(invoices, func(i, j int) bool { return invoices[i].Fee > invoices[j].Fee })
+
+		for position := range invoices {
+			invoice := &invoices[position]
+			for _, rule := range data.Rules {
+				if rule.FromChild < 1 || position+1 < rule.FromChild || (rule.Program != "" && rule.Program != invoice.Program) {
+					continue
+				}
+				discount := rule.Amount
+				if rule.Percent > 0 {
+					discount = invoice.Fee * rule.Percent / 100
+				}
+				if discount > invoice.Fee {
+					discount = invoice.Fee
+				}
+				if discount > invoice.Discount {
+					invoice.Discount = discount
+					invoice.DiscountNote = rule.Description
+				}
+			}
+			invoice.Due = invoice.Fee - invoice.Discount
+		}
+
+		for _, invoice := range invoices {
+			exists := false
+			for _, existing := range data.Invoices {
+				if existing.StudentID == invoice.StudentID && existing.Season == invoice.Season {
+					exists = true
+				}
+			}
+			if !exists {
+				invoice.ID = newID()
+				created = append(created, invoice)
+			}
+		}
+	}
+	return created
+}
+
+// Marks the family's invoices paid if the PaymentIntent they were checked out with succeeded.
+// Returns only the invoices this call marked paid, so a repeated confirm doesn't send a second receipt.
+func confirmDuesPayment(cfg *Config, familyID string) (Family, []DuesInvoice, error) {
+	var data duesData
+	err := dataStore().load(duesDocument, &data)
+	if err != nil {
+		return Family{}, nil, err
+	}
+	// Stripe is asked before the store is locked
+	succeeded := map[string]bool{}
+	for _, invoice := range data.familyInvoices(familyID, true) {
+		if _, checked := succeeded[invoice.PaymentIntentID]; invoice.PaymentIntentID != "" && !checked {
+			succeeded[invoice.PaymentIntentID] = paymentIntentSucceeded(cfg, invoice.PaymentIntentID) == nil
+		}
+	}
+
+	var family Family
+	var paid []DuesInvoice
+	err = dataStore().update(duesDocument, &data, func() error {
+		var found bool
+		family, found = data.family(familyID)
+		if !found {
+			return os.ErrNotExist
+		}
+		now := time.Now()
+		for i := range data.Invoices {
+			invoice := &data.Invoices[i]
+			if invoice.FamilyID != familyID || invoice.Paid != nil || !succeeded[invoice.PaymentIntentID] {
+				continue
+			}
+			invoice.Paid = &now
+			paid = append(paid, *invoice)
+		}
+		if len(paid) == 0 {
+			return errors.New("no successful dues payment found for family " + familyID)
+		}
+		return nil
+	})
+	if err == nil {
+		confirmed := map[string]bool{}
+		for _, invoice := range paid {
+			if !confirmed[invoice.WaiverRedemptionID] {
+				confirmWaiver(invoice.WaiverRedemptionID)
+				confirmed[invoice.WaiverRedemptionID] = true
+			}
+		}
+	}
+	return family, paid, err
+}
+
+func duesLink(family Family) (string, error) {
+	token, err := signToken("dues:" + family.ID)
+	if err != nil {
+		return "", err
+	}
+	return SiteURL + "/dues/" + url.PathEscape(family.ID) + "?token=" + url.QueryEscape(token), nil
+}
+
+func sendDuesInvoiceEmail(family Family, invoices []DuesInvoice) error {
+	link, err := duesLink(family)
+	if err != nil {
+		return err
+	}
+	body := "<html><body style=\"font-family: 'Times New Roman', Times, serif;\"><p>" + html.EscapeString(family.Name) + ",</p><p>Season dues are ready for your family:</p><table cellpadding=\"4\">"
+	total := 0
+	for _, invoice := range invoices {
+		body += duesInvoiceRow(invoice, false)
+		total += invoice.Due
+	}
+	body += "<tr><td><b>Total due</b></td><td style=\"text-align: right;\"><b>$" + formatCents(total) + "</b></td></tr></table>"
+	body += "<p><a href=\"" + html.EscapeString(link) + "\">Pay season dues online</a></p></body></html>"
+	return queueHTMLMail(MailAccountWebServer, "dues", "family:"+family.ID, []string{family.Email}, "Pathfinders Robotics season dues", body)
+}
+
+// Invoices as coaches see them at /admin, without the fee waivers only the board sees
+func invoicesWithoutWaivers(invoices []DuesInvoice) []DuesInvoice {
+	stripped := make([]DuesInvoice, len(invoices))
+	for i, invoice := range invoices {
+		invoice.Waived = 0
+		invoice.WaiverRedemptionID = ""
+		stripped[i] = invoice
+	}
+	return stripped
+}
+
+// Dues receipts deliberately don't use the donation receipt wording from sendPaymentEmail
+func sendDuesReceipt(family Family, paid []DuesInvoice) {
+	body := "<html><body style=\"font-family: 'Times New Roman', Times, serif;\"><p>" + html.EscapeString(family.Name) + ",</p><p>We received your season dues payment on " + calendar().formatDate(time.Now()) + ". Thank you!</p><table cellpadding=\"4\">"
+	total := 0
+	for _, invoice := range paid {
+		body += duesInvoiceRow(invoice, true)
+		total += invoice.Due - invoice.Waived
+	}
+	body += "<tr><td><b>Total paid</b></td><td style=\"text-align: right;\"><b>$" + formatCents(total) + "</b></td></tr></table>"
+	body += "<p><b>Dues payment receipt - not a donation receipt.</b> Season dues are a fee for participation in the program and are not a tax-deductible charitable contribution.</p></body></html>"
+
+	err := queueHTMLMail(MailAccountWebServer, "dues", "family:"+family.ID, []string{family.Email, EmailFinance}, "Pathfinders Robotics season dues receipt", body)
+	if err != nil {
+		fmt.Println(err)
+		fmt.Println("ERROR: DUES RECEIPT TO " + family.Email + " AND FINANCE COULD NOT BE QUEUED")
+	}
+}
+
+// Receipts include any waiver applied at checkout; invoices sent before payment don't
+func duesInvoiceRow(invoice DuesInvoice, withWaiver bool) string {
+	amount := invoice.Due
+	row := "<tr><td>" + html.EscapeString(invoice.StudentName) + " - " + html.EscapeString(invoice.Program) + " " + html.EscapeString(invoice.Season) + " season dues"
+	if invoice.Discount > 0 {
+		row += "<br/><i>$" + formatCents(invoice.Fee) + " less $" + formatCents(invoice.Discount) + " " + html.EscapeString(invoice.DiscountNote) + "</i>"
+	}
+	if withWaiver && invoice.Waived > 0 {
+		row += "<br/><i>less $" + formatCents(invoice.Waived) + " fee waiver</i>"
+		amount -= invoice.Waived
+	}
+	return row + "</td><td style=\"text-align: right;\">$" + formatCents(amount) + "</td></tr>"
+}
+
+const duesPage string = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8" />
+<meta name="viewport" content="width=device-width, initial-scale=1.0" />
+<title>Pathfinders Robotics Season Dues</title>
+<script src="https://js.stripe.com/v3/"></script>
+</head>
+<body>
+<h1>Season Dues</h1>
+<h2 id="family"></h2>
+<table id="invoices"></table>
+<p>Total due: <b id="total"></b></p>
+<form id="pay" hidden>
+<input id="waiver" type="text" placeholder="Fee waiver code (optional)" autocomplete="off" />
+<div id="card"></div>
+<button id="submit" type="submit">Pay</button>
+</form>
+<p id="status"></p>
+<script src="/dues.js"></script>
+</body>
+</html>
+`
+
+// Checks out every unpaid invoice, confirms the card payment with Stripe.js, then has the server check it with Stripe
+const duesScript string = `(function () {
+  var family = location.pathname.split('/')[2];
+  var query = '?token=' + encodeURIComponent(new URLSearchParams(location.search).get('token') || '');
+  var status = document.getElementById('status');
+  var form = document.getElementById('pay');
+  var button = document.getElementById('submit');
+  var stripe, card;
+
+  function cents(amount) {
+    return '$' + (amount / 100).toFixed(2);
+  }
+
+  function request(method, path, body) {
+    return fetch('/dues/' + family + path + query, {
+      method: method,
+      headers: { 'Accept': 'application/json', 'Content-Type': 'application/json' },
+      body: body ? JSON.stringify(body) : undefined
+    }).then(function (res) {
+      if (!res.ok) return res.text().then(function (text) { throw new Error(text); });
+      return res.json();
+    });
+  }
+
+  request('GET', '').then(function (body) {
+    document.getElementById('family').textContent = body.family;
+    var table = document.getElementById('invoices');
+    var total = 0;
+    (body.invoices || []).forEach(function (invoice) {
+      var row = table.insertRow();
+      row.insertCell().textContent = invoice.studentName + ' - ' + invoice.program + ' ' + invoice.season;
+      row.insertCell().textContent = invoice.paid ? 'Paid' : cents(invoice.due);
+      if (!invoice.paid) total += invoice.due;
+    });
+    document.getElementById('total').textContent = cents(total);
+    if (total === 0) {
+      status.textContent = 'Nothing is due. Thank you!';
+      return;
+    }
+    stripe = Stripe(body.publishableKey);
+    card = stripe.elements().create('card');
+    card.mount('#card');
+    form.hidden = false;
+  }).catch(function (err) {
+    status.textContent = err.message;
+  });
+
+  form.addEventListener('submit', function (e) {
+    e.preventDefault();
+    button.disabled = true;
+    status.textContent = 'Processing...';
+    request('POST', '/checkout', { waiverCode: document.getElementById('waiver').value.trim() }).then(function (body) {
+      if (body.paid) return { success: true };
+      return stripe.confirmCardPayment(body.secret, { payment_method: { card: card } }).then(function (result) {
+        if (result.error) throw new Error(result.error.message);
+        return request('POST', '/confirm', {});
+      });
+    }).then(function (body) {
+      if (body.success) {
+        form.hidden = true;
+        status.textContent = 'Thank you! Your dues are paid and a receipt is on its way by email.';
+      } else {
+        status.textContent = 'The payment went through but could not be confirmed yet. Reload this page in a minute to check.';
+      }
+    }).catch(function (err) {
+      status.textContent = err.message;
+      button.disabled = false;
+    });
+  });
+})();
+`
@@ -0,0 +1,730 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stripe/stripe-go"
+)
+
+// Ticketed fundraising events, such as the spaghetti dinner and robot demo night.
+// Tickets are bought through a PaymentIntent like /getSecret, emailed with a signed QR code,
+// and scanned at the door from /volunteer/checkin.
+
+type TicketType struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	Price         int    `json:"price"`
+	Capacity      int    `json:"capacity"`
+	NonDeductible int    `json:"nonDeductible"` // Fair market value of what the attendee receives, per ticket, in cents
+}
+
+type Event struct {
+	ID          string       `json:"id"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Location    string       `json:"location"`
+	Starts      time.Time    `json:"starts"`
+	TicketTypes []TicketType `json:"ticketTypes"`
+}
+
+type TicketOrder struct {
+	ID                 string    `json:"id"`
+	EventID            string    `json:"eventId"`
+	TicketTypeID       string    `json:"ticketTypeId"`
+	Quantity           int       `json:"quantity"`
+	Name               string    `json:"name"`
+	Email              string    `json:"email"`
+	Phone              string    `json:"phone"`
+	Discount           int       `json:"discount,omitempty"`
+	WaiverRedemptionID string    `json:"waiverRedemptionId,omitempty"`
+	PaymentIntentID    string    `json:"paymentIntentId"`
+	Paid               bool      `json:"paid"`
+	Created            time.Time `json:"created"`
+	// Paid after its hold expired, when its tickets had gone to other buyers. No tickets were issued and the
+	// payment is to be refunded.
+	Oversold bool `json:"oversold,omitempty"`
+}
+
+type Ticket struct {
+	ID           string     `json:"id"`
+	OrderID      string     `json:"orderId"`
+	EventID      string     `json:"eventId"`
+	TicketTypeID string     `json:"ticketTypeId"`
+	Holder       string     `json:"holder"`
+	Issued       time.Time  `json:"issued"`
+	CheckedIn    *time.Time `json:"checkedIn,omitempty"`
+}
+
+type eventData struct {
+	Events  []Event       `json:"events"`
+	Orders  []TicketOrder `json:"orders"`
+	Tickets []Ticket      `json:"tickets"`
+}
+
+// For buying tickets
+type TicketCheckout struct {
+	TicketType *string `form:"ticketType" json:"ticketType" binding:"exists"`
+	Quantity   *int    `form:"quantity" json:"quantity" binding:"exists"`
+	Name       *string `form:"name" json:"name" binding:"exists"`
+	Email      *string `form:"email" json:"email" binding:"exists"`
+	Phone      *string `form:"phone" json:"phone" binding:"exists"`
+	WaiverCode *string `form:"waiverCode" json:"waiverCode"`
+}
+
+const EventTicketsDescriptionPrefix string = "Pathfinders Robotics Event Tickets"
+
+const eventsDocument string = "events"
+
+var errSoldOut = errors.New("not enough tickets remaining")
+
+func registerEventRoutes(router *gin.Engine, admin *gin.RouterGroup, board *gin.RouterGroup, volunteer *gin.RouterGroup, configs *liveConfig) {
+	router.GET("/events", func(c *gin.Context) {
+		var data eventData
+		err := dataStore().load(eventsDocument, &data)
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		now := time.Now()
+		var upcoming []gin.H
+		for _, event := range data.Events {
+			if event.Starts.Before(now) {
+				continue
+			}
+			var types []gin.H
+			for _, ticketType := range event.TicketTypes {
+				types = append(types, gin.H{
+					"id":            ticketType.ID,
+					"name":          ticketType.Name,
+					"price":         ticketType.Price,
+					"nonDeductible": ticketType.NonDeductible,
+					"remaining":     ticketType.Capacity - data.ticketsHeld(event.ID, ticketType.ID, now),
+				})
+			}
+			upcoming = append(upcoming, gin.H{
+				"id":          event.ID,
+				"name":        event.Name,
+				"description": event.Description,
+				"location":    event.Location,
+				"starts":      event.Starts,
+				"ticketTypes": types,
+			})
+		}
+		c.JSON(200, upcoming)
+	})
+
+	router.POST("/events/:id/checkout", func(c *gin.Context) {
+		cfg := configs.current()
+		var checkout TicketCheckout
+		err := c.BindJSON(&checkout)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		if *checkout.Quantity < 1 {
+			c.String(http.StatusBadRequest, "Quantity must be at least 1")
+			return
+		}
+
+		var data eventData
+		err = dataStore().load(eventsDocument, &data)
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		event, ticketType, found := data.find(c.Param("id"), *checkout.TicketType)
+		if !found {
+			c.String(http.StatusNotFound, "No such event or ticket type")
+			return
+		}
+		order := TicketOrder{
+			ID:           newID(),
+			EventID:      event.ID,
+			TicketTypeID: ticketType.ID,
+			Quantity:     *checkout.Quantity,
+			Name:         *checkout.Name,
+			Email:        *checkout.Email,
+			Phone:        *checkout.Phone,
+		}
+		total := ticketType.Price * order.Quantity
+		if checkout.WaiverCode != nil && *checkout.WaiverCode != "" {
+			order.Discount, order.WaiverRedemptionID, err = reserveWaiver(*checkout.WaiverCode, WaiverContextEvents, order.ID, total)
+			if err != nil {
+				c.String(http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+
+		// The order holds its tickets before Stripe is called, so the store isn't locked while Stripe answers
+		err = dataStore().update(eventsDocument, &data, func() error {
+			order.Created = time.Now()
+			if data.ticketsHeld(event.ID, ticketType.ID, order.Created)+order.Quantity > ticketType.Capacity {
+				return errSoldOut
+			}
+			data.Orders = append(data.Orders, order)
+			return nil
+		})
+		var secret string
+		if err == nil && total > order.Discount {
+			card := "card"
+			var intent *stripe.PaymentIntent
+			intent, err = cfg.paymentIntents().New(&stripe.PaymentIntentParams{
+				Amount:             stripe.Int64(int64(total - order.Discount)),
+				Currency:           stripe.String(string(stripe.CurrencyUSD)),
+				Description:        stripe.String(EventTicketsDescriptionPrefix + " - " + strconv.Itoa(order.Quantity) + " x " + ticketType.Name + " - " + event.Name),
+				PaymentMethodTypes: []*string{&card},
+				ReceiptEmail:       stripe.String(order.Email),
+			})
+			if err == nil {
+				order.PaymentIntentID = intent.ID
+				secret = intent.ClientSecret
+				err = dataStore().update(eventsDocument, &data, func() error {
+					for i := range data.Orders {
+						if data.Orders[i].ID == order.ID {
+							data.Orders[i].PaymentIntentID = order.PaymentIntentID
+							return nil
+						}
+					}
+					return os.ErrNotExist
+				})
+			}
+			if err != nil {
+				removeTicketOrder(order.ID)
+			}
+		}
+		if err != nil {
+			releaseWaiver(order.WaiverRedemptionID)
+		}
+		if err == errSoldOut {
+			c.String(http.StatusConflict, "Not enough tickets remaining")
+			return
+		}
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+
+		// Fully waived orders have nothing to pay, so their tickets are issued right away
+		if order.PaymentIntentID == "" {
+			tickets, event, order, err := confirmTicketOrder(cfg, event.ID, order.ID)
+			if err != nil {
+				fmt.Println(err)
+				c.String(http.StatusInternalServerError, "Error")
+				return
+			}
+			c.JSON(200, gin.H{
+				"order": order.ID,
+				"paid":  true,
+			})
+			go sendTicketEmail(cfg, event, order, tickets)
+			return
+		}
+		c.JSON(200, gin.H{
+			"secret": secret,
+			"order":  order.ID,
+			"total":  total - order.Discount,
+		})
+	})
+
+	// Called by the page once Stripe reports the card payment succeeded. The PaymentIntent is checked server side
+	// before any tickets are issued.
+	router.POST("/events/:id/confirm", func(c *gin.Context) {
+		cfg := configs.current()
+		var body struct {
+			Order *string `json:"order" binding:"exists"`
+		}
+		err := c.BindJSON(&body)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		tickets, event, order, err := confirmTicketOrder(cfg, c.Param("id"), *body.Order)
+		if err == os.ErrNotExist {
+			c.String(http.StatusNotFound, "No such order")
+			return
+		}
+		if err == errSoldOut {
+			c.JSON(200, gin.H{
+				"success": false,
+				"soldOut": true,
+			})
+			return
+		}
+		if err != nil {
+			fmt.Println(err)
+			c.JSON(200, gin.H{
+				"success": false,
+			})
+			return
+		}
+		c.JSON(200, gin.H{
+			"success": true,
+		})
+		if tickets != nil {
+			go sendTicketEmail(cfg, event, order, tickets)
+		}
+	})
+
+	// Image for the QR code in ticket emails. The signed code in the path is all that's needed to see it.
+	router.GET("/tickets/:code/qr.png", func(c *gin.Context) {
+		code := c.Param("code")
+		payload, ok := verifyToken(code)
+		if !ok || !strings.HasPrefix(payload, "ticket:") {
+			c.String(http.StatusNotFound, "Not found")
+			return
+		}
+		qr, err := encodeQR([]byte(code))
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		image, err := qr.png(8)
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		c.Data(200, "image/png", image)
+	})
+
+	// Orders with the fee waivers applied to them, which coaches don't see
+	if board != nil {
+		board.GET("/events/orders", func(c *gin.Context) {
+			var data eventData
+			err := dataStore().load(eventsDocument, &data)
+			if err != nil {
+				fmt.Println(err)
+				c.String(http.StatusInternalServerError, "Error")
+				return
+			}
+			c.JSON(200, data.Orders)
+		})
+	}
+
+	// Door volunteers sign in with their own credentials, which only reach check-in and order fulfillment
+	if volunteer != nil {
+		volunteer.GET("/checkin", func(c *gin.Context) {
+			c.Data(200, "text/html; charset=utf-8", []byte(checkinPage))
+		})
+		volunteer.GET("/checkin.js", func(c *gin.Context) {
+			c.Data(200, "application/javascript", []byte(checkinScript))
+		})
+
+		// Marks a scanned ticket as used. A ticket that was already scanned is rejected with the time it was first used.
+		volunteer.POST("/checkin", func(c *gin.Context) {
+			var body struct {
+				Code *string `json:"code" binding:"exists"`
+			}
+			err := c.BindJSON(&body)
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+			payload, ok := verifyToken(strings.TrimSpace(*body.Code))
+			if !ok || !strings.HasPrefix(payload, "ticket:") {
+				c.JSON(200, gin.H{"ok": false, "message": "Invalid ticket"})
+				return
+			}
+			ticketID := strings.TrimPrefix(payload, "ticket:")
+
+			var data eventData
+			var ticket Ticket
+			var duplicate bool
+			err = dataStore().update(eventsDocument, &data, func() error {
+				for i := range data.Tickets {
+					if data.Tickets[i].ID == ticketID {
+						if data.Tickets[i].CheckedIn != nil {
+							duplicate = true
+							ticket = data.Tickets[i]
+							return errors.New("duplicate")
+						}
+						now := time.Now()
+						data.Tickets[i].CheckedIn = &now
+						ticket = data.Tickets[i]
+						return nil
+					}
+				}
+				return os.ErrNotExist
+			})
+			if duplicate {
+				c.JSON(200, gin.H{"ok": false, "message": "Already checked in at " + ticket.CheckedIn.In(calendar().location).Format(time.Kitchen), "holder": ticket.Holder})
+				return
+			}
+			if err == os.ErrNotExist {
+				c.JSON(200, gin.H{"ok": false, "message": "Unknown ticket"})
+				return
+			}
+			if err != nil {
+				fmt.Println(err)
+				c.String(http.StatusInternalServerError, "Error")
+				return
+			}
+			event, ticketType, _ := data.find(ticket.EventID, ticket.TicketTypeID)
+			c.JSON(200, gin.H{"ok": true, "message": "Welcome!", "holder": ticket.Holder, "event": event.Name, "ticketType": ticketType.Name})
+		})
+		fmt.Println("Ticket check-in for volunteers is at /volunteer/checkin.")
+	} else {
+		fmt.Println("Ticket check-in is disabled because volunteer credentials are not configured.")
+	}
+
+	if admin == nil {
+		fmt.Println("Event administration is disabled because admin credentials are not configured.")
+		return
+	}
+
+	admin.POST("/events", func(c *gin.Context) {
+		var event Event
+		err := c.BindJSON(&event)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		if event.ID == "" {
+			event.ID = newID()
+		}
+		for i := range event.TicketTypes {
+			if event.TicketTypes[i].ID == "" {
+				event.TicketTypes[i].ID = newID()
+			}
+			if event.TicketTypes[i].NonDeductible > event.TicketTypes[i].Price {
+				c.String(http.StatusBadRequest, "The non-deductible value of a ticket can't exceed its price")
+				return
+			}
+		}
+		var data eventData
+		err = dataStore().update(eventsDocument, &data, func() error {
+			for i := range data.Events {
+				if data.Events[i].ID == event.ID {
+					data.Events[i] = event
+					return nil
+				}
+			}
+			data.Events = append(data.Events, event)
+			return nil
+		})
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		c.JSON(200, event)
+	})
+
+	admin.GET("/events", func(c *gin.Context) {
+		var data eventData
+		err := dataStore().load(eventsDocument, &data)
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		data.Orders = ordersWithoutWaivers(data.Orders)
+		c.JSON(200, data)
+	})
+
+	fmt.Println("Event administration is available at /admin/events.")
+}
+
+func (data *eventData) find(eventID string, ticketTypeID string) (Event, TicketType, bool) {
+	for _, event := range data.Events {
+		if event.ID != eventID {
+			continue
+		}
+		for _, ticketType := range event.TicketTypes {
+			if ticketType.ID == ticketTypeID {
+				return event, ticketType, true
+			}
+		}
+	}
+	return Event{}, TicketType{}, false
+}
+
+// Orders as coaches see them at /admin, without the fee waivers only the board sees
+func ordersWithoutWaivers(orders []TicketOrder) []TicketOrder {
+	stripped := make([]TicketOrder, len(orders))
+	for i, order := range orders {
+		order.Discount = 0
+		order.WaiverRedemptionID = ""
+		stripped[i] = order
+	}
+	return stripped
+}
+
+// Paid tickets plus tickets in orders that are still within their hold
+func (data *eventData) ticketsHeld(eventID string, ticketTypeID string, now time.Time) int {
+	held := 0
+	for _, order := range data.Orders {
+		if order.EventID == eventID && order.TicketTypeID == ticketTypeID && !order.Oversold && (order.Paid || now.Sub(order.Created) < checkoutHoldDuration) {
+			held += order.Quantity
+		}
+	}
+	return held
+}
+
+// Takes back the tickets an order was holding when its checkout couldn't be finished
+func removeTicketOrder(orderID string) {
+	var data eventData
+	err := dataStore().update(eventsDocument, &data, func() error {
+		for i := range data.Orders {
+			if data.Orders[i].ID == orderID && !data.Orders[i].Paid {
+				data.Orders = append(data.Orders[:i], data.Orders[i+1:]...)
+				return nil
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Println(err)
+		fmt.Println("ERROR: TICKET ORDER " + orderID + " COULD NOT BE REMOVED. ITS TICKETS ARE HELD UNTIL THE HOLD EXPIRES.")
+	}
+}
+
+// Issues the tickets for an order once its PaymentIntent has succeeded. Stripe is asked before the store is
+// locked, and the order is checked again under the lock.
+// Returns nil tickets if the order had already been confirmed, so tickets are only emailed once, and errSoldOut
+// if it was paid after its hold expired and its tickets had gone to other buyers.
+func confirmTicketOrder(cfg *Config, eventID string, orderID string) ([]Ticket, Event, TicketOrder, error) {
+	var data eventData
+	err := dataStore().load(eventsDocument, &data)
+	if err != nil {
+		return nil, Event{}, TicketOrder{}, err
+	}
+	var order TicketOrder
+	found := false
+	for _, candidate := range data.Orders {
+		if candidate.ID == orderID && candidate.EventID == eventID {
+			order, found = candidate, true
+		}
+	}
+	if !found {
+		return nil, Event{}, TicketOrder{}, os.ErrNotExist
+	}
+	if !order.Paid && order.PaymentIntentID != "" {
+		err = paymentIntentSucceeded(cfg, order.PaymentIntentID)
+		if err != nil {
+			return nil, Event{}, TicketOrder{}, err
+		}
+	}
+	verifiedIntent := order.PaymentIntentID
+
+	var tickets []Ticket
+	var oversold bool
+	err = dataStore().update(eventsDocument, &data, func() error {
+		index := -1
+		for i := range data.Orders {
+			if data.Orders[i].ID == orderID && data.Orders[i].EventID == eventID {
+				index = i
+			}
+		}
+		if index < 0 {
+			return os.ErrNotExist
+		}
+		order = data.Orders[index]
+		if order.Paid {
+			return nil
+		}
+		if order.PaymentIntentID != verifiedIntent {
+			return errors.New("order " + order.ID + " changed while its payment was checked")
+		}
+		if order.PaymentIntentID == "" {
+			_, ticketType, _ := data.find(order.EventID, order.TicketTypeID)
+			if order.Discount < ticketType.Price*order.Quantity {
+				return errors.New("order " + order.ID + " has no payment")
+			}
+		}
+		// A checkout paid after its hold expired may find its tickets gone to other buyers
+		now := time.Now()
+		if now.Sub(order.Created) >= checkoutHoldDuration {
+			_, ticketType, _ := data.find(order.EventID, order.TicketTypeID)
+			oversold = data.ticketsHeld(order.EventID, order.TicketTypeID, now)+order.Quantity > ticketType.Capacity
+		}
+		data.Orders[index].Paid = true
+		data.Orders[index].Oversold = oversold
+		order = data.Orders[index]
+		if oversold {
+			return nil
+		}
+		for i := 0; i < order.Quantity; i++ {
+			tickets = append(tickets, Ticket{
+				ID:           newID(),
+				OrderID:      order.ID,
+				EventID:      order.EventID,
+				TicketTypeID: order.TicketTypeID,
+				Holder:       order.Name,
+				Issued:       time.Now(),
+			})
+		}
+		data.Tickets = append(data.Tickets, tickets...)
+		return nil
+	})
+	if err != nil {
+		return nil, Event{}, TicketOrder{}, err
+	}
+	event, ticketType, _ := data.find(order.EventID, order.TicketTypeID)
+	if oversold {
+		releaseWaiver(order.WaiverRedemptionID)
+		go sendOversoldEmail(order.ID, order.PaymentIntentID, order.Name, order.Email, "The "+ticketType.Name+" tickets for "+event.Name,
+			ticketType.Price*order.Quantity-order.Discount)
+	}
+	if order.Oversold {
+		return nil, event, order, errSoldOut
+	}
+	if tickets != nil {
+		confirmWaiver(order.WaiverRedemptionID)
+		paid := ticketType.Price*order.Quantity - order.Discount
+		if paid > 0 {
+			goods := ticketType.NonDeductible * order.Quantity
+			if goods > paid {
+				goods = paid
+			}
+			err = recordGifts(Gift{
+				DonorEmail:  order.Email,
+				DonorName:   order.Name,
+				Phone:       order.Phone,
+				Amount:      paid,
+				GoodsValue:  goods,
+				Date:        time.Now(),
+				Source:      GiftSourceEvent,
+				Description: strconv.Itoa(order.Quantity) + " x " + ticketType.Name + " - " + event.Name,
+				StripeID:    order.PaymentIntentID,
+			})
+			if err != nil {
+				fmt.Println(err)
+				fmt.Println("ERROR: TICKET ORDER " + order.ID + " COULD NOT BE RECORDED IN THE GIFT LEDGER")
+			}
+		}
+	}
+	return tickets, event, order, nil
+}
+
+func sendTicketEmail(cfg *Config, event Event, order TicketOrder, tickets []Ticket) {
+	_, ticketType, _ := (&eventData{Events: []Event{event}}).find(event.ID, order.TicketTypeID)
+
+	body := "<html><body style=\"font-family: 'Times New Roman', Times, serif;\"><p>" + html.EscapeString(order.Name) + ",</p>"
+	body += "<p>Thank you for supporting Pathfinders Robotics! Your tickets for <b>" + html.EscapeString(event.Name) + "</b> on " + event.Starts.In(calendar().location).Format("Monday, January 2, 2006 at 3:04 PM") + " at " + html.EscapeString(event.Location) + " are below. Please have them ready to be scanned at the door.</p>"
+	for i, ticket := range tickets {
+		code, err := signToken("ticket:" + ticket.ID)
+		if err != nil {
+			fmt.Println(err)
+			fmt.Println("ERROR: TICKET EMAIL COULD NOT BE GENERATED OR DELIVERED")
+			return
+		}
+		body += "<p><b>Ticket " + strconv.Itoa(i+1) + " of " + strconv.Itoa(len(tickets)) + "</b> - " + html.EscapeString(ticketType.Name) + "<br/>"
+		body += "<img src=\"" + SiteURL + "/tickets/" + code + "/qr.png\" alt=\"Ticket QR code\" width=\"200\" height=\"200\" /><br/>"
+		body += "<span style=\"font-family: monospace; font-size: 9pt;\">" + code + "</span></p>"
+	}
+	if order.Discount > 0 {
+		body += "<p>A fee waiver of $" + formatCents(order.Discount) + " was applied to this order.</p>"
+	}
+	body += "<p>" + ticketDeductibilityStatement(ticketType, (ticketType.Price*order.Quantity-order.Discount)/order.Quantity) + "</p>"
+	org, err := organizationProfile(cfg)
+	if err != nil {
+		fmt.Println(err)
+	}
+	if org.EIN != "" {
+		body += "<p>" + html.EscapeString(org.LegalName) + "<br/>Federal Tax ID " + html.EscapeString(org.EIN) + "</p>"
+	}
+	body += "</body></html>"
+
+	err = queueHTMLMail(MailAccountReceipts, "tickets", "order:"+order.ID, []string{order.Email}, "Your tickets for "+event.Name, body)
+	if err != nil {
+		fmt.Println(err)
+		fmt.Println("ERROR: TICKET EMAIL TO " + order.Email + " COULD NOT BE QUEUED")
+	}
+}
+
+// The IRS requires a written disclosure of the deductible portion when a payment is partly for goods or services.
+// paid is what the buyer actually paid per ticket, which is less than the price when a waiver code was used.
+func ticketDeductibilityStatement(ticketType TicketType, paid int) string {
+	if ticketType.NonDeductible <= 0 {
+		return "No goods or services were provided in exchange for this contribution of $" + formatCents(paid) + " per ticket."
+	}
+	deductible := paid - ticketType.NonDeductible
+	if deductible < 0 {
+		deductible = 0
+	}
+	return "Of the $" + formatCents(paid) + " paid per ticket, $" + formatCents(ticketType.NonDeductible) + " is the estimated value of goods and services provided. The remaining $" + formatCents(deductible) + " per ticket may be deductible as a charitable contribution."
+}
+
+const checkinPage string = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8" />
+<meta name="viewport" content="width=device-width, initial-scale=1.0" />
+<title>Ticket Check-In</title>
+</head>
+<body>
+<h1>Ticket Check-In</h1>
+<video id="camera" width="100%" playsinline muted></video>
+<form id="manual">
+<input id="code" type="text" placeholder="Ticket code" autocomplete="off" />
+<button type="submit">Check in</button>
+</form>
+<h2 id="result"></h2>
+<p id="detail"></p>
+<script src="/volunteer/checkin.js"></script>
+</body>
+</html>
+`
+
+// Scans with the browser's BarcodeDetector where the phone supports it. Codes can always be typed in by hand.
+const checkinScript string = `(function () {
+  var result = document.getElementById('result');
+  var detail = document.getElementById('detail');
+  var last = '';
+  var busy = false;
+
+  function checkIn(code) {
+    if (busy || code === last) return;
+    busy = true;
+    last = code;
+    fetch('/volunteer/checkin', {
+      method: 'POST',
+      credentials: 'same-origin',
+      headers: { 'Content-Type': 'application/json' },
+      body: JSON.stringify({ code: code })
+    }).then(function (res) { return res.json(); }).then(function (body) {
+      result.textContent = body.ok ? 'ADMIT' : 'REJECTED';
+      result.style.color = body.ok ? 'green' : 'red';
+      detail.textContent = body.message + (body.holder ? ' - ' + body.holder : '') + (body.ticketType ? ' (' + body.ticketType + ')' : '');
+    }).catch(function (err) {
+      result.textContent = 'ERROR';
+      detail.textContent = String(err);
+    }).then(function () { busy = false; });
+  }
+
+  document.getElementById('manual').addEventListener('submit', function (e) {
+    e.preventDefault();
+    last = '';
+    checkIn(document.getElementById('code').value.trim());
+  });
+
+  if (!('BarcodeDetector' in window) || !navigator.mediaDevices) {
+    detail.textContent = 'Camera scanning is not supported on this device. Type the code under the QR image instead.';
+    return;
+  }
+  var detector = new BarcodeDetector({ formats: ['qr_code'] });
+  var video = document.getElementById('camera');
+  navigator.mediaDevices.getUserMedia({ video: { facingMode: 'environment' } }).then(function (stream) {
+    video.srcObject = stream;
+    video.play();
+    setInterval(function () {
+      detector.detect(video).then(function (codes) {
+        if (codes.length > 0) checkIn(codes[0].rawValue);
+      });
+    }, 500);
+  });
+})();
+`
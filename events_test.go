@@ -0,0 +1,60 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLateTicketConfirmationDoesNotOversell(t *testing.T) {
+	useTestStore(t)
+	useTestStripe(t, map[string]string{"pi_late": succeededIntent("pi_late", 3000), "pi_room": succeededIntent("pi_room", 1500)})
+	live := false
+	cfg := &Config{Stripe: StripeConfig{Live: &live, DebugKey: "sk_test_x"}}
+	expired := time.Now().Add(-checkoutHoldDuration - time.Minute)
+	var data eventData
+	dataStore().update(eventsDocument, &data, func() error {
+		data.Events = []Event{
+			{ID: "dinner", Name: "Spaghetti Dinner", TicketTypes: []TicketType{{ID: "adult", Name: "Adult", Price: 1500, Capacity: 3}}},
+		}
+		data.Orders = []TicketOrder{
+			// Paid after its hold expired and one of its tickets went to the paid order below
+			{ID: "late", EventID: "dinner", TicketTypeID: "adult", Quantity: 2, Name: "Late Buyer", Email: "late@example.com", PaymentIntentID: "pi_late", Created: expired},
+			{ID: "paid", EventID: "dinner", TicketTypeID: "adult", Quantity: 2, Paid: true, Created: expired},
+			// Also expired, but there is still room for it once the late order is turned away
+			{ID: "room", EventID: "dinner", TicketTypeID: "adult", Quantity: 1, Email: "room@example.com", PaymentIntentID: "pi_room", Created: expired},
+		}
+		return nil
+	})
+
+	tests := []struct {
+		order   string
+		tickets int
+		err     error
+	}{
+		{"late", 0, errSoldOut},
+		{"late", 0, errSoldOut},
+		{"room", 1, nil},
+	}
+	for _, test := range tests {
+		tickets, _, order, err := confirmTicketOrder(cfg, "dinner", test.order)
+		if err != test.err || len(tickets) != test.tickets || !order.Paid {
+			t.Errorf("%s: expected %d tickets and error %v, got %d tickets, %v and %+v", test.order, test.tickets, test.err, len(tickets), err, order)
+		}
+	}
+
+	dataStore().load(eventsDocument, &data)
+	if len(data.Tickets) != 1 || data.Tickets[0].OrderID != "room" || data.ticketsHeld("dinner", "adult", time.Now()) != 3 {
+		t.Errorf("expected only the order with room to get tickets, got %+v", data.Tickets)
+	}
+	if !data.Orders[0].Oversold || data.Orders[2].Oversold {
+		t.Errorf("expected only the late order to be flagged for a refund, got %+v", data.Orders)
+	}
+	// Once, to the buyer and separately to finance, however often the page asks
+	oversold := waitForMail(t, "oversold", 2)
+	if len(oversold) != 2 || oversold[0].To[0] != "late@example.com" || oversold[1].To[0] != EmailFinance {
+		t.Errorf("expected the buyer and finance to be told about the refund, got %+v", oversold)
+	}
+	if gifts, _ := donorGifts("late@example.com"); len(gifts) != 0 {
+		t.Errorf("expected the refunded order not to be recorded as a gift, got %+v", gifts)
+	}
+}
@@ -0,0 +1,305 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stripe/stripe-go"
+)
+
+// The gift ledger: every donation the organization received, whichever way it came in.
+// Card gifts are pulled from Stripe, event tickets are added when their order is confirmed,
+// and checks, cash and bank drafts are entered by finance.
+
+type Gift struct {
+	ID          string    `json:"id"`
+	DonorEmail  string    `json:"donorEmail"`
+	DonorName   string    `json:"donorName"`
+	Addr1       string    `json:"addr1"`
+	Addr2       string    `json:"addr2"`
+	City        string    `json:"city"`
+	State       string    `json:"state"`
+	Zip         string    `json:"zip"`
+	Phone       string    `json:"phone"`
+	Amount      int       `json:"amount"`
+	GoodsValue  int       `json:"goodsValue"` // Value of goods or services the donor received in exchange, in cents
+	Date        time.Time `json:"date"`
+	Source      string    `json:"source"`
+	Description string    `json:"description"`
+	Team        string    `json:"team"`
+	StripeID    string    `json:"stripeId,omitempty"`
+	Note        string    `json:"note,omitempty"`
+}
+
+type giftData struct {
+	Gifts []Gift `json:"gifts"`
+}
+
+// Mailing details a donor keeps up to date from the portal. These take priority over the address on their latest gift.
+type Donor struct {
+	Email   string    `json:"email"`
+	Name    string    `json:"name"`
+	Addr1   string    `json:"addr1"`
+	Addr2   string    `json:"addr2"`
+	City    string    `json:"city"`
+	State   string    `json:"state"`
+	Zip     string    `json:"zip"`
+	Phone   string    `json:"phone"`
+	Updated time.Time `json:"updated"`
+
+	EmailProblem *EmailProblem `json:"emailProblem,omitempty"` // Set when mail to the donor bounces or they complain
+}
+
+type donorData struct {
+	Donors []Donor `json:"donors"`
+}
+
+const GiftSourceStripe string = "stripe"
+const GiftSourceOffline string = "offline"
+const GiftSourceRecurring string = "recurring"
+const GiftSourceEvent string = "event"
+
+const giftsDocument string = "gifts"
+const donorsDocument string = "donors"
+
+func donorKey(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// Adds gifts to the ledger, skipping any whose Stripe ID is already there, and starts or ends
+// the donors' stewardship sequences
+func recordGifts(gifts ...Gift) error {
+	var added []Gift
+	var data giftData
+	err := dataStore().update(giftsDocument, &data, func() error {
+		known := map[string]bool{}
+		for _, gift := range data.Gifts {
+			if gift.StripeID != "" {
+				known[gift.StripeID] = true
+			}
+		}
+		for _, gift := range gifts {
+			if gift.StripeID != "" && known[gift.StripeID] {
+				continue
+			}
+			if gift.ID == "" {
+				gift.ID = newID()
+			}
+			gift.DonorEmail = donorKey(gift.DonorEmail)
+			data.Gifts = append(data.Gifts, gift)
+			added = append(added, gift)
+			if gift.StripeID != "" {
+				known[gift.StripeID] = true
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	err = triggerSequences(added)
+	if err != nil {
+		// The gifts are recorded either way
+		fmt.Println(err)
+		fmt.Println("ERROR: STEWARDSHIP SEQUENCES COULD NOT BE UPDATED FOR NEW GIFTS")
+	}
+	return nil
+}
+
+// The donor's saved profile, or their details from the most recent of gifts (which must be sorted by date)
+func donorProfile(email string, gifts []Gift) Donor {
+	var data donorData
+	err := dataStore().load(donorsDocument, &data)
+	if err != nil {
+		fmt.Println(err)
+	}
+	for _, donor := range data.Donors {
+		if donor.Email == donorKey(email) {
+			return donor
+		}
+	}
+	donor := Donor{Email: donorKey(email)}
+	if len(gifts) > 0 {
+		latest := gifts[len(gifts)-1]
+		donor.Name, donor.Phone = latest.DonorName, latest.Phone
+		donor.Addr1, donor.Addr2, donor.City, donor.State, donor.Zip = latest.Addr1, latest.Addr2, latest.City, latest.State, latest.Zip
+	}
+	return donor
+}
+
+func saveDonorProfile(donor Donor) error {
+	donor.Email = donorKey(donor.Email)
+	donor.Updated = time.Now()
+	var data donorData
+	return dataStore().update(donorsDocument, &data, func() error {
+		for i := range data.Donors {
+			if data.Donors[i].Email == donor.Email {
+				// Only the bounce handling and finance change this, not the donor's own profile edits
+				donor.EmailProblem = data.Donors[i].EmailProblem
+				data.Donors[i] = donor
+				return nil
+			}
+		}
+		data.Donors = append(data.Donors, donor)
+		return nil
+	})
+}
+
+func giftFromPaymentData(data *PaymentData, source string, stripeID string) Gift {
+	_, team, _ := determineTeamEmail(data)
+	return Gift{
+		DonorEmail:  *data.Email,
+		DonorName:   *data.Name,
+		Addr1:       *data.Addr1,
+		Addr2:       *data.Addr2,
+		City:        *data.City,
+		State:       *data.State,
+		Zip:         *data.Zip,
+		Phone:       *data.Phone,
+		Amount:      *data.Amount,
+		Date:        time.Now(),
+		Source:      source,
+		Description: *data.Description,
+		Team:        team,
+		StripeID:    stripeID,
+	}
+}
+
+// Records a card donation in the ledger once Stripe has taken it. Merchandise, dues and tickets aren't gifts,
+// the same as for syncStripeGifts.
+func recordPaymentGift(data *PaymentData, chargeID string) {
+	if data.Description != nil && hasOwnConfirmation(*data.Description) {
+		return
+	}
+	err := recordGifts(giftFromPaymentData(data, GiftSourceStripe, chargeID))
+	if err != nil {
+		fmt.Println(err)
+		fmt.Println("ERROR: GIFT " + chargeID + " COULD NOT BE RECORDED IN THE GIFT LEDGER")
+	}
+}
+
+// Pulls the paid card gifts in [from, to) from Stripe into the ledger. Refunded amounts are subtracted,
+// and payments that get their own confirmation (merchandise, dues, tickets) are left out.
+func syncStripeGifts(cfg *Config, from time.Time, to time.Time) (int, error) {
+	params := &stripe.ChargeListParams{
+		CreatedRange: &stripe.RangeQueryParams{
+			GreaterThanOrEqual: from.Unix(),
+			LesserThan:         to.Unix(),
+		},
+	}
+	var gifts []Gift
+	iter := cfg.charges().List(params)
+	for iter.Next() {
+		ch := iter.Charge()
+		if !ch.Paid || ch.Amount-ch.AmountRefunded <= 0 || hasOwnConfirmation(ch.Description) {
+			continue
+		}
+		gift := Gift{
+			DonorEmail:  ch.ReceiptEmail,
+			Amount:      int(ch.Amount - ch.AmountRefunded),
+			Date:        time.Unix(ch.Created, 0),
+			Source:      GiftSourceStripe,
+			Description: ch.Description,
+			StripeID:    ch.ID,
+		}
+		if ch.Invoice != nil {
+			gift.Source = GiftSourceRecurring
+		}
+		if ch.Shipping != nil {
+			gift.DonorName = ch.Shipping.Name
+			gift.Phone = ch.Shipping.Phone
+			if ch.Shipping.Address != nil {
+				gift.Addr1 = ch.Shipping.Address.Line1
+				gift.Addr2 = ch.Shipping.Address.Line2
+				gift.City = ch.Shipping.Address.City
+				gift.State = ch.Shipping.Address.State
+				gift.Zip = ch.Shipping.Address.PostalCode
+			}
+		}
+		description := ch.Description
+		_, gift.Team, _ = determineTeamEmail(&PaymentData{Description: &description})
+		gifts = append(gifts, gift)
+	}
+	if iter.Err() != nil {
+		return 0, iter.Err()
+	}
+	return len(gifts), recordGifts(gifts...)
+}
+
+// Gifts made in [from, to), grouped by donor email and sorted by date
+func giftsByDonor(from time.Time, to time.Time) (map[string][]Gift, error) {
+	var data giftData
+	err := dataStore().load(giftsDocument, &data)
+	if err != nil {
+		return nil, err
+	}
+	donors := map[string][]Gift{}
+	for _, gift := range data.Gifts {
+		if gift.Date.Before(from) || !gift.Date.Before(to) || gift.DonorEmail == "" {
+			continue
+		}
+		donors[gift.DonorEmail] = append(donors[gift.DonorEmail], gift)
+	}
+	for email := range donors {
+		gifts := donors[email]
+		sort.Slice(gifts, func(i, j int) bool { return gifts[i].Date.Before(gifts[j].Date) })
+	}
+	return donors, nil
+}
+
+func registerGiftRoutes(admin *gin.RouterGroup) {
+	// For checks, cash and bank drafts that never go through Stripe
+	admin.POST("/gifts", func(c *gin.Context) {
+		var gift Gift
+		err := c.BindJSON(&gift)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		if gift.DonorEmail == "" || gift.Amount <= 0 || gift.Date.IsZero() {
+			c.String(http.StatusBadRequest, "A gift needs a donor email, an amount and a date")
+			return
+		}
+		if gift.Source != GiftSourceRecurring {
+			gift.Source = GiftSourceOffline
+		}
+		gift.ID = newID()
+		gift.StripeID = ""
+		err = recordGifts(gift)
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		c.JSON(200, gift)
+	})
+
+	admin.GET("/gifts", func(c *gin.Context) {
+		// ?fiscal=true reports the fiscal year instead of the calendar year
+		fiscal := c.Query("fiscal") == "true"
+		year, err := strconv.Atoi(c.DefaultQuery("year", strconv.Itoa(calendar().now().Year())))
+		if fiscal {
+			year, err = strconv.Atoi(c.DefaultQuery("year", strconv.Itoa(calendar().fiscalYearOf(time.Now()))))
+		}
+		if err != nil {
+			c.String(http.StatusBadRequest, "Invalid year")
+			return
+		}
+		from, to := calendar().calendarYear(year)
+		if fiscal {
+			from, to = calendar().fiscalYear(year)
+		}
+		donors, err := giftsByDonor(from, to)
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		c.JSON(200, donors)
+	})
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bufio"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Just enough of IMAP4rev1 (RFC 3501) to read new messages from one mailbox and mark them seen
+
+type imapClient struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	tag    int
+}
+
+// An untagged response line, with any literals it contained
+type imapResponse struct {
+	line     string
+	literals [][]byte
+}
+
+func dialIMAP(address string, useTLS bool, timeout time.Duration) (*imapClient, error) {
+	dialer := &net.Dialer{Timeout: timeout}
+	var conn net.Conn
+	var err error
+	if useTLS {
+		host, _, _ := net.SplitHostPort(address)
+		conn, err = tls.DialWithDialer(dialer, "tcp", address, &tls.Config{ServerName: host})
+	} else {
+		conn, err = dialer.Dial("tcp", address)
+	}
+	if err != nil {
+		return nil, err
+	}
+	conn.SetDeadline(time.Now().Add(timeout))
+	client := &imapClient{conn: conn, reader: bufio.NewReader(conn)}
+	greeting, err := client.readResponse()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if !strings.HasPrefix(greeting.line, "* OK") {
+		conn.Close()
+		return nil, errors.New("IMAP server greeting: " + greeting.line)
+	}
+	return client, nil
+}
+
+func (client *imapClient) close() error {
+	return client.conn.Close()
+}
+
+// Sends a command and returns its untagged responses, or an error if it doesn't complete with OK
+func (client *imapClient) command(command string) ([]imapResponse, error) {
+	client.tag++
+	tag := "A" + strconv.Itoa(client.tag)
+	_, err := io.WriteString(client.conn, tag+" "+command+"\r\n")
+	if err != nil {
+		return nil, err
+	}
+	var untagged []imapResponse
+	for {
+		response, err := client.readResponse()
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasPrefix(response.line, tag+" ") {
+			status := strings.TrimPrefix(response.line, tag+" ")
+			if !strings.HasPrefix(status, "OK") {
+				return nil, errors.New("IMAP " + strings.SplitN(command, " ", 2)[0] + ": " + status)
+			}
+			return untagged, nil
+		}
+		untagged = append(untagged, response)
+	}
+}
+
+// Reads one response line, following any {n} literals to the real end of the line
+func (client *imapClient) readResponse() (imapResponse, error) {
+	var response imapResponse
+	for {
+		line, err := client.reader.ReadString('\n')
+		if err != nil {
+			return response, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		response.line += line
+		open := strings.LastIndex(line, "{")
+		if open < 0 || !strings.HasSuffix(line, "}") {
+			return response, nil
+		}
+		size, err := strconv.Atoi(line[open+1 : len(line)-1])
+		if err != nil {
+			return response, nil
+		}
+		literal := make([]byte, size)
+		_, err = io.ReadFull(client.reader, literal)
+		if err != nil {
+			return response, err
+		}
+		response.literals = append(response.literals, literal)
+	}
+}
+
+func (client *imapClient) login(username string, password string) error {
+	_, err := client.command("LOGIN " + imapQuote(username) + " " + imapQuote(password))
+	return err
+}
+
+func (client *imapClient) selectMailbox(mailbox string) error {
+	_, err := client.command("SELECT " + imapQuote(mailbox))
+	return err
+}
+
+func (client *imapClient) searchUnseen() ([]string, error) {
+	responses, err := client.command("UID SEARCH UNSEEN")
+	if err != nil {
+		return nil, err
+	}
+	var uids []string
+	for _, response := range responses {
+		if strings.HasPrefix(response.line, "* SEARCH") {
+			uids = append(uids, strings.Fields(strings.TrimPrefix(response.line, "* SEARCH"))...)
+		}
+	}
+	return uids, nil
+}
+
+// The whole message, without marking it seen
+func (client *imapClient) fetch(uid string) ([]byte, error) {
+	responses, err := client.command("UID FETCH " + uid + " (BODY.PEEK[])")
+	if err != nil {
+		return nil, err
+	}
+	for _, response := range responses {
+		if strings.Contains(response.line, "FETCH") && len(response.literals) > 0 {
+			return response.literals[0], nil
+		}
+	}
+	return nil, errors.New("IMAP FETCH: no message with UID " + uid)
+}
+
+func (client *imapClient) markSeen(uid string) error {
+	_, err := client.command("UID STORE " + uid + " +FLAGS.SILENT (\\Seen)")
+	return err
+}
+
+func (client *imapClient) logout() {
+	client.command("LOGOUT")
+	client.close()
+}
+
+func imapQuote(s string) string {
+	return "\"" + strings.Replace(strings.Replace(s, "\\", "\\\\", -1), "\"", "\\\"", -1) + "\""
+}
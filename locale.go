@@ -0,0 +1,169 @@
+package main
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Donor languages. A donation's locale comes from the donation form, or the browser's Accept-Language header
+// when the form doesn't send one, and is kept with the donor's contact preferences for later emails.
+// English ("", or "en") is the default and the fallback whenever a translation is missing: a template in
+// templates/<locale>/ is used when it exists, and otherwise the English one with English dates and amounts.
+
+const LocaleEnglish string = "en"
+const LocaleSpanish string = "es"
+
+// The locales with translations, besides English
+var translatedLocales = []string{LocaleSpanish}
+
+// A supported locale for a tag like "es-MX", or "" for English and anything unsupported
+func normalizeLocale(tag string) string {
+	primary := strings.ToLower(strings.TrimSpace(strings.SplitN(strings.SplitN(tag, "-", 2)[0], "_", 2)[0]))
+	for _, locale := range translatedLocales {
+		if primary == locale {
+			return locale
+		}
+	}
+	return ""
+}
+
+// The supported locale the browser prefers most, or "" when English comes first or nothing is supported
+func acceptLanguageLocale(header string) string {
+	type choice struct {
+		tag     string
+		quality float64
+	}
+	var choices []choice
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		tag := strings.TrimSpace(fields[0])
+		if tag == "" {
+			continue
+		}
+		quality := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				parsed, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64)
+				if err == nil {
+					quality = parsed
+				}
+			}
+		}
+		choices = append(choices, choice{tag, quality})
+	}
+	sort.SliceStable(choices, func(i, j int) bool { return choices[i].quality > choices[j].quality })
+	for _, choice := range choices {
+		primary := strings.ToLower(strings.SplitN(choice.tag, "-", 2)[0])
+		if choice.quality <= 0 {
+			continue
+		}
+		if primary == LocaleEnglish {
+			return ""
+		}
+		if locale := normalizeLocale(primary); locale != "" {
+			return locale
+		}
+	}
+	return ""
+}
+
+// The locale the form asked for, or else the browser's
+func requestLocale(c *gin.Context, fromForm *string) string {
+	if fromForm != nil && strings.TrimSpace(*fromForm) != "" {
+		return normalizeLocale(*fromForm)
+	}
+	return acceptLanguageLocale(c.GetHeader("Accept-Language"))
+}
+
+var spanishMonths = []string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"}
+
+// A date as printed on receipts, e.g. "March 4, 2026" or "4 de marzo de 2026"
+func formatDateLocale(t time.Time, locale string) string {
+	if locale == LocaleSpanish {
+		t = t.In(calendar().location)
+		return strconv.Itoa(t.Day()) + " de " + spanishMonths[t.Month()-1] + " de " + strconv.Itoa(t.Year())
+	}
+	return calendar().formatDate(t)
+}
+
+// An amount without the currency sign, e.g. "1234.50" or "1.234,50"
+func formatCentsLocale(cents int, locale string) string {
+	if locale != LocaleSpanish {
+		return formatCents(cents)
+	}
+	negative := cents < 0
+	if negative {
+		cents = -cents
+	}
+	whole := strconv.Itoa(cents / 100)
+	for i := len(whole) - 3; i > 0; i -= 3 {
+		whole = whole[:i] + "." + whole[i:]
+	}
+	formatted := whole + "," + strconv.Itoa(cents%100/10) + strconv.Itoa(cents%10)
+	if negative {
+		formatted = "-" + formatted
+	}
+	return formatted
+}
+
+// Text the server writes itself, such as the PDF receipt and the footer of donor updates, by locale.
+// Missing translations use the English text.
+var localeText = map[string]map[string]string{
+	LocaleSpanish: {
+		"pdf.thanks":            "Muchas gracias por su generosa donación de %s a la organización Pathfinders Robotics, recibida el %s.",
+		"pdf.support":           "Su donación nos ayudará a apoyar a %s en FIRST® %s.",
+		"pdf.thanksAgain":       "Gracias de nuevo por su generosidad y apoyo.",
+		"pdf.closing":           "Atentamente,",
+		"pdf.heading":           "Recibo de donación - Consérvelo para sus registros",
+		"pdf.donor":             "Donante: ",
+		"pdf.dateReceived":      "Fecha de recepción: ",
+		"pdf.cash":              "Contribución en efectivo: ",
+		"pdf.taxID":             "Número de identificación fiscal federal ",
+		"pdf.number":            "Recibo n.º ",
+		"pdf.issued":            "Recibo %s emitido el %s",
+		"update.footer":         "Recibe este mensaje porque pidió noticias de %s. <a href=\"%s\">Elija de qué equipos recibe noticias</a> o <a href=\"%s\">cancele la suscripción</a>.",
+		"currency":              "%s US$",
+		"receipt.subject":       "Recibo de donación de Pathfinders Robotics",
+		"receipt.duplicate":     "Recibo de donación de Pathfinders Robotics (duplicado)",
+		"receipt.corrected":     "Recibo de donación corregido de Pathfinders Robotics",
+		"receipt.attachment":    "Recibo de Pathfinders Robotics %s.pdf",
+		"receipt.duplicateNote": "<b>DUPLICADO</b> - Copia del recibo %s emitido el %s.",
+		"receipt.correctedNote": "<b>RECIBO CORREGIDO</b> - Reemplaza el recibo %s emitido el %s.",
+		"refund.subject":        "Reembolso de su donación a Pathfinders Robotics",
+	},
+}
+
+var englishText = map[string]string{
+	"pdf.thanks":            "Thank you so much for your very generous donation of %s to the Pathfinders Robotics organization received on %s.",
+	"pdf.support":           "Your donation will help us in supporting %s in FIRST® %s.",
+	"pdf.thanksAgain":       "Thanks again for your generosity and support.",
+	"pdf.closing":           "Respectfully,",
+	"pdf.heading":           "Donation receipt - Keep for your records",
+	"pdf.donor":             "Donor: ",
+	"pdf.dateReceived":      "Date Received: ",
+	"pdf.cash":              "Cash Contribution: ",
+	"pdf.taxID":             "Federal Tax ID ",
+	"pdf.number":            "Receipt No. ",
+	"pdf.issued":            "Receipt %s issued %s",
+	"update.footer":         "You're receiving this because you asked for updates from %s. <a href=\"%s\">Choose which teams you hear from</a> or <a href=\"%s\">unsubscribe</a>.",
+	"currency":              "$%s",
+	"receipt.subject":       "Pathfinders Robotics Donation Receipt",
+	"receipt.duplicate":     "Pathfinders Robotics Donation Receipt (Duplicate)",
+	"receipt.corrected":     "Corrected Pathfinders Robotics Donation Receipt",
+	"receipt.attachment":    "Pathfinders Robotics Receipt %s.pdf",
+	"receipt.duplicateNote": "<b>DUPLICATE</b> - A copy of receipt %s issued %s.",
+	"receipt.correctedNote": "<b>CORRECTED RECEIPT</b> - This replaces receipt %s issued %s.",
+	"refund.subject":        "Your Pathfinders Robotics Donation Was Refunded",
+}
+
+func translate(locale string, key string) string {
+	if text, found := localeText[locale][key]; found {
+		return text
+	}
+	return englishText[key]
+}
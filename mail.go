@@ -0,0 +1,108 @@
+package main
+
+import (
+	"time"
+)
+
+// Everything needed to send as one of the mailboxes, whichever transport is used
+type smtpSettings struct {
+	Account       string // MailAccountWebServer or MailAccountReceipts
+	ServerAddress string
+	ServerPort    string
+	Username      string
+	Password      string
+
+	Transport      string
+	TLSMode        string // 'SMTP_TLS', "" to choose by port
+	Timeout        time.Duration
+	APIURL         string
+	APIKey         string
+	Dir            string // For the file transport
+	DKIMSelectors  string
+	DKIMPrivateKey string
+}
+
+// The settings for a mailbox, whether or not they are complete
+func (cfg *Config) mailAccount(account string) smtpSettings {
+	mail := cfg.Mail
+	settings := smtpSettings{
+		Account:        account,
+		ServerAddress:  mail.SMTPServerAddress,
+		ServerPort:     mail.SMTPServerPort,
+		Username:       mail.WebServerUsername,
+		Password:       mail.WebServerPassword,
+		Transport:      mail.Transport,
+		TLSMode:        mail.SMTPTLS,
+		Timeout:        cfg.mailTimeout(),
+		APIURL:         mail.APIURL,
+		APIKey:         mail.APIKey,
+		Dir:            mail.Dir,
+		DKIMSelectors:  mail.DKIMWebServerSelector,
+		DKIMPrivateKey: mail.DKIMWebServerKey,
+	}
+	if account == MailAccountReceipts {
+		settings.Username, settings.Password = mail.ReceiptsUsername, mail.ReceiptsPassword
+		settings.DKIMSelectors, settings.DKIMPrivateKey = mail.DKIMReceiptsSelector, mail.DKIMReceiptsKey
+	}
+	return settings
+}
+
+// The settings for sending as a mailbox. The server and password are only required for the smtp transport.
+func (cfg *Config) mailAccountSettings(account string) (smtpSettings, error) {
+	settings := cfg.mailAccount(account)
+	usernameVar, passwordVar := "WEBSERVER_EMAIL_USERNAME", "WEBSERVER_EMAIL_PASSWORD"
+	if account == MailAccountReceipts {
+		usernameVar, passwordVar = "DONATION_RECEIPTS_EMAIL_USERNAME", "DONATION_RECEIPTS_EMAIL_PASSWORD"
+	}
+	required := map[string]string{usernameVar: settings.Username}
+	if settings.Transport == MailTransportSMTP {
+		required["SmtpServerAddress"] = settings.ServerAddress
+		required["SmtpServerPort"] = settings.ServerPort
+		required[passwordVar] = settings.Password
+	}
+	for name, val := range required {
+		if val == "" {
+			return smtpSettings{}, &osEnvVarError{"ERROR: '" + name + "' ENVIRONMENT VARIABLE UNAVAILABLE"}
+		}
+	}
+	return settings, nil
+}
+
+// SMTP settings for sending as the web server's own mailbox
+func (cfg *Config) webServerSMTP() (smtpSettings, error) {
+	return cfg.mailAccountSettings(MailAccountWebServer)
+}
+
+// SMTP settings for sending as the donation receipts mailbox
+func (cfg *Config) donationReceiptsSMTP() (smtpSettings, error) {
+	return cfg.mailAccountSettings(MailAccountReceipts)
+}
+
+// Sends from the settings' mailbox unless the message already has a From address, DKIM signed when the mailbox has keys
+func sendMail(settings smtpSettings, msg *mailMessage) error {
+	if msg.From == "" {
+		msg.From = settings.Username
+	}
+	if msg.FromName == "" {
+		msg.FromName = "Pathfinders Robotics"
+	}
+	body, err := msg.bytes()
+	if err != nil {
+		return err
+	}
+	keys, err := dkimKeys(settings)
+	if err != nil {
+		return err
+	}
+	if len(keys) > 0 {
+		body, err = dkimSign(body, addressDomain(msg.From), keys, time.Now())
+		if err != nil {
+			return err
+		}
+	}
+	mailer, err := newMailer(settings)
+	if err != nil {
+		return err
+	}
+	return mailer.Send(msg.From, msg.recipients(), body)
+}
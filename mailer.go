@@ -0,0 +1,198 @@
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// How mail leaves the server, chosen with 'MAIL_TRANSPORT':
+//   smtp (default)  SmtpServerAddress and SmtpServerPort, with 'SMTP_TLS' set to starttls, tls (implicit, the default
+//                   on port 465) or none (local test servers only)
+//   http            POSTs each message to 'MAIL_API_URL' with 'MAIL_API_KEY' as a bearer token
+//   file            Writes each message into the maildir at 'MAIL_DIR' (default ./maildir), for development
+// 'SMTP_TIMEOUT' and the HTTP timeout are in seconds and default to 30.
+
+type Mailer interface {
+	// Delivers a complete message to every envelope recipient, including blind copies
+	Send(from string, recipients []string, message []byte) error
+}
+
+const MailTransportSMTP string = "smtp"
+const MailTransportHTTP string = "http"
+const MailTransportFile string = "file"
+
+// The mailer for a mailbox. Only the smtp transport uses the mailbox password and server.
+func newMailer(settings smtpSettings) (Mailer, error) {
+	switch settings.Transport {
+	case MailTransportSMTP:
+		mode := strings.ToLower(settings.TLSMode)
+		if mode == "" {
+			mode = "starttls"
+			if settings.ServerPort == "465" {
+				mode = "tls"
+			}
+		}
+		if mode != "starttls" && mode != "tls" && mode != "none" {
+			return nil, &osEnvVarError{"ERROR: 'SMTP_TLS' MUST BE 'starttls', 'tls' OR 'none'"}
+		}
+		return &smtpMailer{settings: settings, tlsMode: mode, timeout: settings.Timeout}, nil
+	case MailTransportHTTP:
+		if settings.APIURL == "" || settings.APIKey == "" {
+			return nil, &osEnvVarError{"ERROR: 'MAIL_API_URL' AND 'MAIL_API_KEY' ENVIRONMENT VARIABLES ARE REQUIRED FOR THE HTTP MAIL TRANSPORT"}
+		}
+		return &httpMailer{url: settings.APIURL, key: settings.APIKey, client: &http.Client{Timeout: settings.Timeout}}, nil
+	case MailTransportFile:
+		return &maildirMailer{dir: settings.Dir}, nil
+	}
+	return nil, &osEnvVarError{"ERROR: 'MAIL_TRANSPORT' MUST BE 'smtp', 'http' OR 'file'"}
+}
+
+type smtpMailer struct {
+	settings smtpSettings
+	tlsMode  string
+	timeout  time.Duration
+}
+
+// Like smtp.SendMail, but with a deadline on the whole conversation and STARTTLS required rather than opportunistic
+func (mailer *smtpMailer) Send(from string, recipients []string, message []byte) error {
+	host := mailer.settings.ServerAddress
+	address := net.JoinHostPort(host, mailer.settings.ServerPort)
+	dialer := &net.Dialer{Timeout: mailer.timeout}
+	tlsConfig := &tls.Config{ServerName: host}
+	var conn net.Conn
+	var err error
+	if mailer.tlsMode == "tls" {
+		conn, err = tls.DialWithDialer(dialer, "tcp", address, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", address)
+	}
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Now().Add(mailer.timeout))
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if mailer.tlsMode == "starttls" {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return &osEnvVarError{"ERROR: SMTP SERVER " + address + " DOES NOT SUPPORT STARTTLS. SET 'SMTP_TLS' TO 'tls' FOR IMPLICIT TLS"}
+		}
+		err = client.StartTLS(tlsConfig)
+		if err != nil {
+			return err
+		}
+	}
+	if ok, _ := client.Extension("AUTH"); ok && mailer.settings.Password != "" {
+		// PlainAuth itself refuses to send the password over an unencrypted connection to anything but localhost
+		err = client.Auth(smtp.PlainAuth("", mailer.settings.Username, mailer.settings.Password, host))
+		if err != nil {
+			return err
+		}
+	}
+	err = client.Mail(from)
+	if err != nil {
+		return err
+	}
+	for _, recipient := range recipients {
+		err = client.Rcpt(recipient)
+		if err != nil {
+			return err
+		}
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(message)
+	if err != nil {
+		return err
+	}
+	err = writer.Close()
+	if err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// A transactional mail API taking the raw message. The request body is {"from": address, "to": [addresses],
+// "raw": base64 of the whole message}, and any 2xx response means the message was accepted.
+type httpMailer struct {
+	url    string
+	key    string
+	client *http.Client
+}
+
+type mailAPIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (err *mailAPIError) Error() string {
+	return "mail API returned " + strconv.Itoa(err.StatusCode) + ": " + err.Body
+}
+
+func (mailer *httpMailer) Send(from string, recipients []string, message []byte) error {
+	body, err := json.Marshal(map[string]interface{}{"from": from, "to": recipients, "raw": base64.StdEncoding.EncodeToString(message)})
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequest("POST", mailer.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Authorization", "Bearer "+mailer.key)
+	response, err := mailer.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		detail, _ := ioutil.ReadAll(io.LimitReader(response.Body, 1024))
+		return &mailAPIError{StatusCode: response.StatusCode, Body: strings.TrimSpace(string(detail))}
+	}
+	return nil
+}
+
+// Delivers into a maildir (tmp/, new/, cur/) any mail client can open. The envelope is recorded in
+// Return-Path and X-Envelope-To headers so blind copies can be checked too.
+type maildirMailer struct {
+	dir string
+}
+
+func (mailer *maildirMailer) Send(from string, recipients []string, message []byte) error {
+	for _, sub := range []string{"tmp", "new", "cur"} {
+		err := os.MkdirAll(filepath.Join(mailer.dir, sub), 0700)
+		if err != nil {
+			return err
+		}
+	}
+	hostname, _ := os.Hostname()
+	name := strconv.FormatInt(time.Now().UnixNano(), 10) + "." + newID() + "." + strings.Replace(hostname, "/", "_", -1)
+	var out bytes.Buffer
+	out.WriteString("Return-Path: <" + from + ">\r\n")
+	out.WriteString("X-Envelope-To: " + strings.Join(recipients, ", ") + "\r\n")
+	out.Write(message)
+	tmp := filepath.Join(mailer.dir, "tmp", name)
+	err := ioutil.WriteFile(tmp, out.Bytes(), 0600)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(mailer.dir, "new", name))
+}
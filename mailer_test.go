@@ -0,0 +1,218 @@
+package main
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// What the scripted SMTP server was told in one conversation
+type smtpSession struct {
+	commands []string
+	data     string
+}
+
+// Accepts one SMTP conversation on a local port, advertising extensions after EHLO, and sends what it was told
+// on the channel when the client quits or hangs up
+func serveSMTP(t *testing.T, extensions ...string) (string, string, <-chan smtpSession) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	sessions := make(chan smtpSession, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		var session smtpSession
+		defer func() { sessions <- session }()
+		reader := bufio.NewReader(conn)
+		reply := func(lines ...string) {
+			for _, line := range lines {
+				conn.Write([]byte(line + "\r\n"))
+			}
+		}
+		reply("220 test.example ESMTP")
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			command := strings.TrimRight(line, "\r\n")
+			session.commands = append(session.commands, command)
+			verb := strings.ToUpper(strings.SplitN(command, " ", 2)[0])
+			switch verb {
+			case "EHLO":
+				lines := []string{"250-test.example"}
+				for _, extension := range extensions {
+					lines = append(lines, "250-"+extension)
+				}
+				reply(append(lines, "250 8BITMIME")...)
+			case "AUTH":
+				reply("235 2.7.0 Authentication successful")
+			case "MAIL", "RCPT":
+				reply("250 2.1.0 OK")
+			case "DATA":
+				reply("354 End data with <CR><LF>.<CR><LF>")
+				var data strings.Builder
+				for {
+					line, err := reader.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if line == ".\r\n" {
+						break
+					}
+					data.WriteString(line)
+				}
+				session.data = data.String()
+				reply("250 2.0.0 Queued")
+			case "QUIT":
+				reply("221 2.0.0 Bye")
+				return
+			default:
+				reply("502 5.5.2 Command not recognized")
+			}
+		}
+	}()
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	return host, port, sessions
+}
+
+func TestSMTPMailerDeliversToEveryRecipient(t *testing.T) {
+	host, port, sessions := serveSMTP(t, "AUTH PLAIN")
+	mailer, err := newMailer(smtpSettings{Transport: MailTransportSMTP, TLSMode: "none", ServerAddress: host, ServerPort: port,
+		Username: "receipts@example.org", Password: "secret", Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	message := []byte("Subject: Test\r\n\r\nHello\r\n")
+	err = mailer.Send("receipts@example.org", []string{"donor@example.com", "finance@example.org"}, message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	session := <-sessions
+	expected := []string{
+		"AUTH PLAIN " + base64.StdEncoding.EncodeToString([]byte("\x00receipts@example.org\x00secret")),
+		"MAIL FROM:<receipts@example.org>",
+		"RCPT TO:<donor@example.com>",
+		"RCPT TO:<finance@example.org>",
+		"DATA",
+		"QUIT",
+	}
+	commands := strings.Join(session.commands, "\n")
+	for _, command := range expected {
+		if !strings.Contains(commands, command) {
+			t.Errorf("expected %q in the conversation:\n%s", command, commands)
+		}
+	}
+	if session.data != string(message) {
+		t.Errorf("expected the message %q, got %q", message, session.data)
+	}
+}
+
+func TestSMTPMailerRequiresSTARTTLS(t *testing.T) {
+	host, port, sessions := serveSMTP(t)
+	mailer, err := newMailer(smtpSettings{Transport: MailTransportSMTP, TLSMode: "starttls", ServerAddress: host, ServerPort: port, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = mailer.Send("receipts@example.org", []string{"donor@example.com"}, []byte("Subject: Test\r\n\r\nHello\r\n"))
+	if err == nil || !strings.Contains(err.Error(), "STARTTLS") {
+		t.Fatalf("expected an error about STARTTLS, got %v", err)
+	}
+	session := <-sessions
+	for _, command := range session.commands {
+		if strings.HasPrefix(command, "MAIL") {
+			t.Errorf("the message was sent without STARTTLS: %v", session.commands)
+		}
+	}
+}
+
+func TestHTTPMailerPostsTheRawMessage(t *testing.T) {
+	var request struct {
+		From string   `json:"from"`
+		To   []string `json:"to"`
+		Raw  string   `json:"raw"`
+	}
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&request)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	mailer, err := newMailer(smtpSettings{Transport: MailTransportHTTP, APIURL: server.URL, APIKey: "key", Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	message := []byte("Subject: Test\r\n\r\nHello\r\n")
+	err = mailer.Send("receipts@example.org", []string{"donor@example.com", "finance@example.org"}, message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if authorization != "Bearer key" {
+		t.Errorf("expected the API key as a bearer token, got %q", authorization)
+	}
+	raw, _ := base64.StdEncoding.DecodeString(request.Raw)
+	if request.From != "receipts@example.org" || strings.Join(request.To, ",") != "donor@example.com,finance@example.org" || string(raw) != string(message) {
+		t.Errorf("unexpected request: %+v", request)
+	}
+}
+
+func TestHTTPMailerReportsRejections(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid recipient", http.StatusUnprocessableEntity)
+	}))
+	defer server.Close()
+
+	mailer, err := newMailer(smtpSettings{Transport: MailTransportHTTP, APIURL: server.URL, APIKey: "key", Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = mailer.Send("receipts@example.org", []string{"nobody"}, []byte("Subject: Test\r\n\r\nHello\r\n"))
+	apiErr, ok := err.(*mailAPIError)
+	if !ok || apiErr.StatusCode != http.StatusUnprocessableEntity || apiErr.Body != "invalid recipient" {
+		t.Fatalf("expected a mailAPIError with the status and body, got %v", err)
+	}
+}
+
+func TestMaildirMailerDeliversIntoNew(t *testing.T) {
+	dir := t.TempDir()
+	mailer, err := newMailer(smtpSettings{Transport: MailTransportFile, Dir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	message := []byte("Subject: Test\r\n\r\nHello\r\n")
+	err = mailer.Send("receipts@example.org", []string{"donor@example.com", "finance@example.org"}, message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	delivered, _ := filepath.Glob(filepath.Join(dir, "new", "*"))
+	if len(delivered) != 1 {
+		t.Fatalf("expected one message in new/, found %v", delivered)
+	}
+	if pending, _ := filepath.Glob(filepath.Join(dir, "tmp", "*")); len(pending) != 0 {
+		t.Errorf("expected tmp/ to be empty, found %v", pending)
+	}
+	contents, err := ioutil.ReadFile(delivered[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "Return-Path: <receipts@example.org>\r\nX-Envelope-To: donor@example.com, finance@example.org\r\n" + string(message)
+	if string(contents) != expected {
+		t.Errorf("expected %q, got %q", expected, contents)
+	}
+}
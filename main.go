@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"log"
 	"net/http"
-	"net/smtp"
 	"os"
 	"strconv"
 	"strings"
@@ -14,8 +13,6 @@ import (
 	"github.com/gin-contrib/static"
 	"github.com/gin-gonic/gin"
 	"github.com/stripe/stripe-go"
-	"github.com/stripe/stripe-go/charge"
-	"github.com/stripe/stripe-go/paymentintent"
 )
 
 // For creating Stripe payments via Credit Card
@@ -30,6 +27,12 @@ type PaymentData struct {
 	Zip         *string `form:"zip" json:"zip" binding:"exists"`
 	Email       *string `form:"email" json:"email" binding:"exists"`
 	Phone       *string `form:"phone" json:"phone" binding:"exists"`
+
+	// "Receive updates from donation recipients throughout the season" on the donation form
+	ContactAllowed *bool `form:"contactAllowed" json:"contactAllowed"`
+
+	// The donor's language, e.g. "es", from the form or else the Accept-Language header
+	Locale *string `form:"locale" json:"locale"`
 }
 
 // For creating Stripe payments via PaymentRequestButton
@@ -45,6 +48,10 @@ type Token struct {
 	Email       *string `form:"email" json:"email" binding:"exists"`
 	Phone       *string `form:"phone" json:"phone" binding:"exists"`
 	StripeToken *string `form:"token" json:"token" binding:"exists"`
+	Locale      *string `form:"locale" json:"locale"`
+
+	// The same "Receive updates" checkbox as PaymentData
+	ContactAllowed *bool `form:"contactAllowed" json:"contactAllowed"`
 }
 
 type EmailData struct {
@@ -53,6 +60,7 @@ type EmailData struct {
 	TeamEmail                string
 	Team                     string
 	FIRSTSuffix              string
+	Organization             Organization // Receipts are signed by its officers in office on Received
 	PRAddr1                  string
 	PRCity                   string
 	PRState                  string
@@ -60,6 +68,7 @@ type EmailData struct {
 	PRPhone                  string
 	EIN                      string
 	Date                     string
+	Received                 time.Time // The date above, for formatting it in another language
 	CurrentSeason            string
 	WebServerEmail           string
 	WebServerPassword        string
@@ -67,6 +76,8 @@ type EmailData struct {
 	DonationReceiptsPassword string
 	ServerAddress            string
 	ServerPort               string
+
+	InlineImages bool // Reference the logo and signature as inline attachments instead of by URL
 }
 
 type osEnvVarError struct {
@@ -88,23 +99,74 @@ const FLLSuffix string = "Lego League"
 
 const EmailFinance string = "finance@pathfindersrobotics.org"
 
+const SiteURL string = "https://www.pathfindersrobotics.org"
+
 func main() {
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(os.Args[1:]))
+	}
+
+	cfg, problems := loadConfig()
+	if cfg.Server.Port == "" {
+		problems = append(problems, "'PORT' is required to start the server")
+	}
+
+	// Persistent records and admin pages
+
+	store, storeErr := openDataStore(cfg)
+	if storeErr != nil {
+		fmt.Println(storeErr)
+		fmt.Println("The database given by the 'DATABASE_URL' setting could not be used, or the data directory given by the 'DATA_DIR' setting could not be created. All functionality that keeps records is currently disabled.")
+	} else if cfg.Server.DatabaseURL != "" {
+		setDataStore(store)
+		fmt.Println("Records are being kept in the database given by the 'DATABASE_URL' setting.")
+	} else {
+		setDataStore(store)
+		fmt.Println("Records are being kept in the directory given by the 'DATA_DIR' setting, or ./data if it is unset.")
+		if os.Getenv("DYNO") != "" {
+			fmt.Println("WARNING: THIS IS A HEROKU DYNO, WHOSE DISK IS WIPED ON EVERY RESTART. ATTACH HEROKU POSTGRES SO 'DATABASE_URL' IS SET, OR RECORDS WILL BE LOST.")
+		}
+	}
+
+	problems = append(problems, cfg.receiptProblems()...)
+	if len(problems) > 0 {
+		for _, problem := range problems {
+			fmt.Println(problem)
+		}
+		log.Fatal("ERROR: THE CONFIGURATION HAS " + strconv.Itoa(len(problems)) + " PROBLEM(S) LISTED ABOVE. THE SERVER WILL NOT START UNTIL THEY ARE FIXED.")
+	}
+	cfg.apply()
+	fmt.Println("Configuration loaded from the environment and the 'CONFIG_FILE' YAML file, or ./config.yml if it exists.")
+	configs := newLiveConfig(cfg)
+	reloadConfigOnHangup(configs)
+	fmt.Println("The configuration file and secret files are read again on SIGHUP or POST /admin/config/reload.")
+
+	templates, err := loadEmailTemplates(cfg.Server.TemplatesDir)
+	if err != nil {
+		log.Fatal("ERROR: EMAIL TEMPLATES COULD NOT BE LOADED: " + err.Error())
+	}
+	setEmailTemplates(templates)
+	fmt.Println("Email templates loaded from the 'TEMPLATES_DIR' setting, or ./templates if it is unset.")
+	fmt.Println("Email is sent with the '" + cfg.Mail.Transport + "' transport, per the 'MAIL_TRANSPORT' setting. Options are 'smtp' (the default), 'http' and 'file'.")
+	for _, account := range []string{MailAccountWebServer, MailAccountReceipts} {
+		keys, _ := dkimKeys(cfg.mailAccount(account))
+		if len(keys) > 0 {
+			fmt.Println("Email from the " + account + " mailbox is DKIM signed, per the 'DKIM_" + dkimAccountVars[account] + "_SELECTOR' and 'DKIM_" + dkimAccountVars[account] + "_KEY' environment variables.")
+		} else {
+			fmt.Println("Email from the " + account + " mailbox is not DKIM signed. Set 'DKIM_" + dkimAccountVars[account] + "_SELECTOR' and 'DKIM_" + dkimAccountVars[account] + "_KEY' to sign it.")
+		}
+	}
+
 	router := gin.Default()
 	fmt.Println("Router instance created")
 
 	// Ping functionality
 
-	pingFunctionality, pingErr := strconv.ParseBool(os.Getenv("PING_FUNCTIONALITY"))
-	if pingErr == nil {
-		if pingFunctionality {
-			router.GET("/ping", func(c *gin.Context) { c.String(200, "pong "+fmt.Sprint(time.Now().Unix())) })
-			fmt.Println("Ping functionality established at /ping")
-		} else {
-			fmt.Println("Ping functionality at /ping disabled")
-		}
+	if cfg.Features.Ping {
+		router.GET("/ping", func(c *gin.Context) { c.String(200, "pong "+fmt.Sprint(time.Now().Unix())) })
+		fmt.Println("Ping functionality established at /ping")
 	} else {
-		fmt.Println(pingErr)
-		fmt.Println("The environment variable 'PING_FUNCTIONALITY' did not have a valid 'true' or 'false' value. Ensure the 'PING_FUNCTIONALITY' key is present and has a value of either 'true' or 'false'. All functionality at /ping is currently disabled.")
+		fmt.Println("Ping functionality at /ping disabled")
 	}
 
 	// Ad hoc custom security middleware
@@ -120,25 +182,25 @@ func main() {
 		return func(c *gin.Context) {
 			c.Header("X-Frame-Options", "allow-from https://js.stripe.com")
 			c.Header("X-XSS-Protection", "1; mode=block")
-			c.Header("Content-Security-Policy", "default-src 'none'; script-src 'self' https://storage.googleapis.com https://www.google-analytics.com https://s.ytimg.com https://www.youtube.com https://js.stripe.com https://ajax.cloudflare.com 'sha256-RnmD2Ce58HoPeP9niSSc7DAYa85r04GWTEg4jlfAzfg='; style-src 'self' https://maxcdn.bootstrapcdn.com 'sha256-oWyvTH6ZfCvIDieRREt+hfVBWcf5gzk2WAW4xELF74Q='; img-src 'self' https://www.google-analytics.com data:; connect-src 'self' https://js.stripe.com https://www.google-analytics.com https://www.youtube.com https://m.stripe.network https://q.stripe.com; media-src https://www.youtube.com; child-src 'self' https://www.youtube.com https://js.stripe.com https://m.stripe.network; form-action 'self'; worker-src 'self';")
-			if os.Getenv("GIN_MODE") == "release" {
+			c.Header("Content-Security-Policy", "default-src 'none'; script-src 'self' https://storage.googleapis.com https://www.google-analytics.com https://s.ytimg.com https://www.youtube.com https://js.stripe.com https://ajax.cloudflare.com 'sha256-RnmD2Ce58HoPeP9niSSc7DAYa85r04GWTEg4jlfAzfg='; style-src 'self' https://maxcdn.bootstrapcdn.com 'sha256-oWyvTH6ZfCvIDieRREt+hfVBWcf5gzk2WAW4xELF74Q='; img-src 'self' https://www.google-analytics.com data:; connect-src 'self' https://api.stripe.com https://js.stripe.com https://www.google-analytics.com https://www.youtube.com https://m.stripe.network https://q.stripe.com; media-src https://www.youtube.com; child-src 'self' https://www.youtube.com https://js.stripe.com https://hooks.stripe.com https://m.stripe.network; form-action 'self'; worker-src 'self';")
+			if cfg.Server.GinMode == "release" {
 				if c.Request.Header.Get("X-Forwarded-Proto") != "https" {
 					c.Redirect(http.StatusMovedPermanently, "https://www.pathfindersrobotics.org"+c.Request.URL.Path)
 				}
 			}
 		}
 	}())
-	fmt.Println("HTTP --> HTTPS redirection enabled when environment variable 'GIN_MODE' is 'release'.")
+	fmt.Println("HTTP --> HTTPS redirection enabled when setting 'GIN_MODE' is 'release'.")
 
 	// Static serve site under gzip compression
 	compressionLevel := gzip.DefaultCompression
-	fmt.Println("Using Gzip Compression based on the 'GZIP_COMPRESSION_LVL' environment variable.")
-	fmt.Println("Options for compression include 'BestCompression', 'BestSpeed', 'NoCompression', and 'DefaultCompression'. Default Gzip compression will be used if the 'GZIP_COMPRESSION_LVL' environment variable is unset.")
-	if os.Getenv("GZIP_COMPRESSION_LVL") == "BestCompression" {
+	fmt.Println("Using Gzip Compression based on the 'GZIP_COMPRESSION_LVL' setting.")
+	fmt.Println("Options for compression include 'BestCompression', 'BestSpeed', 'NoCompression', and 'DefaultCompression'. Default Gzip compression will be used if the 'GZIP_COMPRESSION_LVL' setting is unset.")
+	if cfg.Server.GzipCompression == "BestCompression" {
 		compressionLevel = gzip.BestCompression
-	} else if os.Getenv("GZIP_COMPRESSION_LVL") == "BestSpeed" {
+	} else if cfg.Server.GzipCompression == "BestSpeed" {
 		compressionLevel = gzip.BestSpeed
-	} else if os.Getenv("GZIP_COMPRESSION_LVL") == "NoCompression" {
+	} else if cfg.Server.GzipCompression == "NoCompression" {
 		compressionLevel = gzip.NoCompression
 	}
 	router.Use(gzip.Gzip(compressionLevel))
@@ -161,53 +223,113 @@ func main() {
 
 	// Static serve site
 
-	siteServing, siteErr := strconv.ParseBool(os.Getenv("SERVING_SITE"))
-	if siteErr == nil {
-		if siteServing {
-			router.Use(static.Serve("/", static.LocalFile("./static", true)))
-			router.NoRoute(func(c *gin.Context) {
-				c.Redirect(http.StatusMovedPermanently, "https://www.pathfindersrobotics.org/error?code=404")
-			})
-			fmt.Println("Site is being served per the 'SERVING_SITE' environment variable")
-		} else {
-			fmt.Println("Site is not being served, per the 'SERVING_SITE' environment variable.")
-		}
+	if cfg.Features.ServingSite {
+		router.Use(static.Serve("/", static.LocalFile("./static", true)))
+		router.NoRoute(func(c *gin.Context) {
+			c.Redirect(http.StatusMovedPermanently, "https://www.pathfindersrobotics.org/error?code=404")
+		})
+		fmt.Println("Site is being served per the 'SERVING_SITE' setting")
 	} else {
-		fmt.Println("The environment variable 'SERVING_SITE' did not have a valid 'true' or 'false' value. Ensure the 'SERVING_SITE' key is present and has a value of 'true' or 'false'. All site serving functionality is currently disabled.")
+		fmt.Println("Site is not being served, per the 'SERVING_SITE' setting.")
 	}
 
 	// Email Payment notifications
 
-	notifications, notifErr := strconv.ParseBool(os.Getenv("EMAIL_PAYMENT_NOTIFICATIONS"))
-	if notifErr != nil {
-		fmt.Println("The environment variable 'EMAIL_PAYMENT_NOTIFICATIONS' did not have a valid 'true' or 'false' value. Ensure the 'EMAIL_PAYMENT_NOTIFICATIONS' key is present and has a value of either 'true' or 'false'. All Payment Email deliverance functionality is currently disabled.")
+	notifications := cfg.Features.PaymentEmails
+	if notifications {
+		registerPaymentEmailRoute(router, configs)
+		fmt.Println("The payment email functionalities at /paymentEmail are currently enabled, per the 'EMAIL_PAYMENT_NOTIFICATIONS' setting.")
 	} else {
-		if notifications {
-			router.POST("/paymentEmail", func(c *gin.Context) {
-				var data PaymentData
-				err := c.BindJSON(&data)
-				if err != nil {
-					fmt.Println(err)
-				}
-				sendPaymentEmail(&data)
-				c.String(200, "OK")
-			})
-			fmt.Println("The payment email functionalities at /paymentEmail are currently enabled, per the 'EMAIL_PAYMENT_NOTIFICATIONS' environment variable.")
+		fmt.Println("The payment email functionalities at /paymentEmail are currently disabled, per the 'EMAIL_PAYMENT_NOTIFICATIONS' setting.")
+	}
+
+	var admin *gin.RouterGroup
+	if cfg.Server.AdminUsername != "" {
+		admin = router.Group("/admin", gin.BasicAuth(gin.Accounts{cfg.Server.AdminUsername: cfg.Server.AdminPassword}))
+		fmt.Println("Admin pages at /admin are enabled, per the 'ADMIN_USERNAME' and 'ADMIN_PASSWORD' settings.")
+		registerPreviewRoutes(admin, configs)
+		fmt.Println("Email previews are at /admin/preview/receipt, /admin/preview/notification and /admin/preview/refund.")
+		registerConfigRoutes(admin, configs)
+		fmt.Println("Every setting and where it came from, with secrets redacted, is reported at /admin/config.")
+	} else {
+		fmt.Println("The settings 'ADMIN_USERNAME' and 'ADMIN_PASSWORD' were not set. All admin pages at /admin are currently disabled.")
+	}
+
+	// Board members see things coaches shouldn't, such as which families used a fee waiver
+	var board *gin.RouterGroup
+	if cfg.Server.BoardUsername != "" {
+		board = router.Group("/board", gin.BasicAuth(gin.Accounts{cfg.Server.BoardUsername: cfg.Server.BoardPassword}))
+		fmt.Println("Board pages at /board are enabled, per the 'BOARD_USERNAME' and 'BOARD_PASSWORD' settings.")
+	} else {
+		fmt.Println("The settings 'BOARD_USERNAME' and 'BOARD_PASSWORD' were not set. All board pages at /board are currently disabled.")
+	}
+
+	// Door volunteers only get ticket check-in and merchandise pick up
+	var volunteer *gin.RouterGroup
+	if cfg.Server.VolunteerUsername != "" {
+		volunteer = router.Group("/volunteer", gin.BasicAuth(gin.Accounts{cfg.Server.VolunteerUsername: cfg.Server.VolunteerPassword}))
+		fmt.Println("Volunteer pages at /volunteer are enabled, per the 'VOLUNTEER_USERNAME' and 'VOLUNTEER_PASSWORD' settings.")
+	} else {
+		fmt.Println("The settings 'VOLUNTEER_USERNAME' and 'VOLUNTEER_PASSWORD' were not set. All volunteer pages at /volunteer are currently disabled.")
+	}
+
+	if dataStore() != nil {
+		startOutboxWorker(configs)
+		fmt.Println("Outgoing email is queued in the outbox and retried until it is delivered.")
+		if admin != nil {
+			registerOutboxRoutes(admin)
+			registerReceiptRoutes(admin, configs)
+			registerRefundRoutes(admin, configs)
+			fmt.Println("Email delivery status and the dead-letter list are at /admin/outbox, issued receipts at /admin/receipts, and refund notices are sent from /admin/refunds.")
+			registerMailEventRoutes(admin)
+			registerBroadcastRoutes(admin, configs)
+			resumeBroadcasts(configs)
+			fmt.Println("Season update broadcasts to opted-in donors can be sent from /admin/broadcasts.")
+			registerSequenceRoutes(admin)
+			registerOrganizationRoutes(admin, configs)
+			fmt.Println("The organization profile and the officers who sign receipts are at /admin/organization.")
+		}
+		registerOrganizationImageRoute(router)
+		startDigestWorker(configs)
+		for address, frequency := range cfg.Notifications.digestFrequencies() {
+			fmt.Println(address + " gets a " + frequency + " payment digest instead of an email per payment, per the 'NOTIFICATION_DIGESTS' setting. Gifts of $" + formatCents(cfg.Notifications.alertThreshold()) + " or more are still emailed right away ('NOTIFICATION_ALERT_DOLLARS').")
+		}
+		startSequenceWorker()
+		fmt.Println("Stewardship sequences are checked every hour and can be set up at /admin/sequences.")
+		if cfg.Mail.BroadcastOpenTracking {
+			registerBroadcastOpenRoute(router)
+			fmt.Println("Broadcasts may track opens, per the 'BROADCAST_OPEN_TRACKING' setting.")
 		} else {
-			fmt.Println("The payment email functionalities at /paymentEmail are currently disabled, per the 'EMAIL_PAYMENT_NOTIFICATIONS' environment variable.")
+			fmt.Println("The setting 'BROADCAST_OPEN_TRACKING' was not 'true'. Broadcast opens are not tracked.")
+		}
+		registerPreferenceRoutes(router)
+		fmt.Println("Donors can choose which teams email them at /preferences and unsubscribe at /unsubscribe.")
+		if cfg.Bounces.IMAPAddress != "" {
+			startBounceWorker(configs)
+			fmt.Println("The bounce mailbox is checked for bounces and complaints every 5 minutes, per the 'BOUNCE_IMAP_ADDRESS' setting.")
+		} else {
+			fmt.Println("The setting 'BOUNCE_IMAP_ADDRESS' was not set. No bounce mailbox is being checked.")
+		}
+		if cfg.Mail.WebhookSecret != "" {
+			registerMailEventWebhook(router, configs)
+			fmt.Println("Bounce and complaint notifications from the mail provider are accepted at /mail/events, per the 'MAIL_WEBHOOK_SECRET' setting.")
+		} else {
+			fmt.Println("The setting 'MAIL_WEBHOOK_SECRET' was not set. The mail provider webhook at /mail/events is disabled.")
 		}
 	}
 
+	if admin != nil && dataStore() != nil {
+		registerWaiverRoutes(admin, board)
+		fmt.Println("Scholarship and fee-waiver codes can be issued at /admin/waivers.")
+	}
+
 	// Handle Stripe payments
 
-	stripeLive, err := strconv.ParseBool(os.Getenv("STRIPE_LIVE"))
-	if err == nil {
-		fmt.Println("The environment variable 'STRIPE_LIVE' was found with a valid 'true' or 'false' attribute.")
-		if stripeLive {
-			stripe.Key = os.Getenv("STRIPE_LIVE_KEY")
+	if cfg.Stripe.Live != nil {
+		fmt.Println("The setting 'STRIPE_LIVE' was found with a valid 'true' or 'false' attribute.")
+		if *cfg.Stripe.Live {
 			fmt.Println("Stripe functionality is enabled in LIVE mode.")
 		} else {
-			stripe.Key = os.Getenv("STRIPE_DEBUG_KEY")
 			fmt.Println("Stripe functionality is enabled in DEBUG mode.")
 		}
 
@@ -219,7 +341,7 @@ func main() {
 			if err != nil {
 				fmt.Println(err)
 			}
-			intent, _ := paymentintent.New(&stripe.PaymentIntentParams{
+			params := &stripe.PaymentIntentParams{
 				Amount:      stripe.Int64(int64(*paymentIntentData.Amount)),
 				Currency:    stripe.String(string(stripe.CurrencyUSD)),
 				Description: stripe.String(*paymentIntentData.Description),
@@ -239,7 +361,12 @@ func main() {
 					Name:  stripe.String(*paymentIntentData.Name),
 					Phone: stripe.String(*paymentIntentData.Phone),
 				},
-			})
+			}
+			if paymentIntentData.ContactAllowed != nil {
+				params.AddMetadata(paymentContactAllowedMetadata, strconv.FormatBool(*paymentIntentData.ContactAllowed))
+			}
+			params.AddMetadata(paymentLocaleMetadata, requestLocale(c, paymentIntentData.Locale))
+			intent, _ := configs.current().paymentIntents().New(params)
 			c.JSON(200, gin.H{
 				"secret": intent.ClientSecret,
 			})
@@ -251,6 +378,8 @@ func main() {
 			if err != nil {
 				fmt.Println(err)
 			}
+			locale := requestLocale(c, token.Locale)
+			token.Locale = &locale
 
 			params := &stripe.ChargeParams{
 				Amount:       stripe.Int64(int64(*token.Amount)),
@@ -273,13 +402,17 @@ func main() {
 					Token: token.StripeToken,
 				},
 			}
-			ch, _ := charge.New(params)
+			cfg := configs.current()
+			ch, _ := cfg.charges().New(params)
 			if ch.Paid {
 				c.JSON(200, gin.H{
 					"success": true,
 				})
-				if notifErr == nil && notifications {
-					go sendPaymentEmail(tokenToPaymentData(&token))
+				if notifications {
+					go sendPaymentEmail(cfg, tokenToPaymentData(&token), ch.ID)
+				}
+				if dataStore() != nil {
+					go recordPaymentGift(tokenToPaymentData(&token), ch.ID)
 				}
 			} else {
 				c.JSON(200, gin.H{
@@ -288,12 +421,64 @@ func main() {
 			}
 		})
 
+		// Gift ledger and year-end giving statements
+
+		if dataStore() != nil {
+			if admin != nil {
+				registerGiftRoutes(admin)
+				fmt.Println("Offline gifts can be recorded at /admin/gifts.")
+				registerStatementRoutes(admin, configs)
+				fmt.Println("Year-end giving statements can be queued at /admin/statements/:year, which the 'statements' command uses.")
+			}
+			if cfg.Features.YearEndStatements {
+				startYearEndStatementJob(configs)
+				fmt.Println("Year-end giving statements will be emailed each January, per the 'YEAR_END_STATEMENTS' setting.")
+			} else {
+				fmt.Println("Year-end giving statements will not be emailed automatically, per the 'YEAR_END_STATEMENTS' setting. They can still be sent with the 'statements' command.")
+			}
+		}
+
+		// Merchandise store
+
+		if cfg.Features.MerchandiseStore && dataStore() != nil {
+			registerStoreRoutes(router, admin, volunteer, configs)
+			fmt.Println("The merchandise store at /store is currently enabled, per the 'MERCHANDISE_STORE' setting.")
+		} else {
+			fmt.Println("The merchandise store at /store is currently disabled, per the 'MERCHANDISE_STORE' and 'DATA_DIR' settings.")
+		}
+
+		// Season dues
+
+		if cfg.Features.SeasonDues && dataStore() != nil {
+			registerDuesRoutes(router, admin, board, configs)
+			fmt.Println("Season dues at /dues are currently enabled, per the 'SEASON_DUES' setting.")
+		} else {
+			fmt.Println("Season dues at /dues are currently disabled, per the 'SEASON_DUES' and 'DATA_DIR' settings.")
+		}
+
+		// Ticketed events
+
+		if cfg.Features.TicketedEvents && dataStore() != nil {
+			registerEventRoutes(router, admin, board, volunteer, configs)
+			fmt.Println("Ticketed events at /events are currently enabled, per the 'TICKETED_EVENTS' setting.")
+		} else {
+			fmt.Println("Ticketed events at /events are currently disabled, per the 'TICKETED_EVENTS' and 'DATA_DIR' settings.")
+		}
+
+		// Donor portal
+
+		if cfg.Features.DonorPortal && dataStore() != nil {
+			registerPortalRoutes(router, configs)
+			fmt.Println("The donor portal at /portal is currently enabled, per the 'DONOR_PORTAL' setting.")
+		} else {
+			fmt.Println("The donor portal at /portal is currently disabled, per the 'DONOR_PORTAL' and 'DATA_DIR' settings.")
+		}
+
 	} else {
-		fmt.Println(err)
-		fmt.Println("The environment variable 'STRIPE_LIVE' did not have a valid 'true' or 'false' value. Ensure the 'STRIPE_LIVE' key is present and has a value of either 'true' or 'false'. All Stripe functionality is currently disabled.")
+		fmt.Println("The setting 'STRIPE_LIVE' was not set to 'true' or 'false'. All Stripe functionality is currently disabled.")
 	}
 
-	log.Fatal(http.ListenAndServe(":"+os.Getenv("PORT"), router))
+	log.Fatal(http.ListenAndServe(":"+cfg.Server.Port, router))
 }
 
 func tokenToPaymentData(pre *Token) *PaymentData {
@@ -308,53 +493,109 @@ func tokenToPaymentData(pre *Token) *PaymentData {
 		Zip:         pre.Zip,
 		Email:       pre.Email,
 		Phone:       pre.Phone,
+		Locale:      pre.Locale,
+
+		ContactAllowed: pre.ContactAllowed,
 	}
 }
 
-func sendPaymentEmail(data *PaymentData) {
-	htmlEmail := "<html><head><meta http-equiv=\"Content-Type\" content=\"text/html; charset=UTF-8\" /><title>Pathfinders Robotics Donation Receipt</title><meta name=\"viewport\" content=\"width=device-width, initial-scale=1.0\"/><meta http-equiv=\"X-UA-Compatible\" content=\"IE=7\" /><meta http-equiv=\"X-UA-Compatible\" content=\"IE=8\" /><meta http-equiv=\"X-UA-Compatible\" content=\"IE=9\" /><!--[if !mso]><!-- --><meta http-equiv=\"X-UA-Compatible\" content=\"IE=edge\" /><!--<![endif]--></head><body style=\"margin: 0; padding: 5px; font-family: 'Times New Roman', Times, serif; letter-spacing: 0em;\"><table border=\"0\" cellpadding=\"0\" cellspacing=\"0\" width=\"100%\"  style=\"font-size: 12pt;\"><tr><td style=\"width: 50%;\"><img src=\"https://pathfindersrobotics.org/assets/receipts/Logo.png\" alt=\"Pathfinders Robotics\" width=\"265\" border=\"0\" style=\"display: block; height: auto;\" /></td><td style=\"width: 50%; text-align: right;\">Pathfinders Robotics<br/>${PRAddr1}, ${PRCity}, ${PRState} ${PRZip}<br/>${PRPhone}</td></tr></table><table border=\"0\" cellpadding=\"0\" cellspacing=\"0\" width=\"100%\" style=\"border-bottom: 2px solid black; font-size: 14pt;\"><tr><td>${Date}<br/><br/>${Name}<br/>${Addr1} ${Addr2}<br/>${City}, ${State} ${Zip}<br/><br/>Thank you so much for your very generous donation of $${Amount} to the Pathfinders Robotics organization received on ${Date}.<br/><br/>Your donation will help us in supporting ${Team} in FIRST® ${FIRSTSuffix}.<br/><br/>Thanks again for your generosity and support.<br/><br/>Respectfully,<img src=\"https://pathfindersrobotics.org/assets/receipts/Signature.png\" alt=\"Bhooshan Karnik\" width=\"160\" border=\"0\" style=\"display: block; height: auto;\" /><br/>Bhooshan Karnik<br/>Treasurer of Pathfinders Robotics<br/><br/><br/></td></tr></table><br/><table border=\"0\" cellpadding=\"0\" cellspacing=\"0\" width=\"100%\"><tr><td style=\"text-align: center; font-size: 11pt;\"><b>Donation receipt</b> - Keep for your records</td></tr><tr><td style=\"font-size: 13pt;\">Donor: ${Name}<br/>Date Received: ${Date}<br/>Cash Contribution: $${Amount}<br/><br/>Pathfinders Robotics<br/>${PRAddr1}<br/>${PRCity}, ${PRState} ${PRZip}<br/>Federal Tax ID ${EIN}</td></tr></table></body></html>"
+// Called by the page once Stripe.js has confirmed a card payment. Anyone can call it, so the donation is
+// read back from Stripe and the rest of what the page sends is ignored.
+func registerPaymentEmailRoute(router *gin.Engine, configs *liveConfig) {
+	router.POST("/paymentEmail", func(c *gin.Context) {
+		var request struct {
+			PaymentIntent string `json:"paymentIntent"`
+		}
+		err := c.BindJSON(&request)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		cfg := configs.current()
+		data, chargeID, err := verifiedPaymentData(cfg, request.PaymentIntent)
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusBadRequest, "The payment could not be verified")
+			return
+		}
+		if dataStore() != nil {
+			recordPaymentGift(data, chargeID)
+		}
+		sendPaymentEmail(cfg, data, chargeID)
+		c.String(200, "OK")
+	})
+}
 
-	emailData, err := genEmailData(*data)
+// Sends the notification and the receipt for a donation, once per charge
+func sendPaymentEmail(cfg *Config, data *PaymentData, chargeID string) {
+	// Merchandise, dues and tickets get their own confirmations instead of a donation receipt
+	if data.Description != nil && hasOwnConfirmation(*data.Description) {
+		fmt.Println("Skipping donation receipt for non-deductible payment: " + *data.Description)
+		return
+	}
+
+	emailData, err := genEmailData(cfg, *data)
+	var issued Receipt
+	var receiptErr error
+	var retry bool
+	if err == nil {
+		issued, receiptErr = issueReceipt(emailData, chargeReceiptReference(chargeID))
+		if receiptErr == errReceiptAlreadyIssued {
+			// An earlier call saved the receipt, but may have stopped before its emails were queued
+			retry = true
+			issued, receiptErr = originalReceipt(chargeReceiptReference(chargeID))
+			if receiptErr == nil && mailQueued("receipt", "receipt:"+issued.ID) {
+				fmt.Println("Skipping payment emails already sent for charge " + chargeID)
+				return
+			}
+		}
+	}
+	if err == nil && data.ContactAllowed != nil && dataStore() != nil {
+		consentErr := recordDonationConsent(*data.Email, emailData.Team, *data.ContactAllowed)
+		if consentErr != nil {
+			fmt.Println(consentErr)
+			fmt.Println("ERROR: CONTACT PREFERENCE OF " + *data.Email + " COULD NOT BE SAVED")
+		}
+	}
+	if err == nil && data.Locale != nil && dataStore() != nil {
+		localeErr := recordDonorLocale(*data.Email, donationLocale(*data))
+		if localeErr != nil {
+			fmt.Println(localeErr)
+			fmt.Println("ERROR: LANGUAGE OF " + *data.Email + " COULD NOT BE SAVED")
+		}
+	}
 	if err == nil {
-		notifBody := "To: " + emailData.TeamEmail + "\r\nSubject: New Payment\r\n\r\nNew Payment\r\nAmount: " + fmt.Sprintf("%g", float64(*emailData.DonorInformation.Amount)/100.0) + "\r\nDescription: " + *data.Description + "\r\nName: " + *data.Name + "\r\nAddr1: " + *data.Addr1 + "\r\nAddr2: " + *data.Addr2 + "\r\nCity: " + *data.City + "\r\nState: " + *data.State + "\r\nZip: " + *data.Zip + "\r\nEmail: " + *data.Email + "\r\nPhone: " + *data.Phone
-		notifAuth := smtp.PlainAuth("", emailData.WebServerEmail, emailData.WebServerPassword, emailData.ServerAddress)
-		notifErr := smtp.SendMail(emailData.ServerAddress+":"+emailData.ServerPort, notifAuth, emailData.WebServerEmail, []string{emailData.TeamEmail, EmailFinance}, []byte(notifBody))
+		// Addresses with a payment digest only hear about this payment now if it is a large one
+		notification, notifErr := notificationMessage(emailData)
+		if notifErr == nil && !(retry && mailQueued("notification", chargeReceiptReference(chargeID))) {
+			recipients := cfg.Notifications.immediateRecipients(append(notification.To, notification.Bcc...), *data.Amount)
+			if len(recipients) > 0 {
+				notification.To, notification.Bcc = recipients[:1], recipients[1:]
+				_, notifErr = deliverMail(cfg, MailAccountWebServer, "notification", chargeReceiptReference(chargeID), notification)
+			}
+		}
 		if notifErr != nil {
 			fmt.Println(notifErr)
-			fmt.Println("ERROR: NOTIFICATION EMAIL TO TEAM AND FINANCE (BCC) COULD NOT BE SENT")
+			fmt.Println("ERROR: NOTIFICATION EMAIL TO TEAM AND FINANCE (BCC) COULD NOT BE QUEUED")
 		}
 
-		htmlEmail = strings.ReplaceAll(htmlEmail, "${PRAddr1}", emailData.PRAddr1)
-		htmlEmail = strings.ReplaceAll(htmlEmail, "${PRCity}", emailData.PRCity)
-		htmlEmail = strings.ReplaceAll(htmlEmail, "${PRState}", emailData.PRState)
-		htmlEmail = strings.ReplaceAll(htmlEmail, "${PRZip}", emailData.PRZip)
-		htmlEmail = strings.ReplaceAll(htmlEmail, "${PRPhone}", emailData.PRPhone)
-		htmlEmail = strings.ReplaceAll(htmlEmail, "${Date}", emailData.Date)
-		htmlEmail = strings.ReplaceAll(htmlEmail, "${Name}", *emailData.DonorInformation.Name)
-		htmlEmail = strings.ReplaceAll(htmlEmail, "${Addr1}", *emailData.DonorInformation.Addr1)
-		htmlEmail = strings.ReplaceAll(htmlEmail, "${Addr2}", *emailData.DonorInformation.Addr2)
-		htmlEmail = strings.ReplaceAll(htmlEmail, "${City}", *emailData.DonorInformation.City)
-		htmlEmail = strings.ReplaceAll(htmlEmail, "${State}", *emailData.DonorInformation.State)
-		htmlEmail = strings.ReplaceAll(htmlEmail, "${Zip}", *emailData.DonorInformation.Zip)
-		htmlEmail = strings.ReplaceAll(htmlEmail, "${Amount}", fmt.Sprintf("%g", float64(*emailData.DonorInformation.Amount)/100.0))
-		htmlEmail = strings.ReplaceAll(htmlEmail, "${Team}", emailData.Team)
-		htmlEmail = strings.ReplaceAll(htmlEmail, "${FIRSTSuffix}", emailData.FIRSTSuffix)
-		htmlEmail = strings.ReplaceAll(htmlEmail, "${CurrentSeason}", emailData.CurrentSeason)
-		htmlEmail = strings.ReplaceAll(htmlEmail, "${EIN}", emailData.EIN)
-
-		receiptBody := "To: " + emailData.DonationReceiptsEmail + "\nSubject: Pathfinders Robotics Donation Receipt\n" + "MIME-version: 1.0;\nContent-Type: text/html; charset=\"UTF-8\";\n\n" + htmlEmail
-		receiptAuth := smtp.PlainAuth("", emailData.DonationReceiptsEmail, emailData.DonationReceiptsPassword, emailData.ServerAddress)
-		receiptErr := smtp.SendMail(emailData.ServerAddress+":"+emailData.ServerPort, receiptAuth, emailData.DonationReceiptsEmail, []string{*emailData.DonorInformation.Email, emailData.TeamEmail, EmailFinance}, []byte(receiptBody))
+		var receipt *mailMessage
+		if receiptErr == nil {
+			receipt, receiptErr = donationReceiptMessage(cfg, emailData, issued)
+		}
+		if receiptErr == nil {
+			_, receiptErr = deliverMail(cfg, MailAccountReceipts, "receipt", "receipt:"+issued.ID, receipt)
+		}
 		if receiptErr != nil {
 			fmt.Println(receiptErr)
-			fmt.Println("ERROR: RECEIPT EMAIL TO DONOR AND TEAM (BCC) AND FINANCE(BCC) COULD NOT BE SENT")
+			fmt.Println("ERROR: RECEIPT EMAIL TO DONOR AND TEAM (BCC) AND FINANCE(BCC) COULD NOT BE QUEUED")
 		}
 	} else {
 		fmt.Println("ERROR: EMAIL COULD NOT BE GENERATED OR DELIVERED")
 	}
 }
 
-func genEmailData(origin PaymentData) (EmailData, error) {
+func genEmailData(cfg *Config, origin PaymentData) (EmailData, error) {
 	teamEmail, team, firstSuffix := determineTeamEmail(&origin)
 
 	if teamEmail == "" || team == "" || firstSuffix == "" {
@@ -363,95 +604,28 @@ func genEmailData(origin PaymentData) (EmailData, error) {
 		return EmailData{}, &osEnvVarError{err}
 	}
 
-	prAddr1 := os.Getenv("PRAddr1")
-	if prAddr1 == "" {
-		err := "ERROR: 'PRAddr1' ENVIRONMENT VARIABLE UNAVAILABLE"
-		fmt.Println(err)
-		return EmailData{}, &osEnvVarError{err}
-	}
-	prCity := os.Getenv("PRCity")
-	if prCity == "" {
-		err := "ERROR: 'PRCity' ENVIRONMENT VARIABLE UNAVAILABLE"
-		fmt.Println(err)
-		return EmailData{}, &osEnvVarError{err}
-	}
-	prState := os.Getenv("PRState")
-	if prState == "" {
-		err := "ERROR: 'PRState' ENVIRONMENT VARIABLE UNAVAILABLE"
-		fmt.Println(err)
-		return EmailData{}, &osEnvVarError{err}
-	}
-	prZip := os.Getenv("PRZip")
-	if prZip == "" {
-		err := "ERROR: 'PRZip' ENVIRONMENT VARIABLE UNAVAILABLE"
-		fmt.Println(err)
-		return EmailData{}, &osEnvVarError{err}
-	}
-	prPhone := os.Getenv("PRPhone")
-	if prPhone == "" {
-		err := "ERROR: 'PRPhone' ENVIRONMENT VARIABLE UNAVAILABLE"
-		fmt.Println(err)
-		return EmailData{}, &osEnvVarError{err}
-	}
-
-	ein := os.Getenv("EIN")
-	if ein == "" {
-		err := "ERROR: 'EIN' ENVIRONMENT VARIABLE UNAVAILABLE"
-		fmt.Println(err)
-		return EmailData{}, &osEnvVarError{err}
-	}
-
-	est, _ := time.LoadLocation("EST")
-	currentTime := time.Now().In(est)
-	month := currentTime.Month().String()
-	day := currentTime.Day()
-	year := currentTime.Year()
-	date := month + " " + strconv.Itoa(day) + ", " + strconv.Itoa(year)
-
-	currentSeason := ""
-	if currentTime.Month() < 5 {
-		currentSeason = strconv.Itoa(year-1) + "-" + strconv.Itoa(year)
-	} else {
-		currentSeason = strconv.Itoa(year) + "-" + strconv.Itoa(year+1)
-	}
-
-	webserverUsr := os.Getenv("WEBSERVER_EMAIL_USERNAME")
-	if webserverUsr == "" {
-		err := "ERROR: 'WEBSERVER_EMAIL_USERNAME' ENVIRONMENT VARIABLE UNAVAILABLE"
-		fmt.Println(err)
-		return EmailData{}, &osEnvVarError{err}
+	org, err := organizationProfile(cfg)
+	if err == nil {
+		err = org.incomplete()
 	}
-	webserverPwd := os.Getenv("WEBSERVER_EMAIL_PASSWORD")
-	if webserverPwd == "" {
-		err := "ERROR: 'WEBSERVER_EMAIL_PASSWORD' ENVIRONMENT VARIABLE UNAVAILABLE"
+	if err != nil {
 		fmt.Println(err)
-		return EmailData{}, &osEnvVarError{err}
+		return EmailData{}, err
 	}
 
-	donationReceiptsUsr := os.Getenv("DONATION_RECEIPTS_EMAIL_USERNAME")
-	if donationReceiptsUsr == "" {
-		err := "ERROR: 'DONATION_RECEIPTS_EMAIL_USERNAME' ENVIRONMENT VARIABLE UNAVAILABLE"
-		fmt.Println(err)
-		return EmailData{}, &osEnvVarError{err}
-	}
-	donationReceiptsPwd := os.Getenv("DONATION_RECEIPTS_EMAIL_PASSWORD")
-	if donationReceiptsPwd == "" {
-		err := "ERROR: 'DONATION_RECEIPTS_EMAIL_PASSWORD' ENVIRONMENT VARIABLE UNAVAILABLE"
-		fmt.Println(err)
-		return EmailData{}, &osEnvVarError{err}
-	}
+	currentTime := calendar().now()
+	date := calendar().formatDate(currentTime)
+	currentSeason := calendar().season(programForSuffix(firstSuffix), currentTime)
 
-	serverAddress := os.Getenv("SmtpServerAddress")
-	if serverAddress == "" {
-		err := "ERROR: 'SmtpServerAddress' ENVIRONMENT VARIABLE UNAVAILABLE"
+	webServer, err := cfg.webServerSMTP()
+	if err != nil {
 		fmt.Println(err)
-		return EmailData{}, &osEnvVarError{err}
+		return EmailData{}, err
 	}
-	serverPort := os.Getenv("SmtpServerPort")
-	if serverPort == "" {
-		err := "ERROR: 'serverPort' ENVIRONMENT VARIABLE UNAVAILABLE"
+	donationReceipts, err := cfg.donationReceiptsSMTP()
+	if err != nil {
 		fmt.Println(err)
-		return EmailData{}, &osEnvVarError{err}
+		return EmailData{}, err
 	}
 
 	return EmailData{
@@ -459,20 +633,22 @@ func genEmailData(origin PaymentData) (EmailData, error) {
 		TeamEmail:                teamEmail,
 		Team:                     team,
 		FIRSTSuffix:              firstSuffix,
-		PRAddr1:                  prAddr1,
-		PRCity:                   prCity,
-		PRState:                  prState,
-		PRZip:                    prZip,
-		PRPhone:                  prPhone,
-		EIN:                      ein,
+		Organization:             org,
+		PRAddr1:                  org.Addr1,
+		PRCity:                   org.City,
+		PRState:                  org.State,
+		PRZip:                    org.Zip,
+		PRPhone:                  org.Phone,
+		EIN:                      org.EIN,
 		Date:                     date,
+		Received:                 currentTime,
 		CurrentSeason:            currentSeason,
-		WebServerEmail:           webserverUsr,
-		WebServerPassword:        webserverPwd,
-		DonationReceiptsEmail:    donationReceiptsUsr,
-		DonationReceiptsPassword: donationReceiptsPwd,
-		ServerAddress:            serverAddress,
-		ServerPort:               serverPort,
+		WebServerEmail:           webServer.Username,
+		WebServerPassword:        webServer.Password,
+		DonationReceiptsEmail:    donationReceipts.Username,
+		DonationReceiptsPassword: donationReceipts.Password,
+		ServerAddress:            webServer.ServerAddress,
+		ServerPort:               webServer.ServerPort,
 	}, nil
 }
 
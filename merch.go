@@ -0,0 +1,581 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"html/template"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stripe/stripe-go"
+)
+
+// Team merchandise store for t-shirts, buttons and the like.
+// Merchandise is a purchase, not a gift, so it never gets a donation receipt.
+
+type ProductVariant struct {
+	SKU   string `json:"sku"`
+	Size  string `json:"size"`
+	Stock int    `json:"stock"`
+}
+
+type Product struct {
+	ID          string           `json:"id"`
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Price       int              `json:"price"`
+	Active      bool             `json:"active"`
+	Variants    []ProductVariant `json:"variants"`
+}
+
+type MerchOrderItem struct {
+	SKU      string `json:"sku"`
+	Name     string `json:"name"`
+	Size     string `json:"size"`
+	Quantity int    `json:"quantity"`
+	Price    int    `json:"price"`
+}
+
+type MerchOrder struct {
+	ID              string           `json:"id"`
+	Items           []MerchOrderItem `json:"items"`
+	Total           int              `json:"total"`
+	Name            string           `json:"name"`
+	Email           string           `json:"email"`
+	Phone           string           `json:"phone"`
+	PaymentIntentID string           `json:"paymentIntentId"`
+	Paid            bool             `json:"paid"`
+	Created         time.Time        `json:"created"`
+	PickedUp        *time.Time       `json:"pickedUp,omitempty"`
+	// Paid after its hold expired, when its items had been sold to other buyers. Nothing was taken out of stock
+	// and the payment is to be refunded.
+	Oversold bool       `json:"oversold,omitempty"`
+	PaidAt   *time.Time `json:"paidAt,omitempty"`
+}
+
+type storeData struct {
+	Products []Product    `json:"products"`
+	Orders   []MerchOrder `json:"orders"`
+}
+
+type CartItem struct {
+	SKU      *string `form:"sku" json:"sku" binding:"exists"`
+	Quantity *int    `form:"quantity" json:"quantity" binding:"exists"`
+}
+
+// For buying merchandise
+type MerchCheckout struct {
+	Items []CartItem `form:"items" json:"items" binding:"exists"`
+	Name  *string    `form:"name" json:"name" binding:"exists"`
+	Email *string    `form:"email" json:"email" binding:"exists"`
+	Phone *string    `form:"phone" json:"phone" binding:"exists"`
+}
+
+// PaymentIntent descriptions for merchandise start with this, so sendPaymentEmail can tell them apart from donations
+const MerchandiseDescriptionPrefix string = "Pathfinders Robotics Merchandise"
+
+const storeDocument string = "store"
+
+func registerStoreRoutes(router *gin.Engine, admin *gin.RouterGroup, volunteer *gin.RouterGroup, configs *liveConfig) {
+	router.GET("/store", func(c *gin.Context) {
+		var data storeData
+		err := dataStore().load(storeDocument, &data)
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		now := time.Now()
+		var catalog []gin.H
+		for _, product := range data.Products {
+			if !product.Active {
+				continue
+			}
+			var variants []gin.H
+			for _, variant := range product.Variants {
+				variants = append(variants, gin.H{
+					"sku":       variant.SKU,
+					"size":      variant.Size,
+					"available": variant.Stock - data.held(variant.SKU, now),
+				})
+			}
+			catalog = append(catalog, gin.H{
+				"id":          product.ID,
+				"name":        product.Name,
+				"description": product.Description,
+				"price":       product.Price,
+				"variants":    variants,
+			})
+		}
+		c.JSON(200, catalog)
+	})
+
+	router.POST("/store/checkout", func(c *gin.Context) {
+		cfg := configs.current()
+		var checkout MerchCheckout
+		err := c.BindJSON(&checkout)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		if len(checkout.Items) == 0 {
+			c.String(http.StatusBadRequest, "The cart is empty")
+			return
+		}
+
+		var data storeData
+		var order MerchOrder
+		var secret string
+		err = dataStore().update(storeDocument, &data, func() error {
+			now := time.Now()
+			order = MerchOrder{
+				ID:      newID(),
+				Name:    *checkout.Name,
+				Email:   *checkout.Email,
+				Phone:   *checkout.Phone,
+				Created: now,
+			}
+			for _, item := range checkout.Items {
+				product, variant, found := data.find(*item.SKU)
+				if !found || !product.Active {
+					return os.ErrNotExist
+				}
+				inCart := 0
+				for _, earlier := range order.Items {
+					if earlier.SKU == variant.SKU {
+						inCart += earlier.Quantity
+					}
+				}
+				if *item.Quantity < 1 || data.held(variant.SKU, now)+inCart+*item.Quantity > variant.Stock {
+					return errSoldOut
+				}
+				order.Items = append(order.Items, MerchOrderItem{
+					SKU:      variant.SKU,
+					Name:     product.Name,
+					Size:     variant.Size,
+					Quantity: *item.Quantity,
+					Price:    product.Price,
+				})
+				order.Total += product.Price * *item.Quantity
+			}
+
+			data.Orders = append(data.Orders, order)
+			return nil
+		})
+		// The order holds its items before Stripe is called, so the store isn't locked while Stripe answers
+		if err == nil {
+			card := "card"
+			var intent *stripe.PaymentIntent
+			intent, err = cfg.paymentIntents().New(&stripe.PaymentIntentParams{
+				Amount:             stripe.Int64(int64(order.Total)),
+				Currency:           stripe.String(string(stripe.CurrencyUSD)),
+				Description:        stripe.String(MerchandiseDescriptionPrefix + " - Order " + order.ID),
+				PaymentMethodTypes: []*string{&card},
+				ReceiptEmail:       stripe.String(order.Email),
+			})
+			if err == nil {
+				order.PaymentIntentID = intent.ID
+				secret = intent.ClientSecret
+				err = dataStore().update(storeDocument, &data, func() error {
+					for i := range data.Orders {
+						if data.Orders[i].ID == order.ID {
+							data.Orders[i].PaymentIntentID = order.PaymentIntentID
+							return nil
+						}
+					}
+					return errors.New("order " + order.ID + " was removed before its payment was created")
+				})
+			}
+			if err != nil {
+				removeMerchOrder(order.ID)
+			}
+		}
+		if err == os.ErrNotExist {
+			c.String(http.StatusNotFound, "No such item")
+			return
+		}
+		if err == errSoldOut {
+			c.String(http.StatusConflict, "Not enough of an item in stock")
+			return
+		}
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		c.JSON(200, gin.H{
+			"secret": secret,
+			"order":  order.ID,
+			"total":  order.Total,
+		})
+	})
+
+	router.POST("/store/confirm", func(c *gin.Context) {
+		var body struct {
+			Order *string `json:"order" binding:"exists"`
+		}
+		err := c.BindJSON(&body)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		cfg := configs.current()
+		order, newlyPaid, err := confirmMerchOrder(cfg, *body.Order)
+		if err == os.ErrNotExist {
+			c.String(http.StatusNotFound, "No such order")
+			return
+		}
+		if err == errSoldOut {
+			c.JSON(200, gin.H{
+				"success": false,
+				"soldOut": true,
+			})
+			return
+		}
+		if err != nil {
+			fmt.Println(err)
+			c.JSON(200, gin.H{
+				"success": false,
+			})
+			return
+		}
+		c.JSON(200, gin.H{
+			"success": true,
+		})
+		if newlyPaid {
+			go sendMerchConfirmationEmail(cfg, order)
+		}
+	})
+
+	// Volunteers sign in with their own credentials, which only reach fulfillment and ticket check-in
+	if volunteer != nil {
+		// Page for the volunteer handing out orders. Paid orders that haven't been picked up are listed first.
+		volunteer.GET("/fulfillment", func(c *gin.Context) {
+			var data storeData
+			err := dataStore().load(storeDocument, &data)
+			if err != nil {
+				fmt.Println(err)
+				c.String(http.StatusInternalServerError, "Error")
+				return
+			}
+			var waiting, done []MerchOrder
+			for _, order := range data.Orders {
+				if !order.Paid || order.Oversold {
+					continue
+				}
+				if order.PickedUp == nil {
+					waiting = append(waiting, order)
+				} else {
+					done = append(done, order)
+				}
+			}
+			c.Header("Content-Type", "text/html; charset=utf-8")
+			err = fulfillmentPage.Execute(c.Writer, gin.H{"Waiting": waiting, "Done": done})
+			if err != nil {
+				fmt.Println(err)
+			}
+		})
+
+		volunteer.POST("/orders/:id/pickup", func(c *gin.Context) {
+			var data storeData
+			err := dataStore().update(storeDocument, &data, func() error {
+				for i := range data.Orders {
+					if data.Orders[i].ID == c.Param("id") && data.Orders[i].Paid && !data.Orders[i].Oversold {
+						if data.Orders[i].PickedUp == nil {
+							now := time.Now()
+							data.Orders[i].PickedUp = &now
+						}
+						return nil
+					}
+				}
+				return os.ErrNotExist
+			})
+			if err == os.ErrNotExist {
+				c.String(http.StatusNotFound, "No such paid order")
+				return
+			}
+			if err != nil {
+				fmt.Println(err)
+				c.String(http.StatusInternalServerError, "Error")
+				return
+			}
+			c.Redirect(http.StatusSeeOther, "/volunteer/fulfillment")
+		})
+		fmt.Println("Order fulfillment for volunteers is at /volunteer/fulfillment.")
+	} else {
+		fmt.Println("Order fulfillment is disabled because volunteer credentials are not configured.")
+	}
+
+	if admin == nil {
+		fmt.Println("Store administration is disabled because admin credentials are not configured.")
+		return
+	}
+
+	// Adds or replaces a product. Stock counts are what's on the shelf now.
+	admin.POST("/store/products", func(c *gin.Context) {
+		var product Product
+		err := c.BindJSON(&product)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		if product.ID == "" {
+			product.ID = newID()
+		}
+		var data storeData
+		err = dataStore().update(storeDocument, &data, func() error {
+			for _, variant := range product.Variants {
+				if variant.SKU == "" {
+					return errors.New("every variant needs a SKU")
+				}
+				other, _, found := data.find(variant.SKU)
+				if found && other.ID != product.ID {
+					return errors.New("SKU " + variant.SKU + " already belongs to " + other.Name)
+				}
+			}
+			for i := range data.Products {
+				if data.Products[i].ID == product.ID {
+					data.Products[i] = product
+					return nil
+				}
+			}
+			data.Products = append(data.Products, product)
+			return nil
+		})
+		if err != nil {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		c.JSON(200, product)
+	})
+
+	admin.GET("/store/orders", func(c *gin.Context) {
+		var data storeData
+		err := dataStore().load(storeDocument, &data)
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		c.JSON(200, data.Orders)
+	})
+
+	fmt.Println("Store administration is available at /admin/store.")
+}
+
+func (data *storeData) find(sku string) (Product, ProductVariant, bool) {
+	for _, product := range data.Products {
+		for _, variant := range product.Variants {
+			if variant.SKU == sku {
+				return product, variant, true
+			}
+		}
+	}
+	return Product{}, ProductVariant{}, false
+}
+
+// Units of a SKU in unpaid orders that are still within their hold. Paid orders have already come out of stock.
+func (data *storeData) held(sku string, now time.Time) int {
+	held := 0
+	for _, order := range data.Orders {
+		if order.Paid || now.Sub(order.Created) >= checkoutHoldDuration {
+			continue
+		}
+		for _, item := range order.Items {
+			if item.SKU == sku {
+				held += item.Quantity
+			}
+		}
+	}
+	return held
+}
+
+// Whether there is enough stock for the items apart from what other checkouts are holding
+func (data *storeData) available(items []MerchOrderItem, now time.Time) bool {
+	wanted := map[string]int{}
+	for _, item := range items {
+		wanted[item.SKU] += item.Quantity
+	}
+	for sku, quantity := range wanted {
+		_, variant, found := data.find(sku)
+		if !found || data.held(sku, now)+quantity > variant.Stock {
+			return false
+		}
+	}
+	return true
+}
+
+// When the order was paid. Orders from before that was recorded use when they were placed.
+func (order MerchOrder) paidDate() time.Time {
+	if order.PaidAt != nil {
+		return *order.PaidAt
+	}
+	return order.Created
+}
+
+// Takes back the items an order was holding when its checkout couldn't be finished
+func removeMerchOrder(orderID string) {
+	var data storeData
+	err := dataStore().update(storeDocument, &data, func() error {
+		for i := range data.Orders {
+			if data.Orders[i].ID == orderID && !data.Orders[i].Paid {
+				data.Orders = append(data.Orders[:i], data.Orders[i+1:]...)
+				return nil
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Println(err)
+		fmt.Println("ERROR: MERCHANDISE ORDER " + orderID + " COULD NOT BE REMOVED. ITS ITEMS ARE HELD UNTIL THE HOLD EXPIRES.")
+	}
+}
+
+// Marks the order paid and takes its items out of stock. newlyPaid is false if the order was already confirmed.
+// Stripe is asked before the store is locked, and the order is checked again under the lock.
+// Returns errSoldOut if the order was paid after its hold expired and its items had been sold to other buyers.
+func confirmMerchOrder(cfg *Config, orderID string) (MerchOrder, bool, error) {
+	var data storeData
+	err := dataStore().load(storeDocument, &data)
+	if err != nil {
+		return MerchOrder{}, false, err
+	}
+	var order MerchOrder
+	found := false
+	for _, candidate := range data.Orders {
+		if candidate.ID == orderID {
+			order, found = candidate, true
+		}
+	}
+	if !found {
+		return MerchOrder{}, false, os.ErrNotExist
+	}
+	if order.Oversold {
+		return order, false, errSoldOut
+	}
+	if order.Paid {
+		return order, false, nil
+	}
+	err = paymentIntentSucceeded(cfg, order.PaymentIntentID)
+	if err != nil {
+		return order, false, err
+	}
+	verifiedIntent := order.PaymentIntentID
+
+	var newlyPaid, oversold bool
+	err = dataStore().update(storeDocument, &data, func() error {
+		for i := range data.Orders {
+			if data.Orders[i].ID != orderID {
+				continue
+			}
+			order = data.Orders[i]
+			if order.Paid {
+				return nil
+			}
+			if order.PaymentIntentID != verifiedIntent {
+				return errors.New("order " + order.ID + " changed while its payment was checked")
+			}
+			// A checkout paid after its hold expired may find its items sold to other buyers
+			now := time.Now()
+			oversold = now.Sub(order.Created) >= checkoutHoldDuration && !data.available(order.Items, now)
+			data.Orders[i].Paid = true
+			data.Orders[i].PaidAt = &now
+			data.Orders[i].Oversold = oversold
+			order = data.Orders[i]
+			if oversold {
+				return nil
+			}
+			newlyPaid = true
+			for _, item := range order.Items {
+				for p := range data.Products {
+					for v := range data.Products[p].Variants {
+						if data.Products[p].Variants[v].SKU == item.SKU {
+							data.Products[p].Variants[v].Stock -= item.Quantity
+						}
+					}
+				}
+			}
+			return nil
+		}
+		return os.ErrNotExist
+	})
+	if oversold {
+		go sendOversoldEmail(order.ID, order.PaymentIntentID, order.Name, order.Email, "Some of the items in your order", order.Total)
+	}
+	if err == nil && order.Oversold {
+		return order, false, errSoldOut
+	}
+	return order, newlyPaid, err
+}
+
+// The confirmation goes to the buyer alone. Finance is told separately, like for a donation, or in its payment
+// digest if it has one.
+func sendMerchConfirmationEmail(cfg *Config, order MerchOrder) {
+	body := "<html><body style=\"font-family: 'Times New Roman', Times, serif;\"><p>" + html.EscapeString(order.Name) + ",</p>"
+	body += "<p>Thank you for your order from Pathfinders Robotics! Your order number is <b>" + order.ID + "</b>. A team volunteer will have it ready for pick up.</p><table cellpadding=\"4\">"
+	for _, item := range order.Items {
+		description := item.Name
+		if item.Size != "" {
+			description += " (" + item.Size + ")"
+		}
+		body += "<tr><td>" + strconv.Itoa(item.Quantity) + " x " + html.EscapeString(description) + "</td><td style=\"text-align: right;\">$" + formatCents(item.Price*item.Quantity) + "</td></tr>"
+	}
+	body += "<tr><td><b>Total</b></td><td style=\"text-align: right;\"><b>$" + formatCents(order.Total) + "</b></td></tr></table>"
+	body += "<p>This is a merchandise purchase, not a charitable contribution, and is not tax-deductible.</p></body></html>"
+
+	err := queueHTMLMail(MailAccountWebServer, "merchandise", "order:"+order.ID, []string{order.Email}, "Your Pathfinders Robotics order "+order.ID, body)
+	if err != nil {
+		fmt.Println(err)
+		fmt.Println("ERROR: MERCHANDISE ORDER EMAIL TO " + order.Email + " COULD NOT BE QUEUED")
+	}
+
+	if len(cfg.Notifications.immediateRecipients([]string{EmailFinance}, order.Total)) == 0 {
+		return
+	}
+	notification := "New merchandise order " + order.ID + " from " + order.Name + " <" + order.Email + ">, " + order.Phone + "\n\n"
+	for _, item := range order.Items {
+		notification += strconv.Itoa(item.Quantity) + " x " + item.Name
+		if item.Size != "" {
+			notification += " (" + item.Size + ")"
+		}
+		notification += "  $" + formatCents(item.Price*item.Quantity) + "\n"
+	}
+	notification += "Total  $" + formatCents(order.Total) + "\n\nOrders are listed at " + SiteURL + "/admin/store/orders.\n"
+	_, err = enqueueMail(MailAccountWebServer, "merchandise-notification", "order:"+order.ID, &mailMessage{To: []string{EmailFinance}, Subject: "New Merchandise Order", Text: notification})
+	if err != nil {
+		fmt.Println(err)
+		fmt.Println("ERROR: MERCHANDISE ORDER NOTIFICATION TO FINANCE COULD NOT BE QUEUED")
+	}
+}
+
+func formatCents(cents int) string {
+	return fmt.Sprintf("%.2f", float64(cents)/100.0)
+}
+
+var fulfillmentPage = template.Must(template.New("fulfillment").Funcs(template.FuncMap{"dollars": formatCents, "pickedUp": func(t *time.Time) string { return t.In(calendar().location).Format("Jan 2 3:04 PM") }}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8" />
+<meta name="viewport" content="width=device-width, initial-scale=1.0" />
+<title>Order Fulfillment</title>
+</head>
+<body>
+<h1>Waiting for pick up</h1>
+{{range .Waiting}}
+<div style="border-bottom: 1px solid #ccc; padding: 8px 0;">
+<b>{{.Name}}</b> &middot; {{.Phone}} &middot; Order {{.ID}}
+<ul>{{range .Items}}<li>{{.Quantity}} x {{.Name}}{{if .Size}} ({{.Size}}){{end}}</li>{{end}}</ul>
+<form method="POST" action="/volunteer/orders/{{.ID}}/pickup"><button type="submit">Mark picked up</button></form>
+</div>
+{{else}}
+<p>Nothing waiting.</p>
+{{end}}
+<h1>Picked up</h1>
+<ul>{{range .Done}}<li>{{.Name}} &middot; Order {{.ID}} &middot; ${{dollars .Total}} &middot; {{pickedUp .PickedUp}}</li>{{end}}</ul>
+</body>
+</html>
+`))
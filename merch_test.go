@@ -0,0 +1,102 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLateMerchConfirmationDoesNotOversell(t *testing.T) {
+	useTestStore(t)
+	useTestStripe(t, map[string]string{"pi_late": succeededIntent("pi_late", 4000), "pi_held": succeededIntent("pi_held", 2000)})
+	live := false
+	cfg := &Config{Stripe: StripeConfig{Live: &live, DebugKey: "sk_test_x"}}
+	var data storeData
+	dataStore().update(storeDocument, &data, func() error {
+		data.Products = []Product{{ID: "shirt", Name: "Team Shirt", Price: 2000, Active: true, Variants: []ProductVariant{{SKU: "shirt-m", Size: "M", Stock: 2}}}}
+		data.Orders = []MerchOrder{
+			// Paid after its hold expired, when one of the two shirts is held by the checkout below
+			{ID: "late", Items: []MerchOrderItem{{SKU: "shirt-m", Quantity: 1, Price: 2000}, {SKU: "shirt-m", Quantity: 1, Price: 2000}}, Total: 4000,
+				Name: "Late Buyer", Email: "late@example.com", PaymentIntentID: "pi_late", Created: time.Now().Add(-checkoutHoldDuration - time.Minute)},
+			{ID: "held", Items: []MerchOrderItem{{SKU: "shirt-m", Quantity: 1, Price: 2000}}, Total: 2000, Email: "held@example.com", PaymentIntentID: "pi_held", Created: time.Now()},
+		}
+		return nil
+	})
+
+	tests := []struct {
+		order     string
+		newlyPaid bool
+		err       error
+	}{
+		{"late", false, errSoldOut},
+		{"late", false, errSoldOut},
+		{"held", true, nil},
+		{"held", false, nil},
+	}
+	for _, test := range tests {
+		order, newlyPaid, err := confirmMerchOrder(cfg, test.order)
+		if err != test.err || newlyPaid != test.newlyPaid || !order.Paid {
+			t.Errorf("%s: expected newlyPaid %v and error %v, got %v, %v and %+v", test.order, test.newlyPaid, test.err, newlyPaid, err, order)
+		}
+	}
+
+	dataStore().load(storeDocument, &data)
+	if stock := data.Products[0].Variants[0].Stock; stock != 1 {
+		t.Errorf("expected only the held order to come out of stock, leaving 1, got %d", stock)
+	}
+	if !data.Orders[0].Oversold || data.Orders[1].Oversold {
+		t.Errorf("expected only the late order to be flagged for a refund, got %+v", data.Orders)
+	}
+	if oversold := waitForMail(t, "oversold", 2); len(oversold) != 2 || oversold[0].To[0] != "late@example.com" {
+		t.Errorf("expected the buyer and finance to be told about the refund, got %+v", oversold)
+	}
+}
+
+func TestMerchConfirmationKeepsFinanceOffTheBuyersEmail(t *testing.T) {
+	useTestStore(t)
+	paid := time.Now().Add(-time.Hour)
+	order := MerchOrder{ID: "o1", Items: []MerchOrderItem{{SKU: "shirt-m", Name: "Team Shirt", Size: "M", Quantity: 2, Price: 2000}}, Total: 4000,
+		Name: "Sam Buyer", Email: "buyer@example.com", Paid: true, Created: paid, PaidAt: &paid}
+
+	sendMerchConfirmationEmail(&Config{}, order)
+	confirmations := queuedMail(t, "merchandise")
+	if len(confirmations) != 1 || len(confirmations[0].To) != 1 || confirmations[0].To[0] != "buyer@example.com" || len(confirmations[0].Copies) != 0 {
+		t.Errorf("expected the confirmation to go to the buyer alone, got %+v", confirmations)
+	}
+	notifications := queuedMail(t, "merchandise-notification")
+	if len(notifications) != 1 || notifications[0].To[0] != EmailFinance {
+		t.Errorf("expected finance to be told separately, got %+v", notifications)
+	}
+
+	// With a digest, finance reads about the order there instead
+	useTestStore(t)
+	cfg := &Config{Notifications: NotificationConfig{Digests: EmailFinance + "=daily", AlertDollars: 1000}}
+	sendMerchConfirmationEmail(cfg, order)
+	if notifications := queuedMail(t, "merchandise-notification"); len(notifications) != 0 {
+		t.Errorf("expected no notification for finance, who has a digest, got %+v", notifications)
+	}
+	var store storeData
+	dataStore().update(storeDocument, &store, func() error {
+		store.Orders = append(store.Orders, order, MerchOrder{ID: "unpaid", Total: 2000, Created: paid})
+		return nil
+	})
+	report, err := buildDigestReport(cfg, paid.Add(-time.Minute), time.Now(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	merchandise := func(report digestReport) []digestLine {
+		var lines []digestLine
+		for _, line := range report.Payments {
+			if line.Detail == "merchandise" {
+				lines = append(lines, line)
+			}
+		}
+		return lines
+	}
+	if lines := merchandise(report); len(lines) != 1 || lines[0].Amount != 4000 || lines[0].Who != "Sam Buyer <buyer@example.com>" {
+		t.Errorf("expected the paid order in finance's digest, got %+v", lines)
+	}
+	teamReport, err := buildDigestReport(cfg, paid.Add(-time.Minute), time.Now(), FTCPathfinders13497)
+	if err != nil || len(merchandise(teamReport)) != 0 {
+		t.Errorf("expected merchandise to stay out of team digests, got %+v, %v", teamReport.Payments, err)
+	}
+}
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Outgoing mail as RFC 5322 / RFC 2045 messages. A message with HTML is sent as multipart/alternative
+// with a plain-text part, wrapped in multipart/related when it has inline images and in multipart/mixed
+// when it has attachments. Bcc recipients only go in the SMTP envelope, never in the headers.
+
+type mailMessage struct {
+	From     string   `json:"from,omitempty"`
+	FromName string   `json:"fromName,omitempty"`
+	To       []string `json:"to"`
+	Cc       []string `json:"cc,omitempty"`
+	Bcc      []string `json:"bcc,omitempty"`
+	Subject  string   `json:"subject"`
+	// Without the angle brackets. Set when the message is queued, so bounces can be matched to it.
+	MessageID string `json:"messageId,omitempty"`
+	// One-click unsubscribe URL for non-transactional mail, sent as List-Unsubscribe (RFC 8058)
+	Unsubscribe string        `json:"unsubscribe,omitempty"`
+	Text        string        `json:"text,omitempty"` // Generated from HTML if empty
+	HTML        string        `json:"html,omitempty"`
+	Inline      []inlineImage `json:"inline,omitempty"`
+
+	Attachments []mailAttachment `json:"attachments,omitempty"`
+}
+
+// An image the HTML references as "cid:<ContentID>"
+type inlineImage struct {
+	ContentID   string `json:"contentId"`
+	Filename    string `json:"filename"`
+	ContentType string `json:"contentType"`
+	Data        []byte `json:"data"`
+}
+
+type mailAttachment struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"contentType"`
+	Data        []byte `json:"data"`
+}
+
+var errHeaderInjection = errors.New("mail header values can't contain line breaks")
+
+// Everyone the message is delivered to, including Bcc
+func (msg *mailMessage) recipients() []string {
+	var all []string
+	seen := map[string]bool{}
+	for _, list := range [][]string{msg.To, msg.Cc, msg.Bcc} {
+		for _, address := range list {
+			if address != "" && !seen[strings.ToLower(address)] {
+				seen[strings.ToLower(address)] = true
+				all = append(all, address)
+			}
+		}
+	}
+	return all
+}
+
+func (msg *mailMessage) bytes() ([]byte, error) {
+	from, err := formatAddress(msg.FromName, msg.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := formatAddressList(msg.To)
+	if err != nil {
+		return nil, err
+	}
+	cc, err := formatAddressList(msg.Cc)
+	if err != nil {
+		return nil, err
+	}
+	_, err = formatAddressList(msg.Bcc)
+	if err != nil {
+		return nil, err
+	}
+	if strings.ContainsAny(msg.Subject+msg.MessageID+msg.Unsubscribe, "\r\n") {
+		return nil, errHeaderInjection
+	}
+	messageID := msg.MessageID
+	if messageID == "" {
+		messageID = newMessageID(msg.From)
+	}
+
+	var out bytes.Buffer
+	writeHeader(&out, "From", from)
+	if to != "" {
+		writeHeader(&out, "To", to)
+	} else {
+		writeHeader(&out, "To", "undisclosed-recipients:;")
+	}
+	if cc != "" {
+		writeHeader(&out, "Cc", cc)
+	}
+	writeHeader(&out, "Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	writeHeader(&out, "Date", time.Now().Format(time.RFC1123Z))
+	writeHeader(&out, "Message-ID", "<"+messageID+">")
+	writeHeader(&out, "MIME-Version", "1.0")
+	if msg.Unsubscribe != "" {
+		writeHeader(&out, "List-Unsubscribe", "<"+msg.Unsubscribe+">")
+		writeHeader(&out, "List-Unsubscribe-Post", "List-Unsubscribe=One-Click")
+	}
+
+	if msg.HTML == "" {
+		writeHeader(&out, "Content-Type", "text/plain; charset=\"UTF-8\"")
+		writeHeader(&out, "Content-Transfer-Encoding", "quoted-printable")
+		out.WriteString("\r\n")
+		err = writeQuotedPrintable(&out, msg.Text)
+		return out.Bytes(), err
+	}
+	text := msg.Text
+	if text == "" {
+		text = htmlToText(msg.HTML)
+	}
+	contentType, body, err := alternativeBody(text, msg.HTML)
+	if err == nil && len(msg.Inline) > 0 {
+		contentType, body, err = relatedBody(contentType, body, msg.Inline)
+	}
+	if err == nil && len(msg.Attachments) > 0 {
+		contentType, body, err = mixedBody(contentType, body, msg.Attachments)
+	}
+	if err != nil {
+		return nil, err
+	}
+	writeHeader(&out, "Content-Type", contentType)
+	out.WriteString("\r\n")
+	out.Write(body)
+	return out.Bytes(), nil
+}
+
+// Wraps a body in multipart/related with the images it references
+func relatedBody(contentType string, content []byte, images []inlineImage) (string, []byte, error) {
+	var body bytes.Buffer
+	related := multipart.NewWriter(&body)
+	part, err := related.CreatePart(textproto.MIMEHeader{"Content-Type": {contentType}})
+	if err != nil {
+		return "", nil, err
+	}
+	part.Write(content)
+	for _, image := range images {
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Type", image.ContentType)
+		header.Set("Content-Transfer-Encoding", "base64")
+		header.Set("Content-ID", "<"+image.ContentID+">")
+		header.Set("Content-Disposition", "inline; filename=\""+image.Filename+"\"")
+		part, err = related.CreatePart(header)
+		if err != nil {
+			return "", nil, err
+		}
+		writeBase64(part, image.Data)
+	}
+	err = related.Close()
+	return "multipart/related; boundary=\"" + related.Boundary() + "\"; type=\"multipart/alternative\"", body.Bytes(), err
+}
+
+// Wraps a body in multipart/mixed followed by the attachments
+func mixedBody(contentType string, content []byte, attachments []mailAttachment) (string, []byte, error) {
+	var body bytes.Buffer
+	mixed := multipart.NewWriter(&body)
+	part, err := mixed.CreatePart(textproto.MIMEHeader{"Content-Type": {contentType}})
+	if err != nil {
+		return "", nil, err
+	}
+	part.Write(content)
+	for _, attachment := range attachments {
+		if strings.ContainsAny(attachment.Filename, "\"\r\n") {
+			return "", nil, errHeaderInjection
+		}
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Type", attachment.ContentType+"; name=\""+attachment.Filename+"\"")
+		header.Set("Content-Transfer-Encoding", "base64")
+		header.Set("Content-Disposition", "attachment; filename=\""+attachment.Filename+"\"")
+		part, err = mixed.CreatePart(header)
+		if err != nil {
+			return "", nil, err
+		}
+		writeBase64(part, attachment.Data)
+	}
+	err = mixed.Close()
+	return "multipart/mixed; boundary=\"" + mixed.Boundary() + "\"", body.Bytes(), err
+}
+
+// The multipart/alternative body with the text and HTML versions, and its Content-Type
+func alternativeBody(text string, htmlBody string) (string, []byte, error) {
+	var body bytes.Buffer
+	alternative := multipart.NewWriter(&body)
+	for _, version := range []struct{ contentType, content string }{{"text/plain", text}, {"text/html", htmlBody}} {
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Type", version.contentType+"; charset=\"UTF-8\"")
+		header.Set("Content-Transfer-Encoding", "quoted-printable")
+		part, err := alternative.CreatePart(header)
+		if err != nil {
+			return "", nil, err
+		}
+		err = writeQuotedPrintable(part, version.content)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	err := alternative.Close()
+	return "multipart/alternative; boundary=\"" + alternative.Boundary() + "\"", body.Bytes(), err
+}
+
+func writeHeader(out *bytes.Buffer, name string, value string) {
+	out.WriteString(name + ": " + value + "\r\n")
+}
+
+// Line endings are normalized to CRLF before encoding
+func writeQuotedPrintable(out io.Writer, content string) error {
+	content = strings.Replace(strings.Replace(content, "\r\n", "\n", -1), "\n", "\r\n", -1)
+	writer := quotedprintable.NewWriter(out)
+	_, err := writer.Write([]byte(content))
+	if err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+// Base64 in 76 character lines, as RFC 2045 requires
+func writeBase64(out io.Writer, data []byte) {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 76 {
+		io.WriteString(out, encoded[:76]+"\r\n")
+		encoded = encoded[76:]
+	}
+	io.WriteString(out, encoded+"\r\n")
+}
+
+// "Name <address>", with the name RFC 2047 encoded when it isn't plain ASCII
+func formatAddress(name string, address string) (string, error) {
+	if strings.ContainsAny(name+address, "\r\n") {
+		return "", errHeaderInjection
+	}
+	parsed, err := mail.ParseAddress(address)
+	if err != nil {
+		return "", err
+	}
+	parsed.Name = name
+	return parsed.String(), nil
+}
+
+func formatAddressList(addresses []string) (string, error) {
+	var formatted []string
+	for _, address := range addresses {
+		if address == "" {
+			continue
+		}
+		one, err := formatAddress("", address)
+		if err != nil {
+			return "", err
+		}
+		formatted = append(formatted, one)
+	}
+	return strings.Join(formatted, ", "), nil
+}
+
+func newMessageID(from string) string {
+	return newID() + newID() + "@" + addressDomain(from)
+}
+
+func addressDomain(address string) string {
+	at := strings.LastIndex(address, "@")
+	if at < 0 {
+		return "pathfindersrobotics.org"
+	}
+	return address[at+1:]
+}
+
+var htmlBreakPattern = regexp.MustCompile(`(?i)<br\s*/?>|</p>|</tr>|</h[1-6]>|</li>`)
+var htmlCellPattern = regexp.MustCompile(`(?i)</td>|</th>`)
+var htmlDropPattern = regexp.MustCompile(`(?is)<(head|style|script)[^>]*>.*?</(head|style|script)>`)
+var htmlTagPattern = regexp.MustCompile(`<[^>]*>`)
+var blankLinesPattern = regexp.MustCompile(`\n[ \t]*(\n[ \t]*)+`)
+
+// A readable plain-text version of an HTML email, for the text/plain alternative
+func htmlToText(htmlBody string) string {
+	text := htmlDropPattern.ReplaceAllString(htmlBody, "")
+	text = strings.Replace(text, "\r", "", -1)
+	text = strings.Replace(text, "\n", " ", -1)
+	text = htmlBreakPattern.ReplaceAllString(text, "\n")
+	text = htmlCellPattern.ReplaceAllString(text, "  ")
+	text = htmlTagPattern.ReplaceAllString(text, "")
+	text = html.UnescapeString(text)
+	lines := strings.Split(text, "\n")
+	for i := range lines {
+		lines[i] = strings.Join(strings.Fields(lines[i]), " ")
+	}
+	return strings.TrimSpace(blankLinesPattern.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")) + "\n"
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+)
+
+func readTestMessage(t *testing.T, msg *mailMessage) (*mail.Message, []byte) {
+	raw, err := msg.bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("the message doesn't parse: %v\n%s", err, raw)
+	}
+	return parsed, raw
+}
+
+func TestMailMessageRefusesHeaderInjection(t *testing.T) {
+	injected := "x\r\nBcc: everyone@example.net"
+	tests := []struct {
+		name   string
+		change func(msg *mailMessage)
+	}{
+		{"subject", func(msg *mailMessage) { msg.Subject = "Thank you" + injected }},
+		{"subject with only LF", func(msg *mailMessage) { msg.Subject = "Thank you\nBcc: everyone@example.net" }},
+		{"subject with only CR", func(msg *mailMessage) { msg.Subject = "Thank you\rBcc: everyone@example.net" }},
+		{"from name", func(msg *mailMessage) { msg.FromName = "Pathfinders" + injected }},
+		{"from", func(msg *mailMessage) { msg.From = "receipts@example.org" + injected }},
+		{"to", func(msg *mailMessage) { msg.To = []string{"donor@example.com" + injected} }},
+		{"cc", func(msg *mailMessage) { msg.Cc = []string{"team@example.org" + injected} }},
+		{"bcc", func(msg *mailMessage) { msg.Bcc = []string{"finance@example.org" + injected} }},
+		{"message ID", func(msg *mailMessage) { msg.MessageID = "1@example.org>" + injected }},
+		{"unsubscribe", func(msg *mailMessage) { msg.Unsubscribe = "https://example.org/u" + injected }},
+		{"attachment filename", func(msg *mailMessage) {
+			msg.Attachments = []mailAttachment{{Filename: "receipt.pdf" + injected, ContentType: "application/pdf", Data: []byte("%PDF")}}
+		}},
+	}
+	for _, test := range tests {
+		msg := &mailMessage{From: "receipts@example.org", To: []string{"donor@example.com"}, Subject: "Thank you", HTML: "<p>Thank you</p>"}
+		test.change(msg)
+		raw, err := msg.bytes()
+		if err != errHeaderInjection {
+			t.Errorf("%s: expected errHeaderInjection, got %v\n%s", test.name, err, raw)
+		}
+		if !permanentMailError(err) {
+			t.Errorf("%s: expected the refusal to be permanent so the message isn't retried", test.name)
+		}
+	}
+}
+
+func TestMailMessageKeepsBccOutOfTheHeaders(t *testing.T) {
+	msg := &mailMessage{From: "receipts@example.org", FromName: "Pathfinders Robotics", To: []string{"donor@example.com"}, Cc: []string{"team@example.org"},
+		Bcc: []string{"finance@example.org", "Donor@Example.com"}, Subject: "Thank you", HTML: "<p>Thank you</p>"}
+	parsed, raw := readTestMessage(t, msg)
+	if parsed.Header.Get("To") != "<donor@example.com>" || parsed.Header.Get("Cc") != "<team@example.org>" {
+		t.Errorf("unexpected To %q or Cc %q", parsed.Header.Get("To"), parsed.Header.Get("Cc"))
+	}
+	if parsed.Header.Get("Bcc") != "" || strings.Contains(string(raw), "finance@example.org") {
+		t.Errorf("the Bcc address is in the message:\n%s", raw)
+	}
+	if recipients := msg.recipients(); strings.Join(recipients, ",") != "donor@example.com,team@example.org,finance@example.org" {
+		t.Errorf("expected every recipient once for the envelope, got %v", recipients)
+	}
+
+	onlyBcc := &mailMessage{From: "receipts@example.org", Bcc: []string{"finance@example.org"}, Subject: "Digest", Text: "Nothing today"}
+	parsed, raw = readTestMessage(t, onlyBcc)
+	if parsed.Header.Get("To") != "undisclosed-recipients:;" || strings.Contains(string(raw), "finance@example.org") {
+		t.Errorf("expected undisclosed recipients and no Bcc address:\n%s", raw)
+	}
+}
+
+func TestMailMessageEncodesTheSubject(t *testing.T) {
+	tests := []struct {
+		subject string
+		header  string
+	}{
+		{"Thank you for your gift", "Thank you for your gift"},
+		{"Recibo de donación", "=?utf-8?q?Recibo_de_donaci=C3=B3n?="},
+		{"50% off = ¿sí?", "=?utf-8?q?50%_off_=3D_=C2=BFs=C3=AD=3F?="},
+	}
+	var decoder mime.WordDecoder
+	for _, test := range tests {
+		msg := &mailMessage{From: "receipts@example.org", FromName: "José Tesorero", To: []string{"donor@example.com"}, Subject: test.subject, HTML: "<p>Gracias, señora</p>"}
+		parsed, raw := readTestMessage(t, msg)
+		header := parsed.Header.Get("Subject")
+		if header != test.header {
+			t.Errorf("expected the subject %q to be written as %q, got %q", test.subject, test.header, header)
+		}
+		if decoded, err := decoder.DecodeHeader(header); err != nil || decoded != test.subject {
+			t.Errorf("%q decodes to %q, %v", header, decoded, err)
+		}
+		headers := raw[:bytes.Index(raw, []byte("\r\n\r\n"))]
+		if strings.IndexFunc(string(headers), func(r rune) bool { return r > '~' }) >= 0 {
+			t.Errorf("the headers for %q aren't plain ASCII:\n%s", test.subject, headers)
+		}
+		if from, err := parsed.Header.AddressList("From"); err != nil || from[0].Name != "José Tesorero" {
+			t.Errorf("expected the From name to survive encoding, got %v, %v", from, err)
+		}
+
+		// The text alternative is generated from the HTML, and both come back out of quoted-printable intact
+		_, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		reader := multipart.NewReader(parsed.Body, params["boundary"])
+		var parts []string
+		for {
+			part, err := reader.NextPart()
+			if err != nil {
+				break
+			}
+			content, _ := ioutil.ReadAll(part)
+			parts = append(parts, string(content))
+		}
+		if len(parts) != 2 || parts[0] != "Gracias, señora\r\n" || parts[1] != "<p>Gracias, señora</p>" {
+			t.Errorf("unexpected parts %q", parts)
+		}
+	}
+}
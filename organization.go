@@ -0,0 +1,350 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The organization that issues receipts: its legal name, address, EIN, logo, and the officers who sign for it.
+// Officers are kept with the dates they were in office, so a receipt is signed by whoever was treasurer on the
+// gift date, even when it is reissued after the treasurer has changed.
+//
+// The profile is kept at /admin/organization. Until it has been saved once it comes from the 'PRAddr1', 'PRCity',
+// 'PRState', 'PRZip', 'PRPhone' and 'EIN' environment variables, with the logo and signature from
+// ./static/assets/receipts. Uploaded logos and signatures are PNGs stored with the profile.
+
+type Organization struct {
+	LegalName string     `json:"legalName"`
+	Addr1     string     `json:"addr1"`
+	City      string     `json:"city"`
+	State     string     `json:"state"`
+	Zip       string     `json:"zip"`
+	Phone     string     `json:"phone"`
+	EIN       string     `json:"ein"`
+	Logo      string     `json:"logo,omitempty"` // Image ID, "" for static/assets/receipts/Logo.png
+	Officers  []Officer  `json:"officers"`
+	Updated   *time.Time `json:"updated,omitempty"`
+}
+
+type Officer struct {
+	ID        string            `json:"id"`
+	Name      string            `json:"name"`
+	Role      string            `json:"role"`                // e.g. "treasurer" or "president"
+	Title     string            `json:"title"`               // As printed under the signature, e.g. "Treasurer of Pathfinders Robotics"
+	Titles    map[string]string `json:"titles,omitempty"`    // Translations of the title by locale
+	Signature string            `json:"signature,omitempty"` // Image ID, "" for static/assets/receipts/Signature.png
+	From      time.Time         `json:"from"`
+	Until     *time.Time        `json:"until,omitempty"` // When they left office, unset while they serve
+}
+
+type organizationData struct {
+	Profile *Organization `json:"profile,omitempty"`
+}
+
+type organizationImage struct {
+	ID          string    `json:"id"`
+	ContentType string    `json:"contentType"`
+	Data        []byte    `json:"data"`
+	Uploaded    time.Time `json:"uploaded"`
+}
+
+type organizationImageData struct {
+	Images []organizationImage `json:"images"`
+}
+
+const organizationDocument string = "organization"
+const organizationImagesDocument string = "organization-images"
+
+// Receipts are signed by the treasurer
+const ReceiptSignerRole string = "treasurer"
+
+// The treasurer who signed receipts before the profile was kept
+const defaultTreasurer string = "Bhooshan Karnik"
+
+// The saved profile, or the one from the configuration when none has been saved
+func organizationProfile(cfg *Config) (Organization, error) {
+	if dataStore() != nil {
+		var data organizationData
+		err := dataStore().load(organizationDocument, &data)
+		if err != nil {
+			return Organization{}, err
+		}
+		if data.Profile != nil {
+			return *data.Profile, nil
+		}
+	}
+	return Organization{
+		LegalName: "Pathfinders Robotics",
+		Addr1:     cfg.Organization.Addr1,
+		City:      cfg.Organization.City,
+		State:     cfg.Organization.State,
+		Zip:       cfg.Organization.Zip,
+		Phone:     cfg.Organization.Phone,
+		EIN:       cfg.Organization.EIN,
+		Officers:  []Officer{{ID: "default-treasurer", Name: defaultTreasurer, Role: ReceiptSignerRole, Title: "Treasurer of Pathfinders Robotics", Titles: map[string]string{LocaleSpanish: "Tesorero de Pathfinders Robotics"}}},
+	}, nil
+}
+
+// The first thing a receipt would be missing, named for both the profile field and its environment variable
+func (org Organization) incomplete() error {
+	for _, field := range []struct{ value, name, env string }{
+		{org.LegalName, "legalName", ""}, {org.Addr1, "addr1", "PRAddr1"}, {org.City, "city", "PRCity"}, {org.State, "state", "PRState"},
+		{org.Zip, "zip", "PRZip"}, {org.Phone, "phone", "PRPhone"}, {org.EIN, "ein", "EIN"},
+	} {
+		if strings.TrimSpace(field.value) != "" {
+			continue
+		}
+		if field.env == "" {
+			return errors.New("The organization profile has no " + field.name)
+		}
+		return errors.New("The organization profile has no " + field.name + ". Set it at /admin/organization or with the '" + field.env + "' environment variable")
+	}
+	signs := false
+	for _, officer := range org.Officers {
+		signs = signs || officer.Role == ReceiptSignerRole
+	}
+	if !signs {
+		return errors.New("The organization profile has no " + ReceiptSignerRole + " to sign receipts")
+	}
+	return nil
+}
+
+func (officer Officer) inOffice(t time.Time) bool {
+	return !t.Before(officer.From) && (officer.Until == nil || t.Before(*officer.Until))
+}
+
+// The officer with role on t. When nobody was, e.g. for a gift older than the profile's history, it's whoever
+// holds the role now, and failing that whoever held it last.
+func (org Organization) officerOn(role string, t time.Time) (Officer, bool) {
+	var holders []Officer
+	for _, officer := range org.Officers {
+		if officer.Role == role {
+			holders = append(holders, officer)
+		}
+	}
+	if len(holders) == 0 {
+		return Officer{}, false
+	}
+	sort.SliceStable(holders, func(i, j int) bool { return holders[i].From.Before(holders[j].From) })
+	for _, when := range []time.Time{t, time.Now()} {
+		for i := len(holders) - 1; i >= 0; i-- {
+			if holders[i].inOffice(when) {
+				return holders[i], true
+			}
+		}
+	}
+	return holders[len(holders)-1], true
+}
+
+func (officer Officer) localizedTitle(locale string) string {
+	if title, found := officer.Titles[locale]; found && title != "" {
+		return title
+	}
+	return officer.Title
+}
+
+func validateOrganization(org Organization) error {
+	err := org.incomplete()
+	if err != nil {
+		return err
+	}
+	var data organizationImageData
+	err = dataStore().load(organizationImagesDocument, &data)
+	if err != nil {
+		return err
+	}
+	images := map[string]bool{"": true}
+	for _, image := range data.Images {
+		images[image.ID] = true
+	}
+	if !images[org.Logo] {
+		return errors.New("No uploaded image has the logo's ID " + org.Logo)
+	}
+	for i, officer := range org.Officers {
+		name := "Officer " + strconv.Itoa(i+1)
+		if strings.TrimSpace(officer.Name) == "" || strings.TrimSpace(officer.Role) == "" || strings.TrimSpace(officer.Title) == "" {
+			return errors.New(name + " needs a name, role and title")
+		}
+		if officer.Until != nil && !officer.Until.After(officer.From) {
+			return errors.New(name + " must leave office after they start")
+		}
+		if !images[officer.Signature] {
+			return errors.New("No uploaded image has " + officer.Name + "'s signature ID " + officer.Signature)
+		}
+		for locale := range officer.Titles {
+			if normalizeLocale(locale) != locale || locale == "" {
+				return errors.New(name + " has a title for an unsupported locale: " + locale)
+			}
+		}
+		for _, other := range org.Officers[:i] {
+			if other.Role == officer.Role && (other.inOffice(officer.From) || officer.inOffice(other.From)) {
+				return errors.New(officer.Name + " and " + other.Name + " are both " + officer.Role + " at once. Set when the earlier one left office")
+			}
+		}
+	}
+	return nil
+}
+
+var receiptImageCache = struct {
+	sync.Mutex
+	images map[string]*pdfImage
+}{images: map[string]*pdfImage{}}
+
+var receiptImageFiles = map[string]string{"logo": "Logo.png", "signature": "Signature.png"}
+var receiptImageWidths = map[string]int{"logo": 800, "signature": 320}
+
+// A logo or signature as a PNG, and converted for PDFs. The conversion is kept, since receipts reuse the same few images.
+func receiptImage(kind string, id string) ([]byte, *pdfImage, error) {
+	var png []byte
+	if id == "" {
+		var err error
+		png, err = ioutil.ReadFile(filepath.Join("static", "assets", "receipts", receiptImageFiles[kind]))
+		if err != nil {
+			return nil, nil, err
+		}
+	} else {
+		image, found, err := findOrganizationImage(id)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !found {
+			return nil, nil, errors.New("The organization has no image " + id)
+		}
+		png = image.Data
+	}
+	receiptImageCache.Lock()
+	defer receiptImageCache.Unlock()
+	key := kind + "|" + id
+	if converted, found := receiptImageCache.images[key]; found {
+		return png, converted, nil
+	}
+	converted, err := newPDFImage(png, receiptImageWidths[kind])
+	if err != nil {
+		return nil, nil, err
+	}
+	receiptImageCache.images[key] = converted
+	return png, converted, nil
+}
+
+// Where an HTML receipt links a logo or signature from when it isn't attached inline
+func receiptImageURL(kind string, id string) string {
+	if id == "" {
+		return SiteURL + "/assets/receipts/" + receiptImageFiles[kind]
+	}
+	return SiteURL + "/organization/images/" + id
+}
+
+func findOrganizationImage(id string) (organizationImage, bool, error) {
+	var data organizationImageData
+	err := dataStore().load(organizationImagesDocument, &data)
+	if err != nil {
+		return organizationImage{}, false, err
+	}
+	for _, image := range data.Images {
+		if image.ID == id {
+			return image, true, nil
+		}
+	}
+	return organizationImage{}, false, nil
+}
+
+func registerOrganizationRoutes(admin *gin.RouterGroup, configs *liveConfig) {
+	admin.GET("/organization", func(c *gin.Context) {
+		org, err := organizationProfile(configs.current())
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		c.JSON(200, org)
+	})
+
+	// Replaces the profile. Officers who have left stay in the list with the date they left, since reissued
+	// receipts for their gifts are still signed by them.
+	admin.POST("/organization", func(c *gin.Context) {
+		var org Organization
+		err := c.BindJSON(&org)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		for i := range org.Officers {
+			if org.Officers[i].ID == "" {
+				org.Officers[i].ID = newID()
+			}
+		}
+		err = validateOrganization(org)
+		if err != nil {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		now := time.Now()
+		org.Updated = &now
+		var data organizationData
+		err = dataStore().update(organizationDocument, &data, func() error {
+			data.Profile = &org
+			return nil
+		})
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		c.JSON(200, org)
+	})
+
+	// Uploads a logo or signature, sent as the PNG itself. The profile refers to it by the returned ID.
+	admin.POST("/organization/images", func(c *gin.Context) {
+		png, err := ioutil.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, 2<<20))
+		if err != nil {
+			c.String(http.StatusBadRequest, "Images must be at most 2 MB")
+			return
+		}
+		_, err = newPDFImage(png, receiptImageWidths["logo"])
+		if err != nil {
+			c.String(http.StatusBadRequest, "Images must be PNGs: "+err.Error())
+			return
+		}
+		image := organizationImage{ID: newID(), ContentType: "image/png", Data: png, Uploaded: time.Now()}
+		var data organizationImageData
+		err = dataStore().update(organizationImagesDocument, &data, func() error {
+			data.Images = append(data.Images, image)
+			return nil
+		})
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		c.JSON(200, gin.H{"id": image.ID, "url": receiptImageURL("logo", image.ID)})
+	})
+}
+
+// Uploaded images are public like the ones in static/assets/receipts, since receipts viewed outside the
+// email link to them
+func registerOrganizationImageRoute(router *gin.Engine) {
+	router.GET("/organization/images/:id", func(c *gin.Context) {
+		image, found, err := findOrganizationImage(c.Param("id"))
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		if !found {
+			c.String(http.StatusNotFound, "No such image")
+			return
+		}
+		c.Header("Cache-Control", "public, max-age=86400")
+		c.Data(200, image.ContentType, image.Data)
+	})
+}
@@ -0,0 +1,349 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/textproto"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The outbox: outgoing mail is saved before it is sent, so a receipt survives an SMTP outage or a restart.
+// A worker delivers queued messages, retrying with exponential backoff. Messages the mail server rejects
+// outright, or that keep failing, are moved to the dead-letter list for finance to look at and retry.
+
+type outboxMessage struct {
+	ID          string       `json:"id"`
+	Account     string       `json:"account"` // Which mailbox sends it, see mailAccountSettings
+	Kind        string       `json:"kind"`    // e.g. "receipt", for finding messages later
+	Reference   string       `json:"reference,omitempty"`
+	MessageID   string       `json:"messageId"`
+	Message     *mailMessage `json:"message,omitempty"` // Dropped once delivered
+	To          []string     `json:"to"`
+	Copies      []string     `json:"copies,omitempty"` // Blind copies, which are queued as messages of their own
+	Subject     string       `json:"subject"`
+	Status      string       `json:"status"`
+	Attempts    int          `json:"attempts"`
+	NextAttempt time.Time    `json:"nextAttempt"`
+	LastError   string       `json:"lastError,omitempty"`
+	Created     time.Time    `json:"created"`
+	Sent        *time.Time   `json:"sent,omitempty"`
+	Failed      *time.Time   `json:"failed,omitempty"` // When it was moved to the dead-letter list
+}
+
+type outboxData struct {
+	Messages []outboxMessage `json:"messages"`
+}
+
+const outboxDocument string = "outbox"
+
+const OutboxQueued string = "queued"
+const OutboxSent string = "sent"
+const OutboxDead string = "dead"
+
+const MailAccountWebServer string = "webserver"
+const MailAccountReceipts string = "receipts"
+
+const outboxFirstRetry time.Duration = time.Minute
+const outboxMaxRetry time.Duration = 6 * time.Hour
+const outboxMaxAttempts int = 10
+
+// Delivered messages are kept this long so their status can still be looked up
+const outboxKeepSent time.Duration = 90 * 24 * time.Hour
+
+var outboxWake = make(chan struct{}, 1)
+
+// Queues a message, or sends it right away when there is no data store to keep the outbox in
+func deliverMail(cfg *Config, account string, kind string, reference string, msg *mailMessage) (string, error) {
+	if dataStore() == nil {
+		settings, err := cfg.mailAccountSettings(account)
+		if err != nil {
+			return "", err
+		}
+		return "", sendMail(settings, msg)
+	}
+	return enqueueMail(account, kind, reference, msg)
+}
+
+// Saves a message for the worker to deliver
+func enqueueMail(account string, kind string, reference string, msg *mailMessage) (string, error) {
+	return enqueueMailAt(account, kind, reference, msg, time.Now())
+}
+
+// Saves a message for the worker to deliver once at has passed, e.g. to spread a batch out over time.
+// Blind copies are queued as messages of their own, each addressed to its recipient, so a donor address the
+// mail server rejects outright only dead-letters the donor's message and not the team's and finance's copies.
+// The ID returned is the one for the visible recipients.
+func enqueueMailAt(account string, kind string, reference string, msg *mailMessage, at time.Time) (string, error) {
+	if dataStore() == nil {
+		return "", errors.New("there is no data store to keep the outbox in")
+	}
+	if msg.MessageID == "" {
+		msg.MessageID = newMessageID(msg.From)
+	}
+	messages := []*mailMessage{msg}
+	if len(msg.To)+len(msg.Cc) > 0 && len(msg.Bcc) > 0 {
+		visible := *msg
+		visible.Bcc = nil
+		messages = []*mailMessage{&visible}
+		seen := map[string]bool{}
+		for _, address := range visible.recipients() {
+			seen[strings.ToLower(address)] = true
+		}
+		for _, address := range msg.Bcc {
+			if address == "" || seen[strings.ToLower(address)] {
+				continue
+			}
+			seen[strings.ToLower(address)] = true
+			blindCopy := *msg
+			blindCopy.To, blindCopy.Cc, blindCopy.Bcc = []string{address}, nil, nil
+			blindCopy.MessageID = newMessageID(msg.From)
+			messages = append(messages, &blindCopy)
+		}
+	}
+
+	var queued []outboxMessage
+	for i, message := range messages {
+		queued = append(queued, outboxMessage{
+			ID:          newID(),
+			Account:     account,
+			Kind:        kind,
+			Reference:   reference,
+			MessageID:   message.MessageID,
+			Message:     message,
+			To:          message.recipients(),
+			Subject:     message.Subject,
+			Status:      OutboxQueued,
+			NextAttempt: at,
+			Created:     time.Now(),
+		})
+		if i > 0 {
+			queued[0].Copies = append(queued[0].Copies, message.To...)
+		}
+	}
+	var data outboxData
+	err := dataStore().update(outboxDocument, &data, func() error {
+		data.Messages = append(data.Messages, queued...)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	select {
+	case outboxWake <- struct{}{}:
+	default:
+	}
+	return queued[0].ID, nil
+}
+
+// Whether a message of this kind and reference is in the outbox, in any status. An error counts as not queued.
+func mailQueued(kind string, reference string) bool {
+	var data outboxData
+	err := dataStore().load(outboxDocument, &data)
+	if err != nil {
+		fmt.Println(err)
+		return false
+	}
+	for _, queued := range data.Messages {
+		if queued.Kind == kind && queued.Reference == reference {
+			return true
+		}
+	}
+	return false
+}
+
+// Queues an HTML email with a generated plain-text alternative. The first address is the visible recipient
+// and the rest get copies of their own.
+func queueHTMLMail(account string, kind string, reference string, to []string, subject string, html string) error {
+	_, err := enqueueMail(account, kind, reference, &mailMessage{To: to[:1], Bcc: to[1:], Subject: subject, HTML: html})
+	return err
+}
+
+func startOutboxWorker(configs *liveConfig) {
+	go func() {
+		ticker := time.NewTicker(30 * time.Second)
+		defer ticker.Stop()
+		for {
+			deliverOutbox(configs.current())
+			select {
+			case <-ticker.C:
+			case <-outboxWake:
+			}
+		}
+	}()
+}
+
+// Sends every message that is due. Messages are sent outside the store lock and their status saved after,
+// so a crash mid-send can deliver a message twice but never loses one.
+func deliverOutbox(cfg *Config) {
+	var data outboxData
+	err := dataStore().load(outboxDocument, &data)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	now := time.Now()
+	for _, queued := range data.Messages {
+		if queued.Status != OutboxQueued || queued.NextAttempt.After(now) || queued.Message == nil {
+			continue
+		}
+		settings, err := cfg.mailAccountSettings(queued.Account)
+		if err == nil {
+			err = sendMail(settings, queued.Message)
+		}
+		recordDelivery(queued.ID, err)
+	}
+}
+
+func recordDelivery(id string, sendErr error) {
+	var data outboxData
+	err := dataStore().update(outboxDocument, &data, func() error {
+		now := time.Now()
+		var kept []outboxMessage
+		for _, queued := range data.Messages {
+			if queued.ID == id {
+				queued.Attempts++
+				if sendErr == nil {
+					queued.Status = OutboxSent
+					queued.Sent = &now
+					queued.LastError = ""
+					queued.Message = nil
+				} else {
+					queued.LastError = sendErr.Error()
+					if permanentMailError(sendErr) || queued.Attempts >= outboxMaxAttempts {
+						queued.Status = OutboxDead
+						queued.Failed = &now
+						fmt.Println("ERROR: EMAIL " + queued.ID + " (" + queued.Kind + ") TO " + strings.Join(queued.To, ", ") + " MOVED TO THE DEAD-LETTER LIST: " + queued.LastError)
+					} else {
+						queued.NextAttempt = now.Add(outboxBackoff(queued.Attempts))
+					}
+				}
+			}
+			if queued.Status == OutboxSent && queued.Sent != nil && now.Sub(*queued.Sent) > outboxKeepSent {
+				continue
+			}
+			kept = append(kept, queued)
+		}
+		data.Messages = kept
+		return nil
+	})
+	if err != nil {
+		fmt.Println(err)
+		fmt.Println("ERROR: DELIVERY STATUS OF EMAIL " + id + " COULD NOT BE SAVED")
+	}
+}
+
+// 1 minute after the first failure, doubling up to 6 hours
+func outboxBackoff(attempts int) time.Duration {
+	delay := outboxFirstRetry
+	for i := 1; i < attempts && delay < outboxMaxRetry; i++ {
+		delay *= 2
+	}
+	if delay > outboxMaxRetry {
+		delay = outboxMaxRetry
+	}
+	return delay
+}
+
+// A 5xx SMTP reply or a 4xx from the mail API means the message will never be accepted, and a message that
+// can't be built never will be either
+func permanentMailError(err error) bool {
+	if protoErr, ok := err.(*textproto.Error); ok {
+		return protoErr.Code >= 500
+	}
+	if apiErr, ok := err.(*mailAPIError); ok {
+		return apiErr.StatusCode >= 400 && apiErr.StatusCode < 500 && apiErr.StatusCode != http.StatusRequestTimeout && apiErr.StatusCode != http.StatusTooManyRequests
+	}
+	return err == errHeaderInjection || strings.HasPrefix(err.Error(), "mail: ")
+}
+
+func registerOutboxRoutes(admin *gin.RouterGroup) {
+	// Filter with ?status=dead, ?kind=receipt or ?to=someone@example.com
+	admin.GET("/outbox", func(c *gin.Context) {
+		var data outboxData
+		err := dataStore().load(outboxDocument, &data)
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		messages := []outboxMessage{}
+		for _, queued := range data.Messages {
+			if status := c.Query("status"); status != "" && queued.Status != status {
+				continue
+			}
+			if kind := c.Query("kind"); kind != "" && queued.Kind != kind {
+				continue
+			}
+			if to := donorKey(c.Query("to")); to != "" && !containsAddress(queued.To, to) {
+				continue
+			}
+			queued.Message = nil
+			messages = append(messages, queued)
+		}
+		sort.Slice(messages, func(i, j int) bool { return messages[i].Created.After(messages[j].Created) })
+		c.JSON(200, messages)
+	})
+
+	admin.GET("/outbox/:id", func(c *gin.Context) {
+		var data outboxData
+		err := dataStore().load(outboxDocument, &data)
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		for _, queued := range data.Messages {
+			if queued.ID == c.Param("id") {
+				queued.Message = nil
+				c.JSON(200, queued)
+				return
+			}
+		}
+		c.String(http.StatusNotFound, "No such message")
+	})
+
+	// Puts a dead-lettered message back in the queue, e.g. after fixing the donor's address
+	admin.POST("/outbox/:id/retry", func(c *gin.Context) {
+		var data outboxData
+		found := false
+		err := dataStore().update(outboxDocument, &data, func() error {
+			for i := range data.Messages {
+				if data.Messages[i].ID == c.Param("id") && data.Messages[i].Status == OutboxDead && data.Messages[i].Message != nil {
+					data.Messages[i].Status = OutboxQueued
+					data.Messages[i].Attempts = 0
+					data.Messages[i].NextAttempt = time.Now()
+					found = true
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		if !found {
+			c.String(http.StatusNotFound, "No dead-lettered message with that ID")
+			return
+		}
+		select {
+		case outboxWake <- struct{}{}:
+		default:
+		}
+		c.JSON(200, gin.H{"id": c.Param("id"), "status": OutboxQueued})
+	})
+}
+
+func containsAddress(addresses []string, address string) bool {
+	for _, candidate := range addresses {
+		if donorKey(candidate) == address {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"net/textproto"
+	"testing"
+)
+
+func TestBlindCopiesAreQueuedSeparately(t *testing.T) {
+	useTestStore(t)
+	msg := &mailMessage{From: "receipts@example.org", To: []string{"donor@example.com"}, Bcc: []string{Email13497, EmailFinance, "Donor@Example.com"},
+		Subject: "Thank you", HTML: "<p>Thank you</p>"}
+	id, err := enqueueMail(MailAccountReceipts, "receipt", "charge:ch_1", msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	queued := queuedMail(t, "receipt")
+	if len(queued) != 3 {
+		t.Fatalf("expected the donor's message and two copies, got %+v", queued)
+	}
+	expected := []string{"donor@example.com", Email13497, EmailFinance}
+	seen := map[string]bool{}
+	for i, entry := range queued {
+		if len(entry.To) != 1 || entry.To[0] != expected[i] || len(entry.Message.recipients()) != 1 || entry.Message.recipients()[0] != expected[i] {
+			t.Errorf("expected message %d to be delivered only to %s, got %v", i, expected[i], entry.Message.recipients())
+		}
+		if entry.Reference != "charge:ch_1" || seen[entry.MessageID] {
+			t.Errorf("expected each copy to keep the reference and have its own Message-ID, got %+v", entry)
+		}
+		seen[entry.MessageID] = true
+	}
+	if queued[0].ID != id || queued[0].MessageID != msg.MessageID || len(queued[0].Copies) != 2 {
+		t.Errorf("expected the donor's message to be the one returned, listing the copies, got %+v", queued[0])
+	}
+
+	// The donor's mail server refusing the address leaves the team's and finance's copies to be delivered
+	recordDelivery(id, &textproto.Error{Code: 550, Msg: "5.1.1 No such user"})
+	for _, entry := range queuedMail(t, "receipt") {
+		if (entry.ID == id) != (entry.Status == OutboxDead) {
+			t.Errorf("expected only the donor's message to be dead-lettered, got %s for %v", entry.Status, entry.To)
+		}
+	}
+}
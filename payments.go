@@ -0,0 +1,117 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stripe/stripe-go"
+	"github.com/stripe/stripe-go/charge"
+	"github.com/stripe/stripe-go/paymentintent"
+)
+
+// Unpaid checkouts hold what they're buying (tickets, stock, waiver code uses) for this long,
+// so nothing is oversold while a buyer is entering card details
+const checkoutHoldDuration time.Duration = 30 * time.Minute
+
+// Stripe clients with the configuration's key. They are made for each request rather than kept, so a reloaded
+// key is used from the next request on.
+func (cfg *Config) paymentIntents() paymentintent.Client {
+	return paymentintent.Client{B: stripe.GetBackend(stripe.APIBackend), Key: cfg.stripeKey()}
+}
+
+func (cfg *Config) charges() charge.Client {
+	return charge.Client{B: stripe.GetBackend(stripe.APIBackend), Key: cfg.stripeKey()}
+}
+
+// Checkouts create a PaymentIntent the page confirms with Stripe.js, then ask the server to confirm the order.
+// The server looks the PaymentIntent up itself rather than trusting the page.
+func paymentIntentSucceeded(cfg *Config, id string) error {
+	_, err := succeededPaymentIntent(cfg, id)
+	return err
+}
+
+func succeededPaymentIntent(cfg *Config, id string) (*stripe.PaymentIntent, error) {
+	intent, err := cfg.paymentIntents().Get(id, nil)
+	if err != nil {
+		return nil, err
+	}
+	if intent.Status != stripe.PaymentIntentStatusSucceeded {
+		return nil, errors.New("payment intent " + intent.ID + " has status " + string(intent.Status))
+	}
+	return intent, nil
+}
+
+// Metadata /getSecret puts on a donation's PaymentIntent, so the donor's choices on the form are only acted on
+// once Stripe says the payment went through
+const paymentContactAllowedMetadata string = "contactAllowed"
+const paymentLocaleMetadata string = "locale"
+
+// The donation a succeeded PaymentIntent paid for, as Stripe has it, and the ID of its charge. Nothing the page
+// sends to /paymentEmail besides the PaymentIntent's ID is used.
+func verifiedPaymentData(cfg *Config, id string) (*PaymentData, string, error) {
+	intent, err := succeededPaymentIntent(cfg, id)
+	if err != nil {
+		return nil, "", err
+	}
+	if intent.Charges == nil || len(intent.Charges.Data) == 0 {
+		return nil, "", errors.New("payment intent " + intent.ID + " has no charge")
+	}
+	amount := int(intent.Amount)
+	address := stripe.Address{}
+	if intent.Shipping.Address != nil {
+		address = *intent.Shipping.Address
+	}
+	data := &PaymentData{
+		Amount:      &amount,
+		Description: &intent.Description,
+		Name:        &intent.Shipping.Name,
+		Addr1:       &address.Line1,
+		Addr2:       &address.Line2,
+		City:        &address.City,
+		State:       &address.State,
+		Zip:         &address.PostalCode,
+		Email:       &intent.ReceiptEmail,
+		Phone:       &intent.Shipping.Phone,
+	}
+	if allowed, err := strconv.ParseBool(intent.Metadata[paymentContactAllowedMetadata]); err == nil {
+		data.ContactAllowed = &allowed
+	}
+	if locale := intent.Metadata[paymentLocaleMetadata]; locale != "" {
+		data.Locale = &locale
+	}
+	return data, intent.Charges.Data[0].ID, nil
+}
+
+// Payments with these descriptions get their own confirmation email (an order confirmation, dues receipt or tickets)
+// and must never get the donation receipt from sendPaymentEmail
+var ownConfirmationDescriptionPrefixes = []string{MerchandiseDescriptionPrefix, DuesDescriptionPrefix, EventTicketsDescriptionPrefix}
+
+func hasOwnConfirmation(description string) bool {
+	for _, prefix := range ownConfirmationDescriptionPrefixes {
+		if strings.HasPrefix(description, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Tells the buyer, and finance separately, that a checkout paid after its hold expired couldn't be filled and
+// its payment is to be refunded. what is the start of a sentence, e.g. "The Adult tickets for Demo Night".
+func sendOversoldEmail(orderID string, paymentIntentID string, name string, email string, what string, amount int) {
+	body := "<html><body style=\"font-family: 'Times New Roman', Times, serif;\"><p>" + html.EscapeString(name) + ",</p>"
+	body += "<p>We're sorry! " + html.EscapeString(what) + " sold out while your payment was being completed, so order <b>" + orderID + "</b> couldn't be filled.</p>"
+	if amount > 0 {
+		fmt.Println("ERROR: ORDER " + orderID + " WAS PAID AFTER ITS HOLD EXPIRED AND HAD SOLD OUT. REFUND PAYMENT " + paymentIntentID + " OF $" + formatCents(amount) + " IN STRIPE.")
+		body += "<p>Your payment of $" + formatCents(amount) + " will be refunded to your card. Refunds usually appear within 5 to 10 business days.</p>"
+	}
+	body += "</body></html>"
+	err := queueHTMLMail(MailAccountWebServer, "oversold", "order:"+orderID, []string{email, EmailFinance}, "Your Pathfinders Robotics order "+orderID+" couldn't be filled", body)
+	if err != nil {
+		fmt.Println(err)
+		fmt.Println("ERROR: SOLD OUT EMAIL FOR ORDER " + orderID + " COULD NOT BE QUEUED")
+	}
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stripe/stripe-go"
+)
+
+// Points the Stripe client at a server that answers for PaymentIntents with the given bodies by ID
+func useTestStripe(t *testing.T, intents map[string]string) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := intents[strings.TrimPrefix(r.URL.Path, "/v1/payment_intents/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": {"type": "invalid_request_error", "message": "No such payment_intent"}}`))
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	previous := stripe.GetBackend(stripe.APIBackend)
+	stripe.SetBackend(stripe.APIBackend, stripe.GetBackendWithConfig(stripe.APIBackend, &stripe.BackendConfig{URL: server.URL}))
+	t.Cleanup(func() { stripe.SetBackend(stripe.APIBackend, previous) })
+}
+
+func TestPaymentEmailRecordsTheGift(t *testing.T) {
+	useTestStore(t)
+	useTestStripe(t, map[string]string{
+		"pi_paid": `{"id": "pi_paid", "object": "payment_intent", "status": "succeeded", "amount": 2500,
+			"description": "Donation to FTC Pathfinders 13497", "receipt_email": "New@Example.com",
+			"shipping": {"name": "Sam Newdonor", "phone": "555-0101", "address": {"line1": "1 Main St", "city": "Springfield", "state": "IL", "postal_code": "62701"}},
+			"charges": {"object": "list", "data": [{"id": "ch_paid", "object": "charge"}]}}`,
+		"pi_unpaid": `{"id": "pi_unpaid", "object": "payment_intent", "status": "requires_payment_method", "amount": 2500,
+			"description": "Donation to FTC Pathfinders 13497", "receipt_email": "unpaid@example.com"}`,
+	})
+	live := false
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	registerPaymentEmailRoute(router, newLiveConfig(&Config{Stripe: StripeConfig{Live: &live, DebugKey: "sk_test_x"}}))
+
+	post := func(id string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodPost, "/paymentEmail", strings.NewReader(`{"paymentIntent": "`+id+`"}`))
+		request.Header.Set("Content-Type", "application/json")
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	if response := post("pi_unpaid"); response.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an unpaid PaymentIntent, got %d", response.Code)
+	}
+	gifts, err := donorGifts("unpaid@example.com")
+	if err != nil || len(gifts) != 0 {
+		t.Fatalf("an unpaid gift was recorded: %+v, %v", gifts, err)
+	}
+
+	// The page may call again after a retry, which must not record the gift twice
+	for i := 0; i < 2; i++ {
+		if response := post("pi_paid"); response.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", response.Code, response.Body)
+		}
+	}
+	gifts, err = donorGifts("new@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gifts) != 1 || gifts[0].StripeID != "ch_paid" || gifts[0].Amount != 2500 || gifts[0].Source != GiftSourceStripe ||
+		gifts[0].Team != FTCPathfinders13497 || gifts[0].DonorName != "Sam Newdonor" {
+		t.Errorf("unexpected gifts: %+v", gifts)
+	}
+}
+
+func useTestTemplates(t *testing.T) {
+	templates, err := loadEmailTemplates("templates")
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous := emailTemplates()
+	setEmailTemplates(templates)
+	t.Cleanup(func() { setEmailTemplates(previous) })
+}
+
+// Enough configuration for genEmailData to build a receipt
+func testReceiptConfig() *Config {
+	return &Config{Mail: MailConfig{WebServerUsername: "webserver@example.org", ReceiptsUsername: "receipts@example.org"},
+		Organization: OrganizationConfig{Addr1: "1 Robot Way", City: "Springfield", State: "IL", Zip: "62701", Phone: "555-0199", EIN: "12-3456789"}}
+}
+
+func testPaymentData() *PaymentData {
+	amount := 5000
+	description, name, addr1, addr2, city, state, zip, email, phone := "Donation to "+FTCPathfinders13497, "Pat Donor", "2 Elm St", "", "Springfield", "IL", "62701", "donor@example.com", "555-0100"
+	return &PaymentData{Amount: &amount, Description: &description, Name: &name, Addr1: &addr1, Addr2: &addr2, City: &city, State: &state, Zip: &zip, Email: &email, Phone: &phone}
+}
+
+func queuedMail(t *testing.T, kind string) []outboxMessage {
+	var outbox outboxData
+	err := dataStore().load(outboxDocument, &outbox)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var found []outboxMessage
+	for _, queued := range outbox.Messages {
+		if queued.Kind == kind {
+			found = append(found, queued)
+		}
+	}
+	return found
+}
+
+func TestPaymentEmailRetryQueuesAMissingReceipt(t *testing.T) {
+	useTestStore(t)
+	useTestTemplates(t)
+	cfg := testReceiptConfig()
+	data := testPaymentData()
+
+	// An earlier call that stopped right after saving the receipt
+	emailData, err := genEmailData(cfg, *data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issued, err := issueReceipt(emailData, chargeReceiptReference("ch_1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sendPaymentEmail(cfg, data, "ch_1")
+	sendPaymentEmail(cfg, data, "ch_1")
+	// The donor's, the team's and finance's copies
+	receipts := queuedMail(t, "receipt")
+	if len(receipts) != 3 || receipts[0].Reference != "receipt:"+issued.ID || receipts[0].To[0] != "donor@example.com" {
+		t.Fatalf("expected the saved receipt to be queued once, got %+v", receipts)
+	}
+	if !strings.Contains(receipts[0].Message.HTML, issued.Number) {
+		t.Errorf("the queued receipt doesn't carry the saved number %s", issued.Number)
+	}
+	if notifications := queuedMail(t, "notification"); len(notifications) != 2 {
+		t.Errorf("expected one notification, got %+v", notifications)
+	}
+	var saved receiptData
+	dataStore().load(receiptsDocument, &saved)
+	if len(saved.Receipts) != 1 {
+		t.Errorf("expected no new receipt to be issued, got %+v", saved.Receipts)
+	}
+}
+
+// Waits for mail that is queued in the background, e.g. by a goroutine a handler started
+func waitForMail(t *testing.T, kind string, count int) []outboxMessage {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		queued := queuedMail(t, kind)
+		if len(queued) >= count || time.Now().After(deadline) {
+			return queued
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// A succeeded PaymentIntent body for useTestStripe
+func succeededIntent(id string, amount int) string {
+	return `{"id": "` + id + `", "object": "payment_intent", "status": "succeeded", "amount": ` + strconv.Itoa(amount) + `}`
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"bytes"
+	"compress/zlib"
+	"image"
+	"image/png"
+	"strconv"
+	"strings"
+)
+
+// A small single-page PDF writer, enough for receipts: text in the standard Times fonts, lines, and PNG images.
+// Coordinates are in points from the bottom-left corner of a US Letter page.
+
+const pdfPageWidth float64 = 612
+const pdfPageHeight float64 = 792
+
+type pdfDocument struct {
+	content bytes.Buffer
+	images  []pdfImage
+}
+
+type pdfImage struct {
+	name   string
+	width  int
+	height int
+	rgb    []byte // zlib compressed
+	alpha  []byte // zlib compressed, nil when the image is opaque
+}
+
+func (doc *pdfDocument) text(x float64, y float64, size float64, bold bool, s string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	doc.content.WriteString("BT /" + font + " " + pdfNumber(size) + " Tf " + pdfNumber(x) + " " + pdfNumber(y) + " Td (" + pdfEscape(s) + ") Tj ET\n")
+}
+
+func (doc *pdfDocument) textRight(right float64, y float64, size float64, bold bool, s string) {
+	doc.text(right-pdfTextWidth(s, size, bold), y, size, bold, s)
+}
+
+func (doc *pdfDocument) textCenter(center float64, y float64, size float64, bold bool, s string) {
+	doc.text(center-pdfTextWidth(s, size, bold)/2, y, size, bold, s)
+}
+
+// Writes s word-wrapped to width and returns the baseline below the last line
+func (doc *pdfDocument) paragraph(x float64, y float64, width float64, size float64, bold bool, s string) float64 {
+	line := ""
+	for _, word := range strings.Fields(s) {
+		candidate := word
+		if line != "" {
+			candidate = line + " " + word
+		}
+		if line != "" && pdfTextWidth(candidate, size, bold) > width {
+			doc.text(x, y, size, bold, line)
+			y -= size * 1.25
+			line = word
+		} else {
+			line = candidate
+		}
+	}
+	if line != "" {
+		doc.text(x, y, size, bold, line)
+		y -= size * 1.25
+	}
+	return y
+}
+
+func (doc *pdfDocument) line(x1 float64, y1 float64, x2 float64, y2 float64, width float64) {
+	doc.content.WriteString(pdfNumber(width) + " w " + pdfNumber(x1) + " " + pdfNumber(y1) + " m " + pdfNumber(x2) + " " + pdfNumber(y2) + " l S\n")
+}
+
+// Draws an image added with addImage with its bottom-left corner at x, y
+func (doc *pdfDocument) drawImage(name string, x float64, y float64, width float64, height float64) {
+	doc.content.WriteString("q " + pdfNumber(width) + " 0 0 " + pdfNumber(height) + " " + pdfNumber(x) + " " + pdfNumber(y) + " cm /" + name + " Do Q\n")
+}
+
+// Converts a PNG for embedding, scaled down to at most maxWidth pixels wide. Images can be reused across documents.
+func newPDFImage(pngData []byte, maxWidth int) (*pdfImage, error) {
+	img, err := png.Decode(bytes.NewReader(pngData))
+	if err != nil {
+		return nil, err
+	}
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width > maxWidth {
+		height = height * maxWidth / width
+		width = maxWidth
+	}
+	rgb := make([]byte, 0, width*height*3)
+	alpha := make([]byte, 0, width*height)
+	opaque := true
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			r, g, b, a := pdfAveragePixel(img, bounds, x, y, width, height)
+			rgb = append(rgb, r, g, b)
+			alpha = append(alpha, a)
+			if a != 255 {
+				opaque = false
+			}
+		}
+	}
+	image := &pdfImage{width: width, height: height, rgb: pdfCompress(rgb)}
+	if !opaque {
+		image.alpha = pdfCompress(alpha)
+	}
+	return image, nil
+}
+
+// Adds an image to the document and returns the name to draw it with
+func (doc *pdfDocument) addImage(image *pdfImage) string {
+	added := *image
+	added.name = "Im" + strconv.Itoa(len(doc.images)+1)
+	doc.images = append(doc.images, added)
+	return added.name
+}
+
+// The average of the source pixels that map onto pixel x, y of the scaled image, un-premultiplied
+func pdfAveragePixel(img image.Image, bounds image.Rectangle, x int, y int, width int, height int) (byte, byte, byte, byte) {
+	x0, x1 := bounds.Min.X+x*bounds.Dx()/width, bounds.Min.X+(x+1)*bounds.Dx()/width
+	y0, y1 := bounds.Min.Y+y*bounds.Dy()/height, bounds.Min.Y+(y+1)*bounds.Dy()/height
+	var r, g, b, a, n uint64
+	for sy := y0; sy < y1; sy++ {
+		for sx := x0; sx < x1; sx++ {
+			pr, pg, pb, pa := img.At(sx, sy).RGBA()
+			r, g, b, a, n = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa), n+1
+		}
+	}
+	if a == 0 {
+		return 255, 255, 255, 0
+	}
+	return byte(r * 255 / a), byte(g * 255 / a), byte(b * 255 / a), byte(a / n >> 8)
+}
+
+func pdfCompress(data []byte) []byte {
+	var out bytes.Buffer
+	writer := zlib.NewWriter(&out)
+	writer.Write(data)
+	writer.Close()
+	return out.Bytes()
+}
+
+func (doc *pdfDocument) bytes() []byte {
+	var objects [][]byte
+	add := func(object string, stream []byte) int {
+		if stream != nil {
+			object += "\nstream\n" + string(stream) + "\nendstream"
+		}
+		objects = append(objects, []byte(object))
+		return len(objects)
+	}
+
+	// 1 and 2 are the catalog and page tree, filled in at the end
+	add("", nil)
+	add("", nil)
+	regular := add("<< /Type /Font /Subtype /Type1 /BaseFont /Times-Roman /Encoding /WinAnsiEncoding >>", nil)
+	bold := add("<< /Type /Font /Subtype /Type1 /BaseFont /Times-Bold /Encoding /WinAnsiEncoding >>", nil)
+	xobjects := ""
+	for _, image := range doc.images {
+		mask := ""
+		if image.alpha != nil {
+			alpha := add("<< /Type /XObject /Subtype /Image /Width "+strconv.Itoa(image.width)+" /Height "+strconv.Itoa(image.height)+" /ColorSpace /DeviceGray /BitsPerComponent 8 /Filter /FlateDecode /Length "+strconv.Itoa(len(image.alpha))+" >>", image.alpha)
+			mask = " /SMask " + strconv.Itoa(alpha) + " 0 R"
+		}
+		object := add("<< /Type /XObject /Subtype /Image /Width "+strconv.Itoa(image.width)+" /Height "+strconv.Itoa(image.height)+" /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /FlateDecode"+mask+" /Length "+strconv.Itoa(len(image.rgb))+" >>", image.rgb)
+		xobjects += "/" + image.name + " " + strconv.Itoa(object) + " 0 R "
+	}
+	content := pdfCompress(doc.content.Bytes())
+	contents := add("<< /Filter /FlateDecode /Length "+strconv.Itoa(len(content))+" >>", content)
+	page := add("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 "+pdfNumber(pdfPageWidth)+" "+pdfNumber(pdfPageHeight)+"] /Contents "+strconv.Itoa(contents)+" 0 R /Resources << /Font << /F1 "+strconv.Itoa(regular)+" 0 R /F2 "+strconv.Itoa(bold)+" 0 R >> /XObject << "+xobjects+">> >> >>", nil)
+	objects[0] = []byte("<< /Type /Catalog /Pages 2 0 R >>")
+	objects[1] = []byte("<< /Type /Pages /Kids [" + strconv.Itoa(page) + " 0 R] /Count 1 >>")
+
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = out.Len()
+		out.WriteString(strconv.Itoa(i+1) + " 0 obj\n")
+		out.Write(object)
+		out.WriteString("\nendobj\n")
+	}
+	xref := out.Len()
+	out.WriteString("xref\n0 " + strconv.Itoa(len(objects)+1) + "\n0000000000 65535 f \n")
+	for _, offset := range offsets {
+		out.WriteString(pad10(offset) + " 00000 n \n")
+	}
+	out.WriteString("trailer\n<< /Size " + strconv.Itoa(len(objects)+1) + " /Root 1 0 R >>\nstartxref\n" + strconv.Itoa(xref) + "\n%%EOF\n")
+	return out.Bytes()
+}
+
+func pad10(n int) string {
+	s := strconv.Itoa(n)
+	return strings.Repeat("0", 10-len(s)) + s
+}
+
+func pdfNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// Encodes s in WinAnsi, which matches Latin-1 for the characters receipts use, and escapes it for a PDF string
+func pdfEscape(s string) string {
+	var out strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			out.WriteByte('\\')
+			out.WriteRune(r)
+		case r >= 32 && r < 127:
+			out.WriteRune(r)
+		case r >= 160 && r <= 255:
+			out.WriteString("\\" + strconv.FormatInt(int64(r), 8))
+		case r == '’':
+			out.WriteString("\\222")
+		case r == '–':
+			out.WriteString("\\226")
+		default:
+			out.WriteByte('?')
+		}
+	}
+	return out.String()
+}
+
+func pdfTextWidth(s string, size float64, bold bool) float64 {
+	widths := timesRomanWidths
+	if bold {
+		widths = timesBoldWidths
+	}
+	total := 0
+	for _, r := range s {
+		if r >= 32 && r < 127 {
+			total += widths[r-32]
+		} else {
+			total += 500
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// Glyph widths for characters 32 through 126, from the Adobe font metrics
+var timesRomanWidths = [95]int{
+	250, 333, 408, 500, 500, 833, 778, 180, 333, 333, 500, 564, 250, 333, 250, 278,
+	500, 500, 500, 500, 500, 500, 500, 500, 500, 500, 278, 278, 564, 564, 564, 444,
+	921, 722, 667, 667, 722, 611, 556, 722, 722, 333, 389, 722, 611, 889, 722, 722,
+	556, 722, 667, 556, 611, 722, 722, 944, 722, 722, 611, 333, 278, 333, 469, 500,
+	333, 444, 500, 444, 500, 444, 333, 500, 500, 278, 278, 500, 278, 778, 500, 500,
+	500, 500, 333, 389, 278, 500, 500, 722, 500, 500, 444, 480, 200, 480, 541,
+}
+
+var timesBoldWidths = [95]int{
+	250, 333, 555, 500, 500, 1000, 833, 278, 333, 333, 500, 570, 250, 333, 250, 278,
+	500, 500, 500, 500, 500, 500, 500, 500, 500, 500, 333, 333, 570, 570, 570, 500,
+	930, 722, 667, 722, 722, 667, 611, 778, 778, 389, 500, 778, 667, 944, 722, 778,
+	611, 778, 722, 556, 667, 722, 722, 1000, 722, 722, 667, 333, 278, 333, 581, 500,
+	333, 500, 556, 444, 556, 444, 333, 500, 556, 278, 333, 556, 278, 833, 556, 500,
+	556, 556, 444, 389, 333, 556, 500, 722, 500, 500, 444, 394, 220, 394, 520,
+}
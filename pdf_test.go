@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func testPNG(t *testing.T, width int, height int, alpha uint8) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{uint8(x), uint8(y), 128, alpha})
+		}
+	}
+	var buf bytes.Buffer
+	err := png.Encode(&buf, img)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+var pdfReference = regexp.MustCompile(`(\d+) 0 R`)
+var pdfLength = regexp.MustCompile(`/Length (\d+)`)
+
+// Checks the cross-reference table against the objects it points to, every stream's length, and every reference,
+// and returns each object's text by number
+func checkPDFStructure(t *testing.T, pdf []byte) map[int][]byte {
+	if !bytes.HasPrefix(pdf, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(pdf, []byte("\n%%EOF\n")) {
+		t.Fatal("missing the PDF header or end of file marker")
+	}
+	tail := pdf[bytes.LastIndex(pdf, []byte("startxref\n"))+len("startxref\n"):]
+	xref, err := strconv.Atoi(string(tail[:bytes.IndexByte(tail, '\n')]))
+	if err != nil || !bytes.HasPrefix(pdf[xref:], []byte("xref\n")) {
+		t.Fatalf("startxref doesn't point at the xref table: %v", err)
+	}
+	lines := strings.Split(string(pdf[xref:]), "\n")
+	var first, count int
+	if _, err := fmt.Sscan(lines[1], &first, &count); err != nil || first != 0 {
+		t.Fatalf("unexpected subsection %q", lines[1])
+	}
+	if !strings.Contains(string(pdf[xref:]), "/Size "+strconv.Itoa(count)+" ") {
+		t.Errorf("the trailer's /Size doesn't match the %d entries", count)
+	}
+	if lines[2] != "0000000000 65535 f " {
+		t.Errorf("unexpected free entry %q", lines[2])
+	}
+
+	offsets := make([]int, count)
+	entries := pdf[xref+len("xref\n")+len(lines[1])+1:]
+	for i := 0; i < count; i++ {
+		// Entries are exactly 20 bytes including the end of line
+		entry := string(entries[i*20 : i*20+20])
+		if len(strings.TrimRight(entry, " \n")) != 18 || !strings.HasSuffix(entry, " \n") {
+			t.Fatalf("entry %d isn't 20 bytes: %q", i, entry)
+		}
+		offsets[i], _ = strconv.Atoi(entry[:10])
+	}
+
+	objects := map[int][]byte{}
+	for i := 1; i < count; i++ {
+		end := xref
+		if i+1 < count {
+			end = offsets[i+1]
+		}
+		object := pdf[offsets[i]:end]
+		header := strconv.Itoa(i) + " 0 obj\n"
+		if !bytes.HasPrefix(object, []byte(header)) || !bytes.HasSuffix(object, []byte("\nendobj\n")) {
+			t.Fatalf("the offset for object %d points at %q", i, object[:20])
+		}
+		body := object[len(header) : len(object)-len("\nendobj\n")]
+		if start := bytes.Index(body, []byte("\nstream\n")); start >= 0 {
+			length := pdfLength.FindSubmatch(body[:start])
+			if length == nil {
+				t.Fatalf("stream object %d has no /Length", i)
+			}
+			n, _ := strconv.Atoi(string(length[1]))
+			stream := body[start+len("\nstream\n"):]
+			if len(stream) != n+len("\nendstream") || !bytes.HasSuffix(stream, []byte("\nendstream")) {
+				t.Errorf("object %d: /Length %d, but the stream is %d bytes", i, n, len(stream)-len("\nendstream"))
+			}
+		}
+		objects[i] = body
+	}
+	for i, body := range objects {
+		dictionary := body
+		if start := bytes.Index(body, []byte("\nstream\n")); start >= 0 {
+			dictionary = body[:start]
+		}
+		for _, match := range pdfReference.FindAllSubmatch(dictionary, -1) {
+			if n, _ := strconv.Atoi(string(match[1])); n < 1 || n >= count {
+				t.Errorf("object %d refers to missing object %d", i, n)
+			}
+		}
+	}
+	if !strings.Contains(string(pdf[xref:]), "/Root 1 0 R") || !bytes.HasPrefix(objects[1], []byte("<< /Type /Catalog /Pages 2 0 R >>")) {
+		t.Error("the trailer's /Root isn't the catalog")
+	}
+	return objects
+}
+
+func TestPDFStructure(t *testing.T) {
+	opaque, err := newPDFImage(testPNG(t, 40, 20, 255), 30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	transparent, err := newPDFImage(testPNG(t, 10, 10, 128), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opaque.width != 30 || opaque.height != 15 || opaque.alpha != nil || transparent.alpha == nil {
+		t.Fatalf("unexpected images: %dx%d, alpha %v and %v", opaque.width, opaque.height, opaque.alpha != nil, transparent.alpha != nil)
+	}
+
+	doc := &pdfDocument{}
+	doc.drawImage(doc.addImage(opaque), 54, 700, 200, 100)
+	doc.drawImage(doc.addImage(transparent), 54, 600, 50, 50)
+	doc.text(54, 500, 12, false, "Thank you (again) for $50.00 – gracias, señora")
+	doc.line(54, 480, 558, 480, 1.5)
+	objects := checkPDFStructure(t, doc.bytes())
+
+	// Catalog, pages, two fonts, the transparent image's mask, two images, contents and the page
+	if len(objects) != 9 {
+		t.Errorf("expected 9 objects, got %d", len(objects))
+	}
+	if !bytes.Contains(objects[2], []byte("/Kids [9 0 R] /Count 1")) {
+		t.Errorf("unexpected page tree %q", objects[2])
+	}
+	if !bytes.Contains(objects[9], []byte("/XObject << /Im1 5 0 R /Im2 7 0 R >>")) || !bytes.Contains(objects[7], []byte("/SMask 6 0 R")) {
+		t.Errorf("unexpected page %q or image %q", objects[9], objects[7][:120])
+	}
+	contents := objects[8]
+	start := bytes.Index(contents, []byte("\nstream\n")) + len("\nstream\n")
+	reader, err := zlib.NewReader(bytes.NewReader(contents[start : len(contents)-len("\nendstream")]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	text, err := ioutil.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "BT /F1 12 Tf 54 500 Td (Thank you \\(again\\) for $50.00 \\226 gracias, se\\361ora) Tj ET\n"
+	if !strings.Contains(string(text), expected) || !strings.Contains(string(text), "/Im2 Do") {
+		t.Errorf("unexpected content stream:\n%s", text)
+	}
+}
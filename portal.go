@@ -0,0 +1,567 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stripe/stripe-go"
+)
+
+// Donor self-service portal. Donors sign in with a one-time link emailed to the address on their gifts,
+// then can see their giving history, download receipts and statements, update their mailing address,
+// ask for a corrected receipt and stop recurring gifts. Every page only ever shows the signed in donor's records.
+
+type portalSession struct {
+	ID      string    `json:"id"`
+	Email   string    `json:"email"`
+	CSRF    string    `json:"csrf"`
+	Expires time.Time `json:"expires"`
+}
+
+type portalData struct {
+	Sessions  []portalSession      `json:"sessions"`
+	UsedLinks map[string]time.Time `json:"usedLinks"` // Hash of each login link that was used, until it expires
+}
+
+const portalDocument string = "portal"
+const portalCookie string = "portal_session"
+
+const portalLinkDuration time.Duration = 15 * time.Minute
+const portalSessionDuration time.Duration = 30 * time.Minute
+
+func registerPortalRoutes(router *gin.Engine, configs *liveConfig) {
+	router.GET("/portal/login", func(c *gin.Context) {
+		renderPortalPage(c, portalLoginPage, gin.H{})
+	})
+
+	// Always answers the same way, so the form can't be used to find out who has given
+	router.POST("/portal/login", func(c *gin.Context) {
+		email := donorKey(c.PostForm("email"))
+		if email != "" {
+			go sendPortalLink(email)
+		}
+		renderPortalPage(c, portalLoginPage, gin.H{"Sent": true})
+	})
+
+	router.GET("/portal/session", func(c *gin.Context) {
+		cfg := configs.current()
+		token := c.Query("token")
+		payload, ok := verifyToken(token)
+		parts := strings.Split(payload, "|")
+		if !ok || len(parts) != 3 || parts[0] != "portal" {
+			renderPortalPage(c, portalLoginPage, gin.H{"Error": "That sign in link is not valid. Please request a new one."})
+			return
+		}
+		expires, err := strconv.ParseInt(parts[2], 10, 64)
+		if err != nil || time.Now().After(time.Unix(expires, 0)) {
+			renderPortalPage(c, portalLoginPage, gin.H{"Error": "That sign in link has expired. Please request a new one."})
+			return
+		}
+
+		session := portalSession{ID: newID() + newID(), Email: parts[1], CSRF: newID(), Expires: time.Now().Add(portalSessionDuration)}
+		linkHash := sha256.Sum256([]byte(token))
+		var data portalData
+		err = dataStore().update(portalDocument, &data, func() error {
+			now := time.Now()
+			if data.UsedLinks == nil {
+				data.UsedLinks = map[string]time.Time{}
+			}
+			if _, used := data.UsedLinks[hex.EncodeToString(linkHash[:])]; used {
+				return errors.New("used")
+			}
+			data.UsedLinks[hex.EncodeToString(linkHash[:])] = time.Unix(expires, 0)
+			for hash, until := range data.UsedLinks {
+				if now.After(until) {
+					delete(data.UsedLinks, hash)
+				}
+			}
+			var live []portalSession
+			for _, existing := range data.Sessions {
+				if now.Before(existing.Expires) {
+					live = append(live, existing)
+				}
+			}
+			data.Sessions = append(live, session)
+			return nil
+		})
+		if err != nil {
+			renderPortalPage(c, portalLoginPage, gin.H{"Error": "That sign in link was already used. Please request a new one."})
+			return
+		}
+		http.SetCookie(c.Writer, &http.Cookie{
+			Name:     portalCookie,
+			Value:    session.ID,
+			Path:     "/portal",
+			MaxAge:   int(portalSessionDuration.Seconds()),
+			Secure:   cfg.Server.GinMode == "release",
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		})
+		c.Redirect(http.StatusSeeOther, "/portal")
+	})
+
+	portal := router.Group("/portal", requirePortalSession)
+
+	portal.GET("", func(c *gin.Context) {
+		cfg := configs.current()
+		session := c.MustGet("portalSession").(portalSession)
+		gifts, err := donorGifts(session.Email)
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		years := map[int]bool{}
+		for _, gift := range gifts {
+			years[gift.Date.In(calendar().location).Year()] = true
+		}
+		var statementYears []int
+		for year := range years {
+			statementYears = append(statementYears, year)
+		}
+		sort.Sort(sort.Reverse(sort.IntSlice(statementYears)))
+
+		subscriptions, err := donorSubscriptions(cfg, session.Email)
+		if err != nil {
+			fmt.Println(err)
+		}
+		renderPortalPage(c, portalHomePage, gin.H{
+			"Session":       session,
+			"Donor":         donorProfile(session.Email, gifts),
+			"Gifts":         gifts,
+			"Years":         statementYears,
+			"Subscriptions": subscriptions,
+			"Message":       c.Query("message"),
+		})
+	})
+
+	// The receipt exactly as it was emailed
+	portal.GET("/receipts/:gift", func(c *gin.Context) {
+		session := c.MustGet("portalSession").(portalSession)
+		gift, _, ok := findDonorGift(c, session)
+		if !ok {
+			return
+		}
+		receipt, ok := findGiftReceipt(c, gift)
+		if !ok {
+			return
+		}
+		c.Header("Content-Disposition", "attachment; filename=\"receipt-"+receipt.label()+".html\"")
+		c.Data(200, "text/html; charset=utf-8", []byte(receiptPage(*receipt)))
+	})
+
+	portal.GET("/receipts/:gift/pdf", func(c *gin.Context) {
+		cfg := configs.current()
+		session := c.MustGet("portalSession").(portalSession)
+		gift, _, ok := findDonorGift(c, session)
+		if !ok {
+			return
+		}
+		receipt, ok := findGiftReceipt(c, gift)
+		if !ok {
+			return
+		}
+		pdf, err := renderReceiptPDF(cfg, *receipt)
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		c.Header("Content-Disposition", "attachment; filename=\"receipt-"+receipt.label()+".pdf\"")
+		c.Data(200, "application/pdf", pdf)
+	})
+
+	portal.GET("/statements/:year", func(c *gin.Context) {
+		cfg := configs.current()
+		session := c.MustGet("portalSession").(portalSession)
+		year, err := strconv.Atoi(c.Param("year"))
+		if err != nil {
+			c.String(http.StatusBadRequest, "Invalid year")
+			return
+		}
+		from, to := calendar().calendarYear(year)
+		donors, err := giftsByDonor(from, to)
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		gifts := donors[session.Email]
+		if len(gifts) == 0 {
+			c.String(http.StatusNotFound, "No gifts in "+c.Param("year"))
+			return
+		}
+		c.Header("Content-Disposition", "attachment; filename=\"giving-statement-"+c.Param("year")+".html\"")
+		org, err := organizationProfile(cfg)
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		c.Data(200, "text/html; charset=utf-8", []byte(renderStatement(year, donorProfile(session.Email, gifts), gifts, org)))
+	})
+
+	portal.POST("/address", func(c *gin.Context) {
+		session := c.MustGet("portalSession").(portalSession)
+		err := saveDonorProfile(Donor{
+			Email: session.Email,
+			Name:  strings.TrimSpace(c.PostForm("name")),
+			Addr1: strings.TrimSpace(c.PostForm("addr1")),
+			Addr2: strings.TrimSpace(c.PostForm("addr2")),
+			City:  strings.TrimSpace(c.PostForm("city")),
+			State: strings.TrimSpace(c.PostForm("state")),
+			Zip:   strings.TrimSpace(c.PostForm("zip")),
+			Phone: strings.TrimSpace(c.PostForm("phone")),
+		})
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		c.Redirect(http.StatusSeeOther, "/portal?message="+url.QueryEscape("Your mailing address was updated."))
+	})
+
+	// Emails a corrected copy of a receipt using the donor's current name and address
+	portal.POST("/receipts/:gift/reissue", func(c *gin.Context) {
+		cfg := configs.current()
+		session := c.MustGet("portalSession").(portalSession)
+		gift, gifts, ok := findDonorGift(c, session)
+		if !ok {
+			return
+		}
+		emailData, err := giftReceiptData(cfg, gift, donorProfile(session.Email, gifts))
+		if err != nil {
+			c.String(http.StatusNotFound, "A receipt is not available for this gift. Your year-end statement covers it instead.")
+			return
+		}
+		original, ok := findGiftReceipt(c, gift)
+		if !ok {
+			return
+		}
+		emailData.InlineImages = true
+		issued, err := correctReceipt(cfg, *original, newReceiptFields(emailData))
+		var receipt *mailMessage
+		if err == nil {
+			receipt, err = receiptMessage(cfg, issued, translate(issued.Fields.Locale, "receipt.corrected"))
+		}
+		if err == nil {
+			receipt.Bcc = []string{EmailFinance}
+			_, err = enqueueMail(MailAccountReceipts, "receipt", "receipt:"+issued.ID, receipt)
+		}
+		if err != nil {
+			fmt.Println(err)
+			fmt.Println("ERROR: CORRECTED RECEIPT FOR GIFT " + gift.ID + " COULD NOT BE QUEUED")
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		c.Redirect(http.StatusSeeOther, "/portal?message="+url.QueryEscape("A corrected receipt was emailed to you."))
+	})
+
+	// Stops a recurring gift at the end of the current period
+	portal.POST("/recurring/:id/cancel", func(c *gin.Context) {
+		cfg := configs.current()
+		session := c.MustGet("portalSession").(portalSession)
+		subscriptions, err := donorSubscriptions(cfg, session.Email)
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		for _, subscription := range subscriptions {
+			if subscription.ID != c.Param("id") {
+				continue
+			}
+			var updated stripe.Subscription
+			err = stripe.GetBackend(stripe.APIBackend).Call("POST", "/v1/subscriptions/"+subscription.ID, cfg.stripeKey(), &stripe.SubscriptionParams{CancelAtPeriodEnd: stripe.Bool(true)}, &updated)
+			if err != nil {
+				fmt.Println(err)
+				c.String(http.StatusInternalServerError, "Error")
+				return
+			}
+			c.Redirect(http.StatusSeeOther, "/portal?message="+url.QueryEscape("Your recurring gift will stop at the end of the current period."))
+			return
+		}
+		c.String(http.StatusNotFound, "No such recurring gift")
+	})
+
+	portal.POST("/logout", func(c *gin.Context) {
+		session := c.MustGet("portalSession").(portalSession)
+		var data portalData
+		err := dataStore().update(portalDocument, &data, func() error {
+			for i := range data.Sessions {
+				if data.Sessions[i].ID == session.ID {
+					data.Sessions = append(data.Sessions[:i], data.Sessions[i+1:]...)
+					return nil
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			fmt.Println(err)
+		}
+		http.SetCookie(c.Writer, &http.Cookie{Name: portalCookie, Value: "", Path: "/portal", MaxAge: -1})
+		c.Redirect(http.StatusSeeOther, "/portal/login")
+	})
+}
+
+// Loads the session from the cookie, and checks the CSRF token on every POST
+func requirePortalSession(c *gin.Context) {
+	id, err := c.Cookie(portalCookie)
+	if err != nil {
+		c.Redirect(http.StatusSeeOther, "/portal/login")
+		c.Abort()
+		return
+	}
+	var data portalData
+	err = dataStore().load(portalDocument, &data)
+	if err != nil {
+		fmt.Println(err)
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	for _, session := range data.Sessions {
+		if session.ID == id && time.Now().Before(session.Expires) {
+			if c.Request.Method == "POST" && c.PostForm("csrf") != session.CSRF {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.Set("portalSession", session)
+			return
+		}
+	}
+	c.Redirect(http.StatusSeeOther, "/portal/login")
+	c.Abort()
+}
+
+func sendPortalLink(email string) {
+	gifts, err := donorGifts(email)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if len(gifts) == 0 {
+		return
+	}
+	token, err := signToken("portal|" + email + "|" + strconv.FormatInt(time.Now().Add(portalLinkDuration).Unix(), 10))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	link := SiteURL + "/portal/session?token=" + url.QueryEscape(token)
+	body := "<html><body style=\"font-family: 'Times New Roman', Times, serif;\"><p>Use the link below to sign in to your Pathfinders Robotics donor page. It works once and expires in 15 minutes.</p><p><a href=\"" + link + "\">Sign in</a></p><p>If you didn't ask to sign in, you can ignore this email.</p></body></html>"
+	// The outbox's first retries come well within the link's 15 minutes
+	err = queueHTMLMail(MailAccountWebServer, "portal-link", "donor:"+donorKey(email), []string{email}, "Sign in to your Pathfinders Robotics donor page", body)
+	if err != nil {
+		fmt.Println(err)
+		fmt.Println("ERROR: DONOR PORTAL SIGN IN LINK COULD NOT BE QUEUED")
+	}
+}
+
+// Every gift in the ledger from this donor, oldest first
+func donorGifts(email string) ([]Gift, error) {
+	donors, err := giftsByDonor(time.Time{}, time.Now().AddDate(1, 0, 0))
+	if err != nil {
+		return nil, err
+	}
+	return donors[donorKey(email)], nil
+}
+
+func findDonorGift(c *gin.Context, session portalSession) (Gift, []Gift, bool) {
+	gifts, err := donorGifts(session.Email)
+	if err != nil {
+		fmt.Println(err)
+		c.String(http.StatusInternalServerError, "Error")
+		return Gift{}, nil, false
+	}
+	for _, gift := range gifts {
+		if gift.ID == c.Param("gift") {
+			return gift, gifts, true
+		}
+	}
+	c.String(http.StatusNotFound, "No such gift")
+	return Gift{}, nil, false
+}
+
+// The receipt the gift was last issued, or a 404 if it never had one. Only gifts paid through Stripe get
+// receipts; the year-end statement covers the rest.
+func findGiftReceipt(c *gin.Context, gift Gift) (*Receipt, bool) {
+	var receipt *Receipt
+	var err error
+	if gift.StripeID != "" {
+		receipt, err = latestReceipt(chargeReceiptReference(gift.StripeID))
+	}
+	if err != nil {
+		fmt.Println(err)
+		c.String(http.StatusInternalServerError, "Error")
+		return nil, false
+	}
+	if receipt == nil {
+		c.String(http.StatusNotFound, "A receipt is not available for this gift. Your year-end statement covers it instead.")
+		return nil, false
+	}
+	return receipt, true
+}
+
+// The EmailData sendPaymentEmail would have used for this gift, dated when the gift was made.
+// Only team gifts have a receipt; everything else is covered by the year-end statement.
+func giftReceiptData(cfg *Config, gift Gift, donor Donor) (EmailData, error) {
+	amount := gift.Amount
+	description := gift.Description
+	preference, _, err := contactPreference(donor.Email)
+	if err != nil {
+		return EmailData{}, err
+	}
+	if gift.Team != "" && !strings.Contains(description, gift.Team) {
+		description = gift.Team
+	}
+	emailData, err := genEmailData(cfg, PaymentData{
+		Amount:      &amount,
+		Description: &description,
+		Name:        &donor.Name,
+		Addr1:       &donor.Addr1,
+		Addr2:       &donor.Addr2,
+		City:        &donor.City,
+		State:       &donor.State,
+		Zip:         &donor.Zip,
+		Email:       &donor.Email,
+		Phone:       &donor.Phone,
+		Locale:      &preference.Locale,
+	})
+	if err != nil {
+		return EmailData{}, err
+	}
+	emailData.Date = calendar().formatDate(gift.Date)
+	emailData.Received = gift.Date
+	emailData.CurrentSeason = calendar().season(programForSuffix(emailData.FIRSTSuffix), gift.Date)
+	return emailData, nil
+}
+
+// Active Stripe subscriptions for customers with this email
+func donorSubscriptions(cfg *Config, email string) ([]*stripe.Subscription, error) {
+	key := cfg.stripeKey()
+	if key == "" {
+		return nil, nil
+	}
+	backend := stripe.GetBackend(stripe.APIBackend)
+	customerParams := &stripe.CustomerListParams{}
+	customerParams.Filters.AddFilter("email", "", email)
+	var customers stripe.CustomerList
+	err := backend.Call("GET", "/v1/customers", key, customerParams, &customers)
+	if err != nil {
+		return nil, err
+	}
+	var subscriptions []*stripe.Subscription
+	for _, customer := range customers.Data {
+		var list stripe.SubscriptionList
+		err = backend.Call("GET", "/v1/subscriptions", key, &stripe.SubscriptionListParams{Customer: customer.ID, Status: "active"}, &list)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, list.Data...)
+	}
+	return subscriptions, nil
+}
+
+func renderPortalPage(c *gin.Context, page *template.Template, data gin.H) {
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Header("Cache-Control", "no-store")
+	err := page.Execute(c.Writer, data)
+	if err != nil {
+		fmt.Println(err)
+	}
+}
+
+// The calendar is looked up on each call, since loading the configuration replaces it after these are made
+var portalFuncs = template.FuncMap{
+	"dollars": formatCents,
+	"date":    func(t time.Time) string { return calendar().formatDate(t) },
+	"unix":    func(t int64) string { return calendar().formatDate(time.Unix(t, 0)) },
+}
+
+var portalLoginPage = template.Must(template.New("login").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8" />
+<meta name="viewport" content="width=device-width, initial-scale=1.0" />
+<title>Pathfinders Robotics Donor Sign In</title>
+</head>
+<body>
+<h1>Donor Sign In</h1>
+{{if .Error}}<p><b>{{.Error}}</b></p>{{end}}
+{{if .Sent}}
+<p>If that email address is on any of our donation records, a sign in link is on its way. It works once and expires in 15 minutes.</p>
+{{else}}
+<p>Enter the email address you used when donating and we'll send you a link to sign in.</p>
+<form method="POST" action="/portal/login">
+<input type="email" name="email" required autocomplete="email" />
+<button type="submit">Email me a link</button>
+</form>
+{{end}}
+</body>
+</html>
+`))
+
+var portalHomePage = template.Must(template.New("home").Funcs(portalFuncs).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8" />
+<meta name="viewport" content="width=device-width, initial-scale=1.0" />
+<title>Your Giving - Pathfinders Robotics</title>
+</head>
+<body>
+<h1>Your Giving</h1>
+<form method="POST" action="/portal/logout"><input type="hidden" name="csrf" value="{{.Session.CSRF}}" /><button type="submit">Sign out</button></form>
+{{if .Message}}<p><b>{{.Message}}</b></p>{{end}}
+
+<h2>Gifts</h2>
+<table cellpadding="4">
+<tr><th>Date</th><th>Description</th><th>Amount</th><th></th></tr>
+{{range .Gifts}}
+<tr>
+<td>{{date .Date}}</td>
+<td>{{if .Team}}{{.Team}}{{else}}{{.Description}}{{end}}</td>
+<td>${{dollars .Amount}}</td>
+<td>{{if and .Team .StripeID}}<a href="/portal/receipts/{{.ID}}">Download receipt</a> <a href="/portal/receipts/{{.ID}}/pdf">PDF</a>
+<form method="POST" action="/portal/receipts/{{.ID}}/reissue"><input type="hidden" name="csrf" value="{{$.Session.CSRF}}" /><button type="submit">Email a corrected receipt</button></form>{{end}}</td>
+</tr>
+{{end}}
+</table>
+
+<h2>Year-end statements</h2>
+<ul>{{range .Years}}<li><a href="/portal/statements/{{.}}">{{.}} giving statement</a></li>{{end}}</ul>
+
+<h2>Recurring gifts</h2>
+{{range .Subscriptions}}
+<p>{{if .Plan}}${{dollars .Plan.Amount}} every {{.Plan.Interval}}{{end}}, renews {{unix .CurrentPeriodEnd}}
+{{if .CancelAtPeriodEnd}}<b>(ends at the end of this period)</b>{{else}}
+<form method="POST" action="/portal/recurring/{{.ID}}/cancel"><input type="hidden" name="csrf" value="{{$.Session.CSRF}}" /><button type="submit">Stop this recurring gift</button></form>{{end}}</p>
+{{else}}
+<p>You have no recurring card gifts. To change a recurring check or bank draft, please contact {{"finance@pathfindersrobotics.org"}}.</p>
+{{end}}
+
+<h2>Mailing address</h2>
+<p>Receipts and statements are addressed to:</p>
+<form method="POST" action="/portal/address">
+<input type="hidden" name="csrf" value="{{.Session.CSRF}}" />
+<p><label>Name <input name="name" value="{{.Donor.Name}}" /></label></p>
+<p><label>Address <input name="addr1" value="{{.Donor.Addr1}}" /></label></p>
+<p><label>Address line 2 <input name="addr2" value="{{.Donor.Addr2}}" /></label></p>
+<p><label>City <input name="city" value="{{.Donor.City}}" /></label></p>
+<p><label>State <input name="state" value="{{.Donor.State}}" /></label></p>
+<p><label>Zip <input name="zip" value="{{.Donor.Zip}}" /></label></p>
+<p><label>Phone <input name="phone" value="{{.Donor.Phone}}" /></label></p>
+<button type="submit">Save</button>
+</form>
+</body>
+</html>
+`))
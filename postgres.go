@@ -0,0 +1,415 @@
+package main
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Just enough of the PostgreSQL frontend/backend protocol (version 3.0) for the store to keep its documents in
+// Heroku Postgres: TLS, cleartext, MD5 and SCRAM-SHA-256 passwords, and statements with text parameters whose
+// results are read as text. https://www.postgresql.org/docs/current/protocol.html
+
+const postgresTimeout time.Duration = 30 * time.Second
+
+type postgresConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	// Set when the connection failed partway through an exchange and can't be used again
+	broken bool
+	// Exchanges completed on this connection, so a caller can tell a stale connection from a failed statement
+	exchanges int
+}
+
+// An ErrorResponse from the server. The connection is still usable after one.
+type postgresError struct {
+	Severity string
+	Code     string
+	Message  string
+}
+
+func (e *postgresError) Error() string {
+	return "postgres: " + e.Severity + " " + e.Code + ": " + e.Message
+}
+
+// Connects to a postgres:// URL such as Heroku's 'DATABASE_URL'. As with libpq, sslmode=require (or prefer, the
+// default) encrypts without checking the server's certificate, and verify-full checks it against the host name.
+func dialPostgres(rawURL string) (*postgresConn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "postgres" && u.Scheme != "postgresql" {
+		return nil, errors.New("postgres: '" + u.Scheme + "' is not a postgres:// URL")
+	}
+	user := u.User.Username()
+	password, _ := u.User.Password()
+	database := strings.TrimPrefix(u.Path, "/")
+	if database == "" {
+		database = user
+	}
+	address := u.Host
+	if u.Port() == "" {
+		address = net.JoinHostPort(u.Hostname(), "5432")
+	}
+	sslMode := u.Query().Get("sslmode")
+	if sslMode == "" {
+		sslMode = "prefer"
+	}
+	if sslMode != "disable" && sslMode != "prefer" && sslMode != "require" && sslMode != "verify-full" {
+		return nil, errors.New("postgres: sslmode '" + sslMode + "' is not supported")
+	}
+
+	conn, err := net.DialTimeout("tcp", address, postgresTimeout)
+	if err != nil {
+		return nil, err
+	}
+	conn.SetDeadline(time.Now().Add(postgresTimeout))
+	if sslMode != "disable" {
+		// SSLRequest: a length, then the code 1234 5679 in place of a protocol version
+		_, err = conn.Write([]byte{0, 0, 0, 8, 0x04, 0xd2, 0x16, 0x2f})
+		answer := make([]byte, 1)
+		if err == nil {
+			_, err = io.ReadFull(conn, answer)
+		}
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		if answer[0] == 'S' {
+			conn = tls.Client(conn, &tls.Config{ServerName: u.Hostname(), InsecureSkipVerify: sslMode != "verify-full"})
+		} else if sslMode != "prefer" {
+			conn.Close()
+			return nil, errors.New("postgres: the server does not support TLS")
+		}
+	}
+
+	c := &postgresConn{conn: conn, reader: bufio.NewReader(conn)}
+	err = c.startup(user, password, database)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	conn.SetDeadline(time.Time{})
+	return c, nil
+}
+
+func (c *postgresConn) close() {
+	c.send('X', nil)
+	c.conn.Close()
+}
+
+func (c *postgresConn) startup(user string, password string, database string) error {
+	var body []byte
+	body = appendInt32(body, 196608) // Protocol version 3.0
+	for _, parameter := range []string{"user", user, "database", database, "application_name", "pathfinders-server", "client_encoding", "UTF8"} {
+		body = append(append(body, parameter...), 0)
+	}
+	body = append(body, 0)
+	_, err := c.conn.Write(append(appendInt32(nil, int32(len(body)+4)), body...))
+	if err != nil {
+		return err
+	}
+
+	var scram *scramClient
+	for {
+		kind, message, err := c.receive()
+		if err != nil {
+			return err
+		}
+		switch kind {
+		case 'R':
+			if len(message) < 4 {
+				return errors.New("postgres: short authentication request")
+			}
+			switch binary.BigEndian.Uint32(message) {
+			case 0: // AuthenticationOk
+			case 3: // AuthenticationCleartextPassword
+				err = c.send('p', append([]byte(password), 0))
+			case 5: // AuthenticationMD5Password, with a four byte salt
+				if len(message) < 8 {
+					return errors.New("postgres: short MD5 salt")
+				}
+				inner := md5.Sum([]byte(password + user))
+				outer := md5.Sum(append([]byte(hex.EncodeToString(inner[:])), message[4:8]...))
+				err = c.send('p', append([]byte("md5"+hex.EncodeToString(outer[:])), 0))
+			case 10: // AuthenticationSASL, with the mechanisms the server accepts
+				if !strings.Contains("\x00"+string(message[4:]), "\x00SCRAM-SHA-256\x00") {
+					return errors.New("postgres: the server does not offer SCRAM-SHA-256")
+				}
+				scram, err = newSCRAMClient(password)
+				if err == nil {
+					first := scram.clientFirst()
+					response := append([]byte("SCRAM-SHA-256"), 0)
+					response = appendInt32(response, int32(len(first)))
+					err = c.send('p', append(response, first...))
+				}
+			case 11: // AuthenticationSASLContinue
+				if scram == nil {
+					return errors.New("postgres: SASL continued before it started")
+				}
+				var final string
+				final, err = scram.clientFinal(string(message[4:]))
+				if err == nil {
+					err = c.send('p', []byte(final))
+				}
+			case 12: // AuthenticationSASLFinal
+				if scram == nil {
+					return errors.New("postgres: SASL finished before it started")
+				}
+				err = scram.verifyServerFinal(string(message[4:]))
+			default:
+				return errors.New("postgres: unsupported authentication method " + strconv.Itoa(int(binary.BigEndian.Uint32(message))))
+			}
+			if err != nil {
+				return err
+			}
+		case 'E':
+			return parsePostgresError(message)
+		case 'Z':
+			return nil
+		}
+		// BackendKeyData, ParameterStatus and NoticeResponse aren't needed
+	}
+}
+
+// Runs one statement with text parameters and returns its rows, with each column as text. A NULL reads as "".
+func (c *postgresConn) exec(query string, args ...string) ([][]string, error) {
+	if c.broken {
+		return nil, errors.New("postgres: the connection was lost")
+	}
+	c.conn.SetDeadline(time.Now().Add(postgresTimeout))
+	defer c.conn.SetDeadline(time.Time{})
+
+	// Parse, Bind, Execute and Sync in one write, for the unnamed statement and portal
+	var out []byte
+	parse := append(append([]byte{0}, query...), 0, 0, 0)
+	out = appendMessage(out, 'P', parse)
+	bind := []byte{0, 0, 0, 0}
+	bind = appendInt16(bind, int16(len(args)))
+	for _, arg := range args {
+		bind = appendInt32(bind, int32(len(arg)))
+		bind = append(bind, arg...)
+	}
+	bind = appendInt16(bind, 0)
+	out = appendMessage(out, 'B', bind)
+	out = appendMessage(out, 'E', []byte{0, 0, 0, 0, 0})
+	out = appendMessage(out, 'S', nil)
+	_, err := c.conn.Write(out)
+	if err != nil {
+		c.broken = true
+		return nil, err
+	}
+
+	var rows [][]string
+	var failed error
+	for {
+		kind, message, err := c.receive()
+		if err != nil {
+			c.broken = true
+			return nil, err
+		}
+		switch kind {
+		case 'D':
+			row, err := parsePostgresRow(message)
+			if err != nil {
+				c.broken = true
+				return nil, err
+			}
+			rows = append(rows, row)
+		case 'E':
+			failed = parsePostgresError(message)
+		case 'Z':
+			c.exchanges++
+			if failed != nil {
+				return nil, failed
+			}
+			return rows, nil
+		}
+		// ParseComplete, BindComplete, CommandComplete, EmptyQueryResponse and notices need nothing
+	}
+}
+
+func (c *postgresConn) send(kind byte, body []byte) error {
+	_, err := c.conn.Write(appendMessage(nil, kind, body))
+	return err
+}
+
+func (c *postgresConn) receive() (byte, []byte, error) {
+	header := make([]byte, 5)
+	_, err := io.ReadFull(c.reader, header)
+	if err != nil {
+		return 0, nil, err
+	}
+	length := int(binary.BigEndian.Uint32(header[1:]))
+	if length < 4 || length > 1<<30 {
+		return 0, nil, errors.New("postgres: bad message length")
+	}
+	body := make([]byte, length-4)
+	_, err = io.ReadFull(c.reader, body)
+	return header[0], body, err
+}
+
+func parsePostgresRow(message []byte) ([]string, error) {
+	if len(message) < 2 {
+		return nil, errors.New("postgres: short data row")
+	}
+	count := int(binary.BigEndian.Uint16(message))
+	message = message[2:]
+	row := make([]string, count)
+	for i := range row {
+		if len(message) < 4 {
+			return nil, errors.New("postgres: short data row")
+		}
+		length := int(int32(binary.BigEndian.Uint32(message)))
+		message = message[4:]
+		if length < 0 {
+			continue
+		}
+		if len(message) < length {
+			return nil, errors.New("postgres: short data row")
+		}
+		row[i] = string(message[:length])
+		message = message[length:]
+	}
+	return row, nil
+}
+
+// Fields are a type byte and a string each, ending with a zero byte
+func parsePostgresError(message []byte) error {
+	e := &postgresError{}
+	for _, field := range strings.Split(string(message), "\x00") {
+		if field == "" {
+			continue
+		}
+		switch field[0] {
+		case 'S':
+			e.Severity = field[1:]
+		case 'C':
+			e.Code = field[1:]
+		case 'M':
+			e.Message = field[1:]
+		}
+	}
+	return e
+}
+
+func appendMessage(out []byte, kind byte, body []byte) []byte {
+	out = append(out, kind)
+	out = appendInt32(out, int32(len(body)+4))
+	return append(out, body...)
+}
+
+func appendInt32(out []byte, n int32) []byte {
+	return append(out, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+}
+
+func appendInt16(out []byte, n int16) []byte {
+	return append(out, byte(n>>8), byte(n))
+}
+
+// SCRAM-SHA-256 (RFC 5802 and RFC 7677) as PostgreSQL uses it: no channel binding, and an empty user name since
+// the server takes the one from the startup message. Passwords aren't SASLprep normalized, which only matters
+// for non-ASCII passwords; Heroku's are ASCII.
+type scramClient struct {
+	password        string
+	clientNonce     string
+	clientFirstBare string
+	serverSignature []byte
+}
+
+func newSCRAMClient(password string) (*scramClient, error) {
+	nonce := make([]byte, 18)
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return nil, err
+	}
+	return &scramClient{password: password, clientNonce: base64.StdEncoding.EncodeToString(nonce)}, nil
+}
+
+func (s *scramClient) clientFirst() string {
+	s.clientFirstBare = "n=,r=" + s.clientNonce
+	return "n,," + s.clientFirstBare
+}
+
+func (s *scramClient) clientFinal(serverFirst string) (string, error) {
+	attributes := scramAttributes(serverFirst)
+	nonce, salt64 := attributes["r"], attributes["s"]
+	iterations, err := strconv.Atoi(attributes["i"])
+	if err != nil {
+		return "", errors.New("postgres: bad SCRAM iteration count")
+	}
+	if !strings.HasPrefix(nonce, s.clientNonce) || len(nonce) == len(s.clientNonce) {
+		return "", errors.New("postgres: the server's SCRAM nonce doesn't extend ours")
+	}
+	salt, err := base64.StdEncoding.DecodeString(salt64)
+	if err != nil || iterations < 1 {
+		return "", errors.New("postgres: bad SCRAM salt or iteration count")
+	}
+
+	salted := pbkdf2SHA256([]byte(s.password), salt, iterations)
+	clientKey := hmacSHA256(salted, []byte("Client Key"))
+	storedKey := sha256.Sum256(clientKey)
+	withoutProof := "c=biws,r=" + nonce // biws is "n,," in base64
+	authMessage := []byte(s.clientFirstBare + "," + serverFirst + "," + withoutProof)
+	proof := hmacSHA256(storedKey[:], authMessage)
+	for i := range proof {
+		proof[i] ^= clientKey[i]
+	}
+	s.serverSignature = hmacSHA256(hmacSHA256(salted, []byte("Server Key")), authMessage)
+	return withoutProof + ",p=" + base64.StdEncoding.EncodeToString(proof), nil
+}
+
+func (s *scramClient) verifyServerFinal(serverFinal string) error {
+	attributes := scramAttributes(serverFinal)
+	if attributes["e"] != "" {
+		return errors.New("postgres: SCRAM authentication failed: " + attributes["e"])
+	}
+	signature, err := base64.StdEncoding.DecodeString(attributes["v"])
+	if err != nil || !hmac.Equal(signature, s.serverSignature) {
+		return errors.New("postgres: the server's SCRAM signature is wrong")
+	}
+	return nil
+}
+
+func scramAttributes(message string) map[string]string {
+	attributes := map[string]string{}
+	for _, attribute := range strings.Split(message, ",") {
+		if len(attribute) >= 2 && attribute[1] == '=' {
+			attributes[attribute[:1]] = attribute[2:]
+		}
+	}
+	return attributes
+}
+
+func hmacSHA256(key []byte, message []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(message)
+	return mac.Sum(nil)
+}
+
+// PBKDF2 (RFC 8018) with HMAC-SHA-256, for one 32 byte block
+func pbkdf2SHA256(password []byte, salt []byte, iterations int) []byte {
+	u := hmacSHA256(password, append(append([]byte{}, salt...), 0, 0, 0, 1))
+	result := append([]byte{}, u...)
+	for i := 1; i < iterations; i++ {
+		u = hmacSHA256(password, u)
+		for j := range result {
+			result[j] ^= u[j]
+		}
+	}
+	return result
+}
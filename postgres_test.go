@@ -0,0 +1,226 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// The SCRAM-SHA-256 example from RFC 7677 section 3, whose client first message names the user
+func TestSCRAMKnownAnswer(t *testing.T) {
+	scram := &scramClient{password: "pencil", clientNonce: "rOprNGfwEbeRWgbNEkqO"}
+	scram.clientFirst()
+	scram.clientFirstBare = "n=user,r=rOprNGfwEbeRWgbNEkqO"
+	final, err := scram.clientFinal("r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0,s=W22ZaJ0SNY7soEsUEjb6gQ==,i=4096")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "c=biws,r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0,p=dHzbZapWIk4jUhN+Ute9ytag9zjfMHgsqmmiz7AndVQ="
+	if final != expected {
+		t.Errorf("expected the RFC's client final message\n%s\ngot\n%s", expected, final)
+	}
+	if err := scram.verifyServerFinal("v=6rriTRBi23WpRR/wtup+mMhUZUn/dB5nLTJRsjl95G4="); err != nil {
+		t.Errorf("the RFC's server signature was refused: %v", err)
+	}
+	if scram.verifyServerFinal("v=AAAATRBi23WpRR/wtup+mMhUZUn/dB5nLTJRsjl95G4=") == nil {
+		t.Error("a wrong server signature was accepted")
+	}
+	if _, err := scram.clientFinal("r=someoneElsesNonce,s=W22ZaJ0SNY7soEsUEjb6gQ==,i=4096"); err == nil {
+		t.Error("a server nonce that doesn't extend the client's was accepted")
+	}
+}
+
+// A server that trusts every connection and keeps the documents table in a map. It records the statements it
+// runs, and closes the connection instead of answering the statement named by dropOn.
+type fakePostgres struct {
+	mu         sync.Mutex
+	documents  map[string]string
+	statements []string
+	dropOn     string
+}
+
+func serveFakePostgres(t *testing.T, server *fakePostgres) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	return "postgres://app:secret@" + listener.Addr().String() + "/documents?sslmode=disable"
+}
+
+func (server *fakePostgres) drop(statement string) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	server.dropOn = statement
+}
+
+func (server *fakePostgres) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return
+	}
+	if _, err := io.ReadFull(reader, make([]byte, binary.BigEndian.Uint32(header)-4)); err != nil {
+		return
+	}
+	reply := func(kind byte, body []byte) { conn.Write(appendMessage(nil, kind, body)) }
+	reply('R', []byte{0, 0, 0, 0})
+	reply('Z', []byte{'I'})
+
+	var query string
+	var args []string
+	for {
+		kind := make([]byte, 5)
+		if _, err := io.ReadFull(reader, kind); err != nil {
+			return
+		}
+		body := make([]byte, binary.BigEndian.Uint32(kind[1:])-4)
+		if _, err := io.ReadFull(reader, body); err != nil {
+			return
+		}
+		switch kind[0] {
+		case 'P':
+			query = strings.SplitN(string(body[1:]), "\x00", 2)[0]
+		case 'B':
+			args = nil
+			count := int(binary.BigEndian.Uint16(body[4:]))
+			body = body[6:]
+			for i := 0; i < count; i++ {
+				length := int(binary.BigEndian.Uint32(body))
+				args = append(args, string(body[4:4+length]))
+				body = body[4+length:]
+			}
+		case 'S':
+			server.mu.Lock()
+			if query == server.dropOn {
+				server.dropOn = ""
+				server.mu.Unlock()
+				return
+			}
+			server.statements = append(server.statements, query)
+			reply('1', nil)
+			reply('2', nil)
+			switch {
+			case strings.HasPrefix(query, "SELECT body"):
+				if body, ok := server.documents[args[0]]; ok {
+					row := appendInt16(nil, 1)
+					row = appendInt32(row, int32(len(body)))
+					reply('D', append(row, body...))
+				}
+			case strings.HasPrefix(query, "INSERT"):
+				server.documents[args[0]] = args[1]
+			case query == "FAIL":
+				reply('E', []byte("SERROR\x00C42601\x00Msyntax error\x00\x00"))
+			}
+			server.mu.Unlock()
+			reply('C', []byte("OK\x00"))
+			reply('Z', []byte{'I'})
+		case 'X':
+			return
+		}
+	}
+}
+
+func TestDatabaseStore(t *testing.T) {
+	server := &fakePostgres{documents: map[string]string{}}
+	store, err := newDatabaseStore(serveFakePostgres(t, server))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var gifts giftData
+	err = store.update(giftsDocument, &gifts, func() error {
+		gifts.Gifts = append(gifts.Gifts, Gift{ID: "g1", DonorEmail: "donor@example.com", Amount: 5000})
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.update(giftsDocument, &gifts, func() error {
+		gifts.Gifts = append(gifts.Gifts, Gift{ID: "g2"})
+		return errors.New("changed my mind")
+	})
+	if err == nil || err.Error() != "changed my mind" {
+		t.Fatalf("expected fn's error back, got %v", err)
+	}
+	var loaded giftData
+	err = store.load(giftsDocument, &loaded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Gifts) != 1 || loaded.Gifts[0].ID != "g1" || loaded.Gifts[0].Amount != 5000 {
+		t.Errorf("unexpected gifts: %+v", loaded.Gifts)
+	}
+
+	expected := []string{
+		"CREATE TABLE IF NOT EXISTS documents (name text PRIMARY KEY, body text NOT NULL)",
+		"BEGIN", "SELECT pg_advisory_xact_lock(hashtext($1))", "SELECT body FROM documents WHERE name = $1",
+		"INSERT INTO documents (name, body) VALUES ($1, $2) ON CONFLICT (name) DO UPDATE SET body = excluded.body", "COMMIT",
+		"BEGIN", "SELECT pg_advisory_xact_lock(hashtext($1))", "SELECT body FROM documents WHERE name = $1", "ROLLBACK",
+		"SELECT body FROM documents WHERE name = $1",
+	}
+	server.mu.Lock()
+	statements := strings.Join(server.statements, "\n")
+	server.mu.Unlock()
+	if statements != strings.Join(expected, "\n") {
+		t.Errorf("expected the statements\n%s\ngot\n%s", strings.Join(expected, "\n"), statements)
+	}
+
+	var missing outboxData
+	if err := store.load(outboxDocument, &missing); err != nil || len(missing.Messages) != 0 {
+		t.Errorf("expected a missing document to load as empty, got %+v, %v", missing, err)
+	}
+	if _, err := store.db.exec("FAIL"); err == nil || err.Error() != "postgres: ERROR 42601: syntax error" || store.db.broken {
+		t.Errorf("expected the server's error with the connection still usable, got %v", err)
+	}
+}
+
+func TestDatabaseStoreReconnects(t *testing.T) {
+	server := &fakePostgres{documents: map[string]string{giftsDocument: `{"gifts": [{"id": "g1"}]}`}}
+	store, err := newDatabaseStore(serveFakePostgres(t, server))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A connection dropped while idle is replaced and the statement runs on the new one
+	server.drop("BEGIN")
+	var gifts giftData
+	err = store.update(giftsDocument, &gifts, func() error {
+		gifts.Gifts = append(gifts.Gifts, Gift{ID: "g2"})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected the update to be retried on a new connection, got %v", err)
+	}
+	server.mu.Lock()
+	written := server.documents[giftsDocument]
+	server.mu.Unlock()
+	if !strings.Contains(written, `"g2"`) {
+		t.Errorf("the update wasn't written: %s", written)
+	}
+
+	// One dropped partway through a transaction isn't retried, since it may have been committed
+	server.drop("COMMIT")
+	err = store.update(giftsDocument, &gifts, func() error { return nil })
+	if err == nil {
+		t.Error("expected an error when the connection drops at COMMIT")
+	}
+	if err := store.load(giftsDocument, &gifts); err != nil {
+		t.Errorf("expected the store to reconnect for the next statement, got %v", err)
+	}
+}
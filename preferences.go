@@ -0,0 +1,347 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Which teams a donor wants season updates from. Consent is given with the "Receive updates from donation
+// recipients" box on the donation form and changed from the preference page or an unsubscribe link.
+// Every change is kept with when and how it was made. Receipts and other transactional mail ignore this;
+// updates, newsletters and the like go through queueDonorUpdate, which checks it and adds the unsubscribe headers.
+
+type ContactPreference struct {
+	Email   string          `json:"email"`
+	Teams   []string        `json:"teams"` // Teams the donor hears from, none means no updates at all
+	Source  string          `json:"source"`
+	Updated time.Time       `json:"updated"`
+	History []consentChange `json:"history"`
+	Locale  string          `json:"locale,omitempty"` // From their latest donation, "" for English
+}
+
+type consentChange struct {
+	Teams  []string  `json:"teams"`
+	Source string    `json:"source"`
+	Date   time.Time `json:"date"`
+}
+
+type preferenceData struct {
+	Preferences []ContactPreference `json:"preferences"`
+}
+
+const preferencesDocument string = "preferences"
+
+const ConsentSourceDonationForm string = "donation form"
+const ConsentSourcePreferencePage string = "preference page"
+const ConsentSourceUnsubscribe string = "unsubscribe link"
+
+// The teams a donor can hear from, in the order the preference page lists them
+var updateTeams = []string{FTCPathfinders13497, FLLPhoenixVoyagers7885}
+
+func contactPreference(email string) (ContactPreference, bool, error) {
+	var data preferenceData
+	err := dataStore().load(preferencesDocument, &data)
+	if err != nil {
+		return ContactPreference{}, false, err
+	}
+	for _, preference := range data.Preferences {
+		if preference.Email == donorKey(email) {
+			return preference, true, nil
+		}
+	}
+	return ContactPreference{Email: donorKey(email)}, false, nil
+}
+
+// Whether the donor agreed to hear from team. Donors who never answered haven't agreed.
+func contactAllowed(email string, team string) (bool, error) {
+	preference, _, err := contactPreference(email)
+	if err != nil {
+		return false, err
+	}
+	return containsTeam(preference.Teams, team), nil
+}
+
+// Changes the teams a donor hears from with change, which is given the current list and returns the new one
+func updateContactPreference(email string, source string, change func(teams []string) []string) (ContactPreference, error) {
+	var updated ContactPreference
+	var data preferenceData
+	err := dataStore().update(preferencesDocument, &data, func() error {
+		index := -1
+		for i := range data.Preferences {
+			if data.Preferences[i].Email == donorKey(email) {
+				index = i
+			}
+		}
+		if index < 0 {
+			data.Preferences = append(data.Preferences, ContactPreference{Email: donorKey(email), Teams: []string{}})
+			index = len(data.Preferences) - 1
+		}
+		preference := &data.Preferences[index]
+		teams := []string{}
+		for _, team := range updateTeams {
+			if containsTeam(change(preference.Teams), team) {
+				teams = append(teams, team)
+			}
+		}
+		now := time.Now()
+		preference.Teams = teams
+		preference.Source = source
+		preference.Updated = now
+		preference.History = append(preference.History, consentChange{Teams: teams, Source: source, Date: now})
+		updated = *preference
+		return nil
+	})
+	return updated, err
+}
+
+// Records the donation form's checkbox for the team the donation went to
+func recordDonationConsent(email string, team string, allowed bool) error {
+	_, err := updateContactPreference(email, ConsentSourceDonationForm, func(teams []string) []string {
+		if allowed {
+			return append(teams, team)
+		}
+		return withoutTeam(teams, team)
+	})
+	return err
+}
+
+// Remembers the language of the donor's latest donation for later emails
+func recordDonorLocale(email string, locale string) error {
+	var data preferenceData
+	return dataStore().update(preferencesDocument, &data, func() error {
+		for i := range data.Preferences {
+			if data.Preferences[i].Email == donorKey(email) {
+				data.Preferences[i].Locale = locale
+				return nil
+			}
+		}
+		if locale != "" {
+			data.Preferences = append(data.Preferences, ContactPreference{Email: donorKey(email), Teams: []string{}, Locale: locale})
+		}
+		return nil
+	})
+}
+
+func containsTeam(teams []string, team string) bool {
+	for _, candidate := range teams {
+		if candidate == team {
+			return true
+		}
+	}
+	return false
+}
+
+func withoutTeam(teams []string, team string) []string {
+	kept := []string{}
+	for _, candidate := range teams {
+		if candidate != team {
+			kept = append(kept, candidate)
+		}
+	}
+	return kept
+}
+
+// Queues a non-transactional email from team to a donor if they agreed to hear from it and mail to them
+// isn't bouncing. The message gets one-click unsubscribe headers (RFC 8058) and a footer linking to the
+// preference page. Returns false when the donor wasn't emailed.
+func queueDonorUpdate(email string, team string, kind string, reference string, subject string, body string) (bool, error) {
+	preference, _, err := contactPreference(email)
+	if err != nil || !containsTeam(preference.Teams, team) {
+		return false, err
+	}
+	flagged, err := donorEmailFlagged(email)
+	if err != nil || flagged {
+		return false, err
+	}
+	unsubscribe, preferences, err := preferenceLinks(email, team)
+	if err != nil {
+		return false, err
+	}
+	footer := "<p style=\"font-size: small; color: #555555;\">" +
+		fmt.Sprintf(translate(preference.Locale, "update.footer"), html.EscapeString(team), html.EscapeString(preferences), html.EscapeString(unsubscribe)) + "</p>"
+	if strings.Contains(body, "</body>") {
+		body = strings.Replace(body, "</body>", footer+"</body>", 1)
+	} else {
+		body += footer
+	}
+	msg := &mailMessage{To: []string{email}, Subject: subject, HTML: body, Unsubscribe: unsubscribe}
+	_, err = enqueueMail(MailAccountWebServer, kind, reference, msg)
+	return err == nil, err
+}
+
+func donorEmailFlagged(email string) (bool, error) {
+	var data donorData
+	err := dataStore().load(donorsDocument, &data)
+	if err != nil {
+		return false, err
+	}
+	for _, donor := range data.Donors {
+		if donor.Email == donorKey(email) {
+			return donor.EmailProblem != nil, nil
+		}
+	}
+	return false, nil
+}
+
+// The one-click unsubscribe link for one team's updates, and the preference page link
+func preferenceLinks(email string, team string) (string, string, error) {
+	unsubscribe, err := signToken("unsubscribe|" + donorKey(email) + "|" + team)
+	if err != nil {
+		return "", "", err
+	}
+	preferences, err := signToken("preferences|" + donorKey(email))
+	if err != nil {
+		return "", "", err
+	}
+	return SiteURL + "/unsubscribe?token=" + url.QueryEscape(unsubscribe), SiteURL + "/preferences?token=" + url.QueryEscape(preferences), nil
+}
+
+// The email and team in a valid unsubscribe token
+func unsubscribeToken(token string) (string, string, bool) {
+	payload, ok := verifyToken(token)
+	parts := strings.SplitN(payload, "|", 3)
+	if !ok || len(parts) != 3 || parts[0] != "unsubscribe" {
+		return "", "", false
+	}
+	return parts[1], parts[2], true
+}
+
+func preferencesToken(token string) (string, bool) {
+	payload, ok := verifyToken(token)
+	parts := strings.SplitN(payload, "|", 2)
+	if !ok || len(parts) != 2 || parts[0] != "preferences" {
+		return "", false
+	}
+	return parts[1], true
+}
+
+func registerPreferenceRoutes(router *gin.Engine) {
+	// Link scanners follow GET links, so opening the link only asks; the button (or the mail client's
+	// one-click POST) does the unsubscribing
+	router.GET("/unsubscribe", func(c *gin.Context) {
+		email, team, ok := unsubscribeToken(c.Query("token"))
+		if !ok {
+			c.String(http.StatusBadRequest, "That unsubscribe link is not valid.")
+			return
+		}
+		_, preferences, err := preferenceLinks(email, team)
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		renderPortalPage(c, unsubscribePage, gin.H{"Email": email, "Team": team, "Token": c.Query("token"), "Preferences": preferences})
+	})
+
+	router.POST("/unsubscribe", func(c *gin.Context) {
+		email, team, ok := unsubscribeToken(c.Query("token"))
+		if !ok {
+			c.String(http.StatusBadRequest, "That unsubscribe link is not valid.")
+			return
+		}
+		_, err := updateContactPreference(email, ConsentSourceUnsubscribe, func(teams []string) []string {
+			return withoutTeam(teams, team)
+		})
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		_, preferences, _ := preferenceLinks(email, team)
+		renderPortalPage(c, unsubscribePage, gin.H{"Email": email, "Team": team, "Done": true, "Preferences": preferences})
+	})
+
+	router.GET("/preferences", func(c *gin.Context) {
+		email, ok := preferencesToken(c.Query("token"))
+		if !ok {
+			c.String(http.StatusBadRequest, "That link is not valid.")
+			return
+		}
+		preference, _, err := contactPreference(email)
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		renderPreferencePage(c, preference, c.Query("token"), false)
+	})
+
+	router.POST("/preferences", func(c *gin.Context) {
+		email, ok := preferencesToken(c.PostForm("token"))
+		if !ok {
+			c.String(http.StatusBadRequest, "That link is not valid.")
+			return
+		}
+		chosen := c.PostFormArray("team")
+		preference, err := updateContactPreference(email, ConsentSourcePreferencePage, func([]string) []string {
+			return chosen
+		})
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		renderPreferencePage(c, preference, c.PostForm("token"), true)
+	})
+}
+
+func renderPreferencePage(c *gin.Context, preference ContactPreference, token string, saved bool) {
+	type teamChoice struct {
+		Name    string
+		Checked bool
+	}
+	var choices []teamChoice
+	for _, team := range updateTeams {
+		choices = append(choices, teamChoice{Name: team, Checked: containsTeam(preference.Teams, team)})
+	}
+	renderPortalPage(c, preferencePage, gin.H{"Email": preference.Email, "Teams": choices, "Token": token, "Saved": saved})
+}
+
+var unsubscribePage = template.Must(template.New("unsubscribe").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8" />
+<meta name="viewport" content="width=device-width, initial-scale=1.0" />
+<title>Unsubscribe - Pathfinders Robotics</title>
+</head>
+<body>
+<h1>Unsubscribe</h1>
+{{if .Done}}
+<p>{{.Email}} won't get any more updates from {{.Team}}. Receipts for your donations will still be sent.</p>
+{{else}}
+<p>Stop sending updates from {{.Team}} to {{.Email}}?</p>
+<form method="POST" action="/unsubscribe?token={{.Token}}">
+<button type="submit">Unsubscribe</button>
+</form>
+{{end}}
+<p><a href="{{.Preferences}}">Choose which teams you hear from</a></p>
+</body>
+</html>
+`))
+
+var preferencePage = template.Must(template.New("preferences").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8" />
+<meta name="viewport" content="width=device-width, initial-scale=1.0" />
+<title>Email Preferences - Pathfinders Robotics</title>
+</head>
+<body>
+<h1>Email Preferences</h1>
+{{if .Saved}}<p><b>Your preferences were saved.</b></p>{{end}}
+<p>Choose which teams send season updates to {{.Email}}. Receipts for your donations are always sent.</p>
+<form method="POST" action="/preferences">
+<input type="hidden" name="token" value="{{.Token}}" />
+{{range .Teams}}<p><label><input type="checkbox" name="team" value="{{.Name}}"{{if .Checked}} checked{{end}} /> {{.Name}}</label></p>
+{{end}}<button type="submit">Save</button>
+</form>
+</body>
+</html>
+`))
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Previews of the payment emails, rendered exactly as sendPaymentEmail would build them but never queued.
+// Used by /admin/preview and the `preview` command to check template changes without a real donation.
+
+type emailPreview struct {
+	Template string              `json:"template"`
+	Account  string              `json:"account"`
+	Headers  map[string][]string `json:"headers"`
+	Envelope []string            `json:"envelope"` // Everyone it would be delivered to, including Bcc
+	HTML     string              `json:"html,omitempty"`
+	Text     string              `json:"text"`
+
+	receipt *Receipt // The receipt the receipt template was rendered from, for its logo and signature
+}
+
+// The templates that can be previewed. For the refund notice the amount is the amount refunded.
+var previewTemplateNames = []string{"receipt", "notification", "refund"}
+
+var errUnknownPreviewTemplate = errors.New("unknown template, expected one of: " + strings.Join(previewTemplateNames, ", "))
+
+func samplePaymentData() PaymentData {
+	amount := 2500
+	description, name, addr1, addr2, city, state, zip, email, phone := FTCPathfinders13497, "Sample Donor", "123 Main St", "Apt 4", "Ames", "IA", "50010", "donor@example.com", "555-555-0100"
+	return PaymentData{Amount: &amount, Description: &description, Name: &name, Addr1: &addr1, Addr2: &addr2, City: &city, State: &state, Zip: &zip, Email: &email, Phone: &phone}
+}
+
+// Fills anything left out of data from the sample donation
+func previewPaymentData(data PaymentData) PaymentData {
+	sample := samplePaymentData()
+	if data.Amount == nil {
+		data.Amount = sample.Amount
+	}
+	for _, field := range []struct{ value, fallback **string }{
+		{&data.Description, &sample.Description}, {&data.Name, &sample.Name}, {&data.Addr1, &sample.Addr1}, {&data.Addr2, &sample.Addr2},
+		{&data.City, &sample.City}, {&data.State, &sample.State}, {&data.Zip, &sample.Zip}, {&data.Email, &sample.Email}, {&data.Phone, &sample.Phone},
+	} {
+		if *field.value == nil {
+			*field.value = *field.fallback
+		}
+	}
+	return data
+}
+
+// The message, the mailbox it would be sent from and, for the receipt template, the receipt
+func previewMessage(cfg *Config, template string, data PaymentData) (*mailMessage, string, *Receipt, error) {
+	known := false
+	for _, name := range previewTemplateNames {
+		known = known || name == template
+	}
+	if !known {
+		return nil, "", nil, errUnknownPreviewTemplate
+	}
+	emailData, err := genEmailData(cfg, previewPaymentData(data))
+	if err != nil {
+		return nil, "", nil, err
+	}
+	switch template {
+	case "receipt":
+		emailData.InlineImages = true
+		receipt := Receipt{ID: "PREVIEW", Number: "PREVIEW", Fields: newReceiptFields(emailData), Issued: time.Now()}
+		receipt.Fields.Number = receipt.Number
+		receipt.Fields, err = receipt.Fields.withOrganization(cfg, receipt.Issued)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		msg, err := donationReceiptMessage(cfg, emailData, receipt)
+		return msg, MailAccountReceipts, &receipt, err
+	case "notification":
+		msg, err := notificationMessage(emailData)
+		return msg, MailAccountWebServer, nil, err
+	case "refund":
+		msg, err := refundNoticeMessage(emailData)
+		return msg, MailAccountReceipts, nil, err
+	}
+	return nil, "", nil, errUnknownPreviewTemplate
+}
+
+func renderPreview(cfg *Config, template string, data PaymentData) (emailPreview, error) {
+	msg, account, receipt, err := previewMessage(cfg, template, data)
+	if err != nil {
+		return emailPreview{}, err
+	}
+	settings, err := cfg.mailAccountSettings(account)
+	if err == nil {
+		msg.From = settings.Username
+	} else {
+		msg.From = account + "@pathfindersrobotics.org"
+	}
+	if msg.FromName == "" {
+		msg.FromName = "Pathfinders Robotics"
+	}
+	raw, err := msg.bytes()
+	if err != nil {
+		return emailPreview{}, err
+	}
+	parsed, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return emailPreview{}, err
+	}
+	text := msg.Text
+	if text == "" {
+		text = htmlToText(msg.HTML)
+	}
+	return emailPreview{Template: template, Account: account, Headers: parsed.Header, Envelope: msg.recipients(), HTML: msg.HTML, Text: text, receipt: receipt}, nil
+}
+
+// Sends the preview to a single test address only, with the subject marked as a test
+func sendPreview(cfg *Config, template string, data PaymentData, to string) error {
+	msg, account, _, err := previewMessage(cfg, template, data)
+	if err != nil {
+		return err
+	}
+	settings, err := cfg.mailAccountSettings(account)
+	if err != nil {
+		return err
+	}
+	msg.To, msg.Cc, msg.Bcc = []string{to}, nil, nil
+	msg.Subject = "[TEST] " + msg.Subject
+	return sendMail(settings, msg)
+}
+
+func registerPreviewRoutes(admin *gin.RouterGroup, configs *liveConfig) {
+	// Optional PaymentData as the JSON body; ?format=html or ?format=text shows just that part
+	preview := func(c *gin.Context) {
+		// Decoded without binding, since any field left out comes from the sample donation
+		var data PaymentData
+		if c.Request.ContentLength > 0 {
+			err := json.NewDecoder(c.Request.Body).Decode(&data)
+			if err != nil {
+				c.String(http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+		result, err := renderPreview(configs.current(), c.Param("template"), data)
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		switch c.Query("format") {
+		case "html":
+			// Served with the URL images so the page shows them, where the real email attaches them inline
+			if result.receipt != nil {
+				result.HTML = receiptPage(Receipt{HTML: result.HTML, Fields: result.receipt.Fields})
+			}
+			c.Data(200, "text/html; charset=utf-8", []byte(result.HTML))
+		case "text":
+			c.String(200, result.Text)
+		default:
+			c.JSON(200, result)
+		}
+	}
+	admin.GET("/preview/:template", preview)
+	admin.POST("/preview/:template", preview)
+
+	admin.POST("/preview/:template/send", func(c *gin.Context) {
+		var body struct {
+			To   string      `json:"to"`
+			Data PaymentData `json:"data"`
+		}
+		err := json.NewDecoder(c.Request.Body).Decode(&body)
+		if err != nil || body.To == "" {
+			c.String(http.StatusBadRequest, "Expected {\"to\": address, \"data\": optional PaymentData}")
+			return
+		}
+		err = sendPreview(configs.current(), c.Param("template"), body.Data, body.To)
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		c.JSON(200, gin.H{"sent": body.To})
+	})
+}
@@ -0,0 +1,283 @@
+package main
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// A decoder for what encodeQR produces (byte mode, level M, versions 1 to 10), written from the spec's tables
+// rather than the encoder's, so a round trip catches placement, masking and error correction mistakes.
+
+// Total codewords, error correction codewords per block and number of blocks at level M
+var qrTestBlocks = [][3]int{{26, 10, 1}, {44, 16, 1}, {70, 26, 1}, {100, 18, 2}, {134, 24, 2}, {172, 16, 4}, {196, 18, 4}, {242, 22, 4}, {292, 22, 5}, {346, 26, 5}}
+
+var qrTestAlignment = [][]int{nil, {6, 18}, {6, 22}, {6, 26}, {6, 30}, {6, 34}, {6, 22, 38}, {6, 24, 42}, {6, 26, 46}, {6, 28, 50}}
+
+// The format strings for level M with masks 0 to 7, and the version strings for versions 7 to 10
+var qrTestFormats = []int{0x5412, 0x5125, 0x5E7C, 0x5B4B, 0x45F9, 0x40CE, 0x4F97, 0x4AA0}
+var qrTestVersions = map[int]int{7: 0x07C94, 8: 0x085BC, 9: 0x09A99, 10: 0x0A4D3}
+
+func qrTestDecode(t *testing.T, image []byte, scale int) []byte {
+	img, err := png.Decode(bytes.NewReader(image))
+	if err != nil {
+		t.Fatal(err)
+	}
+	size := img.Bounds().Dx()/scale - 8
+	version := (size - 17) / 4
+	if size != version*4+17 || version < 1 || version > 10 {
+		t.Fatalf("unexpected size %d", size)
+	}
+	dark := func(x, y int) bool {
+		r, _, _, _ := img.At((x+4)*scale+scale/2, (y+4)*scale+scale/2).RGBA()
+		return r < 0x8000
+	}
+	for y := -4; y < size+4; y++ {
+		for x := -4; x < size+4; x++ {
+			if (x < 0 || y < 0 || x >= size || y >= size) && dark(x, y) {
+				t.Fatalf("the quiet zone is dark at %d,%d", x, y)
+			}
+		}
+	}
+
+	format, second := 0, 0
+	for i := 0; i < 15; i++ {
+		var a, b bool
+		switch {
+		case i < 6:
+			a = dark(8, i)
+		case i < 8:
+			a = dark(8, i+1)
+		case i == 8:
+			a = dark(7, 8)
+		default:
+			a = dark(14-i, 8)
+		}
+		if i < 8 {
+			b = dark(size-1-i, 8)
+		} else {
+			b = dark(8, size-15+i)
+		}
+		if a {
+			format |= 1 << uint(i)
+		}
+		if b {
+			second |= 1 << uint(i)
+		}
+	}
+	mask := -1
+	for m, expected := range qrTestFormats {
+		if format == expected {
+			mask = m
+		}
+	}
+	if mask < 0 || second != format {
+		t.Fatalf("the format strings %015b and %015b aren't level M", format, second)
+	}
+	if !dark(8, size-8) {
+		t.Error("the dark module is light")
+	}
+	if expected, ok := qrTestVersions[version]; ok {
+		found, transposed := 0, 0
+		for i := 0; i < 18; i++ {
+			if dark(size-11+i%3, i/3) {
+				found |= 1 << uint(i)
+			}
+			if dark(i/3, size-11+i%3) {
+				transposed |= 1 << uint(i)
+			}
+		}
+		if found != expected || transposed != expected {
+			t.Fatalf("expected the version string %018b, got %018b and %018b", expected, found, transposed)
+		}
+	}
+
+	function := make([][]bool, size)
+	for y := range function {
+		function[y] = make([]bool, size)
+	}
+	fill := func(x0, y0, x1, y1 int) {
+		for y := y0; y <= y1; y++ {
+			for x := x0; x <= x1; x++ {
+				function[y][x] = true
+			}
+		}
+	}
+	fill(0, 0, 8, 8)
+	fill(size-8, 0, size-1, 8)
+	fill(0, size-8, 8, size-1)
+	fill(6, 0, 6, size-1)
+	fill(0, 6, size-1, 6)
+	if version >= 7 {
+		fill(size-11, 0, size-9, 5)
+		fill(0, size-11, 5, size-9)
+	}
+	// Every combination of the alignment coordinates except the three that land on finder patterns
+	centers := qrTestAlignment[version-1]
+	for i, cy := range centers {
+		for j, cx := range centers {
+			last := len(centers) - 1
+			if !(i == 0 && j == 0) && !(i == 0 && j == last) && !(i == last && j == 0) {
+				fill(cx-2, cy-2, cx+2, cy+2)
+			}
+		}
+	}
+
+	// Upwards then downwards in two-module columns from the right, skipping the vertical timing pattern
+	var bits []bool
+	up := true
+	for right := size - 1; right > 0; right -= 2 {
+		if right == 6 {
+			right--
+		}
+		for step := 0; step < size; step++ {
+			y := step
+			if up {
+				y = size - 1 - step
+			}
+			for x := right; x > right-2; x-- {
+				if function[y][x] {
+					continue
+				}
+				var invert bool
+				switch mask {
+				case 0:
+					invert = (y+x)%2 == 0
+				case 1:
+					invert = y%2 == 0
+				case 2:
+					invert = x%3 == 0
+				case 3:
+					invert = (y+x)%3 == 0
+				case 4:
+					invert = (y/2+x/3)%2 == 0
+				case 5:
+					invert = (y*x)%2+(y*x)%3 == 0
+				case 6:
+					invert = ((y*x)%2+(y*x)%3)%2 == 0
+				case 7:
+					invert = ((y+x)%2+(y*x)%3)%2 == 0
+				}
+				bits = append(bits, dark(x, y) != invert)
+			}
+		}
+		up = !up
+	}
+	blocks := qrTestBlocks[version-1]
+	total, ecPerBlock, count := blocks[0], blocks[1], blocks[2]
+	if len(bits)/8 != total {
+		t.Fatalf("expected room for %d codewords, found %d bits", total, len(bits))
+	}
+	codewords := make([]byte, total)
+	for i := 0; i < total*8; i++ {
+		if bits[i] {
+			codewords[i/8] |= 1 << uint(7-i%8)
+		}
+	}
+
+	// Short blocks come first and the long ones have one more data codeword
+	dataTotal := total - ecPerBlock*count
+	short, long := dataTotal/count, dataTotal%count
+	deinterleaved := make([][]byte, count)
+	next := 0
+	for i := 0; i < short+1; i++ {
+		for b := 0; b < count; b++ {
+			if i < short || b >= count-long {
+				deinterleaved[b] = append(deinterleaved[b], codewords[next])
+				next++
+			}
+		}
+	}
+	for i := 0; i < ecPerBlock; i++ {
+		for b := 0; b < count; b++ {
+			deinterleaved[b] = append(deinterleaved[b], codewords[next])
+			next++
+		}
+	}
+	var data []byte
+	for b, block := range deinterleaved {
+		for k := 0; k < ecPerBlock; k++ {
+			if syndrome := qrTestSyndrome(block, k); syndrome != 0 {
+				t.Fatalf("block %d has a nonzero syndrome at %d", b, k)
+			}
+		}
+		data = append(data, block[:len(block)-ecPerBlock]...)
+	}
+
+	read := func(offset, n int) int {
+		value := 0
+		for i := offset; i < offset+n; i++ {
+			value = value<<1 | int(data[i/8]>>uint(7-i%8)&1)
+		}
+		return value
+	}
+	if mode := read(0, 4); mode != 4 {
+		t.Fatalf("expected byte mode, got %04b", mode)
+	}
+	countBits := 8
+	if version >= 10 {
+		countBits = 16
+	}
+	length := read(4, countBits)
+	decoded := make([]byte, length)
+	for i := range decoded {
+		decoded[i] = byte(read(4+countBits+i*8, 8))
+	}
+	return decoded
+}
+
+// The block's value at α^k, which is zero for every root of the generator when the codewords are valid
+func qrTestSyndrome(block []byte, k int) byte {
+	alpha := byte(1)
+	for i := 0; i < k; i++ {
+		alpha = qrTestMultiply(alpha, 2)
+	}
+	var sum byte
+	for _, codeword := range block {
+		sum = qrTestMultiply(sum, alpha) ^ codeword
+	}
+	return sum
+}
+
+func qrTestMultiply(x, y byte) byte {
+	var product byte
+	for ; y > 0; y >>= 1 {
+		if y&1 != 0 {
+			product ^= x
+		}
+		carry := x&0x80 != 0
+		x <<= 1
+		if carry {
+			x ^= 0x1D
+		}
+	}
+	return product
+}
+
+func TestQRRoundTrip(t *testing.T) {
+	inputs := []string{
+		"a",
+		"ticket:0123456789abcdef.3Jq9",
+		strings.Repeat("0123456789", 8),
+		strings.Repeat("Ticket 42. ", 11),
+		strings.Repeat("x", 140),
+		strings.Repeat("\xff\x00", 106) + "!",
+	}
+	for _, input := range inputs {
+		qr, err := encodeQR([]byte(input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		image, err := qr.png(8)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if decoded := qrTestDecode(t, image, 8); string(decoded) != input {
+			t.Errorf("version %d: expected %q, decoded %q", (qr.size-17)/4, input, decoded)
+		}
+	}
+	if _, err := encodeQR(make([]byte, 214)); err == nil {
+		t.Error("expected data longer than version 10 holds to be refused")
+	}
+}
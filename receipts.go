@@ -0,0 +1,422 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"html/template"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Issued donation receipts. Each receipt emailed to a donor is recorded with the values it was rendered from
+// and the HTML that was sent, so the same receipt can be downloaded again later by its ID. Donors get theirs
+// from the portal.
+//
+// Receipts are numbered per fiscal year without gaps, e.g. "2026-00042" for the 42nd receipt issued in FY2026.
+// A number is only taken when the receipt is saved, and receipts are never deleted. A duplicate copy reuses
+// the original's number and content; a corrected receipt gets its own number and refers to the one it replaces.
+// The numbers come from the receipts already in the store, so on Heroku the store has to be the database; see
+// receiptProblems.
+
+type Receipt struct {
+	ID         string        `json:"id"`
+	Number     string        `json:"number,omitempty"`
+	FiscalYear int           `json:"fiscalYear,omitempty"`
+	Sequence   int           `json:"sequence,omitempty"`
+	Kind       string        `json:"kind,omitempty"`
+	Original   string        `json:"original,omitempty"`  // ID of the receipt a duplicate or correction was issued for
+	Reference  string        `json:"reference,omitempty"` // The gift it is for, e.g. "charge:<Stripe charge ID>"
+	Fields     receiptFields `json:"fields"`
+	HTML       string        `json:"html,omitempty"` // As emailed, with the images referenced as inline attachments
+	Issued     time.Time     `json:"issued"`
+}
+
+type receiptData struct {
+	Receipts []Receipt `json:"receipts"`
+}
+
+// Corrections finance can make when reissuing a receipt. Unset fields keep the original's values.
+type receiptCorrection struct {
+	Name   *string `json:"name"`
+	Addr1  *string `json:"addr1"`
+	Addr2  *string `json:"addr2"`
+	City   *string `json:"city"`
+	State  *string `json:"state"`
+	Zip    *string `json:"zip"`
+	Email  *string `json:"email"`
+	Amount *int    `json:"amount"` // Cents
+	Date   *string `json:"date"`   // As printed, e.g. "March 4, 2026"
+}
+
+const receiptsDocument string = "receipts"
+
+const ReceiptOriginal string = "original"
+const ReceiptDuplicate string = "duplicate"
+const ReceiptCorrected string = "corrected"
+
+// The number if it has one, for file names and subjects
+func (receipt Receipt) label() string {
+	if receipt.Number != "" {
+		return receipt.Number
+	}
+	return receipt.ID
+}
+
+// Receipts for a gift paid through Stripe are kept under its charge, which the gift ledger records as well
+func chargeReceiptReference(chargeID string) string {
+	return "charge:" + chargeID
+}
+
+// Returned by issueReceipt when reference already has its original receipt
+var errReceiptAlreadyIssued = errors.New("a receipt was already issued for this gift")
+
+// Records and numbers a receipt for emailData. Without a data store the receipt is still returned, just not
+// kept or numbered.
+func issueReceipt(emailData EmailData, reference string) (Receipt, error) {
+	emailData.InlineImages = true
+	return saveReceipt(Receipt{Kind: ReceiptOriginal, Reference: reference, Fields: newReceiptFields(emailData)})
+}
+
+// Gives the receipt the next number of the current fiscal year, unless it is a duplicate, renders it if it
+// doesn't have its HTML yet and saves it. The number and the save happen together, so a failed render or
+// save doesn't use up a number. A gift only ever gets one original.
+func saveReceipt(receipt Receipt) (Receipt, error) {
+	receipt.ID = newID()
+	receipt.Issued = calendar().now()
+	if dataStore() == nil {
+		html, err := renderReceiptFields(receipt.Fields)
+		receipt.HTML = html
+		return receipt, err
+	}
+	var data receiptData
+	err := dataStore().update(receiptsDocument, &data, func() error {
+		for _, issued := range data.Receipts {
+			if receipt.Kind == ReceiptOriginal && issued.Kind == ReceiptOriginal && issued.Reference == receipt.Reference {
+				return errReceiptAlreadyIssued
+			}
+		}
+		if receipt.Kind != ReceiptDuplicate {
+			receipt.FiscalYear = calendar().fiscalYearOf(receipt.Issued)
+			receipt.Sequence = 1
+			for _, issued := range data.Receipts {
+				if issued.FiscalYear == receipt.FiscalYear && issued.Sequence >= receipt.Sequence {
+					receipt.Sequence = issued.Sequence + 1
+				}
+			}
+			receipt.Number = fmt.Sprintf("%d-%05d", receipt.FiscalYear, receipt.Sequence)
+			receipt.Fields.Number = receipt.Number
+		}
+		if receipt.HTML == "" {
+			html, err := renderReceiptFields(receipt.Fields)
+			if err != nil {
+				return err
+			}
+			receipt.HTML = html
+		}
+		data.Receipts = append(data.Receipts, receipt)
+		return nil
+	})
+	return receipt, err
+}
+
+// A copy of a receipt marked as a duplicate, with the same number and content
+func duplicateReceipt(original Receipt) (Receipt, error) {
+	note := "<p>" + fmt.Sprintf(translate(original.Fields.Locale, "receipt.duplicateNote"), html.EscapeString(original.label()), formatDateLocale(original.Issued, original.Fields.Locale)) + "</p>"
+	duplicate := original
+	duplicate.Kind = ReceiptDuplicate
+	duplicate.Original = original.ID
+	duplicate.Fields.Note = template.HTML(note) + original.Fields.Note
+	duplicate.HTML = ""
+	if original.HTML != "" {
+		duplicate.HTML = insertAfterBody(original.HTML, note)
+	}
+	return saveReceipt(duplicate)
+}
+
+// A new receipt with its own number that replaces original
+func correctReceipt(cfg *Config, original Receipt, fields receiptFields) (Receipt, error) {
+	fields, err := fields.withOrganization(cfg, original.Issued)
+	if err != nil {
+		return Receipt{}, err
+	}
+	corrected := Receipt{Kind: ReceiptCorrected, Original: original.ID, Reference: original.Reference, Fields: fields}
+	corrected.Fields.Note = template.HTML("<p>" + fmt.Sprintf(translate(fields.Locale, "receipt.correctedNote"), html.EscapeString(original.label()), formatDateLocale(original.Issued, fields.Locale)) + "</p>")
+	return saveReceipt(corrected)
+}
+
+func (correction receiptCorrection) apply(fields receiptFields) receiptFields {
+	for _, field := range []struct {
+		value *string
+		dest  *string
+	}{
+		{correction.Name, &fields.Name}, {correction.Addr1, &fields.Addr1}, {correction.Addr2, &fields.Addr2},
+		{correction.City, &fields.City}, {correction.State, &fields.State}, {correction.Zip, &fields.Zip},
+		{correction.Email, &fields.Email}, {correction.Date, &fields.Date},
+	} {
+		if field.value != nil {
+			*field.dest = strings.TrimSpace(*field.value)
+		}
+	}
+	if correction.Amount != nil {
+		fields.Amount = formatCentsLocale(*correction.Amount, fields.Locale)
+	}
+	return fields
+}
+
+func insertAfterBody(document string, fragment string) string {
+	if body := bodyTag.FindStringIndex(document); body != nil {
+		return document[:body[1]] + "\n" + fragment + document[body[1]:]
+	}
+	return fragment + document
+}
+
+var bodyTag = regexp.MustCompile(`(?i)<body[^>]*>`)
+
+func findReceipt(id string) (Receipt, bool, error) {
+	var data receiptData
+	err := dataStore().load(receiptsDocument, &data)
+	if err != nil {
+		return Receipt{}, false, err
+	}
+	for _, receipt := range data.Receipts {
+		if receipt.ID == id {
+			return receipt, true, nil
+		}
+	}
+	return Receipt{}, false, nil
+}
+
+// The original receipt issued with reference
+func originalReceipt(reference string) (Receipt, error) {
+	var data receiptData
+	err := dataStore().load(receiptsDocument, &data)
+	if err != nil {
+		return Receipt{}, err
+	}
+	for _, receipt := range data.Receipts {
+		if receipt.Reference == reference && receipt.Kind == ReceiptOriginal {
+			return receipt, nil
+		}
+	}
+	return Receipt{}, errors.New("no receipt was issued for " + reference)
+}
+
+// The most recent receipt issued with reference that isn't a duplicate
+func latestReceipt(reference string) (*Receipt, error) {
+	var data receiptData
+	err := dataStore().load(receiptsDocument, &data)
+	if err != nil {
+		return nil, err
+	}
+	var latest *Receipt
+	for i := range data.Receipts {
+		if data.Receipts[i].Reference == reference && data.Receipts[i].Kind != ReceiptDuplicate {
+			latest = &data.Receipts[i]
+		}
+	}
+	return latest, nil
+}
+
+// Finance can look up any receipt by its number, the donor's email or name, download it again and reissue it
+func registerReceiptRoutes(admin *gin.RouterGroup, configs *liveConfig) {
+	admin.GET("/receipts", func(c *gin.Context) {
+		var data receiptData
+		err := dataStore().load(receiptsDocument, &data)
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		query := strings.ToLower(strings.TrimSpace(c.Query("q")))
+		found := []gin.H{}
+		for i := len(data.Receipts) - 1; i >= 0; i-- {
+			receipt := data.Receipts[i]
+			if query != "" && receipt.Number != query && !strings.Contains(strings.ToLower(receipt.Fields.Email), query) && !strings.Contains(strings.ToLower(receipt.Fields.Name), query) {
+				continue
+			}
+			found = append(found, gin.H{"id": receipt.ID, "number": receipt.Number, "kind": receipt.Kind, "original": receipt.Original, "reference": receipt.Reference,
+				"name": receipt.Fields.Name, "email": receipt.Fields.Email, "amount": receipt.Fields.Amount, "date": receipt.Fields.Date, "team": receipt.Fields.Team, "issued": receipt.Issued})
+		}
+		c.JSON(200, found)
+	})
+
+	admin.GET("/receipts/:id/pdf", func(c *gin.Context) {
+		serveReceiptPDF(c, configs.current(), c.Param("id"))
+	})
+
+	// The receipt exactly as it was emailed, with the images linked from the site
+	admin.GET("/receipts/:id/html", func(c *gin.Context) {
+		receipt, found, err := findReceipt(c.Param("id"))
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		if !found || receipt.HTML == "" {
+			c.String(http.StatusNotFound, "No such receipt")
+			return
+		}
+		c.Data(200, "text/html; charset=utf-8", []byte(receiptPage(receipt)))
+	})
+
+	// Issues a duplicate or corrected copy, and emails it to the donor with finance blind copied when send is true
+	admin.POST("/receipts/:id/reissue", func(c *gin.Context) {
+		cfg := configs.current()
+		var request struct {
+			Kind string `json:"kind"`
+			Send bool   `json:"send"`
+			receiptCorrection
+		}
+		err := c.BindJSON(&request)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		original, found, err := findReceipt(c.Param("id"))
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		if !found {
+			c.String(http.StatusNotFound, "No such receipt")
+			return
+		}
+		var reissued Receipt
+		subject := translate(original.Fields.Locale, "receipt.duplicate")
+		switch request.Kind {
+		case ReceiptDuplicate:
+			reissued, err = duplicateReceipt(original)
+		case ReceiptCorrected:
+			reissued, err = correctReceipt(cfg, original, request.receiptCorrection.apply(original.Fields))
+			subject = translate(original.Fields.Locale, "receipt.corrected")
+		default:
+			c.String(http.StatusBadRequest, "kind must be '"+ReceiptDuplicate+"' or '"+ReceiptCorrected+"'")
+			return
+		}
+		if err == nil && request.Send {
+			var msg *mailMessage
+			msg, err = receiptMessage(cfg, reissued, subject)
+			if err == nil {
+				msg.Bcc = []string{EmailFinance}
+				_, err = enqueueMail(MailAccountReceipts, "receipt", "receipt:"+reissued.ID, msg)
+			}
+		}
+		if err != nil {
+			fmt.Println(err)
+			fmt.Println("ERROR: RECEIPT " + original.label() + " COULD NOT BE REISSUED")
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		reissued.HTML = ""
+		c.JSON(200, reissued)
+	})
+}
+
+// The receipt's HTML with the images linked from the site instead of attached
+func receiptPage(receipt Receipt) string {
+	return strings.Replace(strings.Replace(receipt.HTML, "cid:"+receiptLogoCID, receiptImageURL("logo", receipt.Fields.Logo), -1), "cid:"+receiptSignatureCID, receiptImageURL("signature", receipt.Fields.Signature), -1)
+}
+
+func serveReceiptPDF(c *gin.Context, cfg *Config, id string) {
+	receipt, found, err := findReceipt(id)
+	if err != nil {
+		fmt.Println(err)
+		c.String(http.StatusInternalServerError, "Error")
+		return
+	}
+	if !found {
+		c.String(http.StatusNotFound, "No such receipt")
+		return
+	}
+	pdf, err := renderReceiptPDF(cfg, receipt)
+	if err != nil {
+		fmt.Println(err)
+		c.String(http.StatusInternalServerError, "Error")
+		return
+	}
+	c.Header("Content-Disposition", "inline; filename=\"receipt-"+receipt.label()+".pdf\"")
+	c.Data(200, "application/pdf", pdf)
+}
+
+// The same content as the HTML receipt, laid out for a printed page
+func renderReceiptPDF(cfg *Config, receipt Receipt) ([]byte, error) {
+	fields, err := receipt.Fields.withOrganization(cfg, receipt.Issued)
+	if err != nil {
+		return nil, err
+	}
+	_, logo, err := receiptImage("logo", fields.Logo)
+	if err != nil {
+		return nil, err
+	}
+	_, signature, err := receiptImage("signature", fields.Signature)
+	if err != nil {
+		return nil, err
+	}
+	doc := &pdfDocument{}
+	left, right := 54.0, pdfPageWidth-54
+	y := pdfPageHeight - 54
+
+	logoHeight := 200 * float64(logo.height) / float64(logo.width)
+	doc.drawImage(doc.addImage(logo), left, y-logoHeight, 200, logoHeight)
+	doc.textRight(right, y-10, 11, false, fields.OrgName)
+	doc.textRight(right, y-24, 11, false, fields.PRAddr1+", "+fields.PRCity+", "+fields.PRState+" "+fields.PRZip)
+	doc.textRight(right, y-38, 11, false, fields.PRPhone)
+	y -= 70
+
+	if note := strings.TrimSpace(htmlToText(string(fields.Note))); note != "" {
+		y = doc.paragraph(left, y, right-left, 12, true, note) - 6
+	}
+
+	doc.text(left, y, 13, false, fields.Date)
+	y -= 30
+	doc.text(left, y, 13, false, fields.Name)
+	y -= 16
+	doc.text(left, y, 13, false, strings.TrimSpace(fields.Addr1+" "+fields.Addr2))
+	y -= 16
+	doc.text(left, y, 13, false, fields.City+", "+fields.State+" "+fields.Zip)
+	y -= 30
+	locale := fields.Locale
+	amount := fmt.Sprintf(translate(locale, "currency"), fields.Amount)
+	y = doc.paragraph(left, y, right-left, 13, false, fmt.Sprintf(translate(locale, "pdf.thanks"), amount, fields.Date)) - 14
+	y = doc.paragraph(left, y, right-left, 13, false, fmt.Sprintf(translate(locale, "pdf.support"), fields.Team, fields.FIRSTSuffix)) - 14
+	y = doc.paragraph(left, y, right-left, 13, false, translate(locale, "pdf.thanksAgain")) - 14
+	doc.text(left, y, 13, false, translate(locale, "pdf.closing"))
+
+	signatureHeight := 120 * float64(signature.height) / float64(signature.width)
+	y -= signatureHeight + 4
+	doc.drawImage(doc.addImage(signature), left, y, 120, signatureHeight)
+	y -= 16
+	doc.text(left, y, 13, false, fields.SignerName)
+	y -= 16
+	doc.text(left, y, 13, false, fields.SignerTitle)
+	y -= 24
+	doc.line(left, y, right, y, 1.5)
+	y -= 28
+
+	doc.textCenter(pdfPageWidth/2, y, 11, true, translate(locale, "pdf.heading"))
+	y -= 24
+	for _, line := range []string{
+		translate(locale, "pdf.donor") + fields.Name,
+		translate(locale, "pdf.dateReceived") + fields.Date,
+		translate(locale, "pdf.cash") + amount,
+		"",
+		fields.OrgName,
+		fields.PRAddr1,
+		fields.PRCity + ", " + fields.PRState + " " + fields.PRZip,
+		translate(locale, "pdf.taxID") + fields.EIN,
+	} {
+		doc.text(left, y, 12, false, line)
+		y -= 15
+	}
+	if receipt.Number != "" {
+		doc.text(left, y-9, 12, false, translate(locale, "pdf.number")+receipt.Number)
+	}
+	doc.text(left, 40, 8, false, fmt.Sprintf(translate(locale, "pdf.issued"), receipt.label(), formatDateLocale(receipt.Issued, locale)))
+	return doc.bytes(), nil
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// Uses a calendar with a July fiscal year start whose clock reads whatever *now is set to
+func useTestCalendar(t *testing.T, now *time.Time) *orgCalendar {
+	cal := newOrgCalendar(CalendarConfig{Timezone: defaultTimezone, FiscalYearStart: int(time.July), FTCSeasonStart: int(defaultSeasonStart), FLLSeasonStart: int(defaultSeasonStart)})
+	cal.clock = func() time.Time { return *now }
+	previous := calendar()
+	currentCalendar.Store(cal)
+	t.Cleanup(func() { currentCalendar.Store(previous) })
+	return cal
+}
+
+func testReceiptFields(t *testing.T) receiptFields {
+	emailData, err := genEmailData(testReceiptConfig(), *testPaymentData())
+	if err != nil {
+		t.Fatal(err)
+	}
+	emailData.InlineImages = true
+	return newReceiptFields(emailData)
+}
+
+func TestReceiptNumbersAcrossTheFiscalYear(t *testing.T) {
+	useTestStore(t)
+	useTestTemplates(t)
+	var now time.Time
+	cal := useTestCalendar(t, &now)
+	fields := testReceiptFields(t)
+
+	// FY2026 ends when July 1st, 2026 starts in Chicago, which is 05:00 UTC
+	tests := []struct {
+		issued    time.Time
+		reference string
+		number    string
+		err       error
+	}{
+		{time.Date(2026, time.January, 15, 12, 0, 0, 0, cal.location), "charge:ch_1", "2026-00001", nil},
+		{time.Date(2026, time.June, 30, 23, 59, 0, 0, cal.location), "charge:ch_2", "2026-00002", nil},
+		{time.Date(2026, time.July, 1, 4, 59, 0, 0, time.UTC), "charge:ch_3", "2026-00003", nil},
+		{time.Date(2026, time.July, 1, 0, 0, 0, 0, cal.location), "charge:ch_4", "2027-00001", nil},
+		{time.Date(2026, time.July, 1, 0, 1, 0, 0, cal.location), "charge:ch_2", "", errReceiptAlreadyIssued},
+		{time.Date(2026, time.August, 3, 9, 0, 0, 0, cal.location), "charge:ch_5", "2027-00002", nil},
+	}
+	for _, test := range tests {
+		now = test.issued
+		receipt, err := saveReceipt(Receipt{Kind: ReceiptOriginal, Reference: test.reference, Fields: fields})
+		if err != test.err {
+			t.Errorf("%s at %s: expected error %v, got %v", test.reference, test.issued, test.err, err)
+			continue
+		}
+		if err == nil && (receipt.Number != test.number || receipt.Fields.Number != test.number || !strings.Contains(receipt.HTML, test.number)) {
+			t.Errorf("%s at %s: expected number %s, got %s", test.reference, test.issued, test.number, receipt.Number)
+		}
+	}
+
+	var saved receiptData
+	dataStore().load(receiptsDocument, &saved)
+	if len(saved.Receipts) != 5 {
+		t.Errorf("expected the refused original not to be saved, got %d receipts", len(saved.Receipts))
+	}
+}
+
+func TestDuplicateAndCorrectedReceipts(t *testing.T) {
+	useTestStore(t)
+	useTestTemplates(t)
+	now := time.Date(2026, time.March, 2, 10, 0, 0, 0, time.UTC)
+	useTestCalendar(t, &now)
+	fields := testReceiptFields(t)
+
+	original, err := saveReceipt(Receipt{Kind: ReceiptOriginal, Reference: "charge:ch_1", Fields: fields})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(24 * time.Hour)
+	corrected := fields
+	corrected.Name = "Patricia Donor"
+
+	tests := []struct {
+		name      string
+		issue     func() (Receipt, error)
+		kind      string
+		number    string
+		sameHTML  bool // The original's HTML with only the note inserted
+		contained string
+	}{
+		{"duplicate", func() (Receipt, error) { return duplicateReceipt(original) }, ReceiptDuplicate, original.Number, true, "<b>DUPLICATE</b>"},
+		{"second duplicate", func() (Receipt, error) { return duplicateReceipt(original) }, ReceiptDuplicate, original.Number, true, "<b>DUPLICATE</b>"},
+		{"correction", func() (Receipt, error) { return correctReceipt(testReceiptConfig(), original, corrected) }, ReceiptCorrected, "2026-00002", false, "<b>CORRECTED RECEIPT</b>"},
+	}
+	for _, test := range tests {
+		receipt, err := test.issue()
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if receipt.Kind != test.kind || receipt.Number != test.number || receipt.Original != original.ID || receipt.Reference != original.Reference || receipt.ID == original.ID {
+			t.Errorf("%s: expected a %s numbered %s pointing back to %s, got %+v", test.name, test.kind, test.number, original.ID, receipt)
+		}
+		if !strings.Contains(receipt.HTML, test.contained) || !strings.Contains(receipt.HTML, original.Number) {
+			t.Errorf("%s: expected the HTML to contain %q and the original's number", test.name, test.contained)
+		}
+		note := strings.TrimSuffix(string(receipt.Fields.Note), string(original.Fields.Note))
+		if test.sameHTML && receipt.HTML != insertAfterBody(original.HTML, note) {
+			t.Errorf("%s: expected the original's HTML with only the note added", test.name)
+		}
+	}
+
+	// Duplicates don't use up a number, and the correction is what the gift's receipt is now
+	next, err := saveReceipt(Receipt{Kind: ReceiptOriginal, Reference: "charge:ch_2", Fields: fields})
+	if err != nil || next.Number != "2026-00003" {
+		t.Errorf("expected the next original to be 2026-00003, got %s, %v", next.Number, err)
+	}
+	latest, err := latestReceipt("charge:ch_1")
+	if err != nil || latest == nil || latest.Kind != ReceiptCorrected || latest.Fields.Name != "Patricia Donor" {
+		t.Errorf("expected the correction to be the latest receipt for the gift, got %+v, %v", latest, err)
+	}
+	if found, err := originalReceipt("charge:ch_1"); err != nil || found.ID != original.ID {
+		t.Errorf("expected the original to still be found, got %+v, %v", found, err)
+	}
+}
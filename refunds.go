@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stripe/stripe-go"
+)
+
+// Refunds are made in the Stripe dashboard. Finance then sends the donor the refund notice from here, so the
+// donor has it in writing that the refunded amount is no longer deductible.
+
+func registerRefundRoutes(admin *gin.RouterGroup, configs *liveConfig) {
+	admin.POST("/refunds/:charge/notice", func(c *gin.Context) {
+		cfg := configs.current()
+		ch, err := cfg.charges().Get(c.Param("charge"), nil)
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusNotFound, "No such charge")
+			return
+		}
+		if ch.AmountRefunded <= 0 || hasOwnConfirmation(ch.Description) {
+			c.String(http.StatusBadRequest, "Nothing was refunded from this donation")
+			return
+		}
+		preference, _, err := contactPreference(ch.ReceiptEmail)
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		amount := int(ch.AmountRefunded)
+		data := PaymentData{Amount: &amount, Description: &ch.Description, Email: &ch.ReceiptEmail, Locale: &preference.Locale}
+		var name, phone string
+		address := stripe.Address{}
+		if ch.Shipping != nil {
+			name, phone = ch.Shipping.Name, ch.Shipping.Phone
+			if ch.Shipping.Address != nil {
+				address = *ch.Shipping.Address
+			}
+		}
+		data.Name, data.Phone = &name, &phone
+		data.Addr1, data.Addr2, data.City, data.State, data.Zip = &address.Line1, &address.Line2, &address.City, &address.State, &address.PostalCode
+		emailData, err := genEmailData(cfg, data)
+		if err != nil {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		refunded := time.Unix(ch.Created, 0)
+		if ch.Refunds != nil {
+			for _, refund := range ch.Refunds.Data {
+				if refund.Created > refunded.Unix() {
+					refunded = time.Unix(refund.Created, 0)
+				}
+			}
+		}
+		emailData.Date = calendar().formatDate(refunded)
+		emailData.Received = refunded
+		msg, err := refundNoticeMessage(emailData)
+		var id string
+		if err == nil {
+			id, err = enqueueMail(MailAccountReceipts, "refund", "donor:"+donorKey(ch.ReceiptEmail), msg)
+		}
+		if err != nil {
+			fmt.Println(err)
+			fmt.Println("ERROR: REFUND NOTICE FOR CHARGE " + ch.ID + " COULD NOT BE QUEUED")
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		c.JSON(200, gin.H{"queued": id, "amount": amount, "email": ch.ReceiptEmail})
+	})
+}
@@ -0,0 +1,567 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Stewardship sequences: timed series of emails that start when a donor does something, e.g. a thank-you
+// from a student a week after a first gift and a season recap when the season ends. The receipt itself
+// still goes out right away from the payment flow; sequences only send the follow-ups.
+//
+// A sequence starts on one trigger: a donor's first gift to a team, a gift of at least a minimum amount,
+// the first payment of a recurring gift, or a donor not having given for a number of days. Each step is
+// sent a number of days after the trigger, or when the trigger gift's season ends, using the team's own
+// template when it has one. A donor leaves every sequence they are in when they give again, and leaves a
+// sequence when they stop hearing from its team or their email starts bouncing. Steps can have translations,
+// used for donors whose last donation was in that language; anything not translated is sent in English.
+
+type Sequence struct {
+	ID          string         `json:"id"`
+	Name        string         `json:"name"`
+	Trigger     string         `json:"trigger"`
+	Team        string         `json:"team,omitempty"`        // Only gifts to this team, empty for every team
+	MinimumGift int            `json:"minimumGift,omitempty"` // Cents, for large-gift sequences
+	LapsedDays  int            `json:"lapsedDays,omitempty"`  // For lapsed-donor sequences, default 365
+	Steps       []sequenceStep `json:"steps"`
+	Active      bool           `json:"active"`
+	Created     time.Time      `json:"created"`
+}
+
+type sequenceStep struct {
+	DelayDays   int               `json:"delayDays"`             // Days after the trigger
+	AtSeasonEnd bool              `json:"atSeasonEnd,omitempty"` // Instead sent when the trigger gift's season ends
+	Subject     string            `json:"subject"`
+	Body        string            `json:"body"`                 // html/template with .Name, .Team, .Amount, .GiftDate and .Season
+	TeamBodies  map[string]string `json:"teamBodies,omitempty"` // Bodies used instead of Body for gifts to a team
+
+	Translations map[string]sequenceTranslation `json:"translations,omitempty"` // By locale, e.g. "es"
+}
+
+// A step in another language. Empty values fall back to the English ones.
+type sequenceTranslation struct {
+	Subject    string            `json:"subject,omitempty"`
+	Body       string            `json:"body,omitempty"`
+	TeamBodies map[string]string `json:"teamBodies,omitempty"`
+}
+
+// One donor going through one sequence
+type sequenceEnrollment struct {
+	ID         string      `json:"id"`
+	SequenceID string      `json:"sequenceId"`
+	Email      string      `json:"email"`
+	Name       string      `json:"name"`
+	Team       string      `json:"team"`
+	GiftID     string      `json:"giftId"` // The gift that started it; for lapsed donors their last gift
+	Amount     int         `json:"amount"`
+	GiftDate   time.Time   `json:"giftDate"`
+	Started    time.Time   `json:"started"`
+	NextStep   int         `json:"nextStep"`
+	Sent       []time.Time `json:"sent,omitempty"`
+	Status     string      `json:"status"`
+	ExitReason string      `json:"exitReason,omitempty"`
+	Ended      *time.Time  `json:"ended,omitempty"`
+}
+
+type sequenceData struct {
+	Sequences   []Sequence           `json:"sequences"`
+	Enrollments []sequenceEnrollment `json:"enrollments"`
+}
+
+// The values a step's body can use
+type sequenceFields struct {
+	Name     string
+	Team     string
+	Amount   string
+	GiftDate string
+	Season   string
+}
+
+const sequencesDocument string = "sequences"
+
+const TriggerFirstGift string = "first-gift"
+const TriggerLargeGift string = "large-gift"
+const TriggerRecurringStart string = "recurring-start"
+const TriggerLapsed string = "lapsed"
+
+const EnrollmentActive string = "active"
+const EnrollmentCompleted string = "completed"
+const EnrollmentExited string = "exited"
+
+var sequenceTriggers = []string{TriggerFirstGift, TriggerLargeGift, TriggerRecurringStart, TriggerLapsed}
+
+const defaultLapsedDays int = 365
+
+// Gifts older than this when they reach the ledger, such as a Stripe sync of last year, don't start or end sequences
+const sequenceTriggerWindow time.Duration = 30 * 24 * time.Hour
+
+const sequenceCheckInterval time.Duration = time.Hour
+
+func validateSequence(sequence Sequence) error {
+	known := false
+	for _, trigger := range sequenceTriggers {
+		known = known || sequence.Trigger == trigger
+	}
+	if !known {
+		return errors.New("trigger must be one of: " + strings.Join(sequenceTriggers, ", "))
+	}
+	if sequence.Team != "" && !containsTeam(updateTeams, sequence.Team) {
+		return errors.New("team must be empty or one of: " + strings.Join(updateTeams, ", "))
+	}
+	if sequence.Trigger == TriggerLargeGift && sequence.MinimumGift <= 0 {
+		return errors.New("A large-gift sequence needs a minimumGift in cents")
+	}
+	if len(sequence.Steps) == 0 {
+		return errors.New("A sequence needs at least one step")
+	}
+	for i, step := range sequence.Steps {
+		name := "Step " + strconv.Itoa(i+1)
+		if strings.TrimSpace(step.Subject) == "" {
+			return errors.New(name + " needs a subject")
+		}
+		if step.DelayDays < 0 {
+			return errors.New(name + " can't have a negative delay")
+		}
+		variants := map[string]sequenceTranslation{"": {Subject: step.Subject, Body: step.Body, TeamBodies: step.TeamBodies}}
+		for locale, translation := range step.Translations {
+			if normalizeLocale(locale) != locale || locale == "" {
+				return errors.New(name + " has a translation for an unsupported locale: " + locale)
+			}
+			variants[locale] = translation
+		}
+		for locale, variant := range variants {
+			bodies := map[string]string{"": variant.Body}
+			for team, body := range variant.TeamBodies {
+				if !containsTeam(updateTeams, team) {
+					return errors.New(name + " has a body for an unknown team: " + team)
+				}
+				bodies[team] = body
+			}
+			for team, body := range bodies {
+				if team == "" {
+					team = FTCPathfinders13497
+				}
+				if body == "" && locale != "" {
+					continue
+				}
+				_, err := renderSequenceBody(body, sequenceFields{Name: "Sample Donor", Team: team, Amount: formatCentsLocale(2500, locale), GiftDate: formatDateLocale(time.Now(), locale), Season: "2025-2026"})
+				if err != nil {
+					return errors.New(name + "'s body could not be rendered: " + err.Error())
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// The subject and body for a donor who speaks locale, and the locale of the body: English unless the step
+// has a translated body
+func (step sequenceStep) localized(team string, locale string) (string, string, string) {
+	subject, body, bodyLocale := step.Subject, step.Body, ""
+	if teamBody, found := step.TeamBodies[team]; found {
+		body = teamBody
+	}
+	translation, found := step.Translations[locale]
+	if !found || locale == "" {
+		return subject, body, bodyLocale
+	}
+	if translation.Subject != "" {
+		subject = translation.Subject
+	}
+	if translation.Body != "" {
+		body, bodyLocale = translation.Body, locale
+	}
+	if teamBody := translation.TeamBodies[team]; teamBody != "" {
+		body, bodyLocale = teamBody, locale
+	}
+	return subject, body, bodyLocale
+}
+
+func renderSequenceBody(body string, fields sequenceFields) (string, error) {
+	tmpl, err := template.New("sequence").Option("missingkey=error").Parse(body)
+	if err != nil {
+		return "", err
+	}
+	var rendered bytes.Buffer
+	err = tmpl.Execute(&rendered, fields)
+	return rendered.String(), err
+}
+
+// When a step is due for an enrollment
+func (step sequenceStep) due(enrollment sequenceEnrollment) time.Time {
+	if step.AtSeasonEnd {
+		return calendar().seasonEnd(programForTeam(enrollment.Team), enrollment.GiftDate)
+	}
+	return enrollment.Started.AddDate(0, 0, step.DelayDays)
+}
+
+// Whether a gift starts a gift-triggered sequence, given every gift the donor has made
+func (sequence Sequence) startedBy(gift Gift, donorGifts []Gift) bool {
+	if !sequence.Active || (sequence.Team != "" && gift.Team != sequence.Team) {
+		return false
+	}
+	switch sequence.Trigger {
+	case TriggerFirstGift:
+		for _, earlier := range donorGifts {
+			if earlier.ID != gift.ID && earlier.Team == gift.Team && earlier.Date.Before(gift.Date) {
+				return false
+			}
+		}
+		return true
+	case TriggerLargeGift:
+		return gift.Amount >= sequence.MinimumGift
+	case TriggerRecurringStart:
+		if gift.Source != GiftSourceRecurring {
+			return false
+		}
+		for _, earlier := range donorGifts {
+			if earlier.ID != gift.ID && earlier.Source == GiftSourceRecurring && earlier.Team == gift.Team && earlier.Date.Before(gift.Date) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func newEnrollment(sequence Sequence, gift Gift) sequenceEnrollment {
+	return sequenceEnrollment{
+		ID:         newID(),
+		SequenceID: sequence.ID,
+		Email:      donorKey(gift.DonorEmail),
+		Name:       gift.DonorName,
+		Team:       gift.Team,
+		GiftID:     gift.ID,
+		Amount:     gift.Amount,
+		GiftDate:   gift.Date,
+		Started:    time.Now(),
+		Status:     EnrollmentActive,
+	}
+}
+
+func (enrollment *sequenceEnrollment) end(status string, reason string) {
+	now := time.Now()
+	enrollment.Status = status
+	enrollment.ExitReason = reason
+	enrollment.Ended = &now
+}
+
+// Called with gifts just added to the ledger. Donors who gave leave the sequences they were in,
+// then start any sequence their gift triggers.
+func triggerSequences(gifts []Gift) error {
+	var recent []Gift
+	for _, gift := range gifts {
+		if gift.DonorEmail != "" && time.Since(gift.Date) < sequenceTriggerWindow {
+			recent = append(recent, gift)
+		}
+	}
+	if len(recent) == 0 {
+		return nil
+	}
+	donors, err := giftsByDonor(time.Time{}, time.Now().AddDate(1, 0, 0))
+	if err != nil {
+		return err
+	}
+	var data sequenceData
+	return dataStore().update(sequencesDocument, &data, func() error {
+		for _, gift := range recent {
+			for i := range data.Enrollments {
+				enrollment := &data.Enrollments[i]
+				if enrollment.Status == EnrollmentActive && enrollment.Email == donorKey(gift.DonorEmail) && enrollment.GiftID != gift.ID {
+					enrollment.end(EnrollmentExited, "gave again")
+				}
+			}
+			for _, sequence := range data.Sequences {
+				if sequence.Trigger != TriggerLapsed && sequence.startedBy(gift, donors[donorKey(gift.DonorEmail)]) {
+					data.Enrollments = append(data.Enrollments, newEnrollment(sequence, gift))
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// Starts lapsed-donor sequences for donors whose last gift is old enough. A donor goes through each one
+// at most once per last gift.
+func enrollLapsedDonors() error {
+	donors, err := giftsByDonor(time.Time{}, time.Now().AddDate(1, 0, 0))
+	if err != nil {
+		return err
+	}
+	var data sequenceData
+	return dataStore().update(sequencesDocument, &data, func() error {
+		enrolled := map[string]bool{}
+		for _, enrollment := range data.Enrollments {
+			enrolled[enrollment.SequenceID+"|"+enrollment.GiftID] = true
+		}
+		for _, sequence := range data.Sequences {
+			if !sequence.Active || sequence.Trigger != TriggerLapsed {
+				continue
+			}
+			days := sequence.LapsedDays
+			if days <= 0 {
+				days = defaultLapsedDays
+			}
+			for _, gifts := range donors {
+				var last *Gift
+				for i := range gifts {
+					if sequence.Team == "" || gifts[i].Team == sequence.Team {
+						last = &gifts[i]
+					}
+				}
+				if last == nil || time.Now().Before(last.Date.AddDate(0, 0, days)) || enrolled[sequence.ID+"|"+last.ID] {
+					continue
+				}
+				data.Enrollments = append(data.Enrollments, newEnrollment(sequence, *last))
+				enrolled[sequence.ID+"|"+last.ID] = true
+			}
+		}
+		return nil
+	})
+}
+
+func startSequenceWorker() {
+	go func() {
+		for {
+			err := enrollLapsedDonors()
+			if err != nil {
+				fmt.Println(err)
+				fmt.Println("ERROR: LAPSED DONORS COULD NOT BE CHECKED FOR STEWARDSHIP SEQUENCES")
+			}
+			err = sendDueSequenceSteps()
+			if err != nil {
+				fmt.Println(err)
+				fmt.Println("ERROR: STEWARDSHIP SEQUENCE EMAILS COULD NOT BE SENT")
+			}
+			time.Sleep(sequenceCheckInterval)
+		}
+	}()
+}
+
+// Queues every step that is due. Enrollments in a sequence that was turned off wait until it is back on.
+func sendDueSequenceSteps() error {
+	var data sequenceData
+	err := dataStore().load(sequencesDocument, &data)
+	if err != nil {
+		return err
+	}
+	sequences := map[string]Sequence{}
+	for _, sequence := range data.Sequences {
+		sequences[sequence.ID] = sequence
+	}
+	results := map[string]sequenceEnrollment{}
+	for _, enrollment := range data.Enrollments {
+		sequence, found := sequences[enrollment.SequenceID]
+		if enrollment.Status != EnrollmentActive || !found || !sequence.Active {
+			continue
+		}
+		if enrollment.NextStep >= len(sequence.Steps) {
+			// Steps were removed since the donor started
+			enrollment.end(EnrollmentCompleted, "")
+			results[enrollment.ID] = enrollment
+			continue
+		}
+		step := sequence.Steps[enrollment.NextStep]
+		if time.Now().Before(step.due(enrollment)) {
+			continue
+		}
+		preference, _, err := contactPreference(enrollment.Email)
+		if err != nil {
+			return err
+		}
+		allowed := containsTeam(preference.Teams, enrollment.Team)
+		flagged, err := donorEmailFlagged(enrollment.Email)
+		if err != nil {
+			return err
+		}
+		if !allowed || flagged {
+			reason := "stopped hearing from " + enrollment.Team
+			if flagged {
+				reason = "email bouncing or marked as spam"
+			}
+			enrollment.end(EnrollmentExited, reason)
+			results[enrollment.ID] = enrollment
+			continue
+		}
+		subject, body, locale := step.localized(enrollment.Team, preference.Locale)
+		html, err := renderSequenceBody(body, sequenceFields{
+			Name:     enrollment.Name,
+			Team:     enrollment.Team,
+			Amount:   formatCentsLocale(enrollment.Amount, locale),
+			GiftDate: formatDateLocale(enrollment.GiftDate, locale),
+			Season:   calendar().season(programForTeam(enrollment.Team), enrollment.GiftDate),
+		})
+		if err != nil {
+			return err
+		}
+		reference := "sequence:" + enrollment.ID + ":" + strconv.Itoa(enrollment.NextStep+1)
+		queued, err := queueDonorUpdate(enrollment.Email, enrollment.Team, "sequence", reference, subject, html)
+		if err != nil {
+			return err
+		}
+		if !queued {
+			enrollment.end(EnrollmentExited, "stopped hearing from "+enrollment.Team)
+			results[enrollment.ID] = enrollment
+			continue
+		}
+		enrollment.Sent = append(enrollment.Sent, time.Now())
+		enrollment.NextStep++
+		if enrollment.NextStep == len(sequence.Steps) {
+			enrollment.end(EnrollmentCompleted, "")
+		}
+		results[enrollment.ID] = enrollment
+	}
+	if len(results) == 0 {
+		return nil
+	}
+	return dataStore().update(sequencesDocument, &data, func() error {
+		for i := range data.Enrollments {
+			result, found := results[data.Enrollments[i].ID]
+			// A gift may have ended it while the emails were being queued
+			if found && data.Enrollments[i].Status == EnrollmentActive {
+				data.Enrollments[i] = result
+			}
+		}
+		return nil
+	})
+}
+
+func registerSequenceRoutes(admin *gin.RouterGroup) {
+	admin.GET("/sequences", func(c *gin.Context) {
+		var data sequenceData
+		err := dataStore().load(sequencesDocument, &data)
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		type sequenceSummary struct {
+			Sequence
+			Active    int `json:"activeDonors"`
+			Completed int `json:"completedDonors"`
+			Exited    int `json:"exitedDonors"`
+		}
+		summaries := []sequenceSummary{}
+		for _, sequence := range data.Sequences {
+			summary := sequenceSummary{Sequence: sequence}
+			for _, enrollment := range data.Enrollments {
+				if enrollment.SequenceID != sequence.ID {
+					continue
+				}
+				switch enrollment.Status {
+				case EnrollmentActive:
+					summary.Active++
+				case EnrollmentCompleted:
+					summary.Completed++
+				case EnrollmentExited:
+					summary.Exited++
+				}
+			}
+			summaries = append(summaries, summary)
+		}
+		c.JSON(200, summaries)
+	})
+
+	admin.POST("/sequences", func(c *gin.Context) {
+		saveSequence(c, "")
+	})
+
+	// Replaces a sequence's trigger and steps. Donors already in it continue from the step they reached.
+	admin.POST("/sequences/:id", func(c *gin.Context) {
+		saveSequence(c, c.Param("id"))
+	})
+
+	admin.GET("/sequences/:id/enrollments", func(c *gin.Context) {
+		var data sequenceData
+		err := dataStore().load(sequencesDocument, &data)
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		enrollments := []sequenceEnrollment{}
+		for _, enrollment := range data.Enrollments {
+			if enrollment.SequenceID == c.Param("id") && (c.Query("status") == "" || enrollment.Status == c.Query("status")) {
+				enrollments = append(enrollments, enrollment)
+			}
+		}
+		sort.Slice(enrollments, func(i, j int) bool { return enrollments[i].Started.After(enrollments[j].Started) })
+		c.JSON(200, enrollments)
+	})
+
+	// Takes a donor out of a sequence by hand, e.g. when a coach is thanking them in person
+	admin.POST("/sequence-enrollments/:id/exit", func(c *gin.Context) {
+		found := false
+		var data sequenceData
+		err := dataStore().update(sequencesDocument, &data, func() error {
+			for i := range data.Enrollments {
+				if data.Enrollments[i].ID == c.Param("id") && data.Enrollments[i].Status == EnrollmentActive {
+					data.Enrollments[i].end(EnrollmentExited, "removed by an admin")
+					found = true
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		if !found {
+			c.String(http.StatusNotFound, "No active enrollment with that ID")
+			return
+		}
+		c.String(200, "OK")
+	})
+}
+
+func saveSequence(c *gin.Context, id string) {
+	var sequence Sequence
+	err := c.BindJSON(&sequence)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	err = validateSequence(sequence)
+	if err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+	found := id == ""
+	var data sequenceData
+	err = dataStore().update(sequencesDocument, &data, func() error {
+		if id == "" {
+			sequence.ID = newID()
+			sequence.Created = time.Now()
+			data.Sequences = append(data.Sequences, sequence)
+			return nil
+		}
+		for i := range data.Sequences {
+			if data.Sequences[i].ID == id {
+				sequence.ID, sequence.Created = id, data.Sequences[i].Created
+				data.Sequences[i] = sequence
+				found = true
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Println(err)
+		c.String(http.StatusInternalServerError, "Error")
+		return
+	}
+	if !found {
+		c.String(http.StatusNotFound, "No such sequence")
+		return
+	}
+	c.JSON(200, sequence)
+}
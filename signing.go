@@ -0,0 +1,70 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"sync/atomic"
+)
+
+// Tokens that leave the server (ticket QR codes, links in emails) are signed with HMAC-SHA256
+// using the 'SIGNING_SECRET' setting, so they can't be forged or altered.
+// A token is base64url(payload) + "." + base64url(signature).
+
+// Set from the configuration by Config.apply
+var signingSecret atomic.Value // string
+
+func signingKey() ([]byte, error) {
+	secret, _ := signingSecret.Load().(string)
+	if secret == "" {
+		return nil, &osEnvVarError{"ERROR: 'SIGNING_SECRET' ENVIRONMENT VARIABLE UNAVAILABLE"}
+	}
+	return []byte(secret), nil
+}
+
+func signToken(payload string) (string, error) {
+	key, err := signingKey()
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(payload))
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(tokenMAC(key, encoded)), nil
+}
+
+// Returns the payload of a token if its signature is valid
+func verifyToken(token string) (string, bool) {
+	key, err := signingKey()
+	if err != nil {
+		return "", false
+	}
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return "", false
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(mac, tokenMAC(key, parts[0])) {
+		return "", false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return "", false
+	}
+	return string(payload), true
+}
+
+// Truncated to 128 bits to keep tokens short enough for small QR codes
+func tokenMAC(key []byte, encoded string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(encoded))
+	return mac.Sum(nil)[:16]
+}
+
+// Random identifier for stored records
+func newID() string {
+	raw := make([]byte, 8)
+	rand.Read(raw)
+	return hex.EncodeToString(raw)
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Year-end consolidated giving statements. Each donor gets one email listing every gift they made in the
+// calendar year, with the totals and the disclosures they need for their taxes.
+// The statements run records whose statement has been queued, so an interrupted run picks up where it left off.
+
+type statementRun struct {
+	Year int                  `json:"year"`
+	Sent map[string]time.Time `json:"sent"`
+}
+
+type statementData struct {
+	Runs []statementRun `json:"runs"`
+}
+
+const statementsDocument string = "statements"
+
+// Held while statements are being queued, so two runs can't both queue a donor's statement
+var statementsRunning sync.Mutex
+
+// Queues the statements for a year. With send false nothing is emailed and a summary is written to out instead.
+// If donor is set, only that donor's statement is handled, and it is queued even if it was queued before.
+// Statements go through the outbox, queued to go out at most 'STATEMENT_EMAILS_PER_MINUTE' a minute to stay under
+// the mail server's sending limits.
+func runStatements(cfg *Config, year int, donor string, send bool, out io.Writer) error {
+	from, to := calendar().calendarYear(year)
+	if cfg.stripeKey() != "" {
+		found, err := syncStripeGifts(cfg, from, to)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, "Checked "+strconv.Itoa(found)+" Stripe gifts for "+strconv.Itoa(year)+".")
+	} else {
+		fmt.Fprintln(out, "No Stripe key is configured, so only gifts already in the ledger are included.")
+	}
+
+	donors, err := giftsByDonor(from, to)
+	if err != nil {
+		return err
+	}
+	if donor != "" {
+		donors = map[string][]Gift{donorKey(donor): donors[donorKey(donor)]}
+	}
+
+	org, err := organizationProfile(cfg)
+	if err == nil {
+		err = org.incomplete()
+	}
+	if err != nil {
+		return err
+	}
+	if send {
+		_, err = cfg.donationReceiptsSMTP()
+		if err != nil {
+			return err
+		}
+	}
+
+	interval := time.Minute / time.Duration(cfg.Mail.StatementsPerMinute)
+	due := time.Now()
+
+	sent := statementsSent(year)
+	for email, gifts := range donors {
+		if len(gifts) == 0 {
+			fmt.Fprintln(out, email+": no gifts in "+strconv.Itoa(year))
+			continue
+		}
+		total := 0
+		for _, gift := range gifts {
+			total += gift.Amount
+		}
+		summary := email + ": " + strconv.Itoa(len(gifts)) + " gifts, $" + formatCents(total)
+		if !send {
+			fmt.Fprintln(out, summary)
+			continue
+		}
+		if _, already := sent[email]; already && donor == "" {
+			fmt.Fprintln(out, summary+" (already queued)")
+			continue
+		}
+
+		msg := &mailMessage{To: []string{email}, Subject: strconv.Itoa(year) + " Pathfinders Robotics giving statement", HTML: renderStatement(year, donorProfile(email, gifts), gifts, org)}
+		_, err = enqueueMailAt(MailAccountReceipts, "statement", "donor:"+email, msg, due)
+		if err != nil {
+			fmt.Fprintln(out, summary+" - ERROR: "+err.Error())
+			continue
+		}
+		markStatementSent(year, email)
+		fmt.Fprintln(out, summary+" - queued to go out at "+due.In(calendar().location).Format("3:04 PM"))
+		due = due.Add(interval)
+	}
+	return nil
+}
+
+func statementsSent(year int) map[string]time.Time {
+	var data statementData
+	err := dataStore().load(statementsDocument, &data)
+	if err != nil {
+		fmt.Println(err)
+	}
+	for _, run := range data.Runs {
+		if run.Year == year {
+			return run.Sent
+		}
+	}
+	return map[string]time.Time{}
+}
+
+func markStatementSent(year int, email string) {
+	var data statementData
+	err := dataStore().update(statementsDocument, &data, func() error {
+		for i := range data.Runs {
+			if data.Runs[i].Year == year {
+				data.Runs[i].Sent[email] = time.Now()
+				return nil
+			}
+		}
+		data.Runs = append(data.Runs, statementRun{Year: year, Sent: map[string]time.Time{email: time.Now()}})
+		return nil
+	})
+	if err != nil {
+		fmt.Println(err)
+		fmt.Println("ERROR: COULD NOT RECORD THAT THE STATEMENT TO " + email + " WAS SENT")
+	}
+}
+
+// gifts must all belong to donor and be sorted by date
+func renderStatement(year int, donor Donor, gifts []Gift, org Organization) string {
+	body := "<html><head><meta http-equiv=\"Content-Type\" content=\"text/html; charset=UTF-8\" /><title>Pathfinders Robotics Giving Statement</title></head><body style=\"margin: 0; padding: 5px; font-family: 'Times New Roman', Times, serif;\">"
+	body += "<img src=\"" + receiptImageURL("logo", org.Logo) + "\" alt=\"Pathfinders Robotics\" width=\"265\" border=\"0\" style=\"display: block; height: auto;\" />"
+	body += "<p>" + calendar().formatDate(time.Now()) + "</p>"
+	body += "<p>" + html.EscapeString(donor.Name) + "<br/>" + html.EscapeString(donor.Addr1+" "+donor.Addr2) + "<br/>" + html.EscapeString(donor.City+", "+donor.State+" "+donor.Zip) + "</p>"
+	body += "<p>Thank you for your support of Pathfinders Robotics in " + strconv.Itoa(year) + ". This statement lists every gift we received from you during the year.</p>"
+	body += "<table border=\"0\" cellpadding=\"4\" cellspacing=\"0\" style=\"font-size: 12pt;\"><tr><th style=\"text-align: left;\">Date</th><th style=\"text-align: left;\">Description</th><th style=\"text-align: right;\">Amount</th><th style=\"text-align: right;\">Goods or services received</th></tr>"
+
+	total, goods := 0, 0
+	for _, gift := range gifts {
+		description := gift.Description
+		if gift.Team != "" {
+			description = gift.Team
+		}
+		if description == "" {
+			description = "Donation"
+		}
+		body += "<tr><td>" + calendar().formatDate(gift.Date) + "</td><td>" + html.EscapeString(description) + "</td><td style=\"text-align: right;\">$" + formatCents(gift.Amount) + "</td><td style=\"text-align: right;\">"
+		if gift.GoodsValue > 0 {
+			body += "$" + formatCents(gift.GoodsValue)
+		} else {
+			body += "None"
+		}
+		body += "</td></tr>"
+		total += gift.Amount
+		goods += gift.GoodsValue
+	}
+	body += "<tr><td colspan=\"2\"><b>Total</b></td><td style=\"text-align: right;\"><b>$" + formatCents(total) + "</b></td><td style=\"text-align: right;\"><b>$" + formatCents(goods) + "</b></td></tr></table>"
+
+	if goods == 0 {
+		body += "<p>No goods or services were provided in exchange for these contributions.</p>"
+	} else {
+		deductible := total - goods
+		if deductible < 0 {
+			deductible = 0
+		}
+		body += "<p>Except where a value is listed above, no goods or services were provided in exchange for these contributions. The value of goods or services provided was $" + formatCents(goods) + ", so the amount that may be deductible as a charitable contribution is $" + formatCents(deductible) + ".</p>"
+	}
+	body += "<p>" + html.EscapeString(org.LegalName) + " is a 501(c)(3) nonprofit organization. Federal Tax ID " + html.EscapeString(org.EIN) + ". Please keep this statement for your records.</p></body></html>"
+	return body
+}
+
+// Sends the previous year's statements during January. Checks twice a day, and a restart resumes
+// with the donors who haven't been sent theirs yet.
+func startYearEndStatementJob(configs *liveConfig) {
+	go func() {
+		for {
+			now := calendar().now()
+			if now.Month() == time.January {
+				fmt.Println("Running year-end giving statements for " + strconv.Itoa(now.Year()-1))
+				statementsRunning.Lock()
+				err := runStatements(configs.current(), now.Year()-1, "", true, os.Stdout)
+				statementsRunning.Unlock()
+				if err != nil {
+					fmt.Println(err)
+					fmt.Println("ERROR: YEAR-END GIVING STATEMENTS COULD NOT BE SENT")
+				}
+			}
+			time.Sleep(12 * time.Hour)
+		}
+	}()
+}
+
+// `statements <year> -send` asks the server to queue the statements here rather than writing to the store itself,
+// since a one-off dyno doesn't share the web dyno's disk. Heroku's router gives up on a request after 30 seconds
+// and syncing a year of Stripe gifts can take longer, so the run carries on after the response and its summary
+// goes to the server's log.
+func registerStatementRoutes(admin *gin.RouterGroup, configs *liveConfig) {
+	admin.POST("/statements/:year", func(c *gin.Context) {
+		year, err := strconv.Atoi(c.Param("year"))
+		if err != nil {
+			c.String(http.StatusBadRequest, "'"+c.Param("year")+"' is not a year")
+			return
+		}
+		if !statementsRunning.TryLock() {
+			c.String(http.StatusConflict, "Statements are already being queued")
+			return
+		}
+		donor := c.Query("donor")
+		go func() {
+			defer statementsRunning.Unlock()
+			fmt.Println("Running year-end giving statements for " + strconv.Itoa(year))
+			err := runStatements(configs.current(), year, donor, true, os.Stdout)
+			if err != nil {
+				fmt.Println(err)
+				fmt.Println("ERROR: YEAR-END GIVING STATEMENTS COULD NOT BE SENT")
+			}
+		}()
+		c.String(http.StatusAccepted, "Queuing the "+strconv.Itoa(year)+" statements. The summary will be in the server's log.")
+	})
+}
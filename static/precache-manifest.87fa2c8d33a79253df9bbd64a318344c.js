@@ -16,7 +16,7 @@ self.__precacheManifest = (self.__precacheManifest || []).concat([
     "url": "/static/js/2.0222e13c.chunk.js"
   },
   {
-    "revision": "2b7fe75f4d07cfdc1d9c",
+    "revision": "d2ddd834ddb6759127a1",
     "url": "/static/js/main.eb84e833.chunk.js"
   },
   {
@@ -1,2 +1,2 @@
-(this.webpackJsonppathfinders=this.webpackJsonppathfinders||[]).push([[0],Array(39).concat([function(e,t,a){},,,,,,,,,,function(e,t,a){e.exports=a.p+"static/media/Pathfinders-Logo.5831fc26.svg"},,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,function(e,t,a){e.exports=a.p+"static/media/AndrewScott.7e189760.jpg"},function(e,t,a){e.exports=a.p+"static/media/CarsonBaty.f3cc4af7.jpg"},function(e,t,a){e.exports=a.p+"static/media/ErinStone.968fbfd7.jpg"},function(e,t,a){e.exports=a.p+"static/media/GabriellaAvilez.10712144.jpg"},function(e,t,a){e.exports=a.p+"static/media/MasonHayes.7e8c37d3.jpg"},function(e,t,a){e.exports=a.p+"static/media/SamyuMogolapalli.d46531cc.jpg"},function(e,t,a){e.exports=a.p+"static/media/Team7885.e15e128b.jpg"},,function(e,t,a){e.exports=a.p+"static/media/Team13497.16fc3aa6.jpg"},function(e,t,a){e.exports=a.p+"static/media/Makerspace.4eed80be.jpg"},function(e,t,a){e.exports=a.p+"static/media/Marshalltown.eb6d594a.jpg"},function(e,t,a){e.exports=a.p+"static/media/Kickoff.f3485fb8.jpg"},function(e,t,a){e.exports=a.p+"static/media/TempleTeaching.2f9d2a47.jpg"},function(e,t,a){e.exports=a.p+"static/media/TempleCrazy.2d3ecfe0.jpg"},function(e,t,a){e.exports=a.p+"static/media/PizzaRanch.b66814fa.jpg"},function(e,t,a){e.exports=a.p+"static/media/ISU.51c16aec.jpg"},function(e,t,a){e.exports=a.p+"static/media/STEMFest.fc6602b6.jpg"},function(e,t,a){e.exports=a.p+"static/media/WorkOnRobot.2704ef76.jpg"},function(e,t,a){e.exports=a.p+"static/media/CIJUG.95556508.jpg"},function(e,t,a){e.exports=a.p+"static/media/RobotBuilding.1198411b.jpg"},function(e,t,a){e.exports=a.p+"static/media/CAC.94e185e2.jpg"},function(e,t,a){e.exports=a.p+"static/media/Judges.ca49652a.jpg"},function(e,t,a){e.exports=a.p+"static/media/Teacher.1f523df9.jpg"},function(e,t,a){e.exports=a.p+"static/media/Shuler.b1fdc0bc.jpg"},function(e,t,a){e.exports=a.p+"static/media/Robot.7d1dca50.jpg"},function(e,t,a){e.exports=a.p+"static/media/SiouxBooth.fbf7bc8f.jpg"},function(e,t,a){e.exports=a.p+"static/media/AryaKarnik.745baf48.jpg"},function(e,t,a){e.exports=a.p+"static/media/BenjaminAvilez.22d4bd64.jpg"},function(e,t,a){e.exports=a.p+"static/media/KristenStone.4ce03759.jpg"},function(e,t,a){e.exports=a.p+"static/media/OwenScott.ccd2cf7e.jpg"},function(e,t,a){e.exports=a.p+"static/media/SabarishMogolapalli.09bedb3b.jpg"},function(e,t,a){e.exports=a.p+"static/media/SeanEastman.8657ece3.jpg"},function(e,t,a){e.exports=a.p+"static/media/ShriyaMagatapalli.587af85c.jpg"},function(e,t,a){e.exports=a.p+"static/media/JohnDeere.dfc553be.svg"},function(e,t,a){e.exports=a.p+"static/media/McClureEngineering.7f1b0c76.png"},function(e,t,a){e.exports=a.p+"static/media/McCormack.a6b9abb9.png"},function(e,t){e.exports="data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAfQAAAF3CAYAAABT8rn8AAAAGXRFWHRTb2Z0d2FyZQBBZG9iZSBJbWFnZVJlYWR5ccllPAAAGZNJREFUeNrs3c1vHOd9B/CRrKB5AROlLVrQ7UEuUKY9FJBBoD0YqFeH3miXKdAD3YPpi3SrX5RLLxHlSy9mavUmAQWoQ8wCBRoC4h+wPqRAgBJW0bRBWLRgCkRs0aJRsrUTO5bYeaiHBU2TO7OzM7Pz8vkAAxmWyJ195pnnO7+ZZ2aSBAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAACAdjinCaC7Dt68cDH9YzldXv3BX//mwx9+77N3nzt4b0PLgEAH2hHkl0OIxzAPoZ78y18tJP/1778Q/vNhuoRQv5WG+57WAoEONLQaT5fLJ//+WKAfN0wXVTsIdKCJ1fhpzgj0I6p2EOhA06rxAoGuageBDtQY5JfSP25kVeNTBvrxqv1WqNxV7SDQgXKCfDX94+V0GRT9HQUC/bitWLVv2Rog0IHJq/FwSn110mq8gkA/Eir1u6p2EOhADdV4hYGuageBDtRVjdcU6Kp2EOhAldV4zYGuageBDqrxuj63pkA/XrUfzZB/aKuDQIcuBfnRfeODWXx+zYF+3Eas2od6AQh0aHM1HirxcFr90izXZYaBrmoHgQ6trsZDiC83ZZ0aEOiqdhDooBrvWKCr2kGgg2q8Q4GuageBDqrxjgX68ar9ZrpsqdpBoEOvq/GWB/qREObhfvbwStf7eh8IdJg2xC/GavzVtlTjHQn040Kg31K1g0CHIkE+iNX4ahe+T8sDXdUOAh36V413ONBV7SDQoT/VeE8CXdUOAh26XY33LNCPGyZPbn3b0MMR6KAaF+jdqNo3YtW+p9cj0KFb1Xi41exGH6pxga5qR6BD14L8cvLklHoI84t9boseB7qqHYEOLa/GQ5Bf1iICXdWOQAfVuEBXtYNAB9W4QG+RrVi1b2kKBDqoxgV6+4VK/W7y5JWuqnYEOqjGBbqqHQQ6HA/ySzHEV1XjAl3VDgKd9gV5CPDwAJiB1hDoqnYQ6KjGEeiqdgQ6qMYFOpk2YtU+1BQIdFTjCPRuVO23YtXula4IdFTjCHRVOwh0VOMIdFU7Ah1KDvLlWI0vaw2BjqodgU77qvHVGOSXtIhAR9WOQEc1jkDvshDm4X728HKY+5oDgY5qHIHefvdj1b6lakegoxpHoKvaEeigGhfoqNoR6HQ5yAcxxFe1hkBH1Y5Ap10hfjEG+KuqcYGOqh2BjmocgU51VftGrNr3NAcCHdW4QKf9hsmTB9ZsaAqBjmocgY6qnRa7oAl6G+bfStxyBl0Tzra9FhcFW8+c1wS93vEBEOgAgEAHAAQ6ACDQAUCgAwACHQAQ6ACAQAcAgQ4ACHQAQKADAAId6jZMl1fS5aam+ESbXEmevBEMEOjQWEevr3zm3Nc/vpIuguuE5w7eG6ZLOND5crq8ni57WgWm5/WpUI69WIlvpSH+UHPkCvbQTm+H5dvnnh2kf76cLqtaBgQ6zEKowO+mIT7UFNNV7ekfwzTYX4+h/mq6XNIyINCh6mr8bgjzNMj3NIeqHQQ6tMswVuMbmkLVDgId2iVUjFvpclM1rmoHgQ7tE8LbJLdmV+2vxXBXtYNAh0/ZSDo6ye3HDz7Ttap9LSxpuC/HYF/WfRHooBrv/CS3jz7u5mMn0nAPl0S20mAPlfqqqh2BDv0zTExy61Kw76naEejQHya59SPcVe0IdOioEN4muanaVe0IdGipjcST3DhRtX/pt0bf+ukPPnf5o58aAhHo0FyPD5LHHx4kD3/2OPmlvzh82xl8omrf/4PDSy/JT//tV5P//aeLyWjv8xoGgQ6N8fPHyYdpkP/oZwfaglw+9xv/ebj84ujzyfv//CvJT/5xLlG1I9BhFg7Ohfuxkv/54CD56JHmoJin5j5Ivvh7e+miakegQ70eHyQffvA4+clHB8mjx5qDaqr2n7w3n/z4u19IHj96SsMg0KFUHz5KRh8eJO9/pCmovmr/8u//a7okyfvf+7Vk9N0vJh/8x2c1DAIdpqnGjya5Oa3OLHzht394uDxStSPQoQCT3FC1g0CnpUxyQ9UOAp0WM8kNVTsIdFosDfDRzx6Z5EYnq/Yf/cMXNQq1Oa8JmCVhTperdhDoAIBABwCBDgAIdABAoAMAAh0ABDoAINABAIEOAAh0ABDoAIBABwAEOgAg0AEAgQ4AAh0AEOj03vsfaQMAgQ4ACHQAEOgAgEAHAAQ6ACDQAUCgAwACHQAQ6ACAQAcAgQ4ACHQAQKADAAIdAAQ6ACDQAQCBDgAIdAAQ6ACAQAcABDoAINABQKADAAIdABDoAIBABwCBDgAIdABAoAMAAh0ABDoAINABAIEOAAh0ABDoAIBABwAEOgAg0AFAoAMAAh0AEOgAgEAHAAQ6AAh0AECgAwACHQAQ6AAg0AEAgQ4ACHQAQKADgEAHAAQ6ACDQAUpxWRMg0AHa76ImQKADAAIdABDoAIBABwCBDgAIdABAoAMAAh0ABDoAINABAIEOAAh0euy+JgAEOrTfQ00ACHQAQKADAAIdABDoACDQAQCBDgAIdIAOOPjwMxoBgQ7Qdj//7y9pBAQ6ACDQAUCgAwACHQAQ6ACAQAcAgQ4ACHQAQKADAAIdAAQ6vXQrXYYd/n7hu920mTlD6BsPO/rd9vR9gU6PnPv6x1vpciX9z2fS5e0ODW5hMPvq/F8mV9JlaEtzmrRvrMW+36VgD/39lecO3nsmXdZs5f65oAl6H+whAF8Py8GbF5bTP19Ol+UWfpUwKN9MB+q3bVVyhnroM2v7f3rYZ15Ll1fT5WIL+/1W6PtpiO/Zqip0OF61fzVWLq/HarcNQpX1jDCnaLC3sGIP++YrYZ3TIH9FmKNCZ1zVHsLx7bRqH8SqfbWBq7oRq3KDGX2p2EOfv5sG+NAWQ6AzabiHgWOYBnuo2JfjIHd5xqs1jEFuUKMPwR4OWO+GMFeJI9ApI9gfxupgIw33y8eq9joHujCYvZ4OuFu2CD0I9mGsxjdsDQQ6VYX7/fSPsISJdKsx3AcVfmQYWG/F65zQ5WA3yY3CTIpj2nDfOHb7282k/Il0YRB9RpjThGCvcPJc2G/CZS2T3FChM/NgDwNQGOzW4u1vf5hMN5EuVCmvm/BGxyv20M9vmeSGQKep4R4Gqa04kW41DniXPvUPHx+c9uNhYDPhjdYEewz30M9vnNrPP+3wElJikhsCnRYFexi4jm5/uxyDffn/q5lHnwj0vRjkG1qOFoZ76LcbGcEeDlJNcqMyrqFTV7jfT5fDB2EkTx6IMTxWrYQgf0aY04VgD3059PHHH5/bO//Uo/C/Q79+Ng3yK8IcFTpdq9oPq5lHf3Y+VDEP46lL6FSwf/vcr2/98u/+6OJXvrO3p0UQ6HTaU3/+2EBHZ6XV+MPkOw5WqY9T7gAg0AEAgQ4ACHQAQKADgEAHAAQ6ACDQAQCBDgACHQAQ6ACAQAcABDoACHQAQKADAAIdABDoACDQAQCBDgAIdABAoAOAQAcABDoAINChE4bpcl8zHHqoCUCgQyud+/rHw3R5Nv3PK+my0dNmCN/7ynMH731Vj4CKxhpNAPU6ePPCxfSP1XR5NV0u1fW5f3fjd+r+qnvpcjdd3k6DXGUOAh06He7L6R8vp8tyhwJ9KwR5GuJbtjAIdOhbsF+KFXuo3C+2MNCPqvGNNMj3bFEQ6CDc37ywGqv2QQsCXTUOAh3ICPbLsWpfLqNqLzHQVeMg0IECwV7KJLoSAl01DgIdKCncB8mT0/GrNQV6mJ2+kS63VOMg0IHyg/1SDPWX81btEwb6MFbjG1obBDpQT7gfBftgykBXjYNABxpStd9IzphENybQVeMg0IEGBvvFGOphEt3lMwJdNQ4CHWhRuA+SOIkuBrpqHADaXLV/+9yzl7UEAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAALNzrq4P+qM//pOn0z+upsv8ib/aT5c7f/s333xQ8ue9kP6xdNbfp593zeanor5+u8aPW0/78q5WBy7UNMDNpX98M13mzvgnz6f/5sV0YBqV9HlX48EDzMJijZ81p7mB2gI9NcgYeMLfXU+XtRLPBJR9UPLCKWcXqrCTHtjsnPg+z89o4A4HWO8WOXuSs70+8V1L3FahzZbq/MyWnklYiAcfRfvWbmzPUcnrtXjyoCj9jDs5t/nimPUcVrV+qf3099+roBBaOmv7ZLVJRludHFOm2i/GrGsp+1vGeLJd1hne+D3Ctl0oaTNul332uQmBnicIl9LGfKeE04dvVPQdlmqqvMJOunNswL094yrsaroeN/MOhnGHuJ1zhxgefdcSB8Fw8HhjTJvdKfszWxbioV1W4jJX0u8M23GzpIF7JR7cn/z/pwZYDKc3YtEw7ozJSvpvw+W99WmCPfavt8as+7UyDnBy7kd3CvzeM8eU9O9C+BQtqs5c1zh+3JuiLW5njL2h3z2Ysr0XYiE4qGj8vFfH/n2+YePN9RKOnAdJd6wlsz+lOjfhdlmZ4Oi2igOkG4nT0OP2j3fiwFVmG4V97nb6+6fdf8f1tatx0D0Zrt+cYJ8PhcVbU67nuJ89CoWy+vFCBd1gfcy2X4ptWqR6HreuN+K2LXoAtVjxfnE97hdVZUfh79/2QF+Mg07ho6GOjcELDVmPSS41TLJTzJ0cpEsILGF+9qB7O6n2slGogtcq7O9rRwNjnCfzVsHtvRJ/vop9YSWeNWhcYRLXa37KbVB0fFhp4hgY++tKDbtgLWN50wK9cJVex5EclXRc26y4UZ7T3PGg6UZN67Q0Zahn9a130t9/r4SD96tlHkyeMO1lv6oKk/kZ9tWVpu08sTJf6tKA0MRAX4jVRC0HApS6gxQJZ4FeTLgenPfWy/Wa121pyjNtWaFUVjBVFZyDot+/w4XJXMFxvcqxaqVrjXyhoesVjp6HeSeXxMko8x3ZJvstXvciA9FXehrIUz0HIe8EtBrvzjjtAPulhm+DELxPVzQL+WrBbdzlwiS0yb0GrUvnNDXQ5+PRU+YsznhNrSsbZ7Ou2ZANCvT5CgfVxqrx1rlZVSHhTNtCCx56E/psFX0vzAcaTDKjvmOFyVn7+uKsbxs9drumQK9RmFyymaNKL+32mxlXXfunhFrRI/yFCtezaKCPxmynqgbVXosHu3mvE28nT24jzNrfjmZy59nnBsmTe8ArOfiN63uap5P8d1tUGaDXx6xjlwuTKs5czKLwGMWisow+XMuBbZMD/aiDr4/ZCZ5OGnQdpOwjzyK/L22TUd3rGT93MUeHXhyzg7X5zERT5Q3zSe6T3Qn3KydPbvOpYsZ0Hi9lVP6hf9/Lcf/yJIN70Yp0JV3XzTwFTNKPOzQWG3BGLs9lvv3Yz0ZtatzzDV+/rFtAriZuU2rMjpoVBDMaVMnYLpNe5omDXJ6JdlXsm9sTnMZfb0D7Xs26B7lphUlNVfos5Tkrs962MG9DoAdvnLEThKP/pYQ2VINZA/B8XQ9eYKIDrXGhPpzR+j6YYB2bcP1+LkdY960wWWr6/j7D/t35QD/rFhC3qbWnQg9HuvtT/Dy02ZlnGntcmKzoFv0M9KMj2OM7waIAaI44KM1lBHpWZWV70lXjJrz1tTAR6D0O9MUTzxhWnbenOg92Vej03NIpz6Lvc2HSqAfNCPTyZU1AuB53gqwXAYxs1sYF+ijHrNYF19HpuOuq80+4qkt0N9Dfzfj7+fhChatT/h7Kl3UbyNHkJFU6vT7wPZoPlKMw6YP5Ch8RLNBnLFRwWTNuQ5hn3XLgfuYa5XyD0/6xbTy2SteidL1Kz/kQmb6caVSldzTQgztT/vzOrB8r2MeqI+sfHDvdvjvt74KG20nGn4kKB61Zr7HdTWp6slgTxo9pXzdLQwM9hvE0gXzHJm1coO9OUHUIdLog6yxh1pmozZ61lyq9oxV6UPTpTkPVeSMD/cGJ6mUs19TogM2k+Cnz/Za/oKmIJRNiOxro8elO2wV+9Bs2Z71yXj/fPeO/Vel0UnxkaNEqu6tnGbP2ffeld7RCL9Kpt/v2+s2GyDOJbefEQGemO3UdcA5mGOp3cvT1T4Veh6vzrO8l0EvQyLethXBOd8awQ+S5tjJKXDuflTzhu3tKwC/1PdDjm8CKCO232cYXR9TcvuFg80YJleO0hcmNCf79eoc3ydHDpc46o3f4oJkeXm7ofqBHm0m+VwpuNqU6j/fJTyp08mFLB+jMCXGnfK/vZwT64WDckBdrzPpg6KyfC8u1pJ8Wc+xnIczzVueVjR0hnNJ1Xcq5rftwh84woxK/mjT8tuN41mdhij5RafHZ2EAPQZA23mZGlT7NtaoqFJ2tGV6x2Kp378ZJLFkdeyfn/zsttPpy207RUOvDQc+4A5qyVP0gqjCA5zkbs96DbXcvI9APHzTT1AObdN3WkulfpFNpoDf6We45rkPd6cipx/mkfbdu5Ko6Ttmmu4nb18pgVvD0dqo+u5fzVtztPhycxe+Y9T0bOQ625a14bXg5y1lHruH2ji7dr9m2p6QVuX6et0oX6NShrrk3NxuyHk2QNWY39UEzgzY0buMDPb5ofrPnO0EbD0D2x1Q/WUfpcyffTAVlB0tdp3bjfrA9Zj16c4dOnPSWdYbOg2Y6XKGHThCq9BeTJxOBDhezIRtfoe8U/DtVeraRhyhNZTuOKXVaP+VAtq936GRV6U180EwrLu1eaEsPiEexXT6SHbZlRXM+zW1nzLbcSX9H1s9/JeE0YU7Jdc0wVWVe+wS0ONfnpRP7zqintx/muYNppWEHO++2Yb+7YP9uzCDTpvkAeQL9+zkCf7HHFXqh285U5u0Lc9vv0wc36YHNdjJ+xnujAj0+GyXMhXgjafCEVIE++0F6v4XX0LLCdpRj1m5WoIdbWJ7u6vVFA3thIQjunREAg4yfDady73goT6Oq9LPMxXfGN2mfDc8VGCZnzx+6nsx4crNAN0hXEehhZ/z7kj7HI3057sFp+1m8hJMV6HNJ807l9nWsfBCr9HG3gj2fNOx5FPFg8NRxPv0+Mz9QPK9rMYma34ZmYhyTHEznOaB+QWs1RtaB1SDxvAWBTqUWOvpZtF+eeSjzTTuV2+cqPcdB2EBLCXS6UTUveE8yEwTEMMn3hjNv9mpPlT6viQQ63Qj0WXwe3Q6IowNF/aoZB2F5L5Ug0ClTfHpb3RWzgZdJhCo9z+QkTyNrjm1NINDpfnUu0Jm04sv7BsamPjO8j9ss3Ia4ryUEOvXK8/S2nQmXrB3ZdXQmlfchTar05nArYQnch06Z1XJ4SM5ED9dJwzpMUMp6pOJC4jobE1TpOe5xDo4eNONZBw2o0tNt0einsKnQ6Yx4ejJrxmmR0PWiFmZZ8S1pqsbY1AQCnWZU54UCPT4idlTCZ8PxfvUgZ39ccUmnUYHusbwCnbYGerRbwmdDkSo9hPlAUzXiICyEuRnvAp0GBPo0L5nJPBBw33CztKGqjfc453kWuMlxzarS29q/Z37XhEAnb+eu4vr5JD8r0KtR9DGobbn27HGw7arSHzSkSp/oaYKx4MgaIyu/nCDQKaM6nyrQc76lTqBPLs8AEsJsbZKKJP23g5xV7czflDXBPc4mxzVH1bew5emXK7Gf59kfwl04bzVhf3DbWrmV7LSn7g6vITXwfc2VBvqxnx/3OWW9qGWxhO20H4Oi6dXObnytaJ4wez79t+8m2a+rHUywLZry6st7OQ5AQr9Y9J76ZlTp6bbIGg+qDvRwgPtWuh7h3w4zxsbFpuwPAr1cZVyLu5p2ohcbFupVXj/PG+jhHesLcVb8tN9l6oEiDv5rLeiTwyTfpK+5CqrUpoRjOO2+kmTf4/xC4nkHTarSb1e4T9zI+W8XSiwmKu9bTrk3z1zSoEk68VTsQg0dtW2n3ZdaMlFvVpOMtpvywJYJZk8veRxsY6r0yl7aMqPZ9PvxbYACvYea9B7wOk63t/U6euMDPbbrsOaPHSXNe5Rn3gMb19Kbo8rQvZPUe8/7Wh0fItAp4+CirCPprN/jASDF3EzqvZ79jaY9TrVBs6fJv80qe2lL7A/fqOtgsq65GXUFetMmeTX9aURVrt9uyeuyX+Lg3bbrl/s1/1zRwWsUK4Q6Qv3mFBMGRxXvG+/UsG12ahoDRjXs+7Mcd45X0lUeMNys+ixD+jnrdTVyXYG+ndEBy76msTnl38/au1O29TjDkrddmSE8bNl22ym4DWqfIR8nE16rsEoNQXhtmtn/cR13M8aJd6f8/eP6626O9d8u2CfK3o+3C3z/UY71265gXXeKTvKN22Nsn5im+o2//1oFB9mjeHC7Vud+fr6mwWQ0ptGOvniZp+jujAmHcPqjya/q25xyULw3Jvgm/u4Z2y78v/US+8nuGYPD4TXZOiaVTLizntVn15vY/8K2jAPMtRwHapMc1IS2eKGk04pnnUkoa5xYP+V778ff/VLOn98Zs347JW2r4ZjqdDhF5fq1jPV/UGBdH8RKd3RG//jalM1xbdw6l9DW4YDjhfi7pt1+u7GPvDiLW1vP1f2BJ2cGV3lt4ZQ3hBU+PRwfHlD1NdzSTl+fMjt96t8d2/PoNo4wCA6ruL0u9pHFuMPu5L1VLeeM/DIG2526+1+N+0jp7VDG/lbmZ8V+shR/f6FnCpzSbrsV7Qul78dVtu+J8X1Uwm2mtbd50fHeMwwAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAaIv/E2AAudQO0LMOK+MAAAAASUVORK5CYII="},function(e,t,a){e.exports=a.p+"static/media/RWE.633e40ce.svg"},function(e,t,a){e.exports=a.p+"static/media/Terracon.232fc414.png"},,,,function(e,t,a){e.exports=a.p+"static/media/UnderConstruction.4bc2d788.gif"},,,,function(e,t,a){e.exports=a.p+"static/media/FTC1819.19a16e9e.jpg"},function(e,t,a){e.exports=a.p+"static/media/Mission.09a6ee4a.jpg"},function(e,t,a){e.exports=a.p+"static/media/CubScout.76f9edbc.jpg"},function(e,t,a){e.exports=a.p+"static/media/GirlScout.b6bc0a29.jpg"},function(e,t,a){e.exports=a.p+"static/media/IAState.d083dbba.jpg"},function(e,t,a){e.exports=a.p+"static/media/STEMFest.b9b7be7f.jpg"},function(e,t,a){e.exports=a.p+"static/media/CIJUG.4ccebc27.jpg"},function(e,t,a){e.exports=a.p+"static/media/NewMemberMtg.07230887.jpg"},function(e,t,a){e.exports=a.p+"static/media/Booth.05ecd619.jpg"},function(e,t,a){e.exports=a.p+"static/media/KristenChild.9412ef0d.jpg"},function(e,t,a){e.exports=a.p+"static/media/AnkenyStemFest.524ef093.jpg"},function(e,t,a){e.exports=a.p+"static/media/Library.aa732d20.jpg"},function(e,t,a){e.exports=a.p+"static/media/MealsFromHeartland.3255f7ab.jpg"},function(e,t,a){e.exports=a(204)},,,,,,,,function(e,t,a){},,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,function(e,t,a){"use strict";a.r(t);a(157),a(158);var n=a(0),r=a.n(n),o=a(30),i=a.n(o),s=(a(164),a(8)),l=a(7),c=a(10),u=a(9),m=a(11),d=a(213),h=a(26),p=a(55),f=a(45),E=a(206),b=(a(39),function(e){function t(e){var a;return Object(s.a)(this,t),(a=Object(c.a)(this,Object(u.a)(t).call(this,e))).mounted=!1,a.state={output:r.a.createElement(E.a,{animation:"border",variant:"primary",role:"status"})},a}return Object(m.a)(t,e),Object(l.a)(t,[{key:"componentWillMount",value:function(){this.mounted=!0}},{key:"componentDidMount",value:function(){var e=this,t=new Image;t.onerror=function(){e.mounted&&e.setState({output:r.a.createElement("div",null,r.a.createElement("h1",null,"Uh Oh! Path not found!"))})},t.onload=function(){e.mounted&&e.setState({output:r.a.createElement("img",Object.assign({},Object.assign({},e.props,{className:e.props.className+" "+e.props.responsive?e.props.className+" responsive":e.props.className,text:void 0}),{alt:e.props.alt?e.props.alt:""}))})},t.src=this.props.src}},{key:"componentWillUnmount",value:function(){this.mounted=!1}},{key:"render",value:function(){return r.a.createElement("div",{className:this.props.helvetica?"topMargin":""},this.state.output,r.a.createElement("p",{className:this.props.helvetica?"helvetica":""},this.props.text))}}]),t}(n.Component)),A=a(44),g=a(49),y=a.n(g),v=function(e){function t(){return Object(s.a)(this,t),Object(c.a)(this,Object(u.a)(t).apply(this,arguments))}return Object(m.a)(t,e),Object(l.a)(t,[{key:"render",value:function(){return r.a.createElement(h.a,{fixed:"top",sticky:"top",className:"navbar titanic standardFont",variant:"dark",expand:"lg"},r.a.createElement(A.LinkContainer,{to:"/"},r.a.createElement(h.a.Brand,null,r.a.createElement(b,{src:y.a,width:"30",height:"30",className:"d-inline-block",alt:"Pathfinders Robotics Logo",text:"\xa0Pathfinders Robotics",helvetica:"true"}))),r.a.createElement(h.a.Toggle,{"aria-controls":"basic-navbar-nav"}),r.a.createElement(h.a.Collapse,{id:"basic-navbar-nav"},r.a.createElement(p.a,{className:"mr-auto"},r.a.createElement(A.LinkContainer,{to:"/"},r.a.createElement(p.a.Link,null,"Home")),r.a.createElement(A.LinkContainer,{to:"/donate"},r.a.createElement(p.a.Link,null,"Donate")),r.a.createElement(f.a,{title:"Our Teams",id:"basic-nav-dropdown"},r.a.createElement(A.LinkContainer,{to:"/teams/13497"},r.a.createElement(f.a.Item,null,"FTC Pathfinders 13497")),r.a.createElement(A.LinkContainer,{to:"/teams/7885"},r.a.createElement(f.a.Item,null,"FLL Phoenix Voyagers 7885")),r.a.createElement(f.a.Item,{href:"https://circuitbreakersrobotics.com",target:"_blank",rel:"noopener"},"FTC Circuit Breakers 10435"),r.a.createElement(f.a.Divider,null),r.a.createElement(A.LinkContainer,{to:"/teams/create"},r.a.createElement(f.a.Item,null,"Create a Team"))))))}}]),t}(n.Component),w=a(90),T=function(e){function t(){return Object(s.a)(this,t),Object(c.a)(this,Object(u.a)(t).apply(this,arguments))}return Object(m.a)(t,e),Object(l.a)(t,[{key:"componentDidUpdate",value:function(e){this.props.location.pathname!==e.location.pathname&&window.scrollTo(0,0)}},{key:"render",value:function(){return this.props.children}}]),t}(n.Component),S=Object(w.a)(T),C=a(89),O=a(63),N=a(20),I=a(12),j=a(207),P=a(208),F=a(104),R=a(216),k=a(96),D=a(13),L=a.n(D),M=a(36),W=a(97),x=a.n(W),V=a(98),H=a.n(V),B=a(99),z=a.n(B),Q=a(100),q=a.n(Q),U=a(101),Y=a.n(U),G=a(102),K=a.n(G),X=a(103),J=a.n(X),Z=r.a.createElement(p.a,{variant:"pills",className:"flex-column",defaultActiveKey:"#FLLPathfinders7885"},r.a.createElement(p.a.Item,null,r.a.createElement(p.a.Link,{href:"#FLLPathfinders7885"},"FLL Phoenix Voyagers 7885")),r.a.createElement(p.a.Item,null,r.a.createElement(p.a.Link,{href:"#FLLPathfinders7885Mission"},"Mission")),r.a.createElement(p.a.Item,null,r.a.createElement(p.a.Link,{href:"#FLLPathfinders7885Finances"},"Finances")),r.a.createElement(p.a.Item,null,r.a.createElement(p.a.Link,{href:"#FLLPathfinders7885Team"},"Meet the Team"))),_=function(e){function t(e){var a;return Object(s.a)(this,t),(a=Object(c.a)(this,Object(u.a)(t).call(this,e))).state={width:window.innerWidth,selected:"FLL Phoenix Voyagers 7885"},a.updateWindowWidth=a.updateWindowWidth.bind(Object(I.a)(a)),a}return Object(m.a)(t,e),Object(l.a)(t,[{key:"componentWillMount",value:function(){Object(D.configureAnchors)({offset:-90}),window.addEventListener("resize",this.updateWindowWidth)}},{key:"componentDidMount",value:function(){N.a.pageview(window.location.pathname),this.updateWindowWidth()}},{key:"componentWillUnmount",value:function(){window.removeEventListener("resize",this.updateWindowWidth)}},{key:"updateWindowWidth",value:function(){this.setState({width:window.innerWidth}),this.state.width<775?Object(D.configureAnchors)({offset:-190}):Object(D.configureAnchors)({offset:-90})}},{key:"render",value:function(){var e=this;return r.a.createElement("div",{id:"7885"},r.a.createElement(M.Helmet,null,r.a.createElement("title",null,"FLL Phoenix Voyagers 7885 | Pathfinders Robotics"),r.a.createElement("meta",{name:"description",content:"FLL Pathfinders 7885 started as a feeder team to the FTC Pathfinders 13497. We're composed of six students between fourth and seventh grade. Many of us ..."})),r.a.createElement(j.a,{fluid:!0},r.a.createElement(P.a,null,r.a.createElement(F.a,{className:"teamArticle standardFont"},r.a.createElement(L.a,{id:"FLLPathfinders7885"},r.a.createElement("div",null,this.state.width<775?r.a.createElement("div",null,r.a.createElement("div",{className:"mobileBar"},r.a.createElement(p.a,{variant:"pills",className:"flex-column frozen"},r.a.createElement(f.a,{title:this.state.selected},r.a.createElement(f.a.Item,{href:"#FLLPathfinders7885",onClick:function(){return e.setState({selected:"FLL Phoenix Voyagers 7885"})}},"FLL Phoenix Voyagers 7885"),r.a.createElement(f.a.Item,{href:"#FLLPathfinders7885Mission",onClick:function(){return e.setState({selected:"Mission"})}},"Mission"),r.a.createElement(f.a.Item,{href:"#FLLPathfinders7885Finances",onClick:function(){return e.setState({selected:"Finances"})}},"Finances"),r.a.createElement(f.a.Item,{href:"#FLLPathfinders7885Team",onClick:function(){return e.setState({selected:"Meet the Team"})}},"Meet the Team")))),r.a.createElement("br",null),r.a.createElement("br",null)):null,r.a.createElement("h1",null,"FLL Phoenix Voyagers 7885"),r.a.createElement(b,{src:J.a,alt:"FLL Phoenix Voyagers 7885",responsive:"true"}),r.a.createElement("p",null,"Left to right: Samu Mogallapalli, Gabriella Avilez, Mason Hayes, Carson Baty, Andrew Scott, Erin Stone"),r.a.createElement("p",null,"FLL Phoenix Voyagers 7885 started as a feeder team to the FTC Pathfinders 13497. We're composed of six students between fourth and seventh grade. Many of us are the younger siblings of the FTC Pathfinders 13497 team."),r.a.createElement("p",null,"We advanced to the FLL State Championship right from our very first competition this past December. At the FLL State Championship, we received third place for the Project Award. In our future, we hope to advance to the national competition and inspire others to enjoy STEM as much as we do."))),r.a.createElement(L.a,{id:"FLLPathfinders7885Mission"},r.a.createElement("div",null,r.a.createElement("h1",null,"Our Mission"),r.a.createElement("p",null,"Our mission is to understand and promote STEM as best we can by building and programming a robot and completing a research project through the use of teamwork and FLL's Core Values."))),r.a.createElement(L.a,{id:"FLLPathfinders7885Finances"},r.a.createElement("div",null,r.a.createElement("h1",null,"Finances"),r.a.createElement("h2",null,"How We Spend Our Money"),r.a.createElement("p",null,"Each season, we must pay for..."),r.a.createElement(R.a,null,r.a.createElement(R.a.Item,null,"Robot Game Mat"),r.a.createElement(R.a.Item,null,"Robot Game Elements"),r.a.createElement(R.a.Item,null,"Project Components"),r.a.createElement(R.a.Item,null,"FLL Registration"),r.a.createElement(R.a.Item,null,"Competition Entrance Fees"),r.a.createElement(R.a.Item,null,"Team T-shirts")),r.a.createElement("h2",null,"How Donations Make A Difference"),r.a.createElement("p",null,"Donations allow us to compete in more competitions and upgrade robot parts and equipment. Donations also allow us to reach out to the community to make connections and work with experts. Finally, we use donations to promote STEM alongside the FTC Pathfinders 13497. None of this would be possible without the many donations from our gracious sponsors."))),r.a.createElement(L.a,{id:"FLLPathfinders7885Team"},r.a.createElement("div",null,r.a.createElement("h1",null,"Meet The Team"),r.a.createElement("br",null),r.a.createElement(b,{src:q.a,alt:"Gabriella Avilez"}),r.a.createElement("h3",null,"Gabriella Avilez"),r.a.createElement("br",null),r.a.createElement(b,{src:H.a,alt:"Carson Baty"}),r.a.createElement("h3",null,"Carson Baty, Builder, Programmer"),r.a.createElement("br",null),r.a.createElement(b,{src:Y.a,alt:"Mason Hayes"}),r.a.createElement("h3",null,"Mason Hayes"),r.a.createElement("br",null),r.a.createElement(b,{src:K.a,alt:"Samu Mogallapalli"}),r.a.createElement("h3",null,"Samu Mogallapalli, Builder, Programmer"),r.a.createElement("br",null),r.a.createElement(b,{src:x.a,alt:"Andrew Scott"}),r.a.createElement("h3",null,"Andrew Scott, Builder, Programmer"),r.a.createElement("br",null),r.a.createElement(b,{src:z.a,alt:"Erin Stone"}),r.a.createElement("h3",null,"Erin Stone, Project Captain"),r.a.createElement("br",null)))),r.a.createElement(F.a,{md:"auto",className:"sidenav"},this.state.width>775?Z:null,r.a.createElement(k.a,{variant:"primary",href:"#FLLPathfinders7885",className:"backToTop"},"Back to top")))))}}]),t}(n.Component),$=a(105),ee=a.n($),te=a(106),ae=a.n(te),ne=a(107),re=a.n(ne),oe=a(108),ie=a.n(oe),se=a(109),le=a.n(se),ce=a(110),ue=a.n(ce),me=a(111),de=a.n(me),he=a(112),pe=a.n(he),fe=a(113),Ee=a.n(fe),be=a(114),Ae=a.n(be),ge=a(115),ye=a.n(ge),ve=a(116),we=a.n(ve),Te=a(117),Se=a.n(Te),Ce=a(118),Oe=a.n(Ce),Ne=a(119),Ie=a.n(Ne),je=a(120),Pe=a.n(je),Fe=a(121),Re=a.n(Fe),ke=a(122),De=a.n(ke),Le=a(123),Me=a.n(Le),We=a(124),xe=a.n(We),Ve=a(125),He=a.n(Ve),Be=a(126),ze=a.n(Be),Qe=a(127),qe=a.n(Qe),Ue=a(128),Ye=a.n(Ue),Ge=a(129),Ke=a.n(Ge),Xe=a(130),Je=a.n(Xe),Ze=a(131),_e=a.n(Ze),$e=a(132),et=a.n($e),tt=a(133),at=a.n(tt),nt=a(134),rt=a.n(nt),ot=a(135),it=a.n(ot),st=a(136),lt=a(209),ct=function(e){function t(){return Object(s.a)(this,t),Object(c.a)(this,Object(u.a)(t).apply(this,arguments))}return Object(m.a)(t,e),Object(l.a)(t,[{key:"render",value:function(){return r.a.createElement("div",null,r.a.createElement(st.a,{videoId:"M-0EsbbQUv4",opts:{width:"100%",playerVars:{cc_lang_pref:"en",hl:"en",modestBranding:1,origin:window.location,rel:0},origin:"https://"+window.location.hostname}}),r.a.createElement("p",null,"Our complete sponsorship pamphlet is ",r.a.createElement("a",{href:"/assets/FTC13497SponsorshipPamphlet.pdf",download:!0},"here"),"."))}}]),t}(n.Component),ut=function(e){function t(){return Object(s.a)(this,t),Object(c.a)(this,Object(u.a)(t).apply(this,arguments))}return Object(m.a)(t,e),Object(l.a)(t,[{key:"render",value:function(){return r.a.createElement("div",null,r.a.createElement(lt.a,{bordered:!0,hover:!0,responsive:!0},r.a.createElement("thead",null,r.a.createElement("tr",null,r.a.createElement("th",null,"Benefits"),r.a.createElement("th",null,"$1000+","\n","Platinum"),r.a.createElement("th",null,"$500+","\n","Gold"),r.a.createElement("th",null,"$250+","\n","Silver"),r.a.createElement("th",null,"$100+","\n","Bronze"))),r.a.createElement("tbody",null,r.a.createElement("tr",null,r.a.createElement("td",null,"Team Photo"),r.a.createElement("td",{className:"yesStyle"},"YES"),r.a.createElement("td",{className:"yesStyle"},"YES"),r.a.createElement("td",{className:"yesStyle"},"YES"),r.a.createElement("td",{className:"yesStyle"},"YES"))),r.a.createElement("tbody",null,r.a.createElement("tr",null,r.a.createElement("td",null,"Letter of Gratitude"),r.a.createElement("td",{className:"yesStyle"},"YES"),r.a.createElement("td",{className:"yesStyle"},"YES"),r.a.createElement("td",{className:"yesStyle"},"YES"),r.a.createElement("td",{className:"yesStyle"},"YES"))),r.a.createElement("tbody",null,r.a.createElement("tr",null,r.a.createElement("td",null,"Listed as sponsor on website"),r.a.createElement("td",{className:"yesStyle"},"YES"),r.a.createElement("td",{className:"yesStyle"},"YES"),r.a.createElement("td",{className:"yesStyle"},"YES"),r.a.createElement("td",{className:"yesStyle"},"YES"))),r.a.createElement("tbody",null,r.a.createElement("tr",null,r.a.createElement("td",null,"Listed as sponsor on trifold"),r.a.createElement("td",{className:"yesStyle"},"YES"),r.a.createElement("td",{className:"yesStyle"},"YES"),r.a.createElement("td",{className:"yesStyle"},"YES"),r.a.createElement("td",{className:"yesStyle"},"YES"))),r.a.createElement("tbody",null,r.a.createElement("tr",null,r.a.createElement("td",null,"Listed as sponsor on social media"),r.a.createElement("td",{className:"yesStyle"},"YES"),r.a.createElement("td",{className:"yesStyle"},"YES"),r.a.createElement("td",{className:"yesStyle"},"YES"),r.a.createElement("td",{className:"noStyle"},"NO"))),r.a.createElement("tbody",null,r.a.createElement("tr",null,r.a.createElement("td",null,"Listed as sponsor on t-shirt*, normal size"),r.a.createElement("td",{className:"yesStyle"},"YES"),r.a.createElement("td",{className:"yesStyle"},"YES"),r.a.createElement("td",{className:"noStyle"},"NO"),r.a.createElement("td",{className:"noStyle"},"NO"))),r.a.createElement("tbody",null,r.a.createElement("tr",null,r.a.createElement("td",null,"Listed as sponsor on robot"),r.a.createElement("td",{className:"yesStyle"},"YES"),r.a.createElement("td",{className:"yesStyle"},"YES"),r.a.createElement("td",{className:"noStyle"},"NO"),r.a.createElement("td",{className:"noStyle"},"NO"))),r.a.createElement("tbody",null,r.a.createElement("tr",null,r.a.createElement("td",null,"Listed as sponsor on t-shirt*, large"),r.a.createElement("td",{className:"yesStyle"},"YES"),r.a.createElement("td",{className:"noStyle"},"NO"),r.a.createElement("td",{className:"noStyle"},"NO"),r.a.createElement("td",{className:"noStyle"},"NO")))),r.a.createElement("p",null,"*Donation must be received for September 31st, 2019, to receive their logo on our t-shirt"))}}]),t}(n.Component),mt=r.a.createElement(p.a,{variant:"pills",className:"flex-column",defaultActiveKey:"#FTCPathfinders13497"},r.a.createElement(p.a.Item,null,r.a.createElement(p.a.Link,{href:"#FTCPathfinders13497"},"FTC Pathfinders 13497")),r.a.createElement(p.a.Item,null,r.a.createElement(p.a.Link,{href:"#FTCPathfinders13497Sponsors"},"Sponsors")),r.a.createElement(p.a.Item,null,r.a.createElement(p.a.Link,{href:"#FTCPathfinders13497Mission"},"Mission")),r.a.createElement(p.a.Item,null,r.a.createElement(p.a.Link,{href:"#FTCPathfinders13497Events"},"Events")),r.a.createElement(p.a.Item,null,r.a.createElement(p.a.Link,{href:"#FTCPathfinders13497Finances"},"Finances")),r.a.createElement(p.a.Item,null,r.a.createElement(p.a.Link,{href:"#FTCPathfinders13497Needs"},"\xa0\xa0\xa0\xa0Needs")),r.a.createElement(p.a.Item,null,r.a.createElement(p.a.Link,{href:"#FTCPathfinders13497Wants"},"\xa0\xa0\xa0\xa0Wants")),r.a.createElement(p.a.Item,null,r.a.createElement(p.a.Link,{href:"#FTCPathfinders13497Team"},"Meet the Team"))),dt=function(e){function t(e){var a;return Object(s.a)(this,t),(a=Object(c.a)(this,Object(u.a)(t).call(this,e))).state={width:window.innerWidth,selected:"FTC Pathfinders 13497"},a.updateWindowWidth=a.updateWindowWidth.bind(Object(I.a)(a)),a}return Object(m.a)(t,e),Object(l.a)(t,[{key:"componentWillMount",value:function(){Object(D.configureAnchors)({offset:-90}),window.addEventListener("resize",this.updateWindowWidth)}},{key:"componentDidMount",value:function(){N.a.pageview(window.location.pathname),this.updateWindowWidth()}},{key:"componentWillUnmount",value:function(){window.removeEventListener("resize",this.updateWindowWidth)}},{key:"updateWindowWidth",value:function(){this.setState({width:window.innerWidth}),this.state.width<775?Object(D.configureAnchors)({offset:-190}):Object(D.configureAnchors)({offset:-90})}},{key:"render",value:function(){var e=this;return r.a.createElement("div",{id:"13479"},r.a.createElement(M.Helmet,null,r.a.createElement("title",null,"FTC Pathfinders 13497 | Pathfinders Robotics"),r.a.createElement("meta",{name:"description",content:"Our team began with an FLL team (7885 Masterbuilders). Sabarish and Owen were on FLL team Masterbuilders from 2014-2017. Their first season, the team ad..."})),r.a.createElement(j.a,{fluid:!0},r.a.createElement(P.a,null,r.a.createElement(F.a,{className:"teamArticle standardFont"},r.a.createElement(L.a,{id:"FTCPathfinders13497"},r.a.createElement("div",null,this.state.width<775?r.a.createElement("div",null,r.a.createElement("div",{className:"mobileBar"},r.a.createElement(p.a,{variant:"pills",className:"flex-column frozen"},r.a.createElement(f.a,{title:this.state.selected},r.a.createElement(f.a.Item,{href:"#FTCPathfinders13497",onClick:function(){return e.setState({selected:"FTC Pathfinders 13497"})}},"FTC Pathfinders 13497"),r.a.createElement(f.a.Item,{href:"#FTCPathfinders13497Sponsors",onClick:function(){return e.setState({selected:"Sponsors"})}},"Sponsors"),r.a.createElement(f.a.Item,{href:"#FTCPathfinders13497Mission",onClick:function(){return e.setState({selected:"Mission"})}},"Mission"),r.a.createElement(f.a.Item,{href:"#FTCPathfinders13497Events",onClick:function(){return e.setState({selected:"Events"})}},"Events"),r.a.createElement(f.a.Item,{href:"#FTCPathfinders13497Finances",onClick:function(){return e.setState({selected:"Finances"})}},"Finances"),r.a.createElement(f.a.Item,{href:"#FTCPathfinders13497Needs",onClick:function(){return e.setState({selected:"Needs"})}},"\xa0\xa0\xa0\xa0Needs"),r.a.createElement(f.a.Item,{href:"#FTCPathfinders13497Wants",onClick:function(){return e.setState({selected:"Wants"})}},"\xa0\xa0\xa0\xa0Wants"),r.a.createElement(f.a.Item,{href:"#FTCPathfinders13497Team",onClick:function(){return e.setState({selected:"Meet the Team"})}},"Meet the Team")))),r.a.createElement("br",null),r.a.createElement("br",null)):null,r.a.createElement("h1",null,"FTC Pathfinders 13497"),r.a.createElement(b,{src:ee.a,alt:"FTC Pathfinders 13497",responsive:"true"}),r.a.createElement("p",null,"Back (left to right): Arya Karnik, Benjamin Avilez, Katie Morrison, Sabarish Mogallapalli, Kristen Stone, Shriya Magatapalli"),r.a.createElement("p",null,"Front (left to right): Owen Scott, Sean Eastman"),r.a.createElement("p",null,"Our team began with an FLL team (7885 Masterbuilders). Sabarish and Owen were on FLL team Masterbuilders from 2014-2017. Their first season, the team advanced to the Iowa State Championships. The following year, Shriya joined the team. While we did not advance to state that season, we created an app concept. During the summer of 2016, Katie and Sean joined the team and we entered the app concept in the Verizon App Challenge, winning Best In State and $5000 for our school. Sabarish, Shriya, Owen, and Sankalp (a senior at Waukee High School) wrote the app in 2017 and won the Congressional App Challenge with it. We were invited to share our app in Washington D.C. at the National House of Code. In our third and last year (2016-2017) in FLL, we again advanced to the Iowa State Championships, this time winning a Project Innovation award."),r.a.createElement("p",null,"The FLL team morphed into an FTC team, and Arya, Benjamin, and Kristen joined. We won the Connect award and finalized in the Motivate and Think awards at our league tournament. We advanced to the super qualifiers, where we won the Connect award again and went to state. This season, Sean, Arya, Owen, and Kristen entered the Congressional App Challenge with a new app and won. They were invited to the National House of Code in Washington D.C. to share their app with U.S. Congress."),r.a.createElement("p",null,"Our team is currently managed by our coaches Shannon and Bhooshan, who are two parent volunteers that have been volunteering with the team for the last two years. We are also mentored by Srinivas Magatapalli, who mentored us on our robot and game strategy."))),r.a.createElement(L.a,{id:"FTCPathfinders13497Sponsors"},r.a.createElement("div",null,r.a.createElement("h1",null,"Our Sponsors"),r.a.createElement(F.a,null,r.a.createElement(P.a,null,r.a.createElement(b,{src:Je.a,alt:"John Deere",width:"256px"}),r.a.createElement(b,{src:_e.a,alt:"McClure Engineering",width:"256px"})),r.a.createElement(P.a,null,r.a.createElement(b,{src:et.a,alt:"McCormack Distributing Co",width:"256px"}),r.a.createElement(b,{src:at.a,alt:"Metalforming",width:"256px"})),r.a.createElement(P.a,null,r.a.createElement(b,{src:rt.a,alt:"Rheinisch-Westf\xe4lisches Elektrizit\xe4tswerk AG",width:"256px"}),r.a.createElement(b,{src:it.a,alt:"Terracon",width:"256px"}))))),r.a.createElement(L.a,{id:"FTCPathfinders13497Mission"},r.a.createElement("div",null,r.a.createElement("h1",null,"Our Mission"),r.a.createElement("p",null,"Our team strives to motivate communities by connecting with professionals from various sectors and promoting STEM and FIRST to children, in addition to encouraging high-quality work, emphasizing the value of others, and respecting each other and the community."))),r.a.createElement(L.a,{id:"FTCPathfinders13497Events"},r.a.createElement("div",null,r.a.createElement("h1",null,"What's Happening With The FTC Pathfinders?"),r.a.createElement("br",null),r.a.createElement(b,{src:ae.a,alt:"Teaching a Java programming class at the Des Moines Area Community College Makerspace.",responsive:"true"}),r.a.createElement("p",null,"Teaching a Java programming class at the Des Moines Area Community College Makerspace."),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:re.a,alt:"Introduced FIRST and FTC to engineers at Marshalltown Trowel.",responsive:"true"}),r.a.createElement("p",null,"Introduced FIRST and FTC to engineers at Marshalltown Trowel."),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:ie.a,alt:"Kicked off the FTC season by meeting with other teams at Valley High School.",responsive:"true"}),r.a.createElement("p",null,"Kicked off the FTC season by meeting with other teams at Valley High School."),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:le.a,alt:"Helped raise money for the season by selling ice cream as well as introducing the kids to FIRST and our mission.",responsive:"true"}),r.a.createElement("br",null),r.a.createElement(b,{src:ue.a,alt:"Helped raise money for the season by selling ice cream as well as introducing the kids to FIRST and our mission.",responsive:"true"}),r.a.createElement("p",null,"Helped raise money for the season by selling ice cream as well as introducing the kids to FIRST and our mission."),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:de.a,alt:"Fundraised by bussing tables at Pizza Ranch.",responsive:"true"}),r.a.createElement("p",null,"Fundraised by bussing tables at Pizza Ranch."),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:pe.a,alt:"Meeting and touring the Virtual Reality lab with Iowa State University professor R. Rajagopalan along with the FLL Pathfinders 7885.",responsive:"true"}),r.a.createElement("p",null,"Meeting and touring the Virtual Reality lab with Iowa State University professor R. Rajagopalan along with the FLL Pathfinders 7885."),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:Ee.a,alt:"Teaching kids about FIRST, driving the robots, and how to join at the Ankeny STEM fest.",responsive:"true"}),r.a.createElement("p",null,"Teaching kids about FIRST, driving the robots, and how to join at the Ankeny STEM fest."),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:Ae.a,alt:"Working on our robot.",responsive:"true"}),r.a.createElement("p",null,"Working on our robot."),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:ye.a,alt:"Presenting our Java code to software professionals from all over Iowa at a Central Iowa Java User Groups meeting.",responsive:"true"}),r.a.createElement("p",null,"Presenting our Java code to software professionals from all over Iowa at a Central Iowa Java User Groups meeting."),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:we.a,alt:"Working on our robot.",responsive:"true"}),r.a.createElement("p",null,"Working on our robot."),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:Se.a,alt:"Arya, Kristen, Owen, and Sean won the Congressional App Challenge for the 2018-2019 season with their app, \u201cFastLane\u201d. David Young, a representative from Iowa, presented their award.",responsive:"true"}),r.a.createElement("p",null,"Arya, Kristen, Owen, and Sean won the Congressional App Challenge for the 2018-2019 season with their app, \u201cFastLane\u201d. David Young, a representative from Iowa, presented their award."),r.a.createElement("p",null,"You can learn more about their app ",r.a.createElement("a",{href:"https://www.youtube.com/watch?v=KiRCiVZOT8A"},"here"),"."),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:Oe.a,alt:"Volunteered as a team for the FLL competition at Timberline School.",responsive:"true"}),r.a.createElement("p",null,"Volunteered as a team for the FLL competition at Timberline School."),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:Ie.a,alt:"",responsive:"true"}),r.a.createElement("p",null,r.a.createElement("b",null,"Caption Needed")),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:Pe.a,alt:"",responsive:"true"}),r.a.createElement("p",null,r.a.createElement("b",null,"Caption Needed")),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:Re.a,alt:"",responsive:"true"}),r.a.createElement("p",null,r.a.createElement("b",null,"Caption Needed")),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:De.a,alt:"",responsive:"true"}),r.a.createElement("p",null,r.a.createElement("b",null,"Caption Needed")),r.a.createElement("br",null))),r.a.createElement(L.a,{id:"FTCPathfinders13497Finances"},r.a.createElement("div",null,r.a.createElement("h1",null,"Finances"),r.a.createElement(ct,null),r.a.createElement("h2",null,"How We Spend Our Money"),r.a.createElement("p",null,"Donations are used for our team to be able to improve our robot and the opportunities for local outreaches to increase the awareness of STEM. With the money graciously donated, our team is allowed to increase our robot performance along with equipment. We pride ourselves in being able to give back to the community by teaching and advocating for STEM using donations and sponsorships."),r.a.createElement("h2",null,"How Donations Make A Difference"),r.a.createElement("p",null,"Donations to our team allow us to develop our robot and continue to improve it. We also are able to connect to the community. With your gracious donations, we can set up classes and other outreach opportunities! We are able to continue to share our passion for providing students with STEM opportunities. Donations are vital for us to continue to provide these!"),r.a.createElement("h2",null,"Sponsorship Tiers"),r.a.createElement(ut,null))),r.a.createElement(L.a,{id:"FTCPathfinders13497Needs"},r.a.createElement("div",null,r.a.createElement("h2",null,"What We Need"),r.a.createElement("p",null,"Before we can compete in any competitions, all of our team members must register for FIRST Tech Challenge. Participating in FTC is costly. We must pay registration fees to participate in FTC and participation fees for every competition. There are additional costs that come with travel to and from competitions. Our ability to compete in FTC and promote STEM in our community would be nonexistent without the donations from our gracious sponsors and donors, including John Deere, Klinker Apps, and citizens in our communities."),r.a.createElement("br",null),r.a.createElement("p",null,"In order to compete in FTC, we must construct a robot that efficiently complete the season's challenge while withstanding anything thrown at it. To do so, it requires many high quality parts and innovation. While we pride ourselves on our creativity and innovation, we still need parts that will allow us to perform well in our competitions and to compete for the seasons to come. We can only purchase these parts because of the funding we receive from our generous sponsors and donors."),r.a.createElement("br",null),r.a.createElement("p",null,"Outreach and the promotion of FIRST and STEM are just as important to us as having a working competition robot. STEM is quickly becoming the future of the world as technology advances at blinding speeds. We enjoy reaching out to the youth and spreading awareness of how STEM impacts our world today. We've attended many STEMfests, gone to elementary schools to talk about STEM, and taught boy and girl scouts about STEM. We have also started and mentored many FIRST Lego League teams for kids who are interested in STEM but not yet old enough for FTC. Outreach requires plenty of funding in order to create activities for kids and an outreach robot for them to drive."))),r.a.createElement(L.a,{id:"FTCPathfinders13497Wants"},r.a.createElement("div",null,r.a.createElement("h2",null,"Our Wishlist"),r.a.createElement("p",null,"Our team would like to develop and construct an outreach robot, specially designed for introducing robotics to children and adults alike. We have compiled a wishlist of parts that will allow us to construct the robot. You can find the wishlist complete with parts and pricing ",r.a.createElement("a",{href:"https://docs.google.com/spreadsheets/d/10tHhmR1c0Hu8aR149vtQmZCs9qmD2DRIfzac6Ep_HzY"},"here"),"."))),r.a.createElement(L.a,{id:"FTCPathfinders13497Team"},r.a.createElement("div",null,r.a.createElement("h1",null,"Meet The Team"),r.a.createElement("br",null),r.a.createElement(b,{src:xe.a,alt:"Benjamin Avilez"}),r.a.createElement("h3",null,"Benjamin Avilez, Build lead, Driver"),r.a.createElement("a",{href:"mailto:benjamin.avilez@pathfindersrobotics.org"},"Contact"),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:Ye.a,alt:"Sean Eastman"}),r.a.createElement("h3",null,"Sean Eastman, Builder, Driver"),r.a.createElement("a",{href:"mailto:sean.eastman@pathfindersrobotics.org"},"Contact"),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:Me.a,alt:"Arya Karnik"}),r.a.createElement("h3",null,"Arya Karnik, Builder, Drive team coach, Finance manager"),r.a.createElement("a",{href:"mailto:arya.karnik@pathfindersrobotics.org"},"Contact"),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:Ke.a,alt:"Shriya Magatapalli"}),r.a.createElement("h3",null,"Shriya Magatapalli, Engineering Notebook lead, FLL mentor"),r.a.createElement("a",{href:"mailto:shriya.magatapalli@pathfindersrobotics.org"},"Contact"),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:qe.a,alt:"Sabarish Mogallapalli"}),r.a.createElement("h3",null,"Sabarish Mogallapalli, Outreach lead"),r.a.createElement("a",{href:"mailto:sabarish.mogallapalli@pathfindersrobotics.org"},"Contact"),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:ze.a,alt:"Owen Scott"}),r.a.createElement("h3",null,"Owen Scott, Captain, Builder, Driver, Software lead"),r.a.createElement("a",{href:"mailto:owen.scott@pathfindersrobotics.org"},"Contact"),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:He.a,alt:"Kristen Stone"}),r.a.createElement("h3",null,"Kristen Stone, CAD lead, Engineering Notebook writer, Programmer"),r.a.createElement("a",{href:"mailto:kristen.stone@pathfindersrobotics.org"},"Contact"),r.a.createElement("br",null)))),r.a.createElement(F.a,{md:"auto",className:"sidenav"},this.state.width>775?mt:null,r.a.createElement(k.a,{variant:"primary",href:"#FTCPathfinders13497",className:"backToTop"},"Back to top")))))}}]),t}(n.Component),ht=a(139),pt=a.n(ht),ft=function(e){function t(e){var a;return Object(s.a)(this,t),(a=Object(c.a)(this,Object(u.a)(t).call(this,e))).state={width:window.innerWidth,selected:"What Does Creating A Team Entail?"},a.updateWindowWidth=a.updateWindowWidth.bind(Object(I.a)(a)),a}return Object(m.a)(t,e),Object(l.a)(t,[{key:"componentWillMount",value:function(){Object(D.configureAnchors)({offset:-90}),window.addEventListener("resize",this.updateWindowWidth)}},{key:"componentDidMount",value:function(){N.a.pageview(window.location.pathname),this.updateWindowWidth()}},{key:"componentWillUnmount",value:function(){window.removeEventListener("resize",this.updateWindowWidth)}},{key:"updateWindowWidth",value:function(){this.setState({width:window.innerWidth}),this.state.width<775?Object(D.configureAnchors)({offset:-190}):Object(D.configureAnchors)({offset:-90})}},{key:"render",value:function(){return r.a.createElement("div",{id:"create",className:"centerThis"},r.a.createElement(b,{src:pt.a,alt:"Under Construction",responsive:"true"}))}}]),t}(n.Component),Et=a(79),bt=a.n(Et),At=a(40),gt=a(217),yt=a(212),vt=a(140),wt=a(18),Tt=a(141),St=a(215),Ct=/^(?:A[KLRSZ]|C[AOT]|D[CE]|FL|G[AU]|HI|I[ADLN]|K[SY]|LA|M[ADEINOPST]|N[CDEHJMVY]|O[HKR]|P[AR]|RI|S[CD]|T[NX]|UT|V[AIT]|W[AIVY])*$/i,Ot=/^(?:ALABAMA|ALASKA|ARIZONA|ARKANSAS|CALIFORNIA|COLORADO|CONNECTICUT|DELAWARE|FLORIDA|GEORGIA|HAWAII|IDAHO|ILLINOIS|INDIANA|IOWA|KANSAS|KENTUCKY|LOUISIANA|MAINE|MARYLAND|MASSACHUSETTS|MICHIGAN|MINNESOTA|MISSISSIPPI|MISSOURI|MONTANA|NEBRASKA|NEVADA|NEW\sHAMPSHIRE|NEW\sJERSEY|NEW\sMEXICO|NEW\sYORK|NORTH\sCAROLINA|NORTH\sDAKOTA|OHIO|OKLAHOMA|OREGON|PENNSYLVANIA|RHODE\sISLAND|SOUTH\sCAROLINA|SOUTH\sDAKOTA|TENNESSEE|TEXAS|UTAH|VERMONT|VIRGINIA|WASHINGTON|WEST\sVIRGINIA|WASHINGTON\sDC|WASHINGTON\sD\.C\.|DISTRICT\sOF\sCOLUMBIA|AMERICAN\sSAMOA|SAMOA|GUAM|NORTHERN\sMARIANA\sISLANDS|NORTHERN\sMARIANA|MARIANA\sISLANDS|MARIANA|PUERTO\sRICO|VIRGIN\sISLANDS|VIRGIN)/i,Nt=/^([0-9]{5}(?:-[0-9]{4})?)*$/,It=/^[^\s@]+@[^\s@]+\.[^\s@]+$/i,jt=/(\+1\s\([0-9]{3}\)\s[0-9]{3}-[0-9]{4})/,Pt=["+","1"," ","(",/[1-9]/,/\d/,/\d/,")"," ",/\d/,/\d/,/\d/,"-",/\d/,/\d/,/\d/,/\d/],Ft="Please enter a valid ",Rt=".";function kt(e){if(null!=e){var t=Ft+e+Rt;return{required:t,pattern:t,type:t,step:t,minLength:t,min:t,max:t,fileType:t,maxFileSize:t,validator:t}}return null}var Dt=kt("name"),Lt=kt("address"),Mt=kt("city"),Wt=kt("state"),xt=kt("zip / postal code"),Vt=kt("email"),Ht=kt("phone number"),Bt=function(e){function t(e){var a;return Object(s.a)(this,t),(a=Object(c.a)(this,Object(u.a)(t).call(this,e))).state={name:"",addr1:"",addr2:"",city:"",state:"",zip:"",email:"",phone:"",contactAllowed:!0,formValid:!1},a.onSubmit=a.onSubmit.bind(Object(I.a)(a)),a.onErrorSubmit=a.onErrorSubmit.bind(Object(I.a)(a)),a.updateTabs=a.updateTabs.bind(Object(I.a)(a)),a.updateVal=a.updateVal.bind(Object(I.a)(a)),a.updateState=a.updateState.bind(Object(I.a)(a)),a.updatePhone=a.updatePhone.bind(Object(I.a)(a)),a.twoLetterStateFromFullState=a.twoLetterStateFromFullState.bind(Object(I.a)(a)),a}return Object(m.a)(t,e),Object(l.a)(t,[{key:"onSubmit",value:function(e,t){e.preventDefault(),e.stopPropagation(),this.setState({formValid:!0},this.updateTabs)}},{key:"onErrorSubmit",value:function(e,t){e.preventDefault(),e.stopPropagation(),this.setState({formValid:!1},this.updateTabs)}},{key:"updateTabs",value:function(){var e=this;this.state.formValid?this.props.enablePayment((function(){return e.props.setData({name:e.state.name,addr1:e.state.addr1,addr2:e.state.addr2,city:e.state.city,state:e.state.state.length>2?e.twoLetterStateFromFullState(e.state.state):e.state.state,zip:e.state.zip,email:e.state.email,phone:e.state.phone,amount:e.props.getData().amount},e.props.advanceToFront)})):this.props.disablePayment()}},{key:"updateVal",value:function(e){this.setState(Object(vt.a)({},e.target.name,e.target.value))}},{key:"updateState",value:function(e){this.setState({state:e.target.value.replace(/[^a-zA-Z.]+/g,"")})}},{key:"updatePhone",value:function(e){this.setState({phone:Object(Tt.conformToMask)(e.target.value,Pt,{guide:!1}).conformedValue})}},{key:"render",value:function(){var e=this;return r.a.createElement(wt.ValidationForm,{onSubmit:this.onSubmit,onErrorSubmit:this.onErrorSubmit,immediate:!1},r.a.createElement(St.a.Row,null,r.a.createElement("div",{className:"form-group"},r.a.createElement("label",{htmlFor:"name"},"Name"),r.a.createElement(wt.TextInput,{required:!0,id:"name",name:"name",type:"text",placeholder:"John Doe",errorMessage:Dt,value:this.state.name,onChange:this.updateVal}))),r.a.createElement(St.a.Row,null,r.a.createElement("div",{className:"form-group"},r.a.createElement("label",{htmlFor:"addr1"},"Address Line 1"),r.a.createElement(wt.TextInput,{required:!0,id:"addr1",name:"addr1",type:"text",placeholder:"12345 Artificial Dr",errorMessage:Lt,value:this.state.addr1,onChange:this.updateVal})),"\xa0",r.a.createElement("div",{className:"form-group"},r.a.createElement("label",{htmlFor:"addr2"},"Address Line 2"),r.a.createElement(wt.TextInput,{id:"addr2",name:"addr2",type:"text",placeholder:"Apt. 27",errorMessage:Lt,value:this.state.addr2,onChange:this.updateVal}))),r.a.createElement(St.a.Row,null,r.a.createElement("div",{className:"form-group"},r.a.createElement("label",{htmlFor:"city"},"City"),r.a.createElement(wt.TextInput,{required:!0,id:"city",name:"city",type:"text",placeholder:"City",errorMessage:Mt,value:this.state.city,onChange:this.updateVal})),"\xa0",r.a.createElement("div",{className:"form-group"},r.a.createElement("label",{htmlFor:"state"},"State"),r.a.createElement(wt.TextInput,{required:!0,validator:function(e){return Ct.test(e)||Ot.test(e)},id:"state",name:"state",type:"text",placeholder:"State",maxLength:"25",errorMessage:Wt,value:this.state.state,onChange:this.updateState})),"\xa0",r.a.createElement("div",{className:"form-group"},r.a.createElement("label",{htmlFor:"zip"},"Zip / Postal Code"),r.a.createElement(wt.TextInput,{required:!0,validator:function(e){return Nt.test(e)},id:"zip",name:"zip",type:"text",placeholder:"12345",maxLength:"5",errorMessage:xt,value:this.state.zip,onChange:function(t){return e.setState({zip:isNaN(t.target.value)?e.state.zip:t.target.value})}}))),r.a.createElement(St.a.Row,null,r.a.createElement("div",{className:"form-group"},r.a.createElement("label",{htmlFor:"email"},"Email"),r.a.createElement(wt.TextInput,{required:!0,validator:function(e){return It.test(e)},id:"email",name:"email",type:"email",placeholder:"johndoe@domain.tld",errorMessage:Vt,value:this.state.email,onChange:this.updateVal})),"\xa0",r.a.createElement("div",{className:"form-group"},r.a.createElement("label",{htmlFor:"phone"},"Phone Number"),r.a.createElement(wt.TextInput,{required:!0,validator:function(e){return jt.test(e)},id:"phone",name:"phone",type:"tel",placeholder:"+1 (123) 456-7890",errorMessage:Ht,value:this.state.phone,onChange:this.updatePhone}))),r.a.createElement(St.a.Row,null,r.a.createElement("div",{className:"form-group"},r.a.createElement(wt.Checkbox,{id:"contactAllowed",name:"contactAllowed",type:"checkbox",value:this.state.contactAllowed,onChange:function(t,a){e.setState({contactAllowed:a})},label:"Receive updates from donation recipients throughout the season"}))),r.a.createElement(St.a.Row,null,r.a.createElement("div",null,r.a.createElement(k.a,{type:"submit"},"Submit"))))}},{key:"twoLetterStateFromFullState",value:function(e){switch(e.toUpperCase()){case"ALABAMA":return"AL";case"ALASKA":return"AK";case"ARIZONA":return"AZ";case"ARKANSAS":return"AK";case"CALIFORNIA":return"CA";case"COLORADO":return"CO";case"CONNECTICUT":return"CT";case"DELAWARE":return"DE";case"FLORIDA":return"FL";case"GEORGIA":return"GA";case"HAWAII":return"HI";case"IDAHO":return"ID";case"ILLINOIS":return"IL";case"INDIANA":return"IN";case"IOWA":return"IA";case"KANSAS":return"KS";case"KENTUCKY":return"KY";case"LOUISIANA":return"LA";case"MAINE":return"ME";case"MARYLAND":return"MD";case"MASSACHUSETTS":return"MA";case"MICHIGAN":return"MI";case"MINNESOTA":return"MN";case"MISSISSIPPI":return"MS";case"MISSOURI":return"MO";case"MONTANA":return"MT";case"NEBRASKA":return"NE";case"NEVADA":return"NV";case"NEW HAMPSHIRE":return"NH";case"NEW JERSEY":return"NJ";case"NEW MEXICO":return"NM";case"NEW YORK":return"NY";case"NORTH CAROLINA":return"NC";case"NORTH DAKOTA":return"ND";case"OHIO":return"OH";case"OKLAHOMA":return"OK";case"OREGON":return"OR";case"PENNSYLVANIA":return"PA";case"RHODE ISLAND":return"RI";case"SOUTH CAROLINA":return"SC";case"SOUTH DAKOTA":return"SD";case"TENNESSEE":return"TN";case"TEXAS":return"TX";case"UTAH":return"UT";case"VERMONT":return"VT";case"VIRGINIA":return"VA";case"WASHINGTON":return"WA";case"WEST VIRGINIA":return"WV";case"WISCONSIN":return"WI";case"WYOMING":return"WY";case"WASHINGTON DC":case"WASHINGTON D.C.":case"DISTRICT OF COLUMBIA":return"DC";case"AMERICAN SAMOA":case"SAMOA":return"AS";case"GUAM":return"GU";case"NORTHERN MARIANA ISLANDS":case"NORTHERN MARIANA":case"MARIANA ISLANDS":case"MARIANA":return"MP";case"PUERTO RICO":return"PR";case"VIRGIN ISLANDS":case"VIRGIN":return"VI";default:return null}}}]),t}(n.Component),zt=a(37),Qt=a.n(zt),qt=a(54),Ut=a(210),Yt=a(211),Gt=a(32),Kt=a(214),Xt=a(88);n.Component,n.Component;function Jt(){return(Jt=Object(qt.a)(Qt.a.mark((function e(t){var a;return Qt.a.wrap((function(e){for(;;)switch(e.prev=e.next){case 0:if(!navigator.onLine){e.next=7;break}if((a=new XMLHttpRequest).open("POST","/getSecret",!1),a.setRequestHeader("Content-Type","application/json"),a.send(JSON.stringify(t)),200!==a.status){e.next=7;break}return e.abrupt("return",JSON.parse(a.responseText).secret);case 7:return e.abrupt("return",null);case 8:case"end":return e.stop()}}),e)})))).apply(this,arguments)}function Zt(){return(Zt=Object(qt.a)(Qt.a.mark((function e(t,a){var n,r,o;return Qt.a.wrap((function(e){for(;;)switch(e.prev=e.next){case 0:if(!navigator.onLine){e.next=15;break}if((n=new XMLHttpRequest).open("POST","/paymentRequest",!1),n.setRequestHeader("Content-Type","application/json"),r={},Object.assign(r,a),Object.assign(r,{token:t.id}),n.send(JSON.stringify(r)),200!==n.status){e.next=15;break}if(null===(o=JSON.parse(n.responseText))||void 0===o){e.next=14;break}return e.abrupt("return",!0===o.success);case 14:return e.abrupt("return",!1);case 15:return e.abrupt("return",null);case 16:case"end":return e.stop()}}),e)})))).apply(this,arguments)}function _t(){return(_t=Object(qt.a)(Qt.a.mark((function e(t){var a;return Qt.a.wrap((function(e){for(;;)switch(e.prev=e.next){case 0:(a=new XMLHttpRequest).open("POST","/paymentEmail",!0),a.setRequestHeader("Content-Type","application/json"),a.send(JSON.stringify(t));case 4:case"end":return e.stop()}}),e)})))).apply(this,arguments)}var $t=function(e){function t(){return Object(s.a)(this,t),Object(c.a)(this,Object(u.a)(t).apply(this,arguments))}return Object(m.a)(t,e),Object(l.a)(t,[{key:"render",value:function(){return r.a.createElement("svg",{className:"checkmark",xmlns:"https://www.w3.org/2000/svg",viewBox:"0 0 52 52"},r.a.createElement("circle",{className:"checkmark__circle",cx:"26",cy:"26",r:"25",fill:"none"}),r.a.createElement("path",{className:"checkmark__check",fill:"none",d:"M14.1 27.2l7.1 7.2 16.7-16.8"}))}}]),t}(n.Component),ea=/^[0-9]+(\.[0-9]{1,2})?$/,ta="FTC Pathfinders 13497",aa=function(e){function t(e){var a;return Object(s.a)(this,t),(a=Object(c.a)(this,Object(u.a)(t).call(this,e))).state={amount:"",radioAmount:"",amountValid:!1,team:ta,donateInfo:r.a.createElement(ct,null),teamValid:!0,paymentType:"Payment Type",paymentTypeValid:!1,formValid:!1,showConfirmation:!1,paymentInProgress:!1,dots:".",payDisabled:!1,paid:!1,canMakePayment:null,cardElement:null,cardReady:!1,redirect:!1},a.props.setTiers(r.a.createElement(ut,null)),a.onSubmit=a.onSubmit.bind(Object(I.a)(a)),a.onErrorSubmit=a.onErrorSubmit.bind(Object(I.a)(a)),a.updateAmount=a.updateAmount.bind(Object(I.a)(a)),a.updateRadioChoice=a.updateRadioChoice.bind(Object(I.a)(a)),a.pay=a.pay.bind(Object(I.a)(a)),a.updateDots=a.updateDots.bind(Object(I.a)(a)),a.getButton=a.getButton.bind(Object(I.a)(a)),a.genPaymentRequest=a.genPaymentRequest.bind(Object(I.a)(a)),a}return Object(m.a)(t,e),Object(l.a)(t,[{key:"componentDidMount",value:function(){N.a.pageview(window.location.pathname+"/PaymentForm")}}]),Object(l.a)(t,[{key:"onSubmit",value:function(e,t){e.preventDefault(),e.stopPropagation(),this.setState({amountValid:!0,formValid:this.state.teamValid&&this.state.paymentTypeValid})}},{key:"onErrorSubmit",value:function(e,t){e.preventDefault(),e.stopPropagation(),this.setState({amountValid:!1,formValid:!1})}},{key:"updateAmount",value:function(e){var t=e.target.value.replace(/[^0-9.]+/g,""),a=parseFloat(t),n=ea.test(t)&&a>=.5&&a<=999999.99,r=this.state.radioAmount;"50"!==t&&"50.0"!==t&&"50.00"!==t&&"100"!==t&&"100.0"!==t&&"100.00"!==t&&"250"!==t&&"250.0"!==t&&"250.00"!==t&&"500"!==t&&"500.0"!==t&&"500.00"!==t&&"1000"!==t&&"1000.0"!==t&&"1000.00"!==t&&"5000"!==t&&"5000.0"!==t&&"5000.00"!==t&&""!==t&&(r="Custom Amount"),this.setState({amount:t,radioAmount:r,amountValid:n,formValid:!!n&&(this.state.teamValid&&this.state.paymentTypeValid)})}},{key:"updateRadioChoice",value:function(e){var t=this,a=this.state.amount.replace(/[^0-9.]+/g,""),n=parseFloat(a),r=ea.test(a)&&n>=.5&&n<=999999.99;this.setState({amount:"Custom Amount"!==e.target.value?e.target.value:this.state.amount,radioAmount:e.target.value,amountValid:r,formValid:r&&this.state.teamValid&&this.state.paymentTypeValid},(function(){return t.updateAmount({target:{value:t.state.radioAmount}})}))}},{key:"pay",value:function(e){var t=this;if(e.preventDefault(),e.stopPropagation(),this.state.formValid){var a=this.props.getData(),n={amount:parseFloat(100*this.state.amount),description:"Gracious donation of ".concat(this.state.amount," by ").concat(this.state.paymentType," to ").concat(this.state.team,"."),name:a.name,addr1:a.addr1,addr2:a.addr2,city:a.city,state:a.state,zip:a.zip,email:a.email,phone:a.phone};(function(e){return Jt.apply(this,arguments)})(n).then((function(e){null!==e&&void 0!==e&&t.state.cardReady?t.props.stripe.handleCardPayment(e,t.state.cardElement,{payment_method_data:{billing_details:{address:{city:a.city,line1:a.addr1,line2:a.addr2,postal_code:a.zip,state:a.state},email:a.email,name:a.name,phone:a.phone}},receipt_email:a.email}).then((function(e){e.error?t.setState({showConfirmation:!1,payDisabled:!1,paymentInProgress:!1,paid:!1,error:"There's an issue with your payment. Please ensure your information is correct, try another payment method, or refresh the page and try again."}):(!function(e){_t.apply(this,arguments)}(n),t.setState({paymentInProgress:!1,paid:!0},(function(){return window.setTimeout((function(){return t.setState({redirect:!0})}),2500)})))})):t.setState({showConfirmation:!1,payDisabled:!1,paymentInProgress:!1,paid:!1,error:"There's an issue with your payment. Please ensure your information is correct, try another payment method, or refresh the page and try again."})}))}}},{key:"updateDots",value:function(){var e=Object(qt.a)(Qt.a.mark((function e(){return Qt.a.wrap((function(e){for(;;)switch(e.prev=e.next){case 0:if(!this.state.paymentInProgress){e.next=11;break}e.t0=this.state.dots.length,e.next=1===e.t0?4:2===e.t0?6:8;break;case 4:return this.setState({dots:".."}),e.abrupt("break",10);case 6:return this.setState({dots:"..."}),e.abrupt("break",10);case 8:return this.setState({dots:"."}),e.abrupt("break",10);case 10:window.setTimeout(this.updateDots,750);case 11:case"end":return e.stop()}}),e,this)})));return function(){return e.apply(this,arguments)}}()},{key:"genPaymentRequest",value:function(){var e=this,t=this.props.stripe.paymentRequest({country:"US",currency:"usd",total:{label:"Donation to "+this.state.team,amount:100*parseFloat(this.state.amount)}});return t.on("token",(function(t){var a=t.complete,n=t.token,r=e.props.getData();(function(e,t){return Zt.apply(this,arguments)})(n,{amount:parseFloat(100*e.state.amount),description:"Gracious donation of ".concat(e.state.amount," by ").concat(e.state.paymentType," to ").concat(e.state.team,"."),name:r.name,addr1:r.addr1,addr2:r.addr2,city:r.city,state:r.state,zip:r.zip,email:r.email,phone:r.phone}).then((function(e){a(!0!==e?"Payment unsuccessful. Please refresh the page, try another method, or try again later.":"Thank you for your donation!")}))})),t.canMakePayment().then((function(t){e.setState({canMakePayment:!!t})})),t}},{key:"getButton",value:function(){var e,t,a=this;"Credit / Debit"===this.state.paymentType?(t=this.state.paymentInProgress?r.a.createElement("div",null,r.a.createElement(E.a,{as:"span",animation:"grow",size:"sm",role:"status","aria-hidden":"true"}),"\xa0 Donation in progress",this.state.dots):"Make Donation",e=r.a.createElement(k.a,{onClick:function(e){a.setState({paymentInProgress:!0,payDisabled:!0},(function(){return window.setTimeout(a.updateDots,750)})),a.pay(e)},variant:"outline-success",disabled:this.state.payDisabled},t)):e=null===this.state.canMakePayment?r.a.createElement(E.a,{animation:"border",variant:"primary",role:"status"}):this.state.canMakePayment?r.a.createElement(At.PaymentRequestButtonElement,{paymentRequest:this.state.paymentRequest,className:"PaymentRequestButton paymentRequestButtonWidth",style:{paymentRequestButton:{theme:"dark",type:"donate",height:"38px"}}}):r.a.createElement("p",{className:"redText verticallyCenterThis alignThisRight"},"This payment method is unavailable.");return e}},{key:"render",value:function(){var e=this;return r.a.createElement("div",null,this.state.redirect?r.a.createElement(Xt.a,{push:!0,to:"/"}):null,r.a.createElement(wt.ValidationForm,{onSubmit:this.onSubmit,onErrorSubmit:this.onErrorSubmit,immediate:!1},r.a.createElement(St.a.Row,null,r.a.createElement("div",{className:"form-group"},r.a.createElement("label",{htmlFor:"amount"},"Choose Your Amount (USD)"),r.a.createElement(wt.Radio.RadioGroup,{name:"amount",required:!0,errorMsg:"Please enter a valid amount.",valueSelected:this.state.radioAmount,onChange:this.updateRadioChoice,inline:!1},r.a.createElement(wt.Radio.RadioItem,{id:"50",label:"$50",value:"50"}),r.a.createElement(wt.Radio.RadioItem,{id:"100",label:"$100",value:"100"}),r.a.createElement(wt.Radio.RadioItem,{id:"250",label:"$250",value:"250"}),r.a.createElement(wt.Radio.RadioItem,{id:"500",label:"$500",value:"500"}),r.a.createElement(wt.Radio.RadioItem,{id:"1000",label:"$1000",value:"1000"}),r.a.createElement(wt.Radio.RadioItem,{id:"5000",label:"$5000",value:"5000"}),r.a.createElement(wt.Radio.RadioItem,{id:"custom",label:"Custom",value:"Custom Amount"})),r.a.createElement(wt.TextInputGroup,{required:"Custom Amount"===this.state.radioAmount,validator:"Custom Amount"===this.state.radioAmount?function(){return!0}:function(e){return ea.test(e)&&parseFloat(e)>=.5&&parseFloat(e)<=999999.99},id:"amount",name:"amount",type:"text",placeholder:"12.34",prepend:r.a.createElement("span",{className:"input-group-text"},"$"),errorMessage:kt("amount"),value:this.state.amount,onChange:this.updateAmount})))),r.a.createElement(Ut.a,{className:"mr-2"},r.a.createElement(Yt.a,{id:"paymentType",title:this.state.paymentType},r.a.createElement(Gt.a.Item,{onSelect:function(){return e.setState({paymentType:"Credit / Debit",paymentTypeValid:!0,formValid:e.state.amountValid&&e.state.teamValid})}},"Credit / Debit"),r.a.createElement(Gt.a.Item,{onSelect:function(){return e.setState({paymentType:"Apple Pay / Google Pay / Microsoft Pay",paymentTypeValid:!0,formValid:e.state.amountValid&&e.state.teamValid})}},"Apple / Google Pay"))),r.a.createElement("br",null),r.a.createElement("br",null),this.state.formValid&&"Credit / Debit"===this.state.paymentType?r.a.createElement(At.CardElement,{onReady:function(t){return e.setState({cardElement:t,cardReady:!0})}}):null,r.a.createElement("p",{className:"redText"},"***We do not accept American Express cards. We apologize for any inconvenience.***"),this.state.error?r.a.createElement("p",{className:"redText"},this.state.error):null,r.a.createElement("br",null),this.state.donateInfo,r.a.createElement(k.a,{type:"submit",disabled:!this.state.formValid,onClick:"Apple Pay / Google Pay / Microsoft Pay"!==this.state.paymentType?function(){return e.setState({showConfirmation:!0})}:function(){return e.setState({showConfirmation:!0,paymentRequest:e.genPaymentRequest()})}},"Make donation"),r.a.createElement(Kt.a,{show:this.state.showConfirmation,"aria-labelledby":"contained-modal-title-vcenter",centered:!0,scrollable:!0,keyboard:!1,backdrop:"static"},r.a.createElement(Kt.a.Header,null,r.a.createElement(Kt.a.Title,{id:"contained-modal-title-vcenter"},r.a.createElement(b,{src:y.a,width:"30",height:"30",className:"d-inline-block",alt:"Pathfinders Robotics Logo",text:"Payment Confirmation",helvetica:"true"}))),r.a.createElement(Kt.a.Body,null,this.state.paid?r.a.createElement("div",null,r.a.createElement($t,null),r.a.createElement("p",null,'This donation is tax deductible, and you can claim this deduction via our nonprofit, Pathfinders Robotics, with EIN "83-3047012".')):r.a.createElement("div",null,r.a.createElement("h4",null,"Please confirm your gracious donation of $",parseFloat(this.state.amount).toLocaleString()," by ","Credit / Debit"===this.state.paymentType.toLowerCase()?this.state.paymentType.toLocaleLowerCase():this.state.paymentType," to the ",this.state.team),r.a.createElement("p",null,"Pathfinders Robotics thanks you for your donation."))),r.a.createElement(Kt.a.Footer,null,r.a.createElement(k.a,{onClick:function(){return e.setState({showConfirmation:!1,payDisabled:!1})},variant:"outline-danger",disabled:this.state.paymentInProgress},"Cancel"),this.getButton())))}}]),t}(n.Component),na=Object(At.injectStripe)(aa),ra="information",oa=function(e){function t(e){var a;return Object(s.a)(this,t),(a=Object(c.a)(this,Object(u.a)(t).call(this,e))).state={activeKey:ra,paymentDisabled:!0,publicData:{name:"",addr1:"",addr2:"",city:"",state:"",zip:"",email:"",phone:"",amount:""}},a.onSelect=a.onSelect.bind(Object(I.a)(a)),a.advanceToFront=a.advanceToFront.bind(Object(I.a)(a)),a.disablePayment=a.disablePayment.bind(Object(I.a)(a)),a.enablePayment=a.enablePayment.bind(Object(I.a)(a)),a.getData=a.getData.bind(Object(I.a)(a)),a.setData=a.setData.bind(Object(I.a)(a)),a}return Object(m.a)(t,e),Object(l.a)(t,[{key:"onSelect",value:function(e){this.setState({activeKey:e,paymentDisabled:!!this.state.paymentDisabled||"payment"===this.state.activeKey&&e===ra})}},{key:"advanceToFront",value:function(){this.state.paymentDisabled?this.onSelect(ra):this.onSelect("payment")}},{key:"render",value:function(){return r.a.createElement(gt.a,{activeKey:this.state.activeKey,onSelect:this.onSelect},r.a.createElement(yt.a,{eventKey:ra,title:"Contact Information"},r.a.createElement("br",null),r.a.createElement(Bt,{disablePayment:this.disablePayment,enablePayment:this.enablePayment,getData:this.getData,setData:this.setData,advanceToFront:this.advanceToFront})),r.a.createElement(yt.a,{eventKey:"payment",title:"Payment",disabled:this.state.paymentDisabled},r.a.createElement("br",null),r.a.createElement(na,{getData:this.getData,setData:this.setData,advanceToFront:this.advanceToFront,setTiers:this.props.setTiers})))}},{key:"disablePayment",value:function(e){"function"!==typeof e?this.setState({paymentDisabled:!0}):this.setState({paymentDisabled:!0},e)}},{key:"enablePayment",value:function(e){"function"!==typeof e?this.setState({paymentDisabled:!1}):this.setState({paymentDisabled:!1},e)}},{key:"getData",value:function(){return this.state.publicData}},{key:"setData",value:function(e,t){"function"!==typeof t?this.setState({publicData:e}):this.setState({publicData:e},t)}}]),t}(n.Component),ia=Object(At.injectStripe)(oa),sa=function(e){function t(e){var a;return Object(s.a)(this,t),(a=Object(c.a)(this,Object(u.a)(t).call(this,e))).state={stripe:null},a}return Object(m.a)(t,e),Object(l.a)(t,[{key:"componentDidMount",value:function(){var e=this;window.Stripe?this.setState({stripe:window.Stripe("pk_live_y4v0gH9Y5JTSxRGZfC0PLYg100Y01489iZ")}):document.querySelector("#stripe-js").addEventListener("load",(function(){e.setState({stripe:window.Stripe("pk_live_y4v0gH9Y5JTSxRGZfC0PLYg100Y01489iZ")})}))}},{key:"render",value:function(){return r.a.createElement(At.StripeProvider,{stripe:this.state.stripe},r.a.createElement(At.Elements,null,r.a.createElement(ia,{setTiers:this.props.setTiers})))}}]),t}(n.Component),la="Pathfinders Robotics is a 501(c)3 nonprofit organization and shall operate exclusively for education and charitable purposes within the meaning of Section 501 (c)(3) of the Internal Revenue Code, or the corresponding section of any future Federal tax code. Pathfinders Robotics\u2019s purpose is to develop and teach youth in grades five through twelve Science, Technology, Engineering, and Math skills (STEM) and to develop teams to compete in FIRST Tech Challenge or other STEM related events in Iowa, Regional, National and, International competitions. Our purpose is to provide volunteer and fund-raising opportunities, per the discretion of the board of directors where the teams present and demonstrate STEM activities with other youth in order to have a greater impact for change. To maximize our impact on current efforts, we may seek to collaborate with other non-profit organizations which fall under the 501(c) (3) section of the internal revenue code and are operated exclusively for educational and charitable purposes.",ca="If your company also does charitable gift matching, please consider submitting your donation request to your organization as well. Our 501(c)3 EIN is 83-3047012 and our address is 390 SE Carefree Ln Waukee IA 50263 United States. Please contact Mr. Bhooshan Karnik at (515) 451-7270 for any additional information needed.",ua=function(e){function t(e){var a;return Object(s.a)(this,t),(a=Object(c.a)(this,Object(u.a)(t).call(this,e))).state={sponsorshipTiers:null},a.setSponsorshipTiers=a.setSponsorshipTiers.bind(Object(I.a)(a)),a}return Object(m.a)(t,e),Object(l.a)(t,[{key:"componentDidMount",value:function(){N.a.pageview(window.location.pathname)}},{key:"setSponsorshipTiers",value:function(e){this.setState({sponsorshipTiers:e})}},{key:"render",value:function(){return r.a.createElement("div",{id:"donate"},r.a.createElement(M.Helmet,null,r.a.createElement("title",null,"Donate | Pathfinders Robotics"),r.a.createElement("meta",{name:"description",content:"Pathfinders Robotics is a 501(c)3 nonprofit organization and shall operate exclusively for education and charitable purposes within the meaning of Secti..."})),r.a.createElement(j.a,{fluid:!0},r.a.createElement(bt.a,{minDeviceWidth:1224},r.a.createElement(P.a,null,r.a.createElement(F.a,null,r.a.createElement("h1",null,"Donate"),r.a.createElement("p",null,la),r.a.createElement("p",null,r.a.createElement("b",null,ca)),r.a.createElement("br",null),this.state.sponsorshipTiers),r.a.createElement(F.a,null,r.a.createElement(sa,{setTiers:this.setSponsorshipTiers}),r.a.createElement("br",null)))),r.a.createElement(bt.a,{maxDeviceWidth:1224},r.a.createElement(P.a,null,r.a.createElement("h1",null,"Donate"),r.a.createElement("p",null,la),r.a.createElement("p",null,r.a.createElement("b",null,ca)),r.a.createElement("br",null),this.state.sponsorshipTiers,r.a.createElement("br",null)),r.a.createElement(P.a,null,r.a.createElement(sa,{setTiers:this.setSponsorshipTiers}),r.a.createElement("br",null),r.a.createElement("br",null)))))}}]),t}(n.Component),ma=a(143),da=a.n(ma),ha=a(144),pa=a.n(ha),fa=a(145),Ea=a.n(fa),ba=a(146),Aa=a.n(ba),ga=a(147),ya=a.n(ga),va=a(148),wa=a.n(va),Ta=a(149),Sa=a.n(Ta),Ca=a(150),Oa=a.n(Ca),Na=a(151),Ia=a.n(Na),ja=a(152),Pa=a.n(ja),Fa=a(153),Ra=a.n(Fa),ka=a(154),Da=a.n(ka),La=a(155),Ma=a.n(La),Wa=r.a.createElement(p.a,{variant:"pills",className:"flex-column",defaultActiveKey:"#WhoWeAre"},r.a.createElement(p.a.Item,null,r.a.createElement(p.a.Link,{href:"#WhoWeAre"},"Who We Are")),r.a.createElement(p.a.Item,null,r.a.createElement(p.a.Link,{href:"#OurMission"},"Our Mission")),r.a.createElement(p.a.Item,null,r.a.createElement(p.a.Link,{href:"#Impact"},"Impact"))),xa=function(e){function t(e){var a;return Object(s.a)(this,t),(a=Object(c.a)(this,Object(u.a)(t).call(this,e))).state={width:window.innerWidth,selected:"Who We Are"},a.updateWindowWidth=a.updateWindowWidth.bind(Object(I.a)(a)),a}return Object(m.a)(t,e),Object(l.a)(t,[{key:"componentWillMount",value:function(){Object(D.configureAnchors)({offset:-90}),window.addEventListener("resize",this.updateWindowWidth)}},{key:"componentDidMount",value:function(){N.a.pageview(window.location.pathname),this.updateWindowWidth()}},{key:"componentWillUnmount",value:function(){window.removeEventListener("resize",this.updateWindowWidth)}},{key:"updateWindowWidth",value:function(){this.setState({width:window.innerWidth}),this.state.width<775?Object(D.configureAnchors)({offset:-190}):Object(D.configureAnchors)({offset:-90})}},{key:"render",value:function(){var e=this;return r.a.createElement("div",{id:"home"},r.a.createElement(M.Helmet,null,r.a.createElement("title",null,"Home | Pathfinders Robotics"),r.a.createElement("meta",{name:"description",content:"Pathfinders Robotics is a parent-run 501(c)3 nonprofit organization dedicated to providing support for student-led FIRST robotics teams."})),r.a.createElement(j.a,{fluid:!0},r.a.createElement(P.a,null,r.a.createElement(F.a,{className:"teamArticle standardFont"},r.a.createElement(L.a,{id:"WhoWeAre"},r.a.createElement("div",null,this.state.width<775?r.a.createElement("div",null,r.a.createElement("div",{className:"mobileBar"},r.a.createElement(p.a,{variant:"pills",className:"flex-column frozen"},r.a.createElement(f.a,{title:this.state.selected},r.a.createElement(f.a.Item,{href:"#WhoWeAre",onClick:function(){return e.setState({selected:"Who We Are"})}},"Who We Are"),r.a.createElement(f.a.Item,{href:"#OurMission",onClick:function(){return e.setState({selected:"Our Mission"})}},"Our Mission"),r.a.createElement(f.a.Item,{href:"#Impact",onClick:function(){return e.setState({selected:"Impact"})}},"Impact")))),r.a.createElement("br",null),r.a.createElement("br",null)):null,r.a.createElement("h1",null,"Who We Are"),r.a.createElement(b,{src:da.a,alt:"FTC Pathfinders 2018-2019",responsive:"true"}),r.a.createElement("p",null,"Originally founded in 2017 as a FIRST Tech Challenge Robotics Team, our team began to run a non profit organization as of March eighth of 2019. Pathfinders Robotics is a 501(c)3 nonprofit organization dedicated to providing support for FIRST robotics teams in our local area. We assist in the education of gracious professionalism, programming, and financial recommendations for other competing teams. We enjoy collaborating with both professionals and students alike to discover new and innovative solutions to solve challenges. In addition to the aforementioned collaborative exercises, our team enjoys sharing the knowledge we have accumulated over the years with our other FLL teams and FTC colleagues."))),r.a.createElement(L.a,{id:"OurMission"},r.a.createElement("div",null,r.a.createElement("h1",null,"Our Mission"),r.a.createElement(b,{src:pa.a,alt:"Pathfinders Robotics Mission",responsive:"true"}),r.a.createElement("p",null,"Pathfinders Robotics\u2019 mission is to develop and educate the youth in grades five through twelve. We teach information pertaining to Science, Technology, Engineering, and Math (STEM). Through guidance and feedback we assist in the development of teams to compete in FIRST Tech Challenge, First Lego League, or other STEM-related events in Iowa, Regional, National and International competitions.  We prioritize the spread of STEM,  FIRST, and general robotics, usually directed at a younger audience. We embody gracious professionalism through our interactions with our competitors, financial partners, and professional associates. Gracious professionalism is important to our organization as a whole as it personifies meaningful interactions that create lasting bonds in the STEM field."),r.a.createElement("p",null,"At Pathfinders Robotics, our mission is to support student-led FIRST teams as best we can through the use of our student mentors, web resources, gracious sponsors, and 501(c)3 nonprofit capabilities."))),r.a.createElement(L.a,{id:"Impact"},r.a.createElement("div",null,r.a.createElement("h1",null,"Impact"),r.a.createElement("p",null,"Our community newspaper reached out to us for an interview regarding our involvement in FTC, our impact on the community, and what we do as an organization. We were featured on the front cover and given four pages in the article. You can find the article ",r.a.createElement("a",{href:"https://mywaukee.com/tech-teens/",target:"_blank",rel:"noopener"},"here"),"."),r.a.createElement("br",null),r.a.createElement(b,{src:Ea.a,alt:"We were invited by a Cub Scout Troop to present about robotics to help them obtain a merit badge. We talked about FTC, FLL, FIRST, and how they all impact the community.",responsive:"true"}),r.a.createElement("p",null,"We were invited by a Cub Scout Troop to present about robotics to help them obtain a merit badge. We talked about FTC, FLL, FIRST, and how they all impact the community."),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:Aa.a,alt:"Following our Cub Scout outreach, we presented robotics and FIRST to the NUMBER Girl Scout troop. They enjoyed learning about STEM and our activities which involved STEM.",responsive:"true"}),r.a.createElement("p",null,"Following our Cub Scout outreach, we presented robotics and FIRST to the NUMBER Girl Scout troop. They enjoyed learning about STEM and our activities which involved STEM."),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:ya.a,alt:"Iowa State University Professor of Aerospace Engineering R. Rajagopalan met with the Phoenix Voyagers and discussed their solution for fires in outer space with them. The Phoenix Voyagers and the FTC Pathfinders also toured Iowa State\u2019s Virtual Reality Lab and met with several professionals there.",responsive:"true"}),r.a.createElement("p",null,"Iowa State University Professor of Aerospace Engineering R. Rajagopalan met with the Phoenix Voyagers and discussed their solution for fires in outer space with them. The Phoenix Voyagers and the FTC Pathfinders also toured Iowa State\u2019s Virtual Reality Lab and met with several professionals there."),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:wa.a,alt:"The FTC Pathfinders set up a booth at Ankeny STEM Fest and taught children and their parents about FIRST, FTC, FLL. The children had a lot of fun driving FTC and FLL robots.",responsive:"true"}),r.a.createElement("p",null,"The FTC Pathfinders set up a booth at Ankeny STEM Fest and taught children and their parents about FIRST, FTC, FLL. The children had a lot of fun driving FTC and FLL robots."),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:Sa.a,alt:"Our team was invited to present at CIJUG, we gave a presentation about our code and parts, demonstrated our robot, and explained our outreaches and volunteering.",responsive:"true"}),r.a.createElement("p",null,"Our team was invited to present at CIJUG, we gave a presentation about our code and parts, demonstrated our robot, and explained our outreaches and volunteering."),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:Oa.a,alt:"We hosted an informational meeting at one of our sponsor\u2019s branch along with our sister team the Phoenix Voyagers, we gave a presentation about FIRST, we explained the different aspects of FLL and FTC and showed them our robots.",responsive:"true"}),r.a.createElement("p",null,"We hosted an informational meeting at one of our sponsor\u2019s branch along with our sister team the Phoenix Voyagers, we gave a presentation about FIRST, we explained the different aspects of FLL and FTC and showed them our robots."),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:Ia.a,alt:"During the summer, we set up a booth at the Hindu Temple Cultural Center where we demonstrated our robot and educated children about robotics.",responsive:"true"}),r.a.createElement("br",null),r.a.createElement(b,{src:Pa.a,alt:"During the summer, we set up a booth at the Hindu Temple Cultural Center where we demonstrated our robot and educated children about robotics.",responsive:"true"}),r.a.createElement("p",null,"During the summer, we set up a booth at the Hindu Temple Cultural Center where we demonstrated our robot and educated children about robotics."),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:Ma.a,alt:"",responsive:"true"}),r.a.createElement("p",null,"Teams from Pathfinders Robotics volunteered at Meals From the Heartland to create hundreds of meals for those less fortunate. (FTC Pathfinders 13497, FLL Phoenix Voyagers 7885, and Jr. FLL Robo Robots)."),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:Ra.a,alt:"Teams from Pathfinders Robotics attended the Ankeny STEM fest to share their interest in robotics with the community.  (FTC Pathfinders 13497, FTC Circuit Breakers 10435, and FLL Phoenix Voyagers 7885).",responsive:"true"}),r.a.createElement("p",null,"Teams from Pathfinders Robotics attended the Ankeny STEM fest to share their interest in robotics with the community.  (FTC Pathfinders 13497, FTC Circuit Breakers 10435, and FLL Phoenix Voyagers 7885)."),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:Da.a,alt:"Jeff Margrett, FIRST Senior Mentor and teams from Pathfinders Robotics attended Waukee Public Library \u201cBrick Builders\u201d to share their robots.  (FTC Pathfinders 13497, FTC Circuit Breakers 10435, and FLL Phoenix Voyagers 7885).",responsive:"true"}),r.a.createElement("p",null,"Jeff Margrett, FIRST Senior Mentor and teams from Pathfinders Robotics attended Waukee Public Library \u201cBrick Builders\u201d to share their robots.  (FTC Pathfinders 13497, FTC Circuit Breakers 10435, and FLL Phoenix Voyagers 7885)."),r.a.createElement("br",null),r.a.createElement("br",null)))),r.a.createElement(F.a,{md:"auto",className:"sidenav"},this.state.width>775?Wa:null,r.a.createElement(k.a,{variant:"primary",href:"#WhoWeAre",className:"backToTop"},"Back to top")))))}}]),t}(n.Component),Va=function(e){function t(){return Object(s.a)(this,t),Object(c.a)(this,Object(u.a)(t).apply(this,arguments))}return Object(m.a)(t,e),Object(l.a)(t,[{key:"componentDidMount",value:function(){N.a.pageview(window.location.pathname)}},{key:"render",value:function(){return r.a.createElement("div",{id:"login"},r.a.createElement(M.Helmet,null,r.a.createElement("title",null,"Login | Pathfinders Robotics"),r.a.createElement("meta",{name:"description",content:"Login"})),r.a.createElement("h1",null,"Login"),r.a.createElement("p",null,"Login"))}}]),t}(n.Component),Ha=a(80),Ba=a.n(Ha),za=function(e){function t(e){var a;return Object(s.a)(this,t),(a=Object(c.a)(this,Object(u.a)(t).call(this,e))).getTitleFromCode=a.getTitleFromCode.bind(Object(I.a)(a)),a.getDescriptionFromCode=a.getDescriptionFromCode.bind(Object(I.a)(a)),a.getTitle=a.getTitle.bind(Object(I.a)(a)),a.getDescription=a.getDescription.bind(Object(I.a)(a)),a}return Object(m.a)(t,e),Object(l.a)(t,[{key:"getTitleFromCode",value:function(e){switch(e){case 400:return" Bad Request";case 401:return" Unauthorized";case 402:return" Payment Required";case 403:return" Forbidden";case 404:return" Not Found";case 405:return" Method Not Allowed";case 406:return" Not Acceptable";case 407:return" Proxy Authentication Required";case 408:return" Request Timeout";case 409:return" Conflict";case 410:return" Gone";case 411:return" Length Required";case 412:return" Precondition Failed";case 413:return" Request Entity Too Large";case 414:return" Request-URI Too Long";case 415:return" Unsupported Media Type";case 416:return" Requested Range Not Satisfiable";case 417:return" Expectation Failed";case 418:return" I'm a teapot (RFC 2324)";case 420:return" Enhance Your Calm (Twitter)";case 422:return" Unprocessable Entity (WebDAV)";case 423:return" Locked (WebDAV)";case 424:return" Failed Dependency (WebDAV)";case 425:return" Reserved for WebDAV";case 426:return" Upgrade Required";case 428:return" Precondition Required";case 429:return" Too Many Requests";case 431:return" Request Header Fields Too Large";case 444:return" No Response (Nginx)";case 449:return" Retry With (Microsoft)";case 450:return" Blocked by Windows Parental Controls (Microsoft)";case 451:return" Unavailable For Legal Reasons";case 499:return" Client Closed Request (Nginx)";case 500:return" Internal Server Error";case 501:return" Not Implemented";case 502:return" Bad Gateway";case 503:return" Service Unavailable";case 504:return" Gateway Timeout";case 505:return" HTTP Version Not Supported";case 506:return" Variant Also Negotiates (Experimental)";case 507:return" Insufficient Storage (WebDAV)";case 508:return" Loop Detected (WebDAV)";case 509:return" Bandwidth Limit Exceeded (Apache)";case 510:return" Not Extended";case 511:return" Network Authentication Required";case 598:return" Network read timeout error";case 599:return" Network connect timeout error";default:return" Not Found"}}},{key:"getDescriptionFromCode",value:function(e){switch(e){case 400:return" That's the wrong path to go down!";case 401:return" You're not allowed to go down that path!";case 402:return" You must pay to go down this path!";case 403:return" You're not allowed to go down that path!";case 404:return" We couldn't find that path!";case 405:return" You're not allowed to go down that path!";case 406:return" That path is unacceptable!";case 407:return" That path requires further security!";case 408:return" We've spent too long on that path!";case 409:return" That path is broken!";case 410:return" That path doesn't exist!";case 411:return" That path isn't long enough!";case 412:return" We couldn't start that path!";case 413:return" That path is too big!";case 414:return" That path is too long!";case 415:return" We don't take that kind of path!";case 416:return" That path doesn't work for us!";case 417:return" That path didn't meet expectations!";case 418:return" ... short and stout! / Here is my handle, here is my spout!";case 420:return" Blaze it, bro!";case 422:return" We couldn't process that path!";case 423:return" That path is locked!";case 424:return" That path doesn't have all it needs!";case 425:return" That path is reserved for someone else!";case 426:return" You need to improve to travel on that path!";case 428:return" You need to meet the requirements to travel on that path!";case 429:return" You've tried that path too many times!";case 431:return" Your shoes are too big for that path!";case 444:return" That path just doesn't support you!";case 449:return" When all other paths fail, try, try, try again!";case 450:return" That path has been blocked!";case 451:return" That path is illegal!";case 499:return" You closed access to that path!";case 500:return" There's an issue with the destination!";case 501:return" This path isn't correctly paved!";case 502:return" This path is inaccessible!";case 503:return" This destination is unavailable!";case 504:return" This path took too long to pave!";case 505:return" This path doesn't support your method of travel!";case 506:return" This path is sassy!";case 507:return" There's not enough storage for this path!";case 508:return" This path results in a loop!";case 509:return" This path requires too much bandwidth!";case 510:return" This path has not been extended!";case 511:return" This path requires border patrol!";case 598:case 599:return" Border patrol took too long on this path!";default:return" We couldn't find that path!"}}},{key:"getTitle",value:function(){if(this.props.notFound)return"Error 404: "+this.getTitleFromCode(404);if(this.props.location.search){var e=Ba.a.parse(this.props.location.search.substr(1));if(e&&e.code)return"Error "+e.code.toString()+": "+this.getTitleFromCode(parseInt(e.code))}return"Error "+this.getTitleFromCode(404)}},{key:"getDescription",value:function(){if(this.props.notFound)return this.getDescriptionFromCode(404);if(this.props.location.search){var e=Ba.a.parse(this.props.location.search.substr(1));if(e&&e.code)return this.getDescriptionFromCode(parseInt(e.code))}return this.getDescriptionFromCode(404)}},{key:"render",value:function(){return r.a.createElement("div",{className:"centeredDiv"},r.a.createElement("h1",{className:"centeredTitle"},this.getTitle()),r.a.createElement("h4",{className:"centeredTitle"},this.getDescription()),r.a.createElement(b,{src:y.a,alt:"Pathfinders Robotics Logo",responsive:"true"}))}}]),t}(n.Component);N.a.initialize("UA-137274030-1");var Qa=function(e){function t(){return Object(s.a)(this,t),Object(c.a)(this,Object(u.a)(t).apply(this,arguments))}return Object(m.a)(t,e),Object(l.a)(t,[{key:"componentDidMount",value:function(){N.a.pageview(window.location.pathname)}},{key:"render",value:function(){return r.a.createElement(C.a,null,r.a.createElement(O.a,{exact:!0,path:"/",component:xa}),r.a.createElement(O.a,{path:"/donate",component:ua}),r.a.createElement(O.a,{path:"/login",component:Va}),r.a.createElement(O.a,{path:"/teams/7885",component:_}),r.a.createElement(O.a,{path:"/teams/13497",component:dt}),r.a.createElement(O.a,{path:"/teams/create",component:ft}),r.a.createElement(O.a,{path:"/error",component:za}),r.a.createElement(O.a,{path:"*",render:function(e){return r.a.createElement(za,{notFound:"true"})}}))}}]),t}(n.Component),qa=a(56),Ua=function(e){function t(){return Object(s.a)(this,t),Object(c.a)(this,Object(u.a)(t).apply(this,arguments))}return Object(m.a)(t,e),Object(l.a)(t,[{key:"render",value:function(){return r.a.createElement(h.a,{fixed:"bottom",className:"navbar titanic",variant:"dark",expand:"lg"},r.a.createElement(F.a,null,r.a.createElement(P.a,null,r.a.createElement(h.a.Brand,{className:"titanic noVerticalPadding"},"Connect With Us!")),r.a.createElement(P.a,{className:"navbar titanic"},r.a.createElement(h.a.Brand,{href:"https://www.facebook.com/13497.FTC.Pathfinders/",target:"_blank",rel:"noopener",className:"noVerticalPadding"},r.a.createElement(qa.a,{size:"1.5em"})),r.a.createElement(h.a.Brand,{href:"https://github.com/owens3364/",target:"_blank",rel:"noopener",className:"noVerticalPadding"},r.a.createElement(qa.b,{size:"1.5em"})),r.a.createElement(h.a.Brand,{href:"mailto:info@pathfindersrobotics.org noVerticalPadding"},r.a.createElement(qa.e,{size:"1.5em"})),r.a.createElement(h.a.Brand,{href:"https://www.instagram.com/pathfinders13497/",target:"_blank",rel:"noopener",className:"noVerticalPadding"},r.a.createElement(qa.c,{size:"1.5em"})),r.a.createElement(h.a.Brand,{href:"https://twitter.com/pathfinder13497/",target:"_blank",rel:"noopener",className:"noVerticalPadding"},r.a.createElement(qa.d,{size:"1.5em"})))))}}]),t}(n.Component),Ya=function(e){function t(){return Object(s.a)(this,t),Object(c.a)(this,Object(u.a)(t).apply(this,arguments))}return Object(m.a)(t,e),Object(l.a)(t,[{key:"render",value:function(){return r.a.createElement("div",null,r.a.createElement(v,null),r.a.createElement(d.a,{className:"jumbotron"},r.a.createElement(S,null,r.a.createElement(Qa,null)),r.a.createElement("br",null)),r.a.createElement(Ua,null))}}]),t}(n.Component),Ga=Boolean("localhost"===window.location.hostname||"[::1]"===window.location.hostname||window.location.hostname.match(/^127(?:\.(?:25[0-5]|2[0-4][0-9]|[01]?[0-9][0-9]?)){3}$/));function Ka(e,t){navigator.serviceWorker.register(e).then((function(e){e.onupdatefound=function(){var a=e.installing;null!=a&&(a.onstatechange=function(){"installed"===a.state&&(navigator.serviceWorker.controller?(console.log("New content is available and will be used when all tabs for this page are closed. See http://bit.ly/CRA-PWA."),t&&t.onUpdate&&t.onUpdate(e)):(console.log("Content is cached for offline use."),t&&t.onSuccess&&t.onSuccess(e)))})}})).catch((function(e){console.error("Error during service worker registration:",e)}))}var Xa=a(82);i.a.render(r.a.createElement(Xa.a,null,r.a.createElement(Ya,null)),document.getElementById("root")),function(e){if("serviceWorker"in navigator){if(new URL("",window.location.href).origin!==window.location.origin)return;window.addEventListener("load",(function(){var t="".concat("","/service-worker.js");Ga?(!function(e,t){fetch(e).then((function(a){var n=a.headers.get("content-type");404===a.status||null!=n&&-1===n.indexOf("javascript")?navigator.serviceWorker.ready.then((function(e){e.unregister().then((function(){window.location.reload()}))})):Ka(e,t)})).catch((function(){console.log("No internet connection found. App is running in offline mode.")}))}(t,e),navigator.serviceWorker.ready.then((function(){console.log("This web app is being served cache-first by a service worker. To learn more, visit http://bit.ly/CRA-PWA")}))):Ka(t,e)}))}}()}]),[[156,1,2]]]);
+(this.webpackJsonppathfinders=this.webpackJsonppathfinders||[]).push([[0],Array(39).concat([function(e,t,a){},,,,,,,,,,function(e,t,a){e.exports=a.p+"static/media/Pathfinders-Logo.5831fc26.svg"},,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,function(e,t,a){e.exports=a.p+"static/media/AndrewScott.7e189760.jpg"},function(e,t,a){e.exports=a.p+"static/media/CarsonBaty.f3cc4af7.jpg"},function(e,t,a){e.exports=a.p+"static/media/ErinStone.968fbfd7.jpg"},function(e,t,a){e.exports=a.p+"static/media/GabriellaAvilez.10712144.jpg"},function(e,t,a){e.exports=a.p+"static/media/MasonHayes.7e8c37d3.jpg"},function(e,t,a){e.exports=a.p+"static/media/SamyuMogolapalli.d46531cc.jpg"},function(e,t,a){e.exports=a.p+"static/media/Team7885.e15e128b.jpg"},,function(e,t,a){e.exports=a.p+"static/media/Team13497.16fc3aa6.jpg"},function(e,t,a){e.exports=a.p+"static/media/Makerspace.4eed80be.jpg"},function(e,t,a){e.exports=a.p+"static/media/Marshalltown.eb6d594a.jpg"},function(e,t,a){e.exports=a.p+"static/media/Kickoff.f3485fb8.jpg"},function(e,t,a){e.exports=a.p+"static/media/TempleTeaching.2f9d2a47.jpg"},function(e,t,a){e.exports=a.p+"static/media/TempleCrazy.2d3ecfe0.jpg"},function(e,t,a){e.exports=a.p+"static/media/PizzaRanch.b66814fa.jpg"},function(e,t,a){e.exports=a.p+"static/media/ISU.51c16aec.jpg"},function(e,t,a){e.exports=a.p+"static/media/STEMFest.fc6602b6.jpg"},function(e,t,a){e.exports=a.p+"static/media/WorkOnRobot.2704ef76.jpg"},function(e,t,a){e.exports=a.p+"static/media/CIJUG.95556508.jpg"},function(e,t,a){e.exports=a.p+"static/media/RobotBuilding.1198411b.jpg"},function(e,t,a){e.exports=a.p+"static/media/CAC.94e185e2.jpg"},function(e,t,a){e.exports=a.p+"static/media/Judges.ca49652a.jpg"},function(e,t,a){e.exports=a.p+"static/media/Teacher.1f523df9.jpg"},function(e,t,a){e.exports=a.p+"static/media/Shuler.b1fdc0bc.jpg"},function(e,t,a){e.exports=a.p+"static/media/Robot.7d1dca50.jpg"},function(e,t,a){e.exports=a.p+"static/media/SiouxBooth.fbf7bc8f.jpg"},function(e,t,a){e.exports=a.p+"static/media/AryaKarnik.745baf48.jpg"},function(e,t,a){e.exports=a.p+"static/media/BenjaminAvilez.22d4bd64.jpg"},function(e,t,a){e.exports=a.p+"static/media/KristenStone.4ce03759.jpg"},function(e,t,a){e.exports=a.p+"static/media/OwenScott.ccd2cf7e.jpg"},function(e,t,a){e.exports=a.p+"static/media/SabarishMogolapalli.09bedb3b.jpg"},function(e,t,a){e.exports=a.p+"static/media/SeanEastman.8657ece3.jpg"},function(e,t,a){e.exports=a.p+"static/media/ShriyaMagatapalli.587af85c.jpg"},function(e,t,a){e.exports=a.p+"static/media/JohnDeere.dfc553be.svg"},function(e,t,a){e.exports=a.p+"static/media/McClureEngineering.7f1b0c76.png"},function(e,t,a){e.exports=a.p+"static/media/McCormack.a6b9abb9.png"},function(e,t){e.exports="data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAfQAAAF3CAYAAABT8rn8AAAAGXRFWHRTb2Z0d2FyZQBBZG9iZSBJbWFnZVJlYWR5ccllPAAAGZNJREFUeNrs3c1vHOd9B/CRrKB5AROlLVrQ7UEuUKY9FJBBoD0YqFeH3miXKdAD3YPpi3SrX5RLLxHlSy9mavUmAQWoQ8wCBRoC4h+wPqRAgBJW0bRBWLRgCkRs0aJRsrUTO5bYeaiHBU2TO7OzM7Pz8vkAAxmWyJ195pnnO7+ZZ2aSBAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAACAdjinCaC7Dt68cDH9YzldXv3BX//mwx9+77N3nzt4b0PLgEAH2hHkl0OIxzAPoZ78y18tJP/1778Q/vNhuoRQv5WG+57WAoEONLQaT5fLJ//+WKAfN0wXVTsIdKCJ1fhpzgj0I6p2EOhA06rxAoGuageBDtQY5JfSP25kVeNTBvrxqv1WqNxV7SDQgXKCfDX94+V0GRT9HQUC/bitWLVv2Rog0IHJq/FwSn110mq8gkA/Eir1u6p2EOhADdV4hYGuageBDtRVjdcU6Kp2EOhAldV4zYGuageBDqrxuj63pkA/XrUfzZB/aKuDQIcuBfnRfeODWXx+zYF+3Eas2od6AQh0aHM1HirxcFr90izXZYaBrmoHgQ6trsZDiC83ZZ0aEOiqdhDooBrvWKCr2kGgg2q8Q4GuageBDqrxjgX68ar9ZrpsqdpBoEOvq/GWB/qREObhfvbwStf7eh8IdJg2xC/GavzVtlTjHQn040Kg31K1g0CHIkE+iNX4ahe+T8sDXdUOAh36V413ONBV7SDQoT/VeE8CXdUOAh26XY33LNCPGyZPbn3b0MMR6KAaF+jdqNo3YtW+p9cj0KFb1Xi41exGH6pxga5qR6BD14L8cvLklHoI84t9boseB7qqHYEOLa/GQ5Bf1iICXdWOQAfVuEBXtYNAB9W4QG+RrVi1b2kKBDqoxgV6+4VK/W7y5JWuqnYEOqjGBbqqHQQ6HA/ySzHEV1XjAl3VDgKd9gV5CPDwAJiB1hDoqnYQ6KjGEeiqdgQ6qMYFOpk2YtU+1BQIdFTjCPRuVO23YtXula4IdFTjCHRVOwh0VOMIdFU7Ah1KDvLlWI0vaw2BjqodgU77qvHVGOSXtIhAR9WOQEc1jkDvshDm4X728HKY+5oDgY5qHIHefvdj1b6lakegoxpHoKvaEeigGhfoqNoR6HQ5yAcxxFe1hkBH1Y5Ap10hfjEG+KuqcYGOqh2BjmocgU51VftGrNr3NAcCHdW4QKf9hsmTB9ZsaAqBjmocgY6qnRa7oAl6G+bfStxyBl0Tzra9FhcFW8+c1wS93vEBEOgAgEAHAAQ6ACDQAUCgAwACHQAQ6ACAQAcAgQ4ACHQAQKADAAId6jZMl1fS5aam+ESbXEmevBEMEOjQWEevr3zm3Nc/vpIuguuE5w7eG6ZLOND5crq8ni57WgWm5/WpUI69WIlvpSH+UHPkCvbQTm+H5dvnnh2kf76cLqtaBgQ6zEKowO+mIT7UFNNV7ekfwzTYX4+h/mq6XNIyINCh6mr8bgjzNMj3NIeqHQQ6tMswVuMbmkLVDgId2iVUjFvpclM1rmoHgQ7tE8LbJLdmV+2vxXBXtYNAh0/ZSDo6ye3HDz7Ttap9LSxpuC/HYF/WfRHooBrv/CS3jz7u5mMn0nAPl0S20mAPlfqqqh2BDv0zTExy61Kw76naEejQHya59SPcVe0IdOioEN4muanaVe0IdGipjcST3DhRtX/pt0bf+ukPPnf5o58aAhHo0FyPD5LHHx4kD3/2OPmlvzh82xl8omrf/4PDSy/JT//tV5P//aeLyWjv8xoGgQ6N8fPHyYdpkP/oZwfaglw+9xv/ebj84ujzyfv//CvJT/5xLlG1I9BhFg7Ohfuxkv/54CD56JHmoJin5j5Ivvh7e+miakegQ70eHyQffvA4+clHB8mjx5qDaqr2n7w3n/z4u19IHj96SsMg0KFUHz5KRh8eJO9/pCmovmr/8u//a7okyfvf+7Vk9N0vJh/8x2c1DAIdpqnGjya5Oa3OLHzht394uDxStSPQoQCT3FC1g0CnpUxyQ9UOAp0WM8kNVTsIdFosDfDRzx6Z5EYnq/Yf/cMXNQq1Oa8JmCVhTperdhDoAIBABwCBDgAIdABAoAMAAh0ABDoAINABAIEOAAh0ABDoAIBABwAEOgAg0AEAgQ4AAh0AEOj03vsfaQMAgQ4ACHQAEOgAgEAHAAQ6ACDQAUCgAwACHQAQ6ACAQAcAgQ4ACHQAQKADAAIdAAQ6ACDQAQCBDgAIdAAQ6ACAQAcABDoAINABQKADAAIdABDoAIBABwCBDgAIdABAoAMAAh0ABDoAINABAIEOAAh0ABDoAIBABwAEOgAg0AFAoAMAAh0AEOgAgEAHAAQ6AAh0AECgAwACHQAQ6AAg0AEAgQ4ACHQAQKADgEAHAAQ6ACDQAUpxWRMg0AHa76ImQKADAAIdABDoAIBABwCBDgAIdABAoAMAAh0ABDoAINABAIEOAAh0euy+JgAEOrTfQ00ACHQAQKADAAIdABDoACDQAQCBDgAIdIAOOPjwMxoBgQ7Qdj//7y9pBAQ6ACDQAUCgAwACHQAQ6ACAQAcAgQ4ACHQAQKADAAIdAAQ6vXQrXYYd/n7hu920mTlD6BsPO/rd9vR9gU6PnPv6x1vpciX9z2fS5e0ODW5hMPvq/F8mV9JlaEtzmrRvrMW+36VgD/39lecO3nsmXdZs5f65oAl6H+whAF8Py8GbF5bTP19Ol+UWfpUwKN9MB+q3bVVyhnroM2v7f3rYZ15Ll1fT5WIL+/1W6PtpiO/Zqip0OF61fzVWLq/HarcNQpX1jDCnaLC3sGIP++YrYZ3TIH9FmKNCZ1zVHsLx7bRqH8SqfbWBq7oRq3KDGX2p2EOfv5sG+NAWQ6AzabiHgWOYBnuo2JfjIHd5xqs1jEFuUKMPwR4OWO+GMFeJI9ApI9gfxupgIw33y8eq9joHujCYvZ4OuFu2CD0I9mGsxjdsDQQ6VYX7/fSPsISJdKsx3AcVfmQYWG/F65zQ5WA3yY3CTIpj2nDfOHb7282k/Il0YRB9RpjThGCvcPJc2G/CZS2T3FChM/NgDwNQGOzW4u1vf5hMN5EuVCmvm/BGxyv20M9vmeSGQKep4R4Gqa04kW41DniXPvUPHx+c9uNhYDPhjdYEewz30M9vnNrPP+3wElJikhsCnRYFexi4jm5/uxyDffn/q5lHnwj0vRjkG1qOFoZ76LcbGcEeDlJNcqMyrqFTV7jfT5fDB2EkTx6IMTxWrYQgf0aY04VgD3059PHHH5/bO//Uo/C/Q79+Ng3yK8IcFTpdq9oPq5lHf3Y+VDEP46lL6FSwf/vcr2/98u/+6OJXvrO3p0UQ6HTaU3/+2EBHZ6XV+MPkOw5WqY9T7gAg0AEAgQ4ACHQAQKADgEAHAAQ6ACDQAQCBDgACHQAQ6ACAQAcABDoACHQAQKADAAIdABDoACDQAQCBDgAIdABAoAOAQAcABDoAINChE4bpcl8zHHqoCUCgQyud+/rHw3R5Nv3PK+my0dNmCN/7ynMH731Vj4CKxhpNAPU6ePPCxfSP1XR5NV0u1fW5f3fjd+r+qnvpcjdd3k6DXGUOAh06He7L6R8vp8tyhwJ9KwR5GuJbtjAIdOhbsF+KFXuo3C+2MNCPqvGNNMj3bFEQ6CDc37ywGqv2QQsCXTUOAh3ICPbLsWpfLqNqLzHQVeMg0IECwV7KJLoSAl01DgIdKCncB8mT0/GrNQV6mJ2+kS63VOMg0IHyg/1SDPWX81btEwb6MFbjG1obBDpQT7gfBftgykBXjYNABxpStd9IzphENybQVeMg0IEGBvvFGOphEt3lMwJdNQ4CHWhRuA+SOIkuBrpqHADaXLV/+9yzl7UEAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAALNzrq4P+qM//pOn0z+upsv8ib/aT5c7f/s333xQ8ue9kP6xdNbfp593zeanor5+u8aPW0/78q5WBy7UNMDNpX98M13mzvgnz6f/5sV0YBqV9HlX48EDzMJijZ81p7mB2gI9NcgYeMLfXU+XtRLPBJR9UPLCKWcXqrCTHtjsnPg+z89o4A4HWO8WOXuSs70+8V1L3FahzZbq/MyWnklYiAcfRfvWbmzPUcnrtXjyoCj9jDs5t/nimPUcVrV+qf3099+roBBaOmv7ZLVJRludHFOm2i/GrGsp+1vGeLJd1hne+D3Ctl0oaTNul332uQmBnicIl9LGfKeE04dvVPQdlmqqvMJOunNswL094yrsaroeN/MOhnGHuJ1zhxgefdcSB8Fw8HhjTJvdKfszWxbioV1W4jJX0u8M23GzpIF7JR7cn/z/pwZYDKc3YtEw7ozJSvpvw+W99WmCPfavt8as+7UyDnBy7kd3CvzeM8eU9O9C+BQtqs5c1zh+3JuiLW5njL2h3z2Ysr0XYiE4qGj8vFfH/n2+YePN9RKOnAdJd6wlsz+lOjfhdlmZ4Oi2igOkG4nT0OP2j3fiwFVmG4V97nb6+6fdf8f1tatx0D0Zrt+cYJ8PhcVbU67nuJ89CoWy+vFCBd1gfcy2X4ptWqR6HreuN+K2LXoAtVjxfnE97hdVZUfh79/2QF+Mg07ho6GOjcELDVmPSS41TLJTzJ0cpEsILGF+9qB7O6n2slGogtcq7O9rRwNjnCfzVsHtvRJ/vop9YSWeNWhcYRLXa37KbVB0fFhp4hgY++tKDbtgLWN50wK9cJVex5EclXRc26y4UZ7T3PGg6UZN67Q0Zahn9a130t9/r4SD96tlHkyeMO1lv6oKk/kZ9tWVpu08sTJf6tKA0MRAX4jVRC0HApS6gxQJZ4FeTLgenPfWy/Wa121pyjNtWaFUVjBVFZyDot+/w4XJXMFxvcqxaqVrjXyhoesVjp6HeSeXxMko8x3ZJvstXvciA9FXehrIUz0HIe8EtBrvzjjtAPulhm+DELxPVzQL+WrBbdzlwiS0yb0GrUvnNDXQ5+PRU+YsznhNrSsbZ7Ou2ZANCvT5CgfVxqrx1rlZVSHhTNtCCx56E/psFX0vzAcaTDKjvmOFyVn7+uKsbxs9drumQK9RmFyymaNKL+32mxlXXfunhFrRI/yFCtezaKCPxmynqgbVXosHu3mvE28nT24jzNrfjmZy59nnBsmTe8ArOfiN63uap5P8d1tUGaDXx6xjlwuTKs5czKLwGMWisow+XMuBbZMD/aiDr4/ZCZ5OGnQdpOwjzyK/L22TUd3rGT93MUeHXhyzg7X5zERT5Q3zSe6T3Qn3KydPbvOpYsZ0Hi9lVP6hf9/Lcf/yJIN70Yp0JV3XzTwFTNKPOzQWG3BGLs9lvv3Yz0ZtatzzDV+/rFtAriZuU2rMjpoVBDMaVMnYLpNe5omDXJ6JdlXsm9sTnMZfb0D7Xs26B7lphUlNVfos5Tkrs962MG9DoAdvnLEThKP/pYQ2VINZA/B8XQ9eYKIDrXGhPpzR+j6YYB2bcP1+LkdY960wWWr6/j7D/t35QD/rFhC3qbWnQg9HuvtT/Dy02ZlnGntcmKzoFv0M9KMj2OM7waIAaI44KM1lBHpWZWV70lXjJrz1tTAR6D0O9MUTzxhWnbenOg92Vej03NIpz6Lvc2HSqAfNCPTyZU1AuB53gqwXAYxs1sYF+ijHrNYF19HpuOuq80+4qkt0N9Dfzfj7+fhChatT/h7Kl3UbyNHkJFU6vT7wPZoPlKMw6YP5Ch8RLNBnLFRwWTNuQ5hn3XLgfuYa5XyD0/6xbTy2SteidL1Kz/kQmb6caVSldzTQgztT/vzOrB8r2MeqI+sfHDvdvjvt74KG20nGn4kKB61Zr7HdTWp6slgTxo9pXzdLQwM9hvE0gXzHJm1coO9OUHUIdLog6yxh1pmozZ61lyq9oxV6UPTpTkPVeSMD/cGJ6mUs19TogM2k+Cnz/Za/oKmIJRNiOxro8elO2wV+9Bs2Z71yXj/fPeO/Vel0UnxkaNEqu6tnGbP2ffeld7RCL9Kpt/v2+s2GyDOJbefEQGemO3UdcA5mGOp3cvT1T4Veh6vzrO8l0EvQyLethXBOd8awQ+S5tjJKXDuflTzhu3tKwC/1PdDjm8CKCO232cYXR9TcvuFg80YJleO0hcmNCf79eoc3ydHDpc46o3f4oJkeXm7ofqBHm0m+VwpuNqU6j/fJTyp08mFLB+jMCXGnfK/vZwT64WDckBdrzPpg6KyfC8u1pJ8Wc+xnIczzVueVjR0hnNJ1Xcq5rftwh84woxK/mjT8tuN41mdhij5RafHZ2EAPQZA23mZGlT7NtaoqFJ2tGV6x2Kp378ZJLFkdeyfn/zsttPpy207RUOvDQc+4A5qyVP0gqjCA5zkbs96DbXcvI9APHzTT1AObdN3WkulfpFNpoDf6We45rkPd6cipx/mkfbdu5Ko6Ttmmu4nb18pgVvD0dqo+u5fzVtztPhycxe+Y9T0bOQ625a14bXg5y1lHruH2ji7dr9m2p6QVuX6et0oX6NShrrk3NxuyHk2QNWY39UEzgzY0buMDPb5ofrPnO0EbD0D2x1Q/WUfpcyffTAVlB0tdp3bjfrA9Zj16c4dOnPSWdYbOg2Y6XKGHThCq9BeTJxOBDhezIRtfoe8U/DtVeraRhyhNZTuOKXVaP+VAtq936GRV6U180EwrLu1eaEsPiEexXT6SHbZlRXM+zW1nzLbcSX9H1s9/JeE0YU7Jdc0wVWVe+wS0ONfnpRP7zqintx/muYNppWEHO++2Yb+7YP9uzCDTpvkAeQL9+zkCf7HHFXqh285U5u0Lc9vv0wc36YHNdjJ+xnujAj0+GyXMhXgjafCEVIE++0F6v4XX0LLCdpRj1m5WoIdbWJ7u6vVFA3thIQjunREAg4yfDady73goT6Oq9LPMxXfGN2mfDc8VGCZnzx+6nsx4crNAN0hXEehhZ/z7kj7HI3057sFp+1m8hJMV6HNJ807l9nWsfBCr9HG3gj2fNOx5FPFg8NRxPv0+Mz9QPK9rMYma34ZmYhyTHEznOaB+QWs1RtaB1SDxvAWBTqUWOvpZtF+eeSjzTTuV2+cqPcdB2EBLCXS6UTUveE8yEwTEMMn3hjNv9mpPlT6viQQ63Qj0WXwe3Q6IowNF/aoZB2F5L5Ug0ClTfHpb3RWzgZdJhCo9z+QkTyNrjm1NINDpfnUu0Jm04sv7BsamPjO8j9ss3Ia4ryUEOvXK8/S2nQmXrB3ZdXQmlfchTar05nArYQnch06Z1XJ4SM5ED9dJwzpMUMp6pOJC4jobE1TpOe5xDo4eNONZBw2o0tNt0einsKnQ6Yx4ejJrxmmR0PWiFmZZ8S1pqsbY1AQCnWZU54UCPT4idlTCZ8PxfvUgZ39ccUmnUYHusbwCnbYGerRbwmdDkSo9hPlAUzXiICyEuRnvAp0GBPo0L5nJPBBw33CztKGqjfc453kWuMlxzarS29q/Z37XhEAnb+eu4vr5JD8r0KtR9DGobbn27HGw7arSHzSkSp/oaYKx4MgaIyu/nCDQKaM6nyrQc76lTqBPLs8AEsJsbZKKJP23g5xV7czflDXBPc4mxzVH1bew5emXK7Gf59kfwl04bzVhf3DbWrmV7LSn7g6vITXwfc2VBvqxnx/3OWW9qGWxhO20H4Oi6dXObnytaJ4wez79t+8m2a+rHUywLZry6st7OQ5AQr9Y9J76ZlTp6bbIGg+qDvRwgPtWuh7h3w4zxsbFpuwPAr1cZVyLu5p2ohcbFupVXj/PG+jhHesLcVb8tN9l6oEiDv5rLeiTwyTfpK+5CqrUpoRjOO2+kmTf4/xC4nkHTarSb1e4T9zI+W8XSiwmKu9bTrk3z1zSoEk68VTsQg0dtW2n3ZdaMlFvVpOMtpvywJYJZk8veRxsY6r0yl7aMqPZ9PvxbYACvYea9B7wOk63t/U6euMDPbbrsOaPHSXNe5Rn3gMb19Kbo8rQvZPUe8/7Wh0fItAp4+CirCPprN/jASDF3EzqvZ79jaY9TrVBs6fJv80qe2lL7A/fqOtgsq65GXUFetMmeTX9aURVrt9uyeuyX+Lg3bbrl/s1/1zRwWsUK4Q6Qv3mFBMGRxXvG+/UsG12ahoDRjXs+7Mcd45X0lUeMNys+ixD+jnrdTVyXYG+ndEBy76msTnl38/au1O29TjDkrddmSE8bNl22ym4DWqfIR8nE16rsEoNQXhtmtn/cR13M8aJd6f8/eP6626O9d8u2CfK3o+3C3z/UY71265gXXeKTvKN22Nsn5im+o2//1oFB9mjeHC7Vud+fr6mwWQ0ptGOvniZp+jujAmHcPqjya/q25xyULw3Jvgm/u4Z2y78v/US+8nuGYPD4TXZOiaVTLizntVn15vY/8K2jAPMtRwHapMc1IS2eKGk04pnnUkoa5xYP+V778ff/VLOn98Zs347JW2r4ZjqdDhF5fq1jPV/UGBdH8RKd3RG//jalM1xbdw6l9DW4YDjhfi7pt1+u7GPvDiLW1vP1f2BJ2cGV3lt4ZQ3hBU+PRwfHlD1NdzSTl+fMjt96t8d2/PoNo4wCA6ruL0u9pHFuMPu5L1VLeeM/DIG2526+1+N+0jp7VDG/lbmZ8V+shR/f6FnCpzSbrsV7Qul78dVtu+J8X1Uwm2mtbd50fHeMwwAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAaIv/E2AAudQO0LMOK+MAAAAASUVORK5CYII="},function(e,t,a){e.exports=a.p+"static/media/RWE.633e40ce.svg"},function(e,t,a){e.exports=a.p+"static/media/Terracon.232fc414.png"},,,,function(e,t,a){e.exports=a.p+"static/media/UnderConstruction.4bc2d788.gif"},,,,function(e,t,a){e.exports=a.p+"static/media/FTC1819.19a16e9e.jpg"},function(e,t,a){e.exports=a.p+"static/media/Mission.09a6ee4a.jpg"},function(e,t,a){e.exports=a.p+"static/media/CubScout.76f9edbc.jpg"},function(e,t,a){e.exports=a.p+"static/media/GirlScout.b6bc0a29.jpg"},function(e,t,a){e.exports=a.p+"static/media/IAState.d083dbba.jpg"},function(e,t,a){e.exports=a.p+"static/media/STEMFest.b9b7be7f.jpg"},function(e,t,a){e.exports=a.p+"static/media/CIJUG.4ccebc27.jpg"},function(e,t,a){e.exports=a.p+"static/media/NewMemberMtg.07230887.jpg"},function(e,t,a){e.exports=a.p+"static/media/Booth.05ecd619.jpg"},function(e,t,a){e.exports=a.p+"static/media/KristenChild.9412ef0d.jpg"},function(e,t,a){e.exports=a.p+"static/media/AnkenyStemFest.524ef093.jpg"},function(e,t,a){e.exports=a.p+"static/media/Library.aa732d20.jpg"},function(e,t,a){e.exports=a.p+"static/media/MealsFromHeartland.3255f7ab.jpg"},function(e,t,a){e.exports=a(204)},,,,,,,,function(e,t,a){},,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,function(e,t,a){"use strict";a.r(t);a(157),a(158);var n=a(0),r=a.n(n),o=a(30),i=a.n(o),s=(a(164),a(8)),l=a(7),c=a(10),u=a(9),m=a(11),d=a(213),h=a(26),p=a(55),f=a(45),E=a(206),b=(a(39),function(e){function t(e){var a;return Object(s.a)(this,t),(a=Object(c.a)(this,Object(u.a)(t).call(this,e))).mounted=!1,a.state={output:r.a.createElement(E.a,{animation:"border",variant:"primary",role:"status"})},a}return Object(m.a)(t,e),Object(l.a)(t,[{key:"componentWillMount",value:function(){this.mounted=!0}},{key:"componentDidMount",value:function(){var e=this,t=new Image;t.onerror=function(){e.mounted&&e.setState({output:r.a.createElement("div",null,r.a.createElement("h1",null,"Uh Oh! Path not found!"))})},t.onload=function(){e.mounted&&e.setState({output:r.a.createElement("img",Object.assign({},Object.assign({},e.props,{className:e.props.className+" "+e.props.responsive?e.props.className+" responsive":e.props.className,text:void 0}),{alt:e.props.alt?e.props.alt:""}))})},t.src=this.props.src}},{key:"componentWillUnmount",value:function(){this.mounted=!1}},{key:"render",value:function(){return r.a.createElement("div",{className:this.props.helvetica?"topMargin":""},this.state.output,r.a.createElement("p",{className:this.props.helvetica?"helvetica":""},this.props.text))}}]),t}(n.Component)),A=a(44),g=a(49),y=a.n(g),v=function(e){function t(){return Object(s.a)(this,t),Object(c.a)(this,Object(u.a)(t).apply(this,arguments))}return Object(m.a)(t,e),Object(l.a)(t,[{key:"render",value:function(){return r.a.createElement(h.a,{fixed:"top",sticky:"top",className:"navbar titanic standardFont",variant:"dark",expand:"lg"},r.a.createElement(A.LinkContainer,{to:"/"},r.a.createElement(h.a.Brand,null,r.a.createElement(b,{src:y.a,width:"30",height:"30",className:"d-inline-block",alt:"Pathfinders Robotics Logo",text:"\xa0Pathfinders Robotics",helvetica:"true"}))),r.a.createElement(h.a.Toggle,{"aria-controls":"basic-navbar-nav"}),r.a.createElement(h.a.Collapse,{id:"basic-navbar-nav"},r.a.createElement(p.a,{className:"mr-auto"},r.a.createElement(A.LinkContainer,{to:"/"},r.a.createElement(p.a.Link,null,"Home")),r.a.createElement(A.LinkContainer,{to:"/donate"},r.a.createElement(p.a.Link,null,"Donate")),r.a.createElement(f.a,{title:"Our Teams",id:"basic-nav-dropdown"},r.a.createElement(A.LinkContainer,{to:"/teams/13497"},r.a.createElement(f.a.Item,null,"FTC Pathfinders 13497")),r.a.createElement(A.LinkContainer,{to:"/teams/7885"},r.a.createElement(f.a.Item,null,"FLL Phoenix Voyagers 7885")),r.a.createElement(f.a.Item,{href:"https://circuitbreakersrobotics.com",target:"_blank",rel:"noopener"},"FTC Circuit Breakers 10435"),r.a.createElement(f.a.Divider,null),r.a.createElement(A.LinkContainer,{to:"/teams/create"},r.a.createElement(f.a.Item,null,"Create a Team"))))))}}]),t}(n.Component),w=a(90),T=function(e){function t(){return Object(s.a)(this,t),Object(c.a)(this,Object(u.a)(t).apply(this,arguments))}return Object(m.a)(t,e),Object(l.a)(t,[{key:"componentDidUpdate",value:function(e){this.props.location.pathname!==e.location.pathname&&window.scrollTo(0,0)}},{key:"render",value:function(){return this.props.children}}]),t}(n.Component),S=Object(w.a)(T),C=a(89),O=a(63),N=a(20),I=a(12),j=a(207),P=a(208),F=a(104),R=a(216),k=a(96),D=a(13),L=a.n(D),M=a(36),W=a(97),x=a.n(W),V=a(98),H=a.n(V),B=a(99),z=a.n(B),Q=a(100),q=a.n(Q),U=a(101),Y=a.n(U),G=a(102),K=a.n(G),X=a(103),J=a.n(X),Z=r.a.createElement(p.a,{variant:"pills",className:"flex-column",defaultActiveKey:"#FLLPathfinders7885"},r.a.createElement(p.a.Item,null,r.a.createElement(p.a.Link,{href:"#FLLPathfinders7885"},"FLL Phoenix Voyagers 7885")),r.a.createElement(p.a.Item,null,r.a.createElement(p.a.Link,{href:"#FLLPathfinders7885Mission"},"Mission")),r.a.createElement(p.a.Item,null,r.a.createElement(p.a.Link,{href:"#FLLPathfinders7885Finances"},"Finances")),r.a.createElement(p.a.Item,null,r.a.createElement(p.a.Link,{href:"#FLLPathfinders7885Team"},"Meet the Team"))),_=function(e){function t(e){var a;return Object(s.a)(this,t),(a=Object(c.a)(this,Object(u.a)(t).call(this,e))).state={width:window.innerWidth,selected:"FLL Phoenix Voyagers 7885"},a.updateWindowWidth=a.updateWindowWidth.bind(Object(I.a)(a)),a}return Object(m.a)(t,e),Object(l.a)(t,[{key:"componentWillMount",value:function(){Object(D.configureAnchors)({offset:-90}),window.addEventListener("resize",this.updateWindowWidth)}},{key:"componentDidMount",value:function(){N.a.pageview(window.location.pathname),this.updateWindowWidth()}},{key:"componentWillUnmount",value:function(){window.removeEventListener("resize",this.updateWindowWidth)}},{key:"updateWindowWidth",value:function(){this.setState({width:window.innerWidth}),this.state.width<775?Object(D.configureAnchors)({offset:-190}):Object(D.configureAnchors)({offset:-90})}},{key:"render",value:function(){var e=this;return r.a.createElement("div",{id:"7885"},r.a.createElement(M.Helmet,null,r.a.createElement("title",null,"FLL Phoenix Voyagers 7885 | Pathfinders Robotics"),r.a.createElement("meta",{name:"description",content:"FLL Pathfinders 7885 started as a feeder team to the FTC Pathfinders 13497. We're composed of six students between fourth and seventh grade. Many of us ..."})),r.a.createElement(j.a,{fluid:!0},r.a.createElement(P.a,null,r.a.createElement(F.a,{className:"teamArticle standardFont"},r.a.createElement(L.a,{id:"FLLPathfinders7885"},r.a.createElement("div",null,this.state.width<775?r.a.createElement("div",null,r.a.createElement("div",{className:"mobileBar"},r.a.createElement(p.a,{variant:"pills",className:"flex-column frozen"},r.a.createElement(f.a,{title:this.state.selected},r.a.createElement(f.a.Item,{href:"#FLLPathfinders7885",onClick:function(){return e.setState({selected:"FLL Phoenix Voyagers 7885"})}},"FLL Phoenix Voyagers 7885"),r.a.createElement(f.a.Item,{href:"#FLLPathfinders7885Mission",onClick:function(){return e.setState({selected:"Mission"})}},"Mission"),r.a.createElement(f.a.Item,{href:"#FLLPathfinders7885Finances",onClick:function(){return e.setState({selected:"Finances"})}},"Finances"),r.a.createElement(f.a.Item,{href:"#FLLPathfinders7885Team",onClick:function(){return e.setState({selected:"Meet the Team"})}},"Meet the Team")))),r.a.createElement("br",null),r.a.createElement("br",null)):null,r.a.createElement("h1",null,"FLL Phoenix Voyagers 7885"),r.a.createElement(b,{src:J.a,alt:"FLL Phoenix Voyagers 7885",responsive:"true"}),r.a.createElement("p",null,"Left to right: Samu Mogallapalli, Gabriella Avilez, Mason Hayes, Carson Baty, Andrew Scott, Erin Stone"),r.a.createElement("p",null,"FLL Phoenix Voyagers 7885 started as a feeder team to the FTC Pathfinders 13497. We're composed of six students between fourth and seventh grade. Many of us are the younger siblings of the FTC Pathfinders 13497 team."),r.a.createElement("p",null,"We advanced to the FLL State Championship right from our very first competition this past December. At the FLL State Championship, we received third place for the Project Award. In our future, we hope to advance to the national competition and inspire others to enjoy STEM as much as we do."))),r.a.createElement(L.a,{id:"FLLPathfinders7885Mission"},r.a.createElement("div",null,r.a.createElement("h1",null,"Our Mission"),r.a.createElement("p",null,"Our mission is to understand and promote STEM as best we can by building and programming a robot and completing a research project through the use of teamwork and FLL's Core Values."))),r.a.createElement(L.a,{id:"FLLPathfinders7885Finances"},r.a.createElement("div",null,r.a.createElement("h1",null,"Finances"),r.a.createElement("h2",null,"How We Spend Our Money"),r.a.createElement("p",null,"Each season, we must pay for..."),r.a.createElement(R.a,null,r.a.createElement(R.a.Item,null,"Robot Game Mat"),r.a.createElement(R.a.Item,null,"Robot Game Elements"),r.a.createElement(R.a.Item,null,"Project Components"),r.a.createElement(R.a.Item,null,"FLL Registration"),r.a.createElement(R.a.Item,null,"Competition Entrance Fees"),r.a.createElement(R.a.Item,null,"Team T-shirts")),r.a.createElement("h2",null,"How Donations Make A Difference"),r.a.createElement("p",null,"Donations allow us to compete in more competitions and upgrade robot parts and equipment. Donations also allow us to reach out to the community to make connections and work with experts. Finally, we use donations to promote STEM alongside the FTC Pathfinders 13497. None of this would be possible without the many donations from our gracious sponsors."))),r.a.createElement(L.a,{id:"FLLPathfinders7885Team"},r.a.createElement("div",null,r.a.createElement("h1",null,"Meet The Team"),r.a.createElement("br",null),r.a.createElement(b,{src:q.a,alt:"Gabriella Avilez"}),r.a.createElement("h3",null,"Gabriella Avilez"),r.a.createElement("br",null),r.a.createElement(b,{src:H.a,alt:"Carson Baty"}),r.a.createElement("h3",null,"Carson Baty, Builder, Programmer"),r.a.createElement("br",null),r.a.createElement(b,{src:Y.a,alt:"Mason Hayes"}),r.a.createElement("h3",null,"Mason Hayes"),r.a.createElement("br",null),r.a.createElement(b,{src:K.a,alt:"Samu Mogallapalli"}),r.a.createElement("h3",null,"Samu Mogallapalli, Builder, Programmer"),r.a.createElement("br",null),r.a.createElement(b,{src:x.a,alt:"Andrew Scott"}),r.a.createElement("h3",null,"Andrew Scott, Builder, Programmer"),r.a.createElement("br",null),r.a.createElement(b,{src:z.a,alt:"Erin Stone"}),r.a.createElement("h3",null,"Erin Stone, Project Captain"),r.a.createElement("br",null)))),r.a.createElement(F.a,{md:"auto",className:"sidenav"},this.state.width>775?Z:null,r.a.createElement(k.a,{variant:"primary",href:"#FLLPathfinders7885",className:"backToTop"},"Back to top")))))}}]),t}(n.Component),$=a(105),ee=a.n($),te=a(106),ae=a.n(te),ne=a(107),re=a.n(ne),oe=a(108),ie=a.n(oe),se=a(109),le=a.n(se),ce=a(110),ue=a.n(ce),me=a(111),de=a.n(me),he=a(112),pe=a.n(he),fe=a(113),Ee=a.n(fe),be=a(114),Ae=a.n(be),ge=a(115),ye=a.n(ge),ve=a(116),we=a.n(ve),Te=a(117),Se=a.n(Te),Ce=a(118),Oe=a.n(Ce),Ne=a(119),Ie=a.n(Ne),je=a(120),Pe=a.n(je),Fe=a(121),Re=a.n(Fe),ke=a(122),De=a.n(ke),Le=a(123),Me=a.n(Le),We=a(124),xe=a.n(We),Ve=a(125),He=a.n(Ve),Be=a(126),ze=a.n(Be),Qe=a(127),qe=a.n(Qe),Ue=a(128),Ye=a.n(Ue),Ge=a(129),Ke=a.n(Ge),Xe=a(130),Je=a.n(Xe),Ze=a(131),_e=a.n(Ze),$e=a(132),et=a.n($e),tt=a(133),at=a.n(tt),nt=a(134),rt=a.n(nt),ot=a(135),it=a.n(ot),st=a(136),lt=a(209),ct=function(e){function t(){return Object(s.a)(this,t),Object(c.a)(this,Object(u.a)(t).apply(this,arguments))}return Object(m.a)(t,e),Object(l.a)(t,[{key:"render",value:function(){return r.a.createElement("div",null,r.a.createElement(st.a,{videoId:"M-0EsbbQUv4",opts:{width:"100%",playerVars:{cc_lang_pref:"en",hl:"en",modestBranding:1,origin:window.location,rel:0},origin:"https://"+window.location.hostname}}),r.a.createElement("p",null,"Our complete sponsorship pamphlet is ",r.a.createElement("a",{href:"/assets/FTC13497SponsorshipPamphlet.pdf",download:!0},"here"),"."))}}]),t}(n.Component),ut=function(e){function t(){return Object(s.a)(this,t),Object(c.a)(this,Object(u.a)(t).apply(this,arguments))}return Object(m.a)(t,e),Object(l.a)(t,[{key:"render",value:function(){return r.a.createElement("div",null,r.a.createElement(lt.a,{bordered:!0,hover:!0,responsive:!0},r.a.createElement("thead",null,r.a.createElement("tr",null,r.a.createElement("th",null,"Benefits"),r.a.createElement("th",null,"$1000+","\n","Platinum"),r.a.createElement("th",null,"$500+","\n","Gold"),r.a.createElement("th",null,"$250+","\n","Silver"),r.a.createElement("th",null,"$100+","\n","Bronze"))),r.a.createElement("tbody",null,r.a.createElement("tr",null,r.a.createElement("td",null,"Team Photo"),r.a.createElement("td",{className:"yesStyle"},"YES"),r.a.createElement("td",{className:"yesStyle"},"YES"),r.a.createElement("td",{className:"yesStyle"},"YES"),r.a.createElement("td",{className:"yesStyle"},"YES"))),r.a.createElement("tbody",null,r.a.createElement("tr",null,r.a.createElement("td",null,"Letter of Gratitude"),r.a.createElement("td",{className:"yesStyle"},"YES"),r.a.createElement("td",{className:"yesStyle"},"YES"),r.a.createElement("td",{className:"yesStyle"},"YES"),r.a.createElement("td",{className:"yesStyle"},"YES"))),r.a.createElement("tbody",null,r.a.createElement("tr",null,r.a.createElement("td",null,"Listed as sponsor on website"),r.a.createElement("td",{className:"yesStyle"},"YES"),r.a.createElement("td",{className:"yesStyle"},"YES"),r.a.createElement("td",{className:"yesStyle"},"YES"),r.a.createElement("td",{className:"yesStyle"},"YES"))),r.a.createElement("tbody",null,r.a.createElement("tr",null,r.a.createElement("td",null,"Listed as sponsor on trifold"),r.a.createElement("td",{className:"yesStyle"},"YES"),r.a.createElement("td",{className:"yesStyle"},"YES"),r.a.createElement("td",{className:"yesStyle"},"YES"),r.a.createElement("td",{className:"yesStyle"},"YES"))),r.a.createElement("tbody",null,r.a.createElement("tr",null,r.a.createElement("td",null,"Listed as sponsor on social media"),r.a.createElement("td",{className:"yesStyle"},"YES"),r.a.createElement("td",{className:"yesStyle"},"YES"),r.a.createElement("td",{className:"yesStyle"},"YES"),r.a.createElement("td",{className:"noStyle"},"NO"))),r.a.createElement("tbody",null,r.a.createElement("tr",null,r.a.createElement("td",null,"Listed as sponsor on t-shirt*, normal size"),r.a.createElement("td",{className:"yesStyle"},"YES"),r.a.createElement("td",{className:"yesStyle"},"YES"),r.a.createElement("td",{className:"noStyle"},"NO"),r.a.createElement("td",{className:"noStyle"},"NO"))),r.a.createElement("tbody",null,r.a.createElement("tr",null,r.a.createElement("td",null,"Listed as sponsor on robot"),r.a.createElement("td",{className:"yesStyle"},"YES"),r.a.createElement("td",{className:"yesStyle"},"YES"),r.a.createElement("td",{className:"noStyle"},"NO"),r.a.createElement("td",{className:"noStyle"},"NO"))),r.a.createElement("tbody",null,r.a.createElement("tr",null,r.a.createElement("td",null,"Listed as sponsor on t-shirt*, large"),r.a.createElement("td",{className:"yesStyle"},"YES"),r.a.createElement("td",{className:"noStyle"},"NO"),r.a.createElement("td",{className:"noStyle"},"NO"),r.a.createElement("td",{className:"noStyle"},"NO")))),r.a.createElement("p",null,"*Donation must be received for September 31st, 2019, to receive their logo on our t-shirt"))}}]),t}(n.Component),mt=r.a.createElement(p.a,{variant:"pills",className:"flex-column",defaultActiveKey:"#FTCPathfinders13497"},r.a.createElement(p.a.Item,null,r.a.createElement(p.a.Link,{href:"#FTCPathfinders13497"},"FTC Pathfinders 13497")),r.a.createElement(p.a.Item,null,r.a.createElement(p.a.Link,{href:"#FTCPathfinders13497Sponsors"},"Sponsors")),r.a.createElement(p.a.Item,null,r.a.createElement(p.a.Link,{href:"#FTCPathfinders13497Mission"},"Mission")),r.a.createElement(p.a.Item,null,r.a.createElement(p.a.Link,{href:"#FTCPathfinders13497Events"},"Events")),r.a.createElement(p.a.Item,null,r.a.createElement(p.a.Link,{href:"#FTCPathfinders13497Finances"},"Finances")),r.a.createElement(p.a.Item,null,r.a.createElement(p.a.Link,{href:"#FTCPathfinders13497Needs"},"\xa0\xa0\xa0\xa0Needs")),r.a.createElement(p.a.Item,null,r.a.createElement(p.a.Link,{href:"#FTCPathfinders13497Wants"},"\xa0\xa0\xa0\xa0Wants")),r.a.createElement(p.a.Item,null,r.a.createElement(p.a.Link,{href:"#FTCPathfinders13497Team"},"Meet the Team"))),dt=function(e){function t(e){var a;return Object(s.a)(this,t),(a=Object(c.a)(this,Object(u.a)(t).call(this,e))).state={width:window.innerWidth,selected:"FTC Pathfinders 13497"},a.updateWindowWidth=a.updateWindowWidth.bind(Object(I.a)(a)),a}return Object(m.a)(t,e),Object(l.a)(t,[{key:"componentWillMount",value:function(){Object(D.configureAnchors)({offset:-90}),window.addEventListener("resize",this.updateWindowWidth)}},{key:"componentDidMount",value:function(){N.a.pageview(window.location.pathname),this.updateWindowWidth()}},{key:"componentWillUnmount",value:function(){window.removeEventListener("resize",this.updateWindowWidth)}},{key:"updateWindowWidth",value:function(){this.setState({width:window.innerWidth}),this.state.width<775?Object(D.configureAnchors)({offset:-190}):Object(D.configureAnchors)({offset:-90})}},{key:"render",value:function(){var e=this;return r.a.createElement("div",{id:"13479"},r.a.createElement(M.Helmet,null,r.a.createElement("title",null,"FTC Pathfinders 13497 | Pathfinders Robotics"),r.a.createElement("meta",{name:"description",content:"Our team began with an FLL team (7885 Masterbuilders). Sabarish and Owen were on FLL team Masterbuilders from 2014-2017. Their first season, the team ad..."})),r.a.createElement(j.a,{fluid:!0},r.a.createElement(P.a,null,r.a.createElement(F.a,{className:"teamArticle standardFont"},r.a.createElement(L.a,{id:"FTCPathfinders13497"},r.a.createElement("div",null,this.state.width<775?r.a.createElement("div",null,r.a.createElement("div",{className:"mobileBar"},r.a.createElement(p.a,{variant:"pills",className:"flex-column frozen"},r.a.createElement(f.a,{title:this.state.selected},r.a.createElement(f.a.Item,{href:"#FTCPathfinders13497",onClick:function(){return e.setState({selected:"FTC Pathfinders 13497"})}},"FTC Pathfinders 13497"),r.a.createElement(f.a.Item,{href:"#FTCPathfinders13497Sponsors",onClick:function(){return e.setState({selected:"Sponsors"})}},"Sponsors"),r.a.createElement(f.a.Item,{href:"#FTCPathfinders13497Mission",onClick:function(){return e.setState({selected:"Mission"})}},"Mission"),r.a.createElement(f.a.Item,{href:"#FTCPathfinders13497Events",onClick:function(){return e.setState({selected:"Events"})}},"Events"),r.a.createElement(f.a.Item,{href:"#FTCPathfinders13497Finances",onClick:function(){return e.setState({selected:"Finances"})}},"Finances"),r.a.createElement(f.a.Item,{href:"#FTCPathfinders13497Needs",onClick:function(){return e.setState({selected:"Needs"})}},"\xa0\xa0\xa0\xa0Needs"),r.a.createElement(f.a.Item,{href:"#FTCPathfinders13497Wants",onClick:function(){return e.setState({selected:"Wants"})}},"\xa0\xa0\xa0\xa0Wants"),r.a.createElement(f.a.Item,{href:"#FTCPathfinders13497Team",onClick:function(){return e.setState({selected:"Meet the Team"})}},"Meet the Team")))),r.a.createElement("br",null),r.a.createElement("br",null)):null,r.a.createElement("h1",null,"FTC Pathfinders 13497"),r.a.createElement(b,{src:ee.a,alt:"FTC Pathfinders 13497",responsive:"true"}),r.a.createElement("p",null,"Back (left to right): Arya Karnik, Benjamin Avilez, Katie Morrison, Sabarish Mogallapalli, Kristen Stone, Shriya Magatapalli"),r.a.createElement("p",null,"Front (left to right): Owen Scott, Sean Eastman"),r.a.createElement("p",null,"Our team began with an FLL team (7885 Masterbuilders). Sabarish and Owen were on FLL team Masterbuilders from 2014-2017. Their first season, the team advanced to the Iowa State Championships. The following year, Shriya joined the team. While we did not advance to state that season, we created an app concept. During the summer of 2016, Katie and Sean joined the team and we entered the app concept in the Verizon App Challenge, winning Best In State and $5000 for our school. Sabarish, Shriya, Owen, and Sankalp (a senior at Waukee High School) wrote the app in 2017 and won the Congressional App Challenge with it. We were invited to share our app in Washington D.C. at the National House of Code. In our third and last year (2016-2017) in FLL, we again advanced to the Iowa State Championships, this time winning a Project Innovation award."),r.a.createElement("p",null,"The FLL team morphed into an FTC team, and Arya, Benjamin, and Kristen joined. We won the Connect award and finalized in the Motivate and Think awards at our league tournament. We advanced to the super qualifiers, where we won the Connect award again and went to state. This season, Sean, Arya, Owen, and Kristen entered the Congressional App Challenge with a new app and won. They were invited to the National House of Code in Washington D.C. to share their app with U.S. Congress."),r.a.createElement("p",null,"Our team is currently managed by our coaches Shannon and Bhooshan, who are two parent volunteers that have been volunteering with the team for the last two years. We are also mentored by Srinivas Magatapalli, who mentored us on our robot and game strategy."))),r.a.createElement(L.a,{id:"FTCPathfinders13497Sponsors"},r.a.createElement("div",null,r.a.createElement("h1",null,"Our Sponsors"),r.a.createElement(F.a,null,r.a.createElement(P.a,null,r.a.createElement(b,{src:Je.a,alt:"John Deere",width:"256px"}),r.a.createElement(b,{src:_e.a,alt:"McClure Engineering",width:"256px"})),r.a.createElement(P.a,null,r.a.createElement(b,{src:et.a,alt:"McCormack Distributing Co",width:"256px"}),r.a.createElement(b,{src:at.a,alt:"Metalforming",width:"256px"})),r.a.createElement(P.a,null,r.a.createElement(b,{src:rt.a,alt:"Rheinisch-Westf\xe4lisches Elektrizit\xe4tswerk AG",width:"256px"}),r.a.createElement(b,{src:it.a,alt:"Terracon",width:"256px"}))))),r.a.createElement(L.a,{id:"FTCPathfinders13497Mission"},r.a.createElement("div",null,r.a.createElement("h1",null,"Our Mission"),r.a.createElement("p",null,"Our team strives to motivate communities by connecting with professionals from various sectors and promoting STEM and FIRST to children, in addition to encouraging high-quality work, emphasizing the value of others, and respecting each other and the community."))),r.a.createElement(L.a,{id:"FTCPathfinders13497Events"},r.a.createElement("div",null,r.a.createElement("h1",null,"What's Happening With The FTC Pathfinders?"),r.a.createElement("br",null),r.a.createElement(b,{src:ae.a,alt:"Teaching a Java programming class at the Des Moines Area Community College Makerspace.",responsive:"true"}),r.a.createElement("p",null,"Teaching a Java programming class at the Des Moines Area Community College Makerspace."),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:re.a,alt:"Introduced FIRST and FTC to engineers at Marshalltown Trowel.",responsive:"true"}),r.a.createElement("p",null,"Introduced FIRST and FTC to engineers at Marshalltown Trowel."),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:ie.a,alt:"Kicked off the FTC season by meeting with other teams at Valley High School.",responsive:"true"}),r.a.createElement("p",null,"Kicked off the FTC season by meeting with other teams at Valley High School."),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:le.a,alt:"Helped raise money for the season by selling ice cream as well as introducing the kids to FIRST and our mission.",responsive:"true"}),r.a.createElement("br",null),r.a.createElement(b,{src:ue.a,alt:"Helped raise money for the season by selling ice cream as well as introducing the kids to FIRST and our mission.",responsive:"true"}),r.a.createElement("p",null,"Helped raise money for the season by selling ice cream as well as introducing the kids to FIRST and our mission."),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:de.a,alt:"Fundraised by bussing tables at Pizza Ranch.",responsive:"true"}),r.a.createElement("p",null,"Fundraised by bussing tables at Pizza Ranch."),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:pe.a,alt:"Meeting and touring the Virtual Reality lab with Iowa State University professor R. Rajagopalan along with the FLL Pathfinders 7885.",responsive:"true"}),r.a.createElement("p",null,"Meeting and touring the Virtual Reality lab with Iowa State University professor R. Rajagopalan along with the FLL Pathfinders 7885."),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:Ee.a,alt:"Teaching kids about FIRST, driving the robots, and how to join at the Ankeny STEM fest.",responsive:"true"}),r.a.createElement("p",null,"Teaching kids about FIRST, driving the robots, and how to join at the Ankeny STEM fest."),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:Ae.a,alt:"Working on our robot.",responsive:"true"}),r.a.createElement("p",null,"Working on our robot."),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:ye.a,alt:"Presenting our Java code to software professionals from all over Iowa at a Central Iowa Java User Groups meeting.",responsive:"true"}),r.a.createElement("p",null,"Presenting our Java code to software professionals from all over Iowa at a Central Iowa Java User Groups meeting."),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:we.a,alt:"Working on our robot.",responsive:"true"}),r.a.createElement("p",null,"Working on our robot."),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:Se.a,alt:"Arya, Kristen, Owen, and Sean won the Congressional App Challenge for the 2018-2019 season with their app, \u201cFastLane\u201d. David Young, a representative from Iowa, presented their award.",responsive:"true"}),r.a.createElement("p",null,"Arya, Kristen, Owen, and Sean won the Congressional App Challenge for the 2018-2019 season with their app, \u201cFastLane\u201d. David Young, a representative from Iowa, presented their award."),r.a.createElement("p",null,"You can learn more about their app ",r.a.createElement("a",{href:"https://www.youtube.com/watch?v=KiRCiVZOT8A"},"here"),"."),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:Oe.a,alt:"Volunteered as a team for the FLL competition at Timberline School.",responsive:"true"}),r.a.createElement("p",null,"Volunteered as a team for the FLL competition at Timberline School."),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:Ie.a,alt:"",responsive:"true"}),r.a.createElement("p",null,r.a.createElement("b",null,"Caption Needed")),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:Pe.a,alt:"",responsive:"true"}),r.a.createElement("p",null,r.a.createElement("b",null,"Caption Needed")),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:Re.a,alt:"",responsive:"true"}),r.a.createElement("p",null,r.a.createElement("b",null,"Caption Needed")),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:De.a,alt:"",responsive:"true"}),r.a.createElement("p",null,r.a.createElement("b",null,"Caption Needed")),r.a.createElement("br",null))),r.a.createElement(L.a,{id:"FTCPathfinders13497Finances"},r.a.createElement("div",null,r.a.createElement("h1",null,"Finances"),r.a.createElement(ct,null),r.a.createElement("h2",null,"How We Spend Our Money"),r.a.createElement("p",null,"Donations are used for our team to be able to improve our robot and the opportunities for local outreaches to increase the awareness of STEM. With the money graciously donated, our team is allowed to increase our robot performance along with equipment. We pride ourselves in being able to give back to the community by teaching and advocating for STEM using donations and sponsorships."),r.a.createElement("h2",null,"How Donations Make A Difference"),r.a.createElement("p",null,"Donations to our team allow us to develop our robot and continue to improve it. We also are able to connect to the community. With your gracious donations, we can set up classes and other outreach opportunities! We are able to continue to share our passion for providing students with STEM opportunities. Donations are vital for us to continue to provide these!"),r.a.createElement("h2",null,"Sponsorship Tiers"),r.a.createElement(ut,null))),r.a.createElement(L.a,{id:"FTCPathfinders13497Needs"},r.a.createElement("div",null,r.a.createElement("h2",null,"What We Need"),r.a.createElement("p",null,"Before we can compete in any competitions, all of our team members must register for FIRST Tech Challenge. Participating in FTC is costly. We must pay registration fees to participate in FTC and participation fees for every competition. There are additional costs that come with travel to and from competitions. Our ability to compete in FTC and promote STEM in our community would be nonexistent without the donations from our gracious sponsors and donors, including John Deere, Klinker Apps, and citizens in our communities."),r.a.createElement("br",null),r.a.createElement("p",null,"In order to compete in FTC, we must construct a robot that efficiently complete the season's challenge while withstanding anything thrown at it. To do so, it requires many high quality parts and innovation. While we pride ourselves on our creativity and innovation, we still need parts that will allow us to perform well in our competitions and to compete for the seasons to come. We can only purchase these parts because of the funding we receive from our generous sponsors and donors."),r.a.createElement("br",null),r.a.createElement("p",null,"Outreach and the promotion of FIRST and STEM are just as important to us as having a working competition robot. STEM is quickly becoming the future of the world as technology advances at blinding speeds. We enjoy reaching out to the youth and spreading awareness of how STEM impacts our world today. We've attended many STEMfests, gone to elementary schools to talk about STEM, and taught boy and girl scouts about STEM. We have also started and mentored many FIRST Lego League teams for kids who are interested in STEM but not yet old enough for FTC. Outreach requires plenty of funding in order to create activities for kids and an outreach robot for them to drive."))),r.a.createElement(L.a,{id:"FTCPathfinders13497Wants"},r.a.createElement("div",null,r.a.createElement("h2",null,"Our Wishlist"),r.a.createElement("p",null,"Our team would like to develop and construct an outreach robot, specially designed for introducing robotics to children and adults alike. We have compiled a wishlist of parts that will allow us to construct the robot. You can find the wishlist complete with parts and pricing ",r.a.createElement("a",{href:"https://docs.google.com/spreadsheets/d/10tHhmR1c0Hu8aR149vtQmZCs9qmD2DRIfzac6Ep_HzY"},"here"),"."))),r.a.createElement(L.a,{id:"FTCPathfinders13497Team"},r.a.createElement("div",null,r.a.createElement("h1",null,"Meet The Team"),r.a.createElement("br",null),r.a.createElement(b,{src:xe.a,alt:"Benjamin Avilez"}),r.a.createElement("h3",null,"Benjamin Avilez, Build lead, Driver"),r.a.createElement("a",{href:"mailto:benjamin.avilez@pathfindersrobotics.org"},"Contact"),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:Ye.a,alt:"Sean Eastman"}),r.a.createElement("h3",null,"Sean Eastman, Builder, Driver"),r.a.createElement("a",{href:"mailto:sean.eastman@pathfindersrobotics.org"},"Contact"),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:Me.a,alt:"Arya Karnik"}),r.a.createElement("h3",null,"Arya Karnik, Builder, Drive team coach, Finance manager"),r.a.createElement("a",{href:"mailto:arya.karnik@pathfindersrobotics.org"},"Contact"),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:Ke.a,alt:"Shriya Magatapalli"}),r.a.createElement("h3",null,"Shriya Magatapalli, Engineering Notebook lead, FLL mentor"),r.a.createElement("a",{href:"mailto:shriya.magatapalli@pathfindersrobotics.org"},"Contact"),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:qe.a,alt:"Sabarish Mogallapalli"}),r.a.createElement("h3",null,"Sabarish Mogallapalli, Outreach lead"),r.a.createElement("a",{href:"mailto:sabarish.mogallapalli@pathfindersrobotics.org"},"Contact"),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:ze.a,alt:"Owen Scott"}),r.a.createElement("h3",null,"Owen Scott, Captain, Builder, Driver, Software lead"),r.a.createElement("a",{href:"mailto:owen.scott@pathfindersrobotics.org"},"Contact"),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:He.a,alt:"Kristen Stone"}),r.a.createElement("h3",null,"Kristen Stone, CAD lead, Engineering Notebook writer, Programmer"),r.a.createElement("a",{href:"mailto:kristen.stone@pathfindersrobotics.org"},"Contact"),r.a.createElement("br",null)))),r.a.createElement(F.a,{md:"auto",className:"sidenav"},this.state.width>775?mt:null,r.a.createElement(k.a,{variant:"primary",href:"#FTCPathfinders13497",className:"backToTop"},"Back to top")))))}}]),t}(n.Component),ht=a(139),pt=a.n(ht),ft=function(e){function t(e){var a;return Object(s.a)(this,t),(a=Object(c.a)(this,Object(u.a)(t).call(this,e))).state={width:window.innerWidth,selected:"What Does Creating A Team Entail?"},a.updateWindowWidth=a.updateWindowWidth.bind(Object(I.a)(a)),a}return Object(m.a)(t,e),Object(l.a)(t,[{key:"componentWillMount",value:function(){Object(D.configureAnchors)({offset:-90}),window.addEventListener("resize",this.updateWindowWidth)}},{key:"componentDidMount",value:function(){N.a.pageview(window.location.pathname),this.updateWindowWidth()}},{key:"componentWillUnmount",value:function(){window.removeEventListener("resize",this.updateWindowWidth)}},{key:"updateWindowWidth",value:function(){this.setState({width:window.innerWidth}),this.state.width<775?Object(D.configureAnchors)({offset:-190}):Object(D.configureAnchors)({offset:-90})}},{key:"render",value:function(){return r.a.createElement("div",{id:"create",className:"centerThis"},r.a.createElement(b,{src:pt.a,alt:"Under Construction",responsive:"true"}))}}]),t}(n.Component),Et=a(79),bt=a.n(Et),At=a(40),gt=a(217),yt=a(212),vt=a(140),wt=a(18),Tt=a(141),St=a(215),Ct=/^(?:A[KLRSZ]|C[AOT]|D[CE]|FL|G[AU]|HI|I[ADLN]|K[SY]|LA|M[ADEINOPST]|N[CDEHJMVY]|O[HKR]|P[AR]|RI|S[CD]|T[NX]|UT|V[AIT]|W[AIVY])*$/i,Ot=/^(?:ALABAMA|ALASKA|ARIZONA|ARKANSAS|CALIFORNIA|COLORADO|CONNECTICUT|DELAWARE|FLORIDA|GEORGIA|HAWAII|IDAHO|ILLINOIS|INDIANA|IOWA|KANSAS|KENTUCKY|LOUISIANA|MAINE|MARYLAND|MASSACHUSETTS|MICHIGAN|MINNESOTA|MISSISSIPPI|MISSOURI|MONTANA|NEBRASKA|NEVADA|NEW\sHAMPSHIRE|NEW\sJERSEY|NEW\sMEXICO|NEW\sYORK|NORTH\sCAROLINA|NORTH\sDAKOTA|OHIO|OKLAHOMA|OREGON|PENNSYLVANIA|RHODE\sISLAND|SOUTH\sCAROLINA|SOUTH\sDAKOTA|TENNESSEE|TEXAS|UTAH|VERMONT|VIRGINIA|WASHINGTON|WEST\sVIRGINIA|WASHINGTON\sDC|WASHINGTON\sD\.C\.|DISTRICT\sOF\sCOLUMBIA|AMERICAN\sSAMOA|SAMOA|GUAM|NORTHERN\sMARIANA\sISLANDS|NORTHERN\sMARIANA|MARIANA\sISLANDS|MARIANA|PUERTO\sRICO|VIRGIN\sISLANDS|VIRGIN)/i,Nt=/^([0-9]{5}(?:-[0-9]{4})?)*$/,It=/^[^\s@]+@[^\s@]+\.[^\s@]+$/i,jt=/(\+1\s\([0-9]{3}\)\s[0-9]{3}-[0-9]{4})/,Pt=["+","1"," ","(",/[1-9]/,/\d/,/\d/,")"," ",/\d/,/\d/,/\d/,"-",/\d/,/\d/,/\d/,/\d/],Ft="Please enter a valid ",Rt=".";function kt(e){if(null!=e){var t=Ft+e+Rt;return{required:t,pattern:t,type:t,step:t,minLength:t,min:t,max:t,fileType:t,maxFileSize:t,validator:t}}return null}var Dt=kt("name"),Lt=kt("address"),Mt=kt("city"),Wt=kt("state"),xt=kt("zip / postal code"),Vt=kt("email"),Ht=kt("phone number"),Bt=function(e){function t(e){var a;return Object(s.a)(this,t),(a=Object(c.a)(this,Object(u.a)(t).call(this,e))).state={name:"",addr1:"",addr2:"",city:"",state:"",zip:"",email:"",phone:"",contactAllowed:!0,formValid:!1},a.onSubmit=a.onSubmit.bind(Object(I.a)(a)),a.onErrorSubmit=a.onErrorSubmit.bind(Object(I.a)(a)),a.updateTabs=a.updateTabs.bind(Object(I.a)(a)),a.updateVal=a.updateVal.bind(Object(I.a)(a)),a.updateState=a.updateState.bind(Object(I.a)(a)),a.updatePhone=a.updatePhone.bind(Object(I.a)(a)),a.twoLetterStateFromFullState=a.twoLetterStateFromFullState.bind(Object(I.a)(a)),a}return Object(m.a)(t,e),Object(l.a)(t,[{key:"onSubmit",value:function(e,t){e.preventDefault(),e.stopPropagation(),this.setState({formValid:!0},this.updateTabs)}},{key:"onErrorSubmit",value:function(e,t){e.preventDefault(),e.stopPropagation(),this.setState({formValid:!1},this.updateTabs)}},{key:"updateTabs",value:function(){var e=this;this.state.formValid?this.props.enablePayment((function(){return e.props.setData({name:e.state.name,addr1:e.state.addr1,addr2:e.state.addr2,city:e.state.city,state:e.state.state.length>2?e.twoLetterStateFromFullState(e.state.state):e.state.state,zip:e.state.zip,email:e.state.email,phone:e.state.phone,contactAllowed:e.state.contactAllowed,amount:e.props.getData().amount},e.props.advanceToFront)})):this.props.disablePayment()}},{key:"updateVal",value:function(e){this.setState(Object(vt.a)({},e.target.name,e.target.value))}},{key:"updateState",value:function(e){this.setState({state:e.target.value.replace(/[^a-zA-Z.]+/g,"")})}},{key:"updatePhone",value:function(e){this.setState({phone:Object(Tt.conformToMask)(e.target.value,Pt,{guide:!1}).conformedValue})}},{key:"render",value:function(){var e=this;return r.a.createElement(wt.ValidationForm,{onSubmit:this.onSubmit,onErrorSubmit:this.onErrorSubmit,immediate:!1},r.a.createElement(St.a.Row,null,r.a.createElement("div",{className:"form-group"},r.a.createElement("label",{htmlFor:"name"},"Name"),r.a.createElement(wt.TextInput,{required:!0,id:"name",name:"name",type:"text",placeholder:"John Doe",errorMessage:Dt,value:this.state.name,onChange:this.updateVal}))),r.a.createElement(St.a.Row,null,r.a.createElement("div",{className:"form-group"},r.a.createElement("label",{htmlFor:"addr1"},"Address Line 1"),r.a.createElement(wt.TextInput,{required:!0,id:"addr1",name:"addr1",type:"text",placeholder:"12345 Artificial Dr",errorMessage:Lt,value:this.state.addr1,onChange:this.updateVal})),"\xa0",r.a.createElement("div",{className:"form-group"},r.a.createElement("label",{htmlFor:"addr2"},"Address Line 2"),r.a.createElement(wt.TextInput,{id:"addr2",name:"addr2",type:"text",placeholder:"Apt. 27",errorMessage:Lt,value:this.state.addr2,onChange:this.updateVal}))),r.a.createElement(St.a.Row,null,r.a.createElement("div",{className:"form-group"},r.a.createElement("label",{htmlFor:"city"},"City"),r.a.createElement(wt.TextInput,{required:!0,id:"city",name:"city",type:"text",placeholder:"City",errorMessage:Mt,value:this.state.city,onChange:this.updateVal})),"\xa0",r.a.createElement("div",{className:"form-group"},r.a.createElement("label",{htmlFor:"state"},"State"),r.a.createElement(wt.TextInput,{required:!0,validator:function(e){return Ct.test(e)||Ot.test(e)},id:"state",name:"state",type:"text",placeholder:"State",maxLength:"25",errorMessage:Wt,value:this.state.state,onChange:this.updateState})),"\xa0",r.a.createElement("div",{className:"form-group"},r.a.createElement("label",{htmlFor:"zip"},"Zip / Postal Code"),r.a.createElement(wt.TextInput,{required:!0,validator:function(e){return Nt.test(e)},id:"zip",name:"zip",type:"text",placeholder:"12345",maxLength:"5",errorMessage:xt,value:this.state.zip,onChange:function(t){return e.setState({zip:isNaN(t.target.value)?e.state.zip:t.target.value})}}))),r.a.createElement(St.a.Row,null,r.a.createElement("div",{className:"form-group"},r.a.createElement("label",{htmlFor:"email"},"Email"),r.a.createElement(wt.TextInput,{required:!0,validator:function(e){return It.test(e)},id:"email",name:"email",type:"email",placeholder:"johndoe@domain.tld",errorMessage:Vt,value:this.state.email,onChange:this.updateVal})),"\xa0",r.a.createElement("div",{className:"form-group"},r.a.createElement("label",{htmlFor:"phone"},"Phone Number"),r.a.createElement(wt.TextInput,{required:!0,validator:function(e){return jt.test(e)},id:"phone",name:"phone",type:"tel",placeholder:"+1 (123) 456-7890",errorMessage:Ht,value:this.state.phone,onChange:this.updatePhone}))),r.a.createElement(St.a.Row,null,r.a.createElement("div",{className:"form-group"},r.a.createElement(wt.Checkbox,{id:"contactAllowed",name:"contactAllowed",type:"checkbox",value:this.state.contactAllowed,onChange:function(t,a){e.setState({contactAllowed:a})},label:"Receive updates from donation recipients throughout the season"}))),r.a.createElement(St.a.Row,null,r.a.createElement("div",null,r.a.createElement(k.a,{type:"submit"},"Submit"))))}},{key:"twoLetterStateFromFullState",value:function(e){switch(e.toUpperCase()){case"ALABAMA":return"AL";case"ALASKA":return"AK";case"ARIZONA":return"AZ";case"ARKANSAS":return"AK";case"CALIFORNIA":return"CA";case"COLORADO":return"CO";case"CONNECTICUT":return"CT";case"DELAWARE":return"DE";case"FLORIDA":return"FL";case"GEORGIA":return"GA";case"HAWAII":return"HI";case"IDAHO":return"ID";case"ILLINOIS":return"IL";case"INDIANA":return"IN";case"IOWA":return"IA";case"KANSAS":return"KS";case"KENTUCKY":return"KY";case"LOUISIANA":return"LA";case"MAINE":return"ME";case"MARYLAND":return"MD";case"MASSACHUSETTS":return"MA";case"MICHIGAN":return"MI";case"MINNESOTA":return"MN";case"MISSISSIPPI":return"MS";case"MISSOURI":return"MO";case"MONTANA":return"MT";case"NEBRASKA":return"NE";case"NEVADA":return"NV";case"NEW HAMPSHIRE":return"NH";case"NEW JERSEY":return"NJ";case"NEW MEXICO":return"NM";case"NEW YORK":return"NY";case"NORTH CAROLINA":return"NC";case"NORTH DAKOTA":return"ND";case"OHIO":return"OH";case"OKLAHOMA":return"OK";case"OREGON":return"OR";case"PENNSYLVANIA":return"PA";case"RHODE ISLAND":return"RI";case"SOUTH CAROLINA":return"SC";case"SOUTH DAKOTA":return"SD";case"TENNESSEE":return"TN";case"TEXAS":return"TX";case"UTAH":return"UT";case"VERMONT":return"VT";case"VIRGINIA":return"VA";case"WASHINGTON":return"WA";case"WEST VIRGINIA":return"WV";case"WISCONSIN":return"WI";case"WYOMING":return"WY";case"WASHINGTON DC":case"WASHINGTON D.C.":case"DISTRICT OF COLUMBIA":return"DC";case"AMERICAN SAMOA":case"SAMOA":return"AS";case"GUAM":return"GU";case"NORTHERN MARIANA ISLANDS":case"NORTHERN MARIANA":case"MARIANA ISLANDS":case"MARIANA":return"MP";case"PUERTO RICO":return"PR";case"VIRGIN ISLANDS":case"VIRGIN":return"VI";default:return null}}}]),t}(n.Component),zt=a(37),Qt=a.n(zt),qt=a(54),Ut=a(210),Yt=a(211),Gt=a(32),Kt=a(214),Xt=a(88);n.Component,n.Component;function Jt(){return(Jt=Object(qt.a)(Qt.a.mark((function e(t){var a;return Qt.a.wrap((function(e){for(;;)switch(e.prev=e.next){case 0:if(!navigator.onLine){e.next=7;break}if((a=new XMLHttpRequest).open("POST","/getSecret",!1),a.setRequestHeader("Content-Type","application/json"),a.send(JSON.stringify(t)),200!==a.status){e.next=7;break}return e.abrupt("return",JSON.parse(a.responseText).secret);case 7:return e.abrupt("return",null);case 8:case"end":return e.stop()}}),e)})))).apply(this,arguments)}function Zt(){return(Zt=Object(qt.a)(Qt.a.mark((function e(t,a){var n,r,o;return Qt.a.wrap((function(e){for(;;)switch(e.prev=e.next){case 0:if(!navigator.onLine){e.next=15;break}if((n=new XMLHttpRequest).open("POST","/paymentRequest",!1),n.setRequestHeader("Content-Type","application/json"),r={},Object.assign(r,a),Object.assign(r,{token:t.id}),n.send(JSON.stringify(r)),200!==n.status){e.next=15;break}if(null===(o=JSON.parse(n.responseText))||void 0===o){e.next=14;break}return e.abrupt("return",!0===o.success);case 14:return e.abrupt("return",!1);case 15:return e.abrupt("return",null);case 16:case"end":return e.stop()}}),e)})))).apply(this,arguments)}function _t(){return(_t=Object(qt.a)(Qt.a.mark((function e(t){var a;return Qt.a.wrap((function(e){for(;;)switch(e.prev=e.next){case 0:(a=new XMLHttpRequest).open("POST","/paymentEmail",!0),a.setRequestHeader("Content-Type","application/json"),a.send(JSON.stringify(t));case 4:case"end":return e.stop()}}),e)})))).apply(this,arguments)}var $t=function(e){function t(){return Object(s.a)(this,t),Object(c.a)(this,Object(u.a)(t).apply(this,arguments))}return Object(m.a)(t,e),Object(l.a)(t,[{key:"render",value:function(){return r.a.createElement("svg",{className:"checkmark",xmlns:"https://www.w3.org/2000/svg",viewBox:"0 0 52 52"},r.a.createElement("circle",{className:"checkmark__circle",cx:"26",cy:"26",r:"25",fill:"none"}),r.a.createElement("path",{className:"checkmark__check",fill:"none",d:"M14.1 27.2l7.1 7.2 16.7-16.8"}))}}]),t}(n.Component),ea=/^[0-9]+(\.[0-9]{1,2})?$/,ta="FTC Pathfinders 13497",aa=function(e){function t(e){var a;return Object(s.a)(this,t),(a=Object(c.a)(this,Object(u.a)(t).call(this,e))).state={amount:"",radioAmount:"",amountValid:!1,team:ta,donateInfo:r.a.createElement(ct,null),teamValid:!0,paymentType:"Payment Type",paymentTypeValid:!1,formValid:!1,showConfirmation:!1,paymentInProgress:!1,dots:".",payDisabled:!1,paid:!1,canMakePayment:null,cardElement:null,cardReady:!1,redirect:!1},a.props.setTiers(r.a.createElement(ut,null)),a.onSubmit=a.onSubmit.bind(Object(I.a)(a)),a.onErrorSubmit=a.onErrorSubmit.bind(Object(I.a)(a)),a.updateAmount=a.updateAmount.bind(Object(I.a)(a)),a.updateRadioChoice=a.updateRadioChoice.bind(Object(I.a)(a)),a.pay=a.pay.bind(Object(I.a)(a)),a.updateDots=a.updateDots.bind(Object(I.a)(a)),a.getButton=a.getButton.bind(Object(I.a)(a)),a.genPaymentRequest=a.genPaymentRequest.bind(Object(I.a)(a)),a}return Object(m.a)(t,e),Object(l.a)(t,[{key:"componentDidMount",value:function(){N.a.pageview(window.location.pathname+"/PaymentForm")}}]),Object(l.a)(t,[{key:"onSubmit",value:function(e,t){e.preventDefault(),e.stopPropagation(),this.setState({amountValid:!0,formValid:this.state.teamValid&&this.state.paymentTypeValid})}},{key:"onErrorSubmit",value:function(e,t){e.preventDefault(),e.stopPropagation(),this.setState({amountValid:!1,formValid:!1})}},{key:"updateAmount",value:function(e){var t=e.target.value.replace(/[^0-9.]+/g,""),a=parseFloat(t),n=ea.test(t)&&a>=.5&&a<=999999.99,r=this.state.radioAmount;"50"!==t&&"50.0"!==t&&"50.00"!==t&&"100"!==t&&"100.0"!==t&&"100.00"!==t&&"250"!==t&&"250.0"!==t&&"250.00"!==t&&"500"!==t&&"500.0"!==t&&"500.00"!==t&&"1000"!==t&&"1000.0"!==t&&"1000.00"!==t&&"5000"!==t&&"5000.0"!==t&&"5000.00"!==t&&""!==t&&(r="Custom Amount"),this.setState({amount:t,radioAmount:r,amountValid:n,formValid:!!n&&(this.state.teamValid&&this.state.paymentTypeValid)})}},{key:"updateRadioChoice",value:function(e){var t=this,a=this.state.amount.replace(/[^0-9.]+/g,""),n=parseFloat(a),r=ea.test(a)&&n>=.5&&n<=999999.99;this.setState({amount:"Custom Amount"!==e.target.value?e.target.value:this.state.amount,radioAmount:e.target.value,amountValid:r,formValid:r&&this.state.teamValid&&this.state.paymentTypeValid},(function(){return t.updateAmount({target:{value:t.state.radioAmount}})}))}},{key:"pay",value:function(e){var t=this;if(e.preventDefault(),e.stopPropagation(),this.state.formValid){var a=this.props.getData(),n={amount:parseFloat(100*this.state.amount),description:"Gracious donation of ".concat(this.state.amount," by ").concat(this.state.paymentType," to ").concat(this.state.team,"."),name:a.name,addr1:a.addr1,addr2:a.addr2,city:a.city,state:a.state,zip:a.zip,email:a.email,phone:a.phone,contactAllowed:a.contactAllowed};(function(e){return Jt.apply(this,arguments)})(n).then((function(e){null!==e&&void 0!==e&&t.state.cardReady?t.props.stripe.handleCardPayment(e,t.state.cardElement,{payment_method_data:{billing_details:{address:{city:a.city,line1:a.addr1,line2:a.addr2,postal_code:a.zip,state:a.state},email:a.email,name:a.name,phone:a.phone}},receipt_email:a.email}).then((function(e){e.error?t.setState({showConfirmation:!1,payDisabled:!1,paymentInProgress:!1,paid:!1,error:"There's an issue with your payment. Please ensure your information is correct, try another payment method, or refresh the page and try again."}):(!function(e){_t.apply(this,arguments)}(Object.assign({},n,{paymentIntent:e.paymentIntent.id})),t.setState({paymentInProgress:!1,paid:!0},(function(){return window.setTimeout((function(){return t.setState({redirect:!0})}),2500)})))})):t.setState({showConfirmation:!1,payDisabled:!1,paymentInProgress:!1,paid:!1,error:"There's an issue with your payment. Please ensure your information is correct, try another payment method, or refresh the page and try again."})}))}}},{key:"updateDots",value:function(){var e=Object(qt.a)(Qt.a.mark((function e(){return Qt.a.wrap((function(e){for(;;)switch(e.prev=e.next){case 0:if(!this.state.paymentInProgress){e.next=11;break}e.t0=this.state.dots.length,e.next=1===e.t0?4:2===e.t0?6:8;break;case 4:return this.setState({dots:".."}),e.abrupt("break",10);case 6:return this.setState({dots:"..."}),e.abrupt("break",10);case 8:return this.setState({dots:"."}),e.abrupt("break",10);case 10:window.setTimeout(this.updateDots,750);case 11:case"end":return e.stop()}}),e,this)})));return function(){return e.apply(this,arguments)}}()},{key:"genPaymentRequest",value:function(){var e=this,t=this.props.stripe.paymentRequest({country:"US",currency:"usd",total:{label:"Donation to "+this.state.team,amount:100*parseFloat(this.state.amount)}});return t.on("token",(function(t){var a=t.complete,n=t.token,r=e.props.getData();(function(e,t){return Zt.apply(this,arguments)})(n,{amount:parseFloat(100*e.state.amount),description:"Gracious donation of ".concat(e.state.amount," by ").concat(e.state.paymentType," to ").concat(e.state.team,"."),name:r.name,addr1:r.addr1,addr2:r.addr2,city:r.city,state:r.state,zip:r.zip,email:r.email,phone:r.phone,contactAllowed:r.contactAllowed}).then((function(e){a(!0!==e?"Payment unsuccessful. Please refresh the page, try another method, or try again later.":"Thank you for your donation!")}))})),t.canMakePayment().then((function(t){e.setState({canMakePayment:!!t})})),t}},{key:"getButton",value:function(){var e,t,a=this;"Credit / Debit"===this.state.paymentType?(t=this.state.paymentInProgress?r.a.createElement("div",null,r.a.createElement(E.a,{as:"span",animation:"grow",size:"sm",role:"status","aria-hidden":"true"}),"\xa0 Donation in progress",this.state.dots):"Make Donation",e=r.a.createElement(k.a,{onClick:function(e){a.setState({paymentInProgress:!0,payDisabled:!0},(function(){return window.setTimeout(a.updateDots,750)})),a.pay(e)},variant:"outline-success",disabled:this.state.payDisabled},t)):e=null===this.state.canMakePayment?r.a.createElement(E.a,{animation:"border",variant:"primary",role:"status"}):this.state.canMakePayment?r.a.createElement(At.PaymentRequestButtonElement,{paymentRequest:this.state.paymentRequest,className:"PaymentRequestButton paymentRequestButtonWidth",style:{paymentRequestButton:{theme:"dark",type:"donate",height:"38px"}}}):r.a.createElement("p",{className:"redText verticallyCenterThis alignThisRight"},"This payment method is unavailable.");return e}},{key:"render",value:function(){var e=this;return r.a.createElement("div",null,this.state.redirect?r.a.createElement(Xt.a,{push:!0,to:"/"}):null,r.a.createElement(wt.ValidationForm,{onSubmit:this.onSubmit,onErrorSubmit:this.onErrorSubmit,immediate:!1},r.a.createElement(St.a.Row,null,r.a.createElement("div",{className:"form-group"},r.a.createElement("label",{htmlFor:"amount"},"Choose Your Amount (USD)"),r.a.createElement(wt.Radio.RadioGroup,{name:"amount",required:!0,errorMsg:"Please enter a valid amount.",valueSelected:this.state.radioAmount,onChange:this.updateRadioChoice,inline:!1},r.a.createElement(wt.Radio.RadioItem,{id:"50",label:"$50",value:"50"}),r.a.createElement(wt.Radio.RadioItem,{id:"100",label:"$100",value:"100"}),r.a.createElement(wt.Radio.RadioItem,{id:"250",label:"$250",value:"250"}),r.a.createElement(wt.Radio.RadioItem,{id:"500",label:"$500",value:"500"}),r.a.createElement(wt.Radio.RadioItem,{id:"1000",label:"$1000",value:"1000"}),r.a.createElement(wt.Radio.RadioItem,{id:"5000",label:"$5000",value:"5000"}),r.a.createElement(wt.Radio.RadioItem,{id:"custom",label:"Custom",value:"Custom Amount"})),r.a.createElement(wt.TextInputGroup,{required:"Custom Amount"===this.state.radioAmount,validator:"Custom Amount"===this.state.radioAmount?function(){return!0}:function(e){return ea.test(e)&&parseFloat(e)>=.5&&parseFloat(e)<=999999.99},id:"amount",name:"amount",type:"text",placeholder:"12.34",prepend:r.a.createElement("span",{className:"input-group-text"},"$"),errorMessage:kt("amount"),value:this.state.amount,onChange:this.updateAmount})))),r.a.createElement(Ut.a,{className:"mr-2"},r.a.createElement(Yt.a,{id:"paymentType",title:this.state.paymentType},r.a.createElement(Gt.a.Item,{onSelect:function(){return e.setState({paymentType:"Credit / Debit",paymentTypeValid:!0,formValid:e.state.amountValid&&e.state.teamValid})}},"Credit / Debit"),r.a.createElement(Gt.a.Item,{onSelect:function(){return e.setState({paymentType:"Apple Pay / Google Pay / Microsoft Pay",paymentTypeValid:!0,formValid:e.state.amountValid&&e.state.teamValid})}},"Apple / Google Pay"))),r.a.createElement("br",null),r.a.createElement("br",null),this.state.formValid&&"Credit / Debit"===this.state.paymentType?r.a.createElement(At.CardElement,{onReady:function(t){return e.setState({cardElement:t,cardReady:!0})}}):null,r.a.createElement("p",{className:"redText"},"***We do not accept American Express cards. We apologize for any inconvenience.***"),this.state.error?r.a.createElement("p",{className:"redText"},this.state.error):null,r.a.createElement("br",null),this.state.donateInfo,r.a.createElement(k.a,{type:"submit",disabled:!this.state.formValid,onClick:"Apple Pay / Google Pay / Microsoft Pay"!==this.state.paymentType?function(){return e.setState({showConfirmation:!0})}:function(){return e.setState({showConfirmation:!0,paymentRequest:e.genPaymentRequest()})}},"Make donation"),r.a.createElement(Kt.a,{show:this.state.showConfirmation,"aria-labelledby":"contained-modal-title-vcenter",centered:!0,scrollable:!0,keyboard:!1,backdrop:"static"},r.a.createElement(Kt.a.Header,null,r.a.createElement(Kt.a.Title,{id:"contained-modal-title-vcenter"},r.a.createElement(b,{src:y.a,width:"30",height:"30",className:"d-inline-block",alt:"Pathfinders Robotics Logo",text:"Payment Confirmation",helvetica:"true"}))),r.a.createElement(Kt.a.Body,null,this.state.paid?r.a.createElement("div",null,r.a.createElement($t,null),r.a.createElement("p",null,'This donation is tax deductible, and you can claim this deduction via our nonprofit, Pathfinders Robotics, with EIN "83-3047012".')):r.a.createElement("div",null,r.a.createElement("h4",null,"Please confirm your gracious donation of $",parseFloat(this.state.amount).toLocaleString()," by ","Credit / Debit"===this.state.paymentType.toLowerCase()?this.state.paymentType.toLocaleLowerCase():this.state.paymentType," to the ",this.state.team),r.a.createElement("p",null,"Pathfinders Robotics thanks you for your donation."))),r.a.createElement(Kt.a.Footer,null,r.a.createElement(k.a,{onClick:function(){return e.setState({showConfirmation:!1,payDisabled:!1})},variant:"outline-danger",disabled:this.state.paymentInProgress},"Cancel"),this.getButton())))}}]),t}(n.Component),na=Object(At.injectStripe)(aa),ra="information",oa=function(e){function t(e){var a;return Object(s.a)(this,t),(a=Object(c.a)(this,Object(u.a)(t).call(this,e))).state={activeKey:ra,paymentDisabled:!0,publicData:{name:"",addr1:"",addr2:"",city:"",state:"",zip:"",email:"",phone:"",amount:""}},a.onSelect=a.onSelect.bind(Object(I.a)(a)),a.advanceToFront=a.advanceToFront.bind(Object(I.a)(a)),a.disablePayment=a.disablePayment.bind(Object(I.a)(a)),a.enablePayment=a.enablePayment.bind(Object(I.a)(a)),a.getData=a.getData.bind(Object(I.a)(a)),a.setData=a.setData.bind(Object(I.a)(a)),a}return Object(m.a)(t,e),Object(l.a)(t,[{key:"onSelect",value:function(e){this.setState({activeKey:e,paymentDisabled:!!this.state.paymentDisabled||"payment"===this.state.activeKey&&e===ra})}},{key:"advanceToFront",value:function(){this.state.paymentDisabled?this.onSelect(ra):this.onSelect("payment")}},{key:"render",value:function(){return r.a.createElement(gt.a,{activeKey:this.state.activeKey,onSelect:this.onSelect},r.a.createElement(yt.a,{eventKey:ra,title:"Contact Information"},r.a.createElement("br",null),r.a.createElement(Bt,{disablePayment:this.disablePayment,enablePayment:this.enablePayment,getData:this.getData,setData:this.setData,advanceToFront:this.advanceToFront})),r.a.createElement(yt.a,{eventKey:"payment",title:"Payment",disabled:this.state.paymentDisabled},r.a.createElement("br",null),r.a.createElement(na,{getData:this.getData,setData:this.setData,advanceToFront:this.advanceToFront,setTiers:this.props.setTiers})))}},{key:"disablePayment",value:function(e){"function"!==typeof e?this.setState({paymentDisabled:!0}):this.setState({paymentDisabled:!0},e)}},{key:"enablePayment",value:function(e){"function"!==typeof e?this.setState({paymentDisabled:!1}):this.setState({paymentDisabled:!1},e)}},{key:"getData",value:function(){return this.state.publicData}},{key:"setData",value:function(e,t){"function"!==typeof t?this.setState({publicData:e}):this.setState({publicData:e},t)}}]),t}(n.Component),ia=Object(At.injectStripe)(oa),sa=function(e){function t(e){var a;return Object(s.a)(this,t),(a=Object(c.a)(this,Object(u.a)(t).call(this,e))).state={stripe:null},a}return Object(m.a)(t,e),Object(l.a)(t,[{key:"componentDidMount",value:function(){var e=this;window.Stripe?this.setState({stripe:window.Stripe("pk_live_y4v0gH9Y5JTSxRGZfC0PLYg100Y01489iZ")}):document.querySelector("#stripe-js").addEventListener("load",(function(){e.setState({stripe:window.Stripe("pk_live_y4v0gH9Y5JTSxRGZfC0PLYg100Y01489iZ")})}))}},{key:"render",value:function(){return r.a.createElement(At.StripeProvider,{stripe:this.state.stripe},r.a.createElement(At.Elements,null,r.a.createElement(ia,{setTiers:this.props.setTiers})))}}]),t}(n.Component),la="Pathfinders Robotics is a 501(c)3 nonprofit organization and shall operate exclusively for education and charitable purposes within the meaning of Section 501 (c)(3) of the Internal Revenue Code, or the corresponding section of any future Federal tax code. Pathfinders Robotics\u2019s purpose is to develop and teach youth in grades five through twelve Science, Technology, Engineering, and Math skills (STEM) and to develop teams to compete in FIRST Tech Challenge or other STEM related events in Iowa, Regional, National and, International competitions. Our purpose is to provide volunteer and fund-raising opportunities, per the discretion of the board of directors where the teams present and demonstrate STEM activities with other youth in order to have a greater impact for change. To maximize our impact on current efforts, we may seek to collaborate with other non-profit organizations which fall under the 501(c) (3) section of the internal revenue code and are operated exclusively for educational and charitable purposes.",ca="If your company also does charitable gift matching, please consider submitting your donation request to your organization as well. Our 501(c)3 EIN is 83-3047012 and our address is 390 SE Carefree Ln Waukee IA 50263 United States. Please contact Mr. Bhooshan Karnik at (515) 451-7270 for any additional information needed.",ua=function(e){function t(e){var a;return Object(s.a)(this,t),(a=Object(c.a)(this,Object(u.a)(t).call(this,e))).state={sponsorshipTiers:null},a.setSponsorshipTiers=a.setSponsorshipTiers.bind(Object(I.a)(a)),a}return Object(m.a)(t,e),Object(l.a)(t,[{key:"componentDidMount",value:function(){N.a.pageview(window.location.pathname)}},{key:"setSponsorshipTiers",value:function(e){this.setState({sponsorshipTiers:e})}},{key:"render",value:function(){return r.a.createElement("div",{id:"donate"},r.a.createElement(M.Helmet,null,r.a.createElement("title",null,"Donate | Pathfinders Robotics"),r.a.createElement("meta",{name:"description",content:"Pathfinders Robotics is a 501(c)3 nonprofit organization and shall operate exclusively for education and charitable purposes within the meaning of Secti..."})),r.a.createElement(j.a,{fluid:!0},r.a.createElement(bt.a,{minDeviceWidth:1224},r.a.createElement(P.a,null,r.a.createElement(F.a,null,r.a.createElement("h1",null,"Donate"),r.a.createElement("p",null,la),r.a.createElement("p",null,r.a.createElement("b",null,ca)),r.a.createElement("br",null),this.state.sponsorshipTiers),r.a.createElement(F.a,null,r.a.createElement(sa,{setTiers:this.setSponsorshipTiers}),r.a.createElement("br",null)))),r.a.createElement(bt.a,{maxDeviceWidth:1224},r.a.createElement(P.a,null,r.a.createElement("h1",null,"Donate"),r.a.createElement("p",null,la),r.a.createElement("p",null,r.a.createElement("b",null,ca)),r.a.createElement("br",null),this.state.sponsorshipTiers,r.a.createElement("br",null)),r.a.createElement(P.a,null,r.a.createElement(sa,{setTiers:this.setSponsorshipTiers}),r.a.createElement("br",null),r.a.createElement("br",null)))))}}]),t}(n.Component),ma=a(143),da=a.n(ma),ha=a(144),pa=a.n(ha),fa=a(145),Ea=a.n(fa),ba=a(146),Aa=a.n(ba),ga=a(147),ya=a.n(ga),va=a(148),wa=a.n(va),Ta=a(149),Sa=a.n(Ta),Ca=a(150),Oa=a.n(Ca),Na=a(151),Ia=a.n(Na),ja=a(152),Pa=a.n(ja),Fa=a(153),Ra=a.n(Fa),ka=a(154),Da=a.n(ka),La=a(155),Ma=a.n(La),Wa=r.a.createElement(p.a,{variant:"pills",className:"flex-column",defaultActiveKey:"#WhoWeAre"},r.a.createElement(p.a.Item,null,r.a.createElement(p.a.Link,{href:"#WhoWeAre"},"Who We Are")),r.a.createElement(p.a.Item,null,r.a.createElement(p.a.Link,{href:"#OurMission"},"Our Mission")),r.a.createElement(p.a.Item,null,r.a.createElement(p.a.Link,{href:"#Impact"},"Impact"))),xa=function(e){function t(e){var a;return Object(s.a)(this,t),(a=Object(c.a)(this,Object(u.a)(t).call(this,e))).state={width:window.innerWidth,selected:"Who We Are"},a.updateWindowWidth=a.updateWindowWidth.bind(Object(I.a)(a)),a}return Object(m.a)(t,e),Object(l.a)(t,[{key:"componentWillMount",value:function(){Object(D.configureAnchors)({offset:-90}),window.addEventListener("resize",this.updateWindowWidth)}},{key:"componentDidMount",value:function(){N.a.pageview(window.location.pathname),this.updateWindowWidth()}},{key:"componentWillUnmount",value:function(){window.removeEventListener("resize",this.updateWindowWidth)}},{key:"updateWindowWidth",value:function(){this.setState({width:window.innerWidth}),this.state.width<775?Object(D.configureAnchors)({offset:-190}):Object(D.configureAnchors)({offset:-90})}},{key:"render",value:function(){var e=this;return r.a.createElement("div",{id:"home"},r.a.createElement(M.Helmet,null,r.a.createElement("title",null,"Home | Pathfinders Robotics"),r.a.createElement("meta",{name:"description",content:"Pathfinders Robotics is a parent-run 501(c)3 nonprofit organization dedicated to providing support for student-led FIRST robotics teams."})),r.a.createElement(j.a,{fluid:!0},r.a.createElement(P.a,null,r.a.createElement(F.a,{className:"teamArticle standardFont"},r.a.createElement(L.a,{id:"WhoWeAre"},r.a.createElement("div",null,this.state.width<775?r.a.createElement("div",null,r.a.createElement("div",{className:"mobileBar"},r.a.createElement(p.a,{variant:"pills",className:"flex-column frozen"},r.a.createElement(f.a,{title:this.state.selected},r.a.createElement(f.a.Item,{href:"#WhoWeAre",onClick:function(){return e.setState({selected:"Who We Are"})}},"Who We Are"),r.a.createElement(f.a.Item,{href:"#OurMission",onClick:function(){return e.setState({selected:"Our Mission"})}},"Our Mission"),r.a.createElement(f.a.Item,{href:"#Impact",onClick:function(){return e.setState({selected:"Impact"})}},"Impact")))),r.a.createElement("br",null),r.a.createElement("br",null)):null,r.a.createElement("h1",null,"Who We Are"),r.a.createElement(b,{src:da.a,alt:"FTC Pathfinders 2018-2019",responsive:"true"}),r.a.createElement("p",null,"Originally founded in 2017 as a FIRST Tech Challenge Robotics Team, our team began to run a non profit organization as of March eighth of 2019. Pathfinders Robotics is a 501(c)3 nonprofit organization dedicated to providing support for FIRST robotics teams in our local area. We assist in the education of gracious professionalism, programming, and financial recommendations for other competing teams. We enjoy collaborating with both professionals and students alike to discover new and innovative solutions to solve challenges. In addition to the aforementioned collaborative exercises, our team enjoys sharing the knowledge we have accumulated over the years with our other FLL teams and FTC colleagues."))),r.a.createElement(L.a,{id:"OurMission"},r.a.createElement("div",null,r.a.createElement("h1",null,"Our Mission"),r.a.createElement(b,{src:pa.a,alt:"Pathfinders Robotics Mission",responsive:"true"}),r.a.createElement("p",null,"Pathfinders Robotics\u2019 mission is to develop and educate the youth in grades five through twelve. We teach information pertaining to Science, Technology, Engineering, and Math (STEM). Through guidance and feedback we assist in the development of teams to compete in FIRST Tech Challenge, First Lego League, or other STEM-related events in Iowa, Regional, National and International competitions.  We prioritize the spread of STEM,  FIRST, and general robotics, usually directed at a younger audience. We embody gracious professionalism through our interactions with our competitors, financial partners, and professional associates. Gracious professionalism is important to our organization as a whole as it personifies meaningful interactions that create lasting bonds in the STEM field."),r.a.createElement("p",null,"At Pathfinders Robotics, our mission is to support student-led FIRST teams as best we can through the use of our student mentors, web resources, gracious sponsors, and 501(c)3 nonprofit capabilities."))),r.a.createElement(L.a,{id:"Impact"},r.a.createElement("div",null,r.a.createElement("h1",null,"Impact"),r.a.createElement("p",null,"Our community newspaper reached out to us for an interview regarding our involvement in FTC, our impact on the community, and what we do as an organization. We were featured on the front cover and given four pages in the article. You can find the article ",r.a.createElement("a",{href:"https://mywaukee.com/tech-teens/",target:"_blank",rel:"noopener"},"here"),"."),r.a.createElement("br",null),r.a.createElement(b,{src:Ea.a,alt:"We were invited by a Cub Scout Troop to present about robotics to help them obtain a merit badge. We talked about FTC, FLL, FIRST, and how they all impact the community.",responsive:"true"}),r.a.createElement("p",null,"We were invited by a Cub Scout Troop to present about robotics to help them obtain a merit badge. We talked about FTC, FLL, FIRST, and how they all impact the community."),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:Aa.a,alt:"Following our Cub Scout outreach, we presented robotics and FIRST to the NUMBER Girl Scout troop. They enjoyed learning about STEM and our activities which involved STEM.",responsive:"true"}),r.a.createElement("p",null,"Following our Cub Scout outreach, we presented robotics and FIRST to the NUMBER Girl Scout troop. They enjoyed learning about STEM and our activities which involved STEM."),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:ya.a,alt:"Iowa State University Professor of Aerospace Engineering R. Rajagopalan met with the Phoenix Voyagers and discussed their solution for fires in outer space with them. The Phoenix Voyagers and the FTC Pathfinders also toured Iowa State\u2019s Virtual Reality Lab and met with several professionals there.",responsive:"true"}),r.a.createElement("p",null,"Iowa State University Professor of Aerospace Engineering R. Rajagopalan met with the Phoenix Voyagers and discussed their solution for fires in outer space with them. The Phoenix Voyagers and the FTC Pathfinders also toured Iowa State\u2019s Virtual Reality Lab and met with several professionals there."),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:wa.a,alt:"The FTC Pathfinders set up a booth at Ankeny STEM Fest and taught children and their parents about FIRST, FTC, FLL. The children had a lot of fun driving FTC and FLL robots.",responsive:"true"}),r.a.createElement("p",null,"The FTC Pathfinders set up a booth at Ankeny STEM Fest and taught children and their parents about FIRST, FTC, FLL. The children had a lot of fun driving FTC and FLL robots."),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:Sa.a,alt:"Our team was invited to present at CIJUG, we gave a presentation about our code and parts, demonstrated our robot, and explained our outreaches and volunteering.",responsive:"true"}),r.a.createElement("p",null,"Our team was invited to present at CIJUG, we gave a presentation about our code and parts, demonstrated our robot, and explained our outreaches and volunteering."),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:Oa.a,alt:"We hosted an informational meeting at one of our sponsor\u2019s branch along with our sister team the Phoenix Voyagers, we gave a presentation about FIRST, we explained the different aspects of FLL and FTC and showed them our robots.",responsive:"true"}),r.a.createElement("p",null,"We hosted an informational meeting at one of our sponsor\u2019s branch along with our sister team the Phoenix Voyagers, we gave a presentation about FIRST, we explained the different aspects of FLL and FTC and showed them our robots."),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:Ia.a,alt:"During the summer, we set up a booth at the Hindu Temple Cultural Center where we demonstrated our robot and educated children about robotics.",responsive:"true"}),r.a.createElement("br",null),r.a.createElement(b,{src:Pa.a,alt:"During the summer, we set up a booth at the Hindu Temple Cultural Center where we demonstrated our robot and educated children about robotics.",responsive:"true"}),r.a.createElement("p",null,"During the summer, we set up a booth at the Hindu Temple Cultural Center where we demonstrated our robot and educated children about robotics."),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:Ma.a,alt:"",responsive:"true"}),r.a.createElement("p",null,"Teams from Pathfinders Robotics volunteered at Meals From the Heartland to create hundreds of meals for those less fortunate. (FTC Pathfinders 13497, FLL Phoenix Voyagers 7885, and Jr. FLL Robo Robots)."),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:Ra.a,alt:"Teams from Pathfinders Robotics attended the Ankeny STEM fest to share their interest in robotics with the community.  (FTC Pathfinders 13497, FTC Circuit Breakers 10435, and FLL Phoenix Voyagers 7885).",responsive:"true"}),r.a.createElement("p",null,"Teams from Pathfinders Robotics attended the Ankeny STEM fest to share their interest in robotics with the community.  (FTC Pathfinders 13497, FTC Circuit Breakers 10435, and FLL Phoenix Voyagers 7885)."),r.a.createElement("br",null),r.a.createElement("br",null),r.a.createElement(b,{src:Da.a,alt:"Jeff Margrett, FIRST Senior Mentor and teams from Pathfinders Robotics attended Waukee Public Library \u201cBrick Builders\u201d to share their robots.  (FTC Pathfinders 13497, FTC Circuit Breakers 10435, and FLL Phoenix Voyagers 7885).",responsive:"true"}),r.a.createElement("p",null,"Jeff Margrett, FIRST Senior Mentor and teams from Pathfinders Robotics attended Waukee Public Library \u201cBrick Builders\u201d to share their robots.  (FTC Pathfinders 13497, FTC Circuit Breakers 10435, and FLL Phoenix Voyagers 7885)."),r.a.createElement("br",null),r.a.createElement("br",null)))),r.a.createElement(F.a,{md:"auto",className:"sidenav"},this.state.width>775?Wa:null,r.a.createElement(k.a,{variant:"primary",href:"#WhoWeAre",className:"backToTop"},"Back to top")))))}}]),t}(n.Component),Va=function(e){function t(){return Object(s.a)(this,t),Object(c.a)(this,Object(u.a)(t).apply(this,arguments))}return Object(m.a)(t,e),Object(l.a)(t,[{key:"componentDidMount",value:function(){N.a.pageview(window.location.pathname)}},{key:"render",value:function(){return r.a.createElement("div",{id:"login"},r.a.createElement(M.Helmet,null,r.a.createElement("title",null,"Login | Pathfinders Robotics"),r.a.createElement("meta",{name:"description",content:"Login"})),r.a.createElement("h1",null,"Login"),r.a.createElement("p",null,"Login"))}}]),t}(n.Component),Ha=a(80),Ba=a.n(Ha),za=function(e){function t(e){var a;return Object(s.a)(this,t),(a=Object(c.a)(this,Object(u.a)(t).call(this,e))).getTitleFromCode=a.getTitleFromCode.bind(Object(I.a)(a)),a.getDescriptionFromCode=a.getDescriptionFromCode.bind(Object(I.a)(a)),a.getTitle=a.getTitle.bind(Object(I.a)(a)),a.getDescription=a.getDescription.bind(Object(I.a)(a)),a}return Object(m.a)(t,e),Object(l.a)(t,[{key:"getTitleFromCode",value:function(e){switch(e){case 400:return" Bad Request";case 401:return" Unauthorized";case 402:return" Payment Required";case 403:return" Forbidden";case 404:return" Not Found";case 405:return" Method Not Allowed";case 406:return" Not Acceptable";case 407:return" Proxy Authentication Required";case 408:return" Request Timeout";case 409:return" Conflict";case 410:return" Gone";case 411:return" Length Required";case 412:return" Precondition Failed";case 413:return" Request Entity Too Large";case 414:return" Request-URI Too Long";case 415:return" Unsupported Media Type";case 416:return" Requested Range Not Satisfiable";case 417:return" Expectation Failed";case 418:return" I'm a teapot (RFC 2324)";case 420:return" Enhance Your Calm (Twitter)";case 422:return" Unprocessable Entity (WebDAV)";case 423:return" Locked (WebDAV)";case 424:return" Failed Dependency (WebDAV)";case 425:return" Reserved for WebDAV";case 426:return" Upgrade Required";case 428:return" Precondition Required";case 429:return" Too Many Requests";case 431:return" Request Header Fields Too Large";case 444:return" No Response (Nginx)";case 449:return" Retry With (Microsoft)";case 450:return" Blocked by Windows Parental Controls (Microsoft)";case 451:return" Unavailable For Legal Reasons";case 499:return" Client Closed Request (Nginx)";case 500:return" Internal Server Error";case 501:return" Not Implemented";case 502:return" Bad Gateway";case 503:return" Service Unavailable";case 504:return" Gateway Timeout";case 505:return" HTTP Version Not Supported";case 506:return" Variant Also Negotiates (Experimental)";case 507:return" Insufficient Storage (WebDAV)";case 508:return" Loop Detected (WebDAV)";case 509:return" Bandwidth Limit Exceeded (Apache)";case 510:return" Not Extended";case 511:return" Network Authentication Required";case 598:return" Network read timeout error";case 599:return" Network connect timeout error";default:return" Not Found"}}},{key:"getDescriptionFromCode",value:function(e){switch(e){case 400:return" That's the wrong path to go down!";case 401:return" You're not allowed to go down that path!";case 402:return" You must pay to go down this path!";case 403:return" You're not allowed to go down that path!";case 404:return" We couldn't find that path!";case 405:return" You're not allowed to go down that path!";case 406:return" That path is unacceptable!";case 407:return" That path requires further security!";case 408:return" We've spent too long on that path!";case 409:return" That path is broken!";case 410:return" That path doesn't exist!";case 411:return" That path isn't long enough!";case 412:return" We couldn't start that path!";case 413:return" That path is too big!";case 414:return" That path is too long!";case 415:return" We don't take that kind of path!";case 416:return" That path doesn't work for us!";case 417:return" That path didn't meet expectations!";case 418:return" ... short and stout! / Here is my handle, here is my spout!";case 420:return" Blaze it, bro!";case 422:return" We couldn't process that path!";case 423:return" That path is locked!";case 424:return" That path doesn't have all it needs!";case 425:return" That path is reserved for someone else!";case 426:return" You need to improve to travel on that path!";case 428:return" You need to meet the requirements to travel on that path!";case 429:return" You've tried that path too many times!";case 431:return" Your shoes are too big for that path!";case 444:return" That path just doesn't support you!";case 449:return" When all other paths fail, try, try, try again!";case 450:return" That path has been blocked!";case 451:return" That path is illegal!";case 499:return" You closed access to that path!";case 500:return" There's an issue with the destination!";case 501:return" This path isn't correctly paved!";case 502:return" This path is inaccessible!";case 503:return" This destination is unavailable!";case 504:return" This path took too long to pave!";case 505:return" This path doesn't support your method of travel!";case 506:return" This path is sassy!";case 507:return" There's not enough storage for this path!";case 508:return" This path results in a loop!";case 509:return" This path requires too much bandwidth!";case 510:return" This path has not been extended!";case 511:return" This path requires border patrol!";case 598:case 599:return" Border patrol took too long on this path!";default:return" We couldn't find that path!"}}},{key:"getTitle",value:function(){if(this.props.notFound)return"Error 404: "+this.getTitleFromCode(404);if(this.props.location.search){var e=Ba.a.parse(this.props.location.search.substr(1));if(e&&e.code)return"Error "+e.code.toString()+": "+this.getTitleFromCode(parseInt(e.code))}return"Error "+this.getTitleFromCode(404)}},{key:"getDescription",value:function(){if(this.props.notFound)return this.getDescriptionFromCode(404);if(this.props.location.search){var e=Ba.a.parse(this.props.location.search.substr(1));if(e&&e.code)return this.getDescriptionFromCode(parseInt(e.code))}return this.getDescriptionFromCode(404)}},{key:"render",value:function(){return r.a.createElement("div",{className:"centeredDiv"},r.a.createElement("h1",{className:"centeredTitle"},this.getTitle()),r.a.createElement("h4",{className:"centeredTitle"},this.getDescription()),r.a.createElement(b,{src:y.a,alt:"Pathfinders Robotics Logo",responsive:"true"}))}}]),t}(n.Component);N.a.initialize("UA-137274030-1");var Qa=function(e){function t(){return Object(s.a)(this,t),Object(c.a)(this,Object(u.a)(t).apply(this,arguments))}return Object(m.a)(t,e),Object(l.a)(t,[{key:"componentDidMount",value:function(){N.a.pageview(window.location.pathname)}},{key:"render",value:function(){return r.a.createElement(C.a,null,r.a.createElement(O.a,{exact:!0,path:"/",component:xa}),r.a.createElement(O.a,{path:"/donate",component:ua}),r.a.createElement(O.a,{path:"/login",component:Va}),r.a.createElement(O.a,{path:"/teams/7885",component:_}),r.a.createElement(O.a,{path:"/teams/13497",component:dt}),r.a.createElement(O.a,{path:"/teams/create",component:ft}),r.a.createElement(O.a,{path:"/error",component:za}),r.a.createElement(O.a,{path:"*",render:function(e){return r.a.createElement(za,{notFound:"true"})}}))}}]),t}(n.Component),qa=a(56),Ua=function(e){function t(){return Object(s.a)(this,t),Object(c.a)(this,Object(u.a)(t).apply(this,arguments))}return Object(m.a)(t,e),Object(l.a)(t,[{key:"render",value:function(){return r.a.createElement(h.a,{fixed:"bottom",className:"navbar titanic",variant:"dark",expand:"lg"},r.a.createElement(F.a,null,r.a.createElement(P.a,null,r.a.createElement(h.a.Brand,{className:"titanic noVerticalPadding"},"Connect With Us!")),r.a.createElement(P.a,{className:"navbar titanic"},r.a.createElement(h.a.Brand,{href:"https://www.facebook.com/13497.FTC.Pathfinders/",target:"_blank",rel:"noopener",className:"noVerticalPadding"},r.a.createElement(qa.a,{size:"1.5em"})),r.a.createElement(h.a.Brand,{href:"https://github.com/owens3364/",target:"_blank",rel:"noopener",className:"noVerticalPadding"},r.a.createElement(qa.b,{size:"1.5em"})),r.a.createElement(h.a.Brand,{href:"mailto:info@pathfindersrobotics.org noVerticalPadding"},r.a.createElement(qa.e,{size:"1.5em"})),r.a.createElement(h.a.Brand,{href:"https://www.instagram.com/pathfinders13497/",target:"_blank",rel:"noopener",className:"noVerticalPadding"},r.a.createElement(qa.c,{size:"1.5em"})),r.a.createElement(h.a.Brand,{href:"https://twitter.com/pathfinder13497/",target:"_blank",rel:"noopener",className:"noVerticalPadding"},r.a.createElement(qa.d,{size:"1.5em"})))))}}]),t}(n.Component),Ya=function(e){function t(){return Object(s.a)(this,t),Object(c.a)(this,Object(u.a)(t).apply(this,arguments))}return Object(m.a)(t,e),Object(l.a)(t,[{key:"render",value:function(){return r.a.createElement("div",null,r.a.createElement(v,null),r.a.createElement(d.a,{className:"jumbotron"},r.a.createElement(S,null,r.a.createElement(Qa,null)),r.a.createElement("br",null)),r.a.createElement(Ua,null))}}]),t}(n.Component),Ga=Boolean("localhost"===window.location.hostname||"[::1]"===window.location.hostname||window.location.hostname.match(/^127(?:\.(?:25[0-5]|2[0-4][0-9]|[01]?[0-9][0-9]?)){3}$/));function Ka(e,t){navigator.serviceWorker.register(e).then((function(e){e.onupdatefound=function(){var a=e.installing;null!=a&&(a.onstatechange=function(){"installed"===a.state&&(navigator.serviceWorker.controller?(console.log("New content is available and will be used when all tabs for this page are closed. See http://bit.ly/CRA-PWA."),t&&t.onUpdate&&t.onUpdate(e)):(console.log("Content is cached for offline use."),t&&t.onSuccess&&t.onSuccess(e)))})}})).catch((function(e){console.error("Error during service worker registration:",e)}))}var Xa=a(82);i.a.render(r.a.createElement(Xa.a,null,r.a.createElement(Ya,null)),document.getElementById("root")),function(e){if("serviceWorker"in navigator){if(new URL("",window.location.href).origin!==window.location.origin)return;window.addEventListener("load",(function(){var t="".concat("","/service-worker.js");Ga?(!function(e,t){fetch(e).then((function(a){var n=a.headers.get("content-type");404===a.status||null!=n&&-1===n.indexOf("javascript")?navigator.serviceWorker.ready.then((function(e){e.unregister().then((function(){window.location.reload()}))})):Ka(e,t)})).catch((function(){console.log("No internet connection found. App is running in offline mode.")}))}(t,e),navigator.serviceWorker.ready.then((function(){console.log("This web app is being served cache-first by a service worker. To learn more, visit http://bit.ly/CRA-PWA")}))):Ka(t,e)}))}}()}]),[[156,1,2]]]);
 //# sourceMappingURL=main.eb84e833.chunk.js.map
\ No newline at end of file
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+)
+
+// Records the server keeps between restarts (events, orders, tickets and so on) are stored as JSON documents,
+// either in the Postgres database named by the 'DATABASE_URL' environment variable (set by Heroku Postgres), or
+// in the directory named by 'DATA_DIR'. A dyno's disk is wiped on every restart, so on Heroku only the database
+// keeps them.
+// Every read-modify-write goes through update so two requests, or the server and a command, can't clobber each
+// other's changes.
+type jsonStore struct {
+	mu  sync.Mutex
+	dir string
+	// When set, documents are rows of the database's documents table instead of files in dir
+	databaseURL string
+	db          *postgresConn
+}
+
+// Set once at startup, and read through dataStore so the workers and requests always see a whole store
+var currentStore atomic.Value // *jsonStore
+
+// The store, or nil when the data directory couldn't be created
+func dataStore() *jsonStore {
+	store, _ := currentStore.Load().(*jsonStore)
+	return store
+}
+
+func setDataStore(store *jsonStore) {
+	currentStore.Store(store)
+}
+
+// The database if 'DATABASE_URL' is set, and the data directory otherwise
+func openDataStore(cfg *Config) (*jsonStore, error) {
+	if cfg.Server.DatabaseURL != "" {
+		return newDatabaseStore(cfg.Server.DatabaseURL)
+	}
+	return newJSONStore(cfg.Server.DataDir)
+}
+
+func newJSONStore(dir string) (*jsonStore, error) {
+	if dir == "" {
+		dir = "./data"
+	}
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, err
+	}
+	return &jsonStore{dir: dir}, nil
+}
+
+func newDatabaseStore(databaseURL string) (*jsonStore, error) {
+	s := &jsonStore{databaseURL: databaseURL}
+	err := s.withDatabase(func(db *postgresConn) error {
+		_, err := db.exec("CREATE TABLE IF NOT EXISTS documents (name text PRIMARY KEY, body text NOT NULL)")
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Loads the named document into v. A document that doesn't exist yet leaves v untouched.
+func (s *jsonStore) load(name string, v interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.databaseURL != "" {
+		return s.withDatabase(func(db *postgresConn) error {
+			return readDocument(db, name, v)
+		})
+	}
+	return s.read(name, v)
+}
+
+// Loads the named document into v, runs fn, and writes v back if fn didn't return an error.
+// The database holds a transaction-scoped advisory lock on the name throughout, and the directory a lock file,
+// so other processes sharing the store wait their turn too.
+func (s *jsonStore) update(name string, v interface{}, fn func() error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.databaseURL != "" {
+		return s.withDatabase(func(db *postgresConn) error {
+			_, err := db.exec("BEGIN")
+			if err != nil {
+				return err
+			}
+			err = updateDocument(db, name, v, fn)
+			if err != nil {
+				if !db.broken {
+					db.exec("ROLLBACK")
+				}
+				return err
+			}
+			_, err = db.exec("COMMIT")
+			return err
+		})
+	}
+
+	unlock, err := lockFile(filepath.Join(s.dir, name+".lock"))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	err = s.read(name, v)
+	if err != nil {
+		return err
+	}
+	err = fn()
+	if err != nil {
+		return err
+	}
+	return s.write(name, v)
+}
+
+func (s *jsonStore) read(name string, v interface{}) error {
+	raw, err := ioutil.ReadFile(filepath.Join(s.dir, name+".json"))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// Writes to a temporary file first and renames it into place so a crash mid-write never leaves a half-written document
+func (s *jsonStore) write(name string, v interface{}) error {
+	raw, err := json.MarshalIndent(v, "", "\t")
+	if err != nil {
+		return err
+	}
+	tmp := filepath.Join(s.dir, name+".json.tmp")
+	err = ioutil.WriteFile(tmp, raw, 0600)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(s.dir, name+".json"))
+}
+
+// Runs fn on the store's database connection, connecting first if there isn't one. A connection the server
+// closed while it sat idle fails on its first statement, before anything was done, so fn is tried once more
+// on a new connection.
+func (s *jsonStore) withDatabase(fn func(db *postgresConn) error) error {
+	for attempt := 0; ; attempt++ {
+		reused := s.db != nil
+		if s.db == nil {
+			db, err := dialPostgres(s.databaseURL)
+			if err != nil {
+				return err
+			}
+			s.db = db
+		}
+		db, before := s.db, s.db.exchanges
+		err := fn(db)
+		if db.broken {
+			db.conn.Close()
+			s.db = nil
+			if reused && db.exchanges == before && attempt == 0 {
+				continue
+			}
+		}
+		return err
+	}
+}
+
+func readDocument(db *postgresConn, name string, v interface{}) error {
+	rows, err := db.exec("SELECT body FROM documents WHERE name = $1", name)
+	if err != nil || len(rows) == 0 {
+		return err
+	}
+	return json.Unmarshal([]byte(rows[0][0]), v)
+}
+
+func updateDocument(db *postgresConn, name string, v interface{}, fn func() error) error {
+	_, err := db.exec("SELECT pg_advisory_xact_lock(hashtext($1))", name)
+	if err != nil {
+		return err
+	}
+	err = readDocument(db, name, v)
+	if err != nil {
+		return err
+	}
+	err = fn()
+	if err != nil {
+		return err
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = db.exec("INSERT INTO documents (name, body) VALUES ($1, $2) ON CONFLICT (name) DO UPDATE SET body = excluded.body", name, string(raw))
+	return err
+}
@@ -0,0 +1,434 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	texttemplate "text/template"
+	"time"
+)
+
+// Email templates, loaded from 'TEMPLATES_DIR' (default ./templates) when the server starts.
+// receipt.html is the donation receipt, notification.txt is the new payment email to the team and finance, and
+// refund.txt is the notice a donor gets when a donation is refunded.
+// A team can override any of them with a file of the same name in teams/<team>/, e.g. teams/ftc13497/receipt.html.
+// Translations go in a directory named for the locale, e.g. es/receipt.html or teams/ftc13497/es/receipt.html.
+// The receipt uses html/template, so donor-supplied values are always escaped.
+
+type emailTemplateSet struct {
+	receipts      map[string]*template.Template // By templateKey, team "" and locale "" are the defaults
+	notifications map[string]*texttemplate.Template
+	refunds       map[string]*texttemplate.Template
+}
+
+func templateKey(team string, locale string) string {
+	return team + "|" + locale
+}
+
+// Templates for the locale are used when there's one for the team or a default one. Otherwise it's English.
+func (set *emailTemplateSet) receiptLocale(team string, locale string) string {
+	if set == nil || locale == "" || (set.receipts[templateKey(team, locale)] == nil && set.receipts[templateKey("", locale)] == nil) {
+		return ""
+	}
+	return locale
+}
+
+func (set *emailTemplateSet) notificationLocale(team string, locale string) string {
+	if set == nil || locale == "" || (set.notifications[templateKey(team, locale)] == nil && set.notifications[templateKey("", locale)] == nil) {
+		return ""
+	}
+	return locale
+}
+
+func (set *emailTemplateSet) refundLocale(team string, locale string) string {
+	if set == nil || locale == "" || (set.refunds[templateKey(team, locale)] == nil && set.refunds[templateKey("", locale)] == nil) {
+		return ""
+	}
+	return locale
+}
+
+// The values a receipt or notification template can use
+type receiptFields struct {
+	Name          string        `json:"name"`
+	Addr1         string        `json:"addr1"`
+	Addr2         string        `json:"addr2"`
+	City          string        `json:"city"`
+	State         string        `json:"state"`
+	Zip           string        `json:"zip"`
+	Email         string        `json:"email"`
+	Phone         string        `json:"phone"`
+	Description   string        `json:"description"`
+	Amount        string        `json:"amount"` // Dollars, e.g. "25.00"
+	Date          string        `json:"date"`
+	CurrentSeason string        `json:"currentSeason"`
+	Team          string        `json:"team"`
+	FIRSTSuffix   string        `json:"firstSuffix"`
+	OrgName       string        `json:"orgName"`
+	PRAddr1       string        `json:"prAddr1"`
+	PRCity        string        `json:"prCity"`
+	PRState       string        `json:"prState"`
+	PRZip         string        `json:"prZip"`
+	PRPhone       string        `json:"prPhone"`
+	EIN           string        `json:"ein"`
+	SignerName    string        `json:"signerName"`       // The treasurer in office on the gift date
+	SignerTitle   string        `json:"signerTitle"`      // In the receipt's locale
+	Number        string        `json:"number"`           // Receipt number, e.g. "2026-00042", empty when receipts aren't being recorded
+	Locale        string        `json:"locale,omitempty"` // The translation used, "" for English
+	Note          template.HTML `json:"note"`             // Set by the server only, e.g. to mark a corrected copy
+	LogoSrc       template.URL  `json:"logoSrc"`
+	SignatureSrc  template.URL  `json:"signatureSrc"`
+	Logo          string        `json:"logo,omitempty"`      // Organization image IDs, "" for the files in static/assets/receipts
+	Signature     string        `json:"signature,omitempty"` // so reissued receipts keep the images they were issued with
+}
+
+const receiptTemplateFile string = "receipt.html"
+const notificationTemplateFile string = "notification.txt"
+const refundTemplateFile string = "refund.txt"
+
+// Directory under teams/ for each team's overrides
+var teamTemplateDirs = map[string]string{
+	FTCPathfinders13497:    "ftc13497",
+	FLLPhoenixVoyagers7885: "fll7885",
+}
+
+// Placeholders a receipt must show for it to count as a donation receipt
+var requiredReceiptFields = []string{"Name", "Amount", "Date", "EIN", "Number", "SignerName"}
+
+// Loaded once at startup and read through emailTemplates
+var currentTemplates atomic.Value // *emailTemplateSet
+
+func emailTemplates() *emailTemplateSet {
+	templates, _ := currentTemplates.Load().(*emailTemplateSet)
+	return templates
+}
+
+func setEmailTemplates(templates *emailTemplateSet) {
+	currentTemplates.Store(templates)
+}
+
+// Parses every template and renders it once with sample values, so a typo or a missing placeholder
+// stops the server at startup instead of failing when a donor's receipt is sent.
+func loadEmailTemplates(dir string) (*emailTemplateSet, error) {
+	if dir == "" {
+		dir = "./templates"
+	}
+	set := &emailTemplateSet{receipts: map[string]*template.Template{}, notifications: map[string]*texttemplate.Template{}, refunds: map[string]*texttemplate.Template{}}
+
+	receipt, err := parseReceiptTemplate(filepath.Join(dir, receiptTemplateFile), true)
+	if err != nil {
+		return nil, err
+	}
+	set.receipts[templateKey("", "")] = receipt
+	notification, err := parseNotificationTemplate(filepath.Join(dir, notificationTemplateFile), true)
+	if err != nil {
+		return nil, err
+	}
+	set.notifications[templateKey("", "")] = notification
+	refund, err := parseNotificationTemplate(filepath.Join(dir, refundTemplateFile), true)
+	if err != nil {
+		return nil, err
+	}
+	set.refunds[templateKey("", "")] = refund
+
+	teamDirs := map[string]string{"": ""}
+	for team, teamDir := range teamTemplateDirs {
+		teamDirs[team] = filepath.Join("teams", teamDir)
+	}
+	for team, teamDir := range teamDirs {
+		for _, locale := range append([]string{""}, translatedLocales...) {
+			if team == "" && locale == "" {
+				continue
+			}
+			receipt, err = parseReceiptTemplate(filepath.Join(dir, teamDir, locale, receiptTemplateFile), false)
+			if err != nil {
+				return nil, err
+			}
+			if receipt != nil {
+				set.receipts[templateKey(team, locale)] = receipt
+			}
+			notification, err = parseNotificationTemplate(filepath.Join(dir, teamDir, locale, notificationTemplateFile), false)
+			if err != nil {
+				return nil, err
+			}
+			if notification != nil {
+				set.notifications[templateKey(team, locale)] = notification
+			}
+			refund, err = parseNotificationTemplate(filepath.Join(dir, teamDir, locale, refundTemplateFile), false)
+			if err != nil {
+				return nil, err
+			}
+			if refund != nil {
+				set.refunds[templateKey(team, locale)] = refund
+			}
+		}
+	}
+	return set, nil
+}
+
+// Returns nil without an error when the file doesn't exist and isn't required
+func parseReceiptTemplate(path string, required bool) (*template.Template, error) {
+	source, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) && !required {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := template.New(path).Option("missingkey=error").Parse(string(source))
+	if err != nil {
+		return nil, err
+	}
+	var rendered bytes.Buffer
+	err = tmpl.Execute(&rendered, sampleReceiptFields())
+	if err != nil {
+		return nil, fmt.Errorf("template %s: %v", path, err)
+	}
+	for _, field := range requiredReceiptFields {
+		if !strings.Contains(rendered.String(), "sample"+field) {
+			return nil, fmt.Errorf("template %s: a receipt must include {{.%s}}", path, field)
+		}
+	}
+	return tmpl, nil
+}
+
+func parseNotificationTemplate(path string, required bool) (*texttemplate.Template, error) {
+	source, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) && !required {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := texttemplate.New(path).Option("missingkey=error").Parse(string(source))
+	if err != nil {
+		return nil, err
+	}
+	err = tmpl.Execute(ioutil.Discard, sampleReceiptFields())
+	if err != nil {
+		return nil, fmt.Errorf("template %s: %v", path, err)
+	}
+	return tmpl, nil
+}
+
+// Every field set to "sample" + its name, so the rendered output shows which placeholders were used
+func sampleReceiptFields() receiptFields {
+	return receiptFields{
+		Name: "sampleName", Addr1: "sampleAddr1", Addr2: "sampleAddr2", City: "sampleCity", State: "sampleState", Zip: "sampleZip",
+		Email: "sampleEmail", Phone: "samplePhone", Description: "sampleDescription", Amount: "sampleAmount", Date: "sampleDate",
+		CurrentSeason: "sampleCurrentSeason", Team: "sampleTeam", FIRSTSuffix: "sampleFIRSTSuffix",
+		OrgName: "sampleOrgName", SignerName: "sampleSignerName", SignerTitle: "sampleSignerTitle",
+		PRAddr1: "samplePRAddr1", PRCity: "samplePRCity", PRState: "samplePRState", PRZip: "samplePRZip", PRPhone: "samplePRPhone",
+		EIN: "sampleEIN", Number: "sampleNumber", Note: "sampleNote", LogoSrc: "cid:sampleLogoSrc", SignatureSrc: "cid:sampleSignatureSrc",
+	}
+}
+
+// The receipt's values, with the date and amount formatted for the receipt translation that will be used
+func newReceiptFields(emailData EmailData) receiptFields {
+	return localizedReceiptFields(emailData, emailTemplates().receiptLocale(emailData.Team, donationLocale(emailData.DonorInformation)))
+}
+
+func localizedReceiptFields(emailData EmailData, locale string) receiptFields {
+	donor := emailData.DonorInformation
+	fields := receiptFields{
+		Name:          *donor.Name,
+		Addr1:         *donor.Addr1,
+		Addr2:         *donor.Addr2,
+		City:          *donor.City,
+		State:         *donor.State,
+		Zip:           *donor.Zip,
+		Email:         *donor.Email,
+		Phone:         *donor.Phone,
+		Description:   *donor.Description,
+		Amount:        formatCentsLocale(*donor.Amount, locale),
+		Date:          emailData.Date,
+		CurrentSeason: emailData.CurrentSeason,
+		Team:          emailData.Team,
+		FIRSTSuffix:   emailData.FIRSTSuffix,
+		OrgName:       emailData.Organization.LegalName,
+		PRAddr1:       emailData.PRAddr1,
+		PRCity:        emailData.PRCity,
+		PRState:       emailData.PRState,
+		PRZip:         emailData.PRZip,
+		PRPhone:       emailData.PRPhone,
+		EIN:           emailData.EIN,
+		Locale:        locale,
+		Logo:          emailData.Organization.Logo,
+	}
+	if locale != "" && !emailData.Received.IsZero() {
+		fields.Date = formatDateLocale(emailData.Received, locale)
+	}
+	received := emailData.Received
+	if received.IsZero() {
+		received = time.Now()
+	}
+	if signer, found := emailData.Organization.officerOn(ReceiptSignerRole, received); found {
+		fields.SignerName = signer.Name
+		fields.SignerTitle = signer.localizedTitle(locale)
+		fields.Signature = signer.Signature
+	}
+	fields.LogoSrc = template.URL(receiptImageURL("logo", fields.Logo))
+	fields.SignatureSrc = template.URL(receiptImageURL("signature", fields.Signature))
+	if emailData.InlineImages {
+		fields.LogoSrc = template.URL("cid:" + receiptLogoCID)
+		fields.SignatureSrc = template.URL("cid:" + receiptSignatureCID)
+	}
+	return fields
+}
+
+// Receipts recorded before the organization profile was kept are signed by the treasurer in office when
+// they were issued
+func (fields receiptFields) withOrganization(cfg *Config, issued time.Time) (receiptFields, error) {
+	if fields.SignerName != "" {
+		return fields, nil
+	}
+	org, err := organizationProfile(cfg)
+	if err != nil {
+		return fields, err
+	}
+	if fields.OrgName == "" {
+		fields.OrgName = org.LegalName
+	}
+	if signer, found := org.officerOn(ReceiptSignerRole, issued); found {
+		fields.SignerName = signer.Name
+		fields.SignerTitle = signer.localizedTitle(fields.Locale)
+		fields.Signature = signer.Signature
+	}
+	return fields, nil
+}
+
+const receiptLogoCID string = "logo@pathfindersrobotics.org"
+const receiptSignatureCID string = "signature@pathfindersrobotics.org"
+
+// The logo and signature for receipts rendered with InlineImages
+func receiptImages(fields receiptFields) ([]inlineImage, error) {
+	logo, _, err := receiptImage("logo", fields.Logo)
+	if err != nil {
+		return nil, err
+	}
+	signature, _, err := receiptImage("signature", fields.Signature)
+	if err != nil {
+		return nil, err
+	}
+	return []inlineImage{
+		{ContentID: receiptLogoCID, Filename: "Logo.png", ContentType: "image/png", Data: logo},
+		{ContentID: receiptSignatureCID, Filename: "Signature.png", ContentType: "image/png", Data: signature},
+	}, nil
+}
+
+func renderReceipt(emailData EmailData) (string, error) {
+	return renderReceiptFields(newReceiptFields(emailData))
+}
+
+// Uses the template of the team the receipt is for, in the receipt's locale
+func renderReceiptFields(fields receiptFields) (string, error) {
+	var tmpl *template.Template
+	for _, key := range templateKeys(fields.Team, fields.Locale) {
+		if tmpl == nil {
+			tmpl = emailTemplates().receipts[key]
+		}
+	}
+	var rendered bytes.Buffer
+	err := tmpl.Execute(&rendered, fields)
+	return rendered.String(), err
+}
+
+// The receipt as an email to the donor, with the logo and signature attached inline and the PDF copy attached.
+// Recorded receipts are sent exactly as they were rendered when issued.
+func receiptMessage(cfg *Config, receipt Receipt, subject string) (*mailMessage, error) {
+	fields, err := receipt.Fields.withOrganization(cfg, receipt.Issued)
+	if err != nil {
+		return nil, err
+	}
+	receipt.Fields = fields
+	html := receipt.HTML
+	if html == "" {
+		html, err = renderReceiptFields(receipt.Fields)
+		if err != nil {
+			return nil, err
+		}
+	}
+	images, err := receiptImages(receipt.Fields)
+	if err != nil {
+		return nil, err
+	}
+	pdf, err := renderReceiptPDF(cfg, receipt)
+	if err != nil {
+		return nil, err
+	}
+	return &mailMessage{
+		To:          []string{receipt.Fields.Email},
+		Subject:     subject,
+		HTML:        html,
+		Inline:      images,
+		Attachments: []mailAttachment{{Filename: fmt.Sprintf(translate(receipt.Fields.Locale, "receipt.attachment"), receipt.label()), ContentType: "application/pdf", Data: pdf}},
+	}, nil
+}
+
+// The receipt sendPaymentEmail sends, with the team and finance blind copied
+func donationReceiptMessage(cfg *Config, emailData EmailData, receipt Receipt) (*mailMessage, error) {
+	msg, err := receiptMessage(cfg, receipt, translate(receipt.Fields.Locale, "receipt.subject"))
+	if err != nil {
+		return nil, err
+	}
+	msg.Bcc = []string{emailData.TeamEmail, EmailFinance}
+	return msg, nil
+}
+
+// The new payment email to the team, with finance blind copied
+func notificationMessage(emailData EmailData) (*mailMessage, error) {
+	notification, err := renderNotification(emailData)
+	if err != nil {
+		return nil, err
+	}
+	return &mailMessage{To: []string{emailData.TeamEmail}, Bcc: []string{EmailFinance}, Subject: "New Payment", Text: notification}, nil
+}
+
+// The plain text notification body
+func renderNotification(emailData EmailData) (string, error) {
+	locale := emailTemplates().notificationLocale(emailData.Team, donationLocale(emailData.DonorInformation))
+	var tmpl *texttemplate.Template
+	for _, key := range templateKeys(emailData.Team, locale) {
+		if tmpl == nil {
+			tmpl = emailTemplates().notifications[key]
+		}
+	}
+	var rendered bytes.Buffer
+	err := tmpl.Execute(&rendered, localizedReceiptFields(emailData, locale))
+	return rendered.String(), err
+}
+
+// The notice to the donor when a donation is refunded, with the team and finance blind copied. The amount in
+// emailData is the amount refunded and the date is when it was refunded.
+func refundNoticeMessage(emailData EmailData) (*mailMessage, error) {
+	locale := emailTemplates().refundLocale(emailData.Team, donationLocale(emailData.DonorInformation))
+	var tmpl *texttemplate.Template
+	for _, key := range templateKeys(emailData.Team, locale) {
+		if tmpl == nil {
+			tmpl = emailTemplates().refunds[key]
+		}
+	}
+	var rendered bytes.Buffer
+	err := tmpl.Execute(&rendered, localizedReceiptFields(emailData, locale))
+	if err != nil {
+		return nil, err
+	}
+	return &mailMessage{To: []string{*emailData.DonorInformation.Email}, Bcc: []string{emailData.TeamEmail, EmailFinance}, Subject: translate(locale, "refund.subject"), Text: rendered.String()}, nil
+}
+
+// Where to look for a template, most specific first: the team's translation, the default translation,
+// the team's English template and the default English one
+func templateKeys(team string, locale string) []string {
+	return []string{templateKey(team, locale), templateKey("", locale), templateKey(team, ""), templateKey("", "")}
+}
+
+func donationLocale(data PaymentData) string {
+	if data.Locale == nil {
+		return ""
+	}
+	return normalizeLocale(*data.Locale)
+}
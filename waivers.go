@@ -0,0 +1,276 @@
+package main
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Scholarship and fee-waiver codes for dues and event tickets.
+// Coaches can see how much each code has been used from /admin, but which family or buyer used a code
+// is only shown to the board at /board, along with the dues invoices and ticket orders the codes were used on.
+
+type WaiverCode struct {
+	Code        string    `json:"code"`
+	Description string    `json:"description"`
+	Percent     int       `json:"percent"`
+	Amount      int       `json:"amount"`
+	MaxUses     int       `json:"maxUses"`
+	Expires     time.Time `json:"expires"`
+	AppliesTo   []string  `json:"appliesTo"` // WaiverContextDues and/or WaiverContextEvents; empty applies to both
+	Created     time.Time `json:"created"`
+}
+
+type WaiverRedemption struct {
+	ID        string    `json:"id"`
+	Code      string    `json:"code"`
+	Context   string    `json:"context"`
+	Reference string    `json:"reference"` // Family ID for dues, order ID for tickets
+	Discount  int       `json:"discount"`
+	Created   time.Time `json:"created"`
+	Confirmed bool      `json:"confirmed"`
+	// Confirmed after its hold expired and the use was taken by another checkout, so the code went over MaxUses
+	OverLimit bool `json:"overLimit,omitempty"`
+}
+
+type waiverData struct {
+	Codes       []WaiverCode       `json:"codes"`
+	Redemptions []WaiverRedemption `json:"redemptions"`
+}
+
+const WaiverContextDues string = "dues"
+const WaiverContextEvents string = "events"
+
+const waiversDocument string = "waivers"
+
+var errInvalidWaiver = errors.New("this code is not valid")
+
+func registerWaiverRoutes(admin *gin.RouterGroup, board *gin.RouterGroup) {
+	admin.POST("/waivers", func(c *gin.Context) {
+		var code WaiverCode
+		err := c.BindJSON(&code)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		if (code.Percent <= 0) == (code.Amount <= 0) || code.Percent > 100 {
+			c.String(http.StatusBadRequest, "A code needs either a percent between 1 and 100 or a fixed amount, but not both")
+			return
+		}
+		if code.MaxUses < 1 {
+			code.MaxUses = 1
+		}
+		if code.Code == "" {
+			code.Code = newWaiverCode()
+		}
+		code.Code = strings.ToUpper(code.Code)
+		code.Created = time.Now()
+
+		var data waiverData
+		err = dataStore().update(waiversDocument, &data, func() error {
+			if _, found := data.find(code.Code); found {
+				return errors.New("code " + code.Code + " already exists")
+			}
+			data.Codes = append(data.Codes, code)
+			return nil
+		})
+		if err != nil {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		c.JSON(200, code)
+	})
+
+	// Usage without identities, for coaches
+	admin.GET("/waivers", func(c *gin.Context) {
+		var data waiverData
+		err := dataStore().load(waiversDocument, &data)
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		var codes []gin.H
+		for _, code := range data.Codes {
+			total := 0
+			for _, redemption := range data.Redemptions {
+				if redemption.Code == code.Code && redemption.Confirmed {
+					total += redemption.Discount
+				}
+			}
+			codes = append(codes, gin.H{
+				"code":        code.Code,
+				"description": code.Description,
+				"percent":     code.Percent,
+				"amount":      code.Amount,
+				"maxUses":     code.MaxUses,
+				"expires":     code.Expires,
+				"appliesTo":   code.AppliesTo,
+				"uses":        data.uses(code.Code, time.Now()),
+				"totalWaived": total,
+			})
+		}
+		c.JSON(200, codes)
+	})
+
+	if board == nil {
+		fmt.Println("The waiver usage log for the board is disabled because board credentials are not configured.")
+		return
+	}
+
+	board.GET("/waivers/redemptions", func(c *gin.Context) {
+		var data waiverData
+		err := dataStore().load(waiversDocument, &data)
+		if err != nil {
+			fmt.Println(err)
+			c.String(http.StatusInternalServerError, "Error")
+			return
+		}
+		var confirmed []WaiverRedemption
+		for _, redemption := range data.Redemptions {
+			if redemption.Confirmed {
+				confirmed = append(confirmed, redemption)
+			}
+		}
+		c.JSON(200, confirmed)
+	})
+}
+
+func (data *waiverData) find(code string) (WaiverCode, bool) {
+	for _, waiver := range data.Codes {
+		if waiver.Code == strings.ToUpper(strings.TrimSpace(code)) {
+			return waiver, true
+		}
+	}
+	return WaiverCode{}, false
+}
+
+// Confirmed redemptions plus ones still held by an unfinished checkout
+func (data *waiverData) uses(code string, now time.Time) int {
+	uses := 0
+	for _, redemption := range data.Redemptions {
+		if redemption.Code == code && (redemption.Confirmed || now.Sub(redemption.Created) < checkoutHoldDuration) {
+			uses++
+		}
+	}
+	return uses
+}
+
+// Holds one use of a waiver code against a checkout of the given amount and returns the discount.
+// The use is released if the checkout isn't confirmed within checkoutHoldDuration.
+func reserveWaiver(code string, context string, reference string, amount int) (int, string, error) {
+	var data waiverData
+	var discount int
+	var redemptionID string
+	err := dataStore().update(waiversDocument, &data, func() error {
+		waiver, found := data.find(code)
+		if !found {
+			return errInvalidWaiver
+		}
+		now := time.Now()
+		if !waiver.Expires.IsZero() && now.After(waiver.Expires) {
+			return errInvalidWaiver
+		}
+		if data.uses(waiver.Code, now) >= waiver.MaxUses {
+			return errInvalidWaiver
+		}
+		applies := len(waiver.AppliesTo) == 0
+		for _, allowed := range waiver.AppliesTo {
+			if allowed == context {
+				applies = true
+			}
+		}
+		if !applies {
+			return errInvalidWaiver
+		}
+
+		discount = waiver.Amount
+		if waiver.Percent > 0 {
+			discount = amount * waiver.Percent / 100
+		}
+		if discount > amount {
+			discount = amount
+		}
+		// Stripe won't charge less than 50 cents, so a discount that would leave less than that waives the whole amount
+		if amount-discount < 50 {
+			discount = amount
+		}
+		redemptionID = newID()
+		data.Redemptions = append(data.Redemptions, WaiverRedemption{
+			ID:        redemptionID,
+			Code:      waiver.Code,
+			Context:   context,
+			Reference: reference,
+			Discount:  discount,
+			Created:   now,
+		})
+		return nil
+	})
+	return discount, redemptionID, err
+}
+
+func releaseWaiver(redemptionID string) {
+	changeWaiverRedemption(redemptionID, func(data *waiverData, i int) {
+		data.Redemptions = append(data.Redemptions[:i], data.Redemptions[i+1:]...)
+	})
+}
+
+// Confirms a held use once its checkout is paid. A checkout paid after its hold expired may find the use given to
+// another one. The discount has been charged by then, so the use is still confirmed, but marked over the limit
+// in the board's log.
+func confirmWaiver(redemptionID string) {
+	changeWaiverRedemption(redemptionID, func(data *waiverData, i int) {
+		redemption := &data.Redemptions[i]
+		if redemption.Confirmed {
+			return
+		}
+		now := time.Now()
+		waiver, _ := data.find(redemption.Code)
+		// A use whose hold is still current is already counted
+		if now.Sub(redemption.Created) >= checkoutHoldDuration && data.uses(redemption.Code, now) >= waiver.MaxUses {
+			redemption.OverLimit = true
+			fmt.Println("ERROR: WAIVER CODE " + redemption.Code + " WENT OVER ITS LIMIT OF " + strconv.Itoa(waiver.MaxUses) +
+				" USES WHEN A CHECKOUT WAS PAID AFTER ITS HOLD EXPIRED. SEE /board/waivers/redemptions")
+		}
+		redemption.Confirmed = true
+		// Only the code and amount are logged; who used it stays in the board's view
+		fmt.Println("Waiver code " + redemption.Code + " redeemed for $" + formatCents(redemption.Discount) + " of " + redemption.Context)
+	})
+}
+
+func changeWaiverRedemption(redemptionID string, change func(data *waiverData, i int)) {
+	if redemptionID == "" {
+		return
+	}
+	var data waiverData
+	err := dataStore().update(waiversDocument, &data, func() error {
+		for i := range data.Redemptions {
+			if data.Redemptions[i].ID == redemptionID {
+				change(&data, i)
+				return nil
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Println(err)
+		fmt.Println("ERROR: WAIVER REDEMPTION " + redemptionID + " COULD NOT BE UPDATED")
+	}
+}
+
+// Eight characters without the easily confused 0/O and 1/I
+func newWaiverCode() string {
+	const alphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	raw := make([]byte, 8)
+	rand.Read(raw)
+	for i := range raw {
+		raw[i] = alphabet[int(raw[i])%len(alphabet)]
+	}
+	return string(raw)
+}
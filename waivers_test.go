@@ -0,0 +1,52 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLateWaiverConfirmationIsMarkedOverTheLimit(t *testing.T) {
+	useTestStore(t)
+	var data waiverData
+	dataStore().update(waiversDocument, &data, func() error {
+		data.Codes = append(data.Codes, WaiverCode{Code: "SCHOLAR1", Percent: 100, MaxUses: 1})
+		return nil
+	})
+	expireHold := func(redemptionID string) {
+		dataStore().update(waiversDocument, &data, func() error {
+			for i := range data.Redemptions {
+				if data.Redemptions[i].ID == redemptionID {
+					data.Redemptions[i].Created = data.Redemptions[i].Created.Add(-checkoutHoldDuration - time.Minute)
+				}
+			}
+			return nil
+		})
+	}
+
+	_, late, err := reserveWaiver("scholar1", WaiverContextDues, "family1", 10000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := reserveWaiver("SCHOLAR1", WaiverContextDues, "family2", 10000); err != errInvalidWaiver {
+		t.Fatalf("expected the held use to refuse a second checkout, got %v", err)
+	}
+	expireHold(late)
+	_, onTime, err := reserveWaiver("SCHOLAR1", WaiverContextEvents, "order1", 2000)
+	if err != nil {
+		t.Fatalf("expected the expired hold's use to go to the next checkout, got %v", err)
+	}
+	confirmWaiver(onTime)
+	confirmWaiver(late)
+	confirmWaiver(late)
+
+	dataStore().load(waiversDocument, &data)
+	expected := map[string]bool{onTime: false, late: true}
+	for _, redemption := range data.Redemptions {
+		if !redemption.Confirmed || redemption.OverLimit != expected[redemption.ID] {
+			t.Errorf("expected %s to be confirmed with OverLimit %v, got %+v", redemption.Reference, expected[redemption.ID], redemption)
+		}
+	}
+	if len(data.Redemptions) != 2 || data.uses("SCHOLAR1", time.Now()) != 2 {
+		t.Errorf("expected both paid uses to count, got %+v", data.Redemptions)
+	}
+}